@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const rootModuleName = "(root)"
+
+// AggregateInput is one named input of an aggregate report. Fingerprint is
+// empty if the input contains no cycle error.
+type AggregateInput struct {
+	Name        string `json:"name"`
+	File        string `json:"file"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+}
+
+// AggregateCycle is a cycle shared by one or more inputs. Count is the
+// number of inputs it appeared in, and Workspaces their names.
+type AggregateCycle struct {
+	Fingerprint   string     `json:"fingerprint"`
+	Severity      string     `json:"severity"`
+	Nodes         []string   `json:"nodes"`
+	MinimalCycles [][]string `json:"minimal_cycles"`
+	Patterns      []string   `json:"patterns"`
+	Count         int        `json:"count"`
+	Workspaces    []string   `json:"workspaces"`
+}
+
+// AggregateCount counts the distinct cycles and the inputs a module, pattern
+// or resource type appears in.
+type AggregateCount struct {
+	Name       string `json:"name"`
+	Cycles     int    `json:"distinct_cycles"`
+	Workspaces int    `json:"workspaces"`
+}
+
+// AggregateMetadata describes how every analysis of an aggregate report was
+// made.
+type AggregateMetadata struct {
+	Tool                  string `json:"tool"`
+	Version               string `json:"version"`
+	AnalysisSchemaVersion int    `json:"analysis_schema_version"`
+	Redacted              bool   `json:"redacted"`
+}
+
+// AggregateAnalysis is the JSON analysis of a distinct cycle, shared by the
+// workspaces that hit it.
+type AggregateAnalysis struct {
+	Fingerprint string          `json:"fingerprint"`
+	Count       int             `json:"count"`
+	Workspaces  []string        `json:"workspaces"`
+	Analysis    json.RawMessage `json:"analysis"`
+}
+
+type AggregateReport struct {
+	Metadata      AggregateMetadata   `json:"metadata"`
+	Inputs        []AggregateInput    `json:"inputs"`
+	Cycles        []AggregateCycle    `json:"cycles"`
+	Modules       []AggregateCount    `json:"modules"`
+	Patterns      []AggregateCount    `json:"patterns"`
+	ResourceTypes []AggregateCount    `json:"resource_types"`
+	Analyses      []AggregateAnalysis `json:"analyses,omitempty"`
+}
+
+// parseNamedInput splits a NAME=FILE argument. Without a name, the file is
+// its own name.
+func parseNamedInput(arg string) (name, file string) {
+	if name, file, ok := strings.Cut(arg, "="); ok && name != "" && !strings.ContainsAny(name, `/\`) {
+		return name, file
+	}
+	return arg, arg
+}
+
+func runAggregate(ctx context.Context, config Config) error {
+	if len(config.Args) == 0 {
+		return inputErrorf("aggregate requires at least one input, e.g. tfcycle aggregate prod=prod.log staging=staging.log")
+	}
+	
+	if config.Analyses && outputFormat(config) != "json" {
+		return inputErrorf("--analyses needs --json")
+	}
+	ruleOpts, err := suggestionRulesOptions(config)
+	if err != nil {
+		return err
+	}
+	
+	seen := make(map[string]bool)
+	for _, arg := range config.Args {
+		name, _ := parseNamedInput(arg)
+		if seen[name] {
+			return inputErrorf("input name %s is given more than once", name)
+		}
+		seen[name] = true
+	}
+	
+	var inputs []AggregateInput
+	var analyzers []*tfcycle.CycleAnalyzer
+	for _, arg := range config.Args {
+		name, file := parseNamedInput(arg)
+		text, versions, err := readInput(ctx, file, config)
+		if err != nil {
+			return inputErrorf("failed to read %s: %w", name, err)
+		}
+		input := AggregateInput{Name: name, File: file}
+		if text == "" {
+			logger.Debug("no cycle error in input", "input", name)
+			inputs = append(inputs, input)
+			analyzers = append(analyzers, nil)
+			continue
+		}
+		
+		cycle, err := tfcycle.ParseInput(text)
+		if err != nil {
+			return inputErrorf("failed to parse cycle error in %s: %w", name, err)
+		}
+		withInputVersions(cycle, versions)
+		cycle, err = filterCycle(cycle, config)
+		if err != nil {
+			return err
+		}
+		if config.Redact {
+			cycle = tfcycle.NewRedactor().RedactCycle(cycle)
+		}
+		
+		analyzer := tfcycle.NewCycleAnalyzer(cycle, ruleOpts...)
+		analyzeWithTimeout(ctx, analyzer, config)
+		input.Fingerprint = CycleFingerprint(analyzer.NodeNames())
+		input.Severity = analyzer.Severity().String()
+		inputs = append(inputs, input)
+		analyzers = append(analyzers, analyzer)
+	}
+	
+	report := NewAggregateReport(inputs, analyzers)
+	report.Metadata.Redacted = config.Redact
+	if config.Analyses {
+		if report.Analyses, err = aggregateAnalyses(report, inputs, analyzers); err != nil {
+			return err
+		}
+	}
+	switch outputFormat(config) {
+	case "json":
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		return writeReport(string(jsonData)+"\n", config)
+	case "markdown":
+		return writeReport(FormatAggregateMarkdown(report), config)
+	default:
+		return writeReport(FormatAggregateReport(report), config)
+	}
+}
+
+// NewAggregateReport deduplicates the cycles of inputs by fingerprint and
+// counts the modules, patterns and resource types involved. analyzers[i]
+// analyzes inputs[i], or is nil if the input has no cycle.
+func NewAggregateReport(inputs []AggregateInput, analyzers []*tfcycle.CycleAnalyzer) AggregateReport {
+	report := AggregateReport{
+		Metadata: AggregateMetadata{Tool: "tfcycle", Version: version, AnalysisSchemaVersion: tfcycle.AnalysisSchemaVersion},
+		Inputs:   inputs,
+		Cycles:   []AggregateCycle{},
+	}
+	
+	byFingerprint := make(map[string]int)
+	modules := newAggregateCounter()
+	patterns := newAggregateCounter()
+	resourceTypes := newAggregateCounter()
+	for i, analyzer := range analyzers {
+		if analyzer == nil {
+			continue
+		}
+		input := inputs[i]
+		
+		index, seen := byFingerprint[input.Fingerprint]
+		if !seen {
+			index = len(report.Cycles)
+			byFingerprint[input.Fingerprint] = index
+			report.Cycles = append(report.Cycles, AggregateCycle{
+				Fingerprint:   input.Fingerprint,
+				Severity:      input.Severity,
+				Nodes:         analyzer.NodeNames(),
+				MinimalCycles: analyzer.FindMinimalCycles(),
+				Patterns:      []string{},
+			})
+		}
+		cycle := &report.Cycles[index]
+		cycle.Workspaces = append(cycle.Workspaces, input.Name)
+		cycle.Count++
+		
+		for _, minimal := range analyzer.FindMinimalCycles() {
+			for _, rule := range analyzer.MatchedRules(minimal) {
+				patterns.add(rule, input)
+				if !seen && !slices.Contains(cycle.Patterns, rule) {
+					cycle.Patterns = append(cycle.Patterns, rule)
+				}
+			}
+			for _, name := range minimal {
+				node := analyzer.Cycle().GetNodeByName(name)
+				if node == nil {
+					continue
+				}
+				module := strings.Join(node.ModulePath, ".")
+				if module == "" {
+					module = rootModuleName
+				}
+				modules.add(module, input)
+				resourceTypes.add(node.ResourceType, input)
+			}
+		}
+	}
+	
+	sort.SliceStable(report.Cycles, func(i, j int) bool {
+		return report.Cycles[i].Count > report.Cycles[j].Count
+	})
+	report.Modules = modules.counts()
+	report.Patterns = patterns.counts()
+	report.ResourceTypes = resourceTypes.counts()
+	return report
+}
+
+// aggregateAnalyses returns the JSON analysis of each distinct cycle of
+// report, in the order of report.Cycles. Inputs with the same fingerprint
+// share the analysis of the first of them.
+func aggregateAnalyses(report AggregateReport, inputs []AggregateInput, analyzers []*tfcycle.CycleAnalyzer) ([]AggregateAnalysis, error) {
+	first := make(map[string]*tfcycle.CycleAnalyzer)
+	for i, analyzer := range analyzers {
+		if analyzer != nil && first[inputs[i].Fingerprint] == nil {
+			first[inputs[i].Fingerprint] = analyzer
+		}
+	}
+	
+	analyses := []AggregateAnalysis{}
+	for _, cycle := range report.Cycles {
+		analysis, err := tfcycle.NewOutputFormatter(first[cycle.Fingerprint], false).FormatAsJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		analyses = append(analyses, AggregateAnalysis{
+			Fingerprint: cycle.Fingerprint,
+			Count:       cycle.Count,
+			Workspaces:  cycle.Workspaces,
+			Analysis:    json.RawMessage(analysis),
+		})
+	}
+	return analyses, nil
+}
+
+type aggregateCounter struct {
+	cycles     map[string]map[string]bool
+	workspaces map[string]map[string]bool
+}
+
+func newAggregateCounter() *aggregateCounter {
+	return &aggregateCounter{
+		cycles:     make(map[string]map[string]bool),
+		workspaces: make(map[string]map[string]bool),
+	}
+}
+
+func (ac *aggregateCounter) add(name string, input AggregateInput) {
+	if ac.cycles[name] == nil {
+		ac.cycles[name] = make(map[string]bool)
+		ac.workspaces[name] = make(map[string]bool)
+	}
+	ac.cycles[name][input.Fingerprint] = true
+	ac.workspaces[name][input.Name] = true
+}
+
+func (ac *aggregateCounter) counts() []AggregateCount {
+	counts := make([]AggregateCount, 0, len(ac.cycles))
+	for name := range ac.cycles {
+		counts = append(counts, AggregateCount{Name: name, Cycles: len(ac.cycles[name]), Workspaces: len(ac.workspaces[name])})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Workspaces != counts[j].Workspaces {
+			return counts[i].Workspaces > counts[j].Workspaces
+		}
+		if counts[i].Cycles != counts[j].Cycles {
+			return counts[i].Cycles > counts[j].Cycles
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+func (report AggregateReport) affectedInputs() int {
+	affected := 0
+	for _, input := range report.Inputs {
+		if input.Fingerprint != "" {
+			affected++
+		}
+	}
+	return affected
+}
+
+func FormatAggregateReport(report AggregateReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("📊 CYCLES ACROSS %d WORKSPACES\n\n", len(report.Inputs)))
+	output.WriteString(fmt.Sprintf("%d of %d workspaces hit %d distinct cycle(s).\n", report.affectedInputs(), len(report.Inputs), len(report.Cycles)))
+	if len(report.Cycles) == 0 {
+		return output.String()
+	}
+	
+	output.WriteString("\nCYCLES\n")
+	for i, cycle := range report.Cycles {
+		output.WriteString(fmt.Sprintf("%d. %s (%s) in %s\n", i+1, cycle.Fingerprint, cycle.Severity, strings.Join(cycle.Workspaces, ", ")))
+		for _, minimal := range cycle.MinimalCycles {
+			output.WriteString(fmt.Sprintf("   %s → %s\n", strings.Join(minimal, " → "), minimal[0]))
+		}
+	}
+	
+	for _, section := range []struct {
+		title  string
+		counts []AggregateCount
+	}{
+		{"MODULES", report.Modules},
+		{"PATTERNS", report.Patterns},
+		{"RESOURCE TYPES", report.ResourceTypes},
+	} {
+		output.WriteString(fmt.Sprintf("\n%-40s  %10s  %6s\n", section.title, "WORKSPACES", "CYCLES"))
+		for _, count := range section.counts {
+			output.WriteString(fmt.Sprintf("%-40s  %10d  %6d\n", count.Name, count.Workspaces, count.Cycles))
+		}
+	}
+	return output.String()
+}
+
+func FormatAggregateMarkdown(report AggregateReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("# Cycles across %d workspaces\n\n", len(report.Inputs)))
+	output.WriteString(fmt.Sprintf("%d of %d workspaces hit %d distinct cycle(s).\n", report.affectedInputs(), len(report.Inputs), len(report.Cycles)))
+	if len(report.Cycles) == 0 {
+		return output.String()
+	}
+	
+	output.WriteString("\n## Cycles\n\n| Fingerprint | Severity | Workspaces | Minimal cycles |\n|---|---|---|---|\n")
+	for _, cycle := range report.Cycles {
+		paths := make([]string, len(cycle.MinimalCycles))
+		for i, minimal := range cycle.MinimalCycles {
+			paths[i] = "`" + strings.Join(append(slices.Clone(minimal), minimal[0]), " → ") + "`"
+		}
+		output.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", cycle.Fingerprint, cycle.Severity, strings.Join(cycle.Workspaces, ", "), strings.Join(paths, "<br>")))
+	}
+	
+	for _, section := range []struct {
+		title  string
+		counts []AggregateCount
+	}{
+		{"Modules", report.Modules},
+		{"Patterns", report.Patterns},
+		{"Resource types", report.ResourceTypes},
+	} {
+		output.WriteString(fmt.Sprintf("\n## %s\n\n| Name | Workspaces | Distinct cycles |\n|---|---|---|\n", section.title))
+		for _, count := range section.counts {
+			output.WriteString(fmt.Sprintf("| `%s` | %d | %d |\n", count.Name, count.Workspaces, count.Cycles))
+		}
+	}
+	return output.String()
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func TestParseNamedInput(t *testing.T) {
+	tests := map[string][2]string{
+		"prod=logs/prod.txt":  {"prod", "logs/prod.txt"},
+		"logs/prod.txt":       {"logs/prod.txt", "logs/prod.txt"},
+		"logs/a=b.txt":        {"logs/a=b.txt", "logs/a=b.txt"},
+		"=logs/prod.txt":      {"=logs/prod.txt", "=logs/prod.txt"},
+		"eu-west=./cycle.log": {"eu-west", "./cycle.log"},
+	}
+	for arg, want := range tests {
+		if name, file := parseNamedInput(arg); name != want[0] || file != want[1] {
+			t.Errorf("Expected %v for %s, got %s, %s", want, arg, name, file)
+		}
+	}
+}
+
+func TestAggregate_JSON(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"prod.log":    "Error: Cycle: module.net.aws_security_group.a, module.net.aws_security_group.b\n",
+		"staging.log": "Error: Cycle: module.net.aws_security_group.a, module.net.aws_security_group.b\n",
+		"dev.log":     "Error: Cycle: aws_iam_role.r, aws_iam_policy.p\n",
+		"qa.log":      "Plan: 1 to add, 0 to change, 0 to destroy.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	
+	output := filepath.Join(dir, "report.json")
+	args := []string{"aggregate", "--json", "--output", output}
+	for _, name := range []string{"dev", "prod", "staging", "qa"} {
+		args = append(args, name+"="+filepath.Join(dir, name+".log"))
+	}
+	config, err := parseArgs(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report AggregateReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	
+	if len(report.Inputs) != 4 || report.Inputs[3].Fingerprint != "" {
+		t.Errorf("Expected four inputs with qa clean, got %+v", report.Inputs)
+	}
+	if len(report.Cycles) != 2 || report.Cycles[0].Count != 2 || len(report.Cycles[0].Workspaces) != 2 || report.Cycles[0].Workspaces[0] != "prod" {
+		t.Fatalf("Expected the shared cycle first, got %+v", report.Cycles)
+	}
+	if len(report.Cycles[0].Patterns) != 1 || report.Cycles[0].Patterns[0] != "security-groups" {
+		t.Errorf("Expected the security group pattern, got %v", report.Cycles[0].Patterns)
+	}
+	if top := report.Modules[0]; top.Name != "module.net" || top.Workspaces != 2 || top.Cycles != 1 {
+		t.Errorf("Expected module.net in two workspaces, got %+v", top)
+	}
+}
+
+func TestAggregate_DuplicateName(t *testing.T) {
+	config, err := parseArgs([]string{"aggregate", "prod=a.log", "prod=b.log"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error, got: %v", err)
+	}
+}
+
+func TestAggregate_Analyses(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"prod.log":    "Error: Cycle: module.net.aws_security_group.a, module.net.aws_security_group.b\n",
+		"staging.log": "Error: Cycle: module.net.aws_security_group.a, module.net.aws_security_group.b\n",
+		"dev.log":     "Error: Cycle: aws_iam_role.r, aws_iam_policy.p\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	
+	output := filepath.Join(dir, "report.json")
+	args := []string{"aggregate", "--json", "--analyses", "--output", output}
+	for _, name := range []string{"prod", "staging", "dev"} {
+		args = append(args, name+"="+filepath.Join(dir, name+".log"))
+	}
+	config, err := parseArgs(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report AggregateReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if report.Metadata.Tool != "tfcycle" || report.Metadata.AnalysisSchemaVersion != tfcycle.AnalysisSchemaVersion {
+		t.Errorf("Expected shared metadata, got %+v", report.Metadata)
+	}
+	if len(report.Analyses) != 2 || len(report.Analyses[0].Workspaces) != 2 || report.Analyses[0].Fingerprint != report.Cycles[0].Fingerprint {
+		t.Fatalf("Expected an analysis per distinct cycle, got %+v", report.Analyses)
+	}
+	var analysis struct {
+		MinimalCycles [][]string `json:"minimal_cycles"`
+	}
+	if err := json.Unmarshal(report.Analyses[0].Analysis, &analysis); err != nil || len(analysis.MinimalCycles) != 1 {
+		t.Errorf("Expected the full analysis of the shared cycle, got %s", report.Analyses[0].Analysis)
+	}
+	
+	config.JSON = false
+	if err := runCommand(config); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error without --json, got: %v", err)
+	}
+}
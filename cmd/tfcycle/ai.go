@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const (
+	defaultAIEndpoint = "https://api.openai.com/v1"
+	defaultAIModel    = "gpt-4o-mini"
+)
+
+const aiSystemPrompt = "You are an experienced Terraform engineer helping a colleague resolve a dependency cycle. Be concrete and concise."
+
+const defaultAIPrompt = `Terraform reported a dependency cycle. Below is tfcycle's structured analysis of it as JSON,
+focused on {{.Address}}. The dependency edges are inferred by heuristics, not read from the
+Terraform graph, so treat them as hypotheses.
+
+{{.Analysis}}
+
+Reply in Markdown with two sections:
+
+## Root cause
+Explain in plain language which references most likely close the cycle and why {{.Address}} is involved.
+
+## Remediation plan
+A numbered list of concrete steps to break the cycle, with short HCL snippets where they help.
+`
+
+type AIAnalysis struct {
+	Address       string                    `json:"address"`
+	Severity      string                    `json:"severity"`
+	Partial       bool                      `json:"partial,omitempty"`
+	Nodes         []string                  `json:"nodes"`
+	MinimalCycles [][]string                `json:"minimal_cycles"`
+	Explanations  []tfcycle.NodeExplanation `json:"explanations"`
+}
+
+type AIPromptData struct {
+	Address  string
+	Analysis string
+}
+
+type AIExplainer struct {
+	Endpoint string
+	Model    string
+	APIKey   string
+	Prompt   *template.Template
+	client   *http.Client
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func NewAIExplainer(config Config) (*AIExplainer, error) {
+	explainer := &AIExplainer{
+		Endpoint: firstNonEmpty(config.AIEndpoint, defaultAIEndpoint),
+		Model:    firstNonEmpty(config.AIModel, defaultAIModel),
+		APIKey:   firstNonEmpty(config.AIAPIKey, os.Getenv("OPENAI_API_KEY")),
+		client:   &http.Client{Timeout: 2 * time.Minute},
+	}
+	
+	text := defaultAIPrompt
+	if config.AIPrompt != "" {
+		content, err := os.ReadFile(config.AIPrompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt template: %w", err)
+		}
+		text = string(content)
+	}
+	
+	prompt, err := template.New("prompt").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template: %w", err)
+	}
+	explainer.Prompt = prompt
+	return explainer, nil
+}
+
+func NewAIAnalysis(analyzer *tfcycle.CycleAnalyzer, address string, explanations []tfcycle.NodeExplanation) AIAnalysis {
+	return AIAnalysis{
+		Address:       address,
+		Severity:      analyzer.Severity().String(),
+		Partial:       analyzer.Partial,
+		Nodes:         analyzer.NodeNames(),
+		MinimalCycles: analyzer.FindMinimalCycles(),
+		Explanations:  explanations,
+	}
+}
+
+func (ae *AIExplainer) RenderPrompt(analysis AIAnalysis) (string, error) {
+	jsonData, err := json.MarshalIndent(analysis, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	
+	var prompt strings.Builder
+	data := AIPromptData{Address: analysis.Address, Analysis: string(jsonData)}
+	if err := ae.Prompt.Execute(&prompt, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+	return prompt.String(), nil
+}
+
+func (ae *AIExplainer) Explain(ctx context.Context, analysis AIAnalysis) (string, error) {
+	prompt, err := ae.RenderPrompt(analysis)
+	if err != nil {
+		return "", err
+	}
+	
+	body, err := json.Marshal(chatRequest{
+		Model: ae.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: aiSystemPrompt},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return "", err
+	}
+	
+	endpoint := strings.TrimRight(ae.Endpoint, "/") + "/chat/completions"
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if ae.APIKey != "" {
+		request.Header.Set("Authorization", "Bearer "+ae.APIKey)
+	}
+	
+	logger.Debug("requesting AI explanation", "endpoint", endpoint, "model", ae.Model)
+	response, err := ae.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("AI request failed: %w", err)
+	}
+	defer response.Body.Close()
+	
+	responseBody, err := io.ReadAll(io.LimitReader(response.Body, maxRequestBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read AI response: %w", err)
+	}
+	
+	var completion chatResponse
+	if err := json.Unmarshal(responseBody, &completion); err != nil {
+		return "", fmt.Errorf("AI endpoint returned %s: %s", response.Status, strings.TrimSpace(string(responseBody)))
+	}
+	if completion.Error != nil {
+		return "", fmt.Errorf("AI endpoint returned %s: %s", response.Status, completion.Error.Message)
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("AI endpoint returned %s", response.Status)
+	}
+	if len(completion.Choices) == 0 || strings.TrimSpace(completion.Choices[0].Message.Content) == "" {
+		return "", fmt.Errorf("AI endpoint returned no explanation")
+	}
+	
+	return strings.TrimSpace(completion.Choices[0].Message.Content), nil
+}
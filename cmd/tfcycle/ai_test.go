@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const aiTestError = `Error: Cycle: aws_security_group.sg_ping, aws_security_group.sg_8080, aws_instance.web (destroy)`
+
+func testAIAnalysis(t *testing.T) AIAnalysis {
+	cycle, err := tfcycle.NewParser().ParseError(aiTestError)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := tfcycle.NewCycleAnalyzer(cycle)
+	nodes := analyzer.MatchNodes("sg_8080")
+	return NewAIAnalysis(analyzer, "aws_security_group.sg_8080", []tfcycle.NodeExplanation{analyzer.ExplainNode(nodes[0])})
+}
+
+func TestAIExplainer_Explain(t *testing.T) {
+	var received chatRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Expected /v1/chat/completions, got %s", r.URL.Path)
+		}
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "## Root cause\nThe groups reference each other.\n"}}]}`))
+	}))
+	defer server.Close()
+	
+	explainer, err := NewAIExplainer(Config{AIEndpoint: server.URL + "/v1/", AIModel: "llama3.1", AIAPIKey: "secret"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	result, err := explainer.Explain(context.Background(), testAIAnalysis(t))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	if result != "## Root cause\nThe groups reference each other." {
+		t.Errorf("Expected trimmed model reply, got %q", result)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Expected bearer API key, got %q", auth)
+	}
+	if received.Model != "llama3.1" || len(received.Messages) != 2 {
+		t.Fatalf("Expected model llama3.1 with system and user messages, got %+v", received)
+	}
+	prompt := received.Messages[1].Content
+	if !strings.Contains(prompt, `"minimal_cycles"`) || !strings.Contains(prompt, "aws_security_group.sg_8080") {
+		t.Errorf("Expected structured analysis in prompt, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "Error: Cycle:") {
+		t.Errorf("Expected raw error text to stay out of the prompt, got:\n%s", prompt)
+	}
+}
+
+func TestAIExplainer_PromptTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.tmpl")
+	os.WriteFile(path, []byte("Why is {{.Address}} in a cycle?"), 0644)
+	
+	explainer, err := NewAIExplainer(Config{AIPrompt: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	prompt, err := explainer.RenderPrompt(testAIAnalysis(t))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if prompt != "Why is aws_security_group.sg_8080 in a cycle?" {
+		t.Errorf("Expected custom prompt, got %q", prompt)
+	}
+	
+	os.WriteFile(path, []byte("{{.Missing"), 0644)
+	if _, err := NewAIExplainer(Config{AIPrompt: path}); err == nil {
+		t.Errorf("Expected error for an invalid template")
+	}
+}
+
+func TestAIExplainer_EndpointError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": {"message": "Incorrect API key provided"}}`))
+	}))
+	defer server.Close()
+	
+	explainer, _ := NewAIExplainer(Config{AIEndpoint: server.URL})
+	_, err := explainer.Explain(context.Background(), testAIAnalysis(t))
+	if err == nil || !strings.Contains(err.Error(), "Incorrect API key provided") {
+		t.Errorf("Expected endpoint error message, got %v", err)
+	}
+}
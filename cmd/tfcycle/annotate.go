@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const annotationRule = "────────────────────────────────────────────────────────────"
+
+func runAnnotate(ctx context.Context, config Config) error {
+	var log bytes.Buffer
+	errorText, versions, err := readInputEcho(ctx, config.ErrorFile, &log, config, 0)
+	if err != nil {
+		return inputErrorf("failed to read input: %w", err)
+	}
+	if errorText == "" {
+		logger.Debug("no cycle error in input, echoing it unchanged")
+		return writeOutput(log.String(), config.Output)
+	}
+	
+	cycle, err := tfcycle.ParseInput(errorText)
+	if err != nil {
+		return inputErrorf("failed to parse cycle error: %w", err)
+	}
+	withInputVersions(cycle, versions)
+	
+	var index *tfcycle.ConfigIndex
+	if config.ConfigDir != "" {
+		index, err = scanConfig(config.ConfigDir, config)
+		if err != nil {
+			return inputError(err)
+		}
+		index.ResolveLocations(cycle)
+	}
+	if err := resolveConstructPaths(cycle, config); err != nil {
+		return err
+	}
+	
+	ruleOpts, err := suggestionRulesOptions(config)
+	if err != nil {
+		return err
+	}
+	opts := append(analyzerOptions(config, index), feedbackOptions(config)...)
+	opts = append(opts, versionOptions(config)...)
+	analyzer := tfcycle.NewCycleAnalyzer(cycle, append(opts, ruleOpts...)...)
+	analyzeWithTimeout(ctx, analyzer, config)
+	formatter := tfcycle.NewOutputFormatter(analyzer, config.Verbose)
+	setDisplayLimits(formatter, config)
+	if err := setTemplates(formatter, config); err != nil {
+		return err
+	}
+	lang, err := tfcycle.ResolveLanguage(config.Lang)
+	if err != nil {
+		return inputError(err)
+	}
+	if err := formatter.SetLanguage(lang); err != nil {
+		return err
+	}
+	
+	return writeOutput(AnnotateLog(log.String(), cycle, formatter.FormatAnalysis()), config.Output)
+}
+
+// AnnotateLog returns log with the members of its cycle diagnostic numbered
+// in the order Terraform listed them, and analysis inserted right after the
+// diagnostic. A boxed diagnostic ends with its closing "╵" line. JSON input
+// is returned with the analysis appended.
+func AnnotateLog(log string, cycle *tfcycle.TfCycle, analysis string) string {
+	var output strings.Builder
+	var section tfcycle.CycleSection
+	var held strings.Builder
+	
+	const (
+		scanning = iota
+		inDiagnostic
+		inBox
+		annotated
+	)
+	state := scanning
+	insert := func() {
+		if output.Len() > 0 && !strings.HasSuffix(output.String(), "\n") {
+			output.WriteString("\n")
+		}
+		output.WriteString(fmt.Sprintf("── tfcycle %s\n", annotationRule))
+		output.WriteString(strings.TrimRight(analysis, "\n") + "\n")
+		output.WriteString(fmt.Sprintf("───────────%s\n", annotationRule))
+		state = annotated
+	}
+	
+	for _, line := range strings.SplitAfter(log, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch state {
+		case scanning:
+			section.AddLine(line)
+			switch {
+			case section.JSON():
+				output.WriteString(log)
+				insert()
+				return output.String()
+			case section.Found():
+				held.WriteString(line)
+				state = inDiagnostic
+			default:
+				output.WriteString(line)
+			}
+		case inDiagnostic:
+			section.AddLine(line)
+			if !section.Done() {
+				held.WriteString(line)
+				continue
+			}
+			output.WriteString(numberMembers(held.String(), cycle))
+			output.WriteString(line)
+			if strings.HasPrefix(trimmed, "│") {
+				state = inBox
+				continue
+			}
+			insert()
+		case inBox:
+			switch {
+			case strings.HasPrefix(trimmed, "│"):
+				output.WriteString(line)
+			case strings.HasPrefix(trimmed, "╵"):
+				output.WriteString(line)
+				insert()
+			default:
+				insert()
+				output.WriteString(line)
+			}
+		default:
+			output.WriteString(line)
+		}
+	}
+	
+	switch state {
+	case inDiagnostic:
+		output.WriteString(numberMembers(held.String(), cycle))
+		insert()
+	case inBox:
+		insert()
+	}
+	return output.String()
+}
+
+// numberMembers prefixes the first occurrence of each node of cycle in text
+// with its position, e.g. "[2] aws_iam_role.app". Nodes that cannot be
+// found are left alone.
+func numberMembers(text string, cycle *tfcycle.TfCycle) string {
+	var output strings.Builder
+	rest := text
+	for i, node := range cycle.Nodes {
+		index := strings.Index(rest, node.RawString)
+		if node.RawString == "" || index < 0 {
+			continue
+		}
+		output.WriteString(rest[:index])
+		output.WriteString(fmt.Sprintf("[%d] %s", i+1, node.RawString))
+		rest = rest[index+len(node.RawString):]
+	}
+	output.WriteString(rest)
+	return output.String()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func TestAnnotateLog(t *testing.T) {
+	tests := map[string]struct {
+		log  string
+		want string
+	}{
+		"boxed": {
+			log:  "Planning...\n╷\n│ Error: Cycle: aws_instance.a, aws_instance.b (destroy)\n│ \n╵\nReleasing state lock.\n",
+			want: "Planning...\n╷\n│ Error: Cycle: [1] aws_instance.a, [2] aws_instance.b (destroy)\n│ \n╵\nANALYSIS\nReleasing state lock.\n",
+		},
+		"plain": {
+			log:  "Error: Cycle: aws_instance.a, aws_instance.b (destroy)\n\nDone.\n",
+			want: "Error: Cycle: [1] aws_instance.a, [2] aws_instance.b (destroy)\n\nANALYSIS\nDone.\n",
+		},
+		"end of input": {
+			log:  "Error: Cycle: aws_instance.a, aws_instance.b (destroy)",
+			want: "Error: Cycle: [1] aws_instance.a, [2] aws_instance.b (destroy)\nANALYSIS\n",
+		},
+	}
+	
+	for name, tt := range tests {
+		cycle, err := tfcycle.Parse(context.Background(), strings.NewReader(tt.log))
+		if err != nil {
+			t.Fatalf("%s: expected no error, got: %v", name, err)
+		}
+		got := AnnotateLog(tt.log, cycle, "ANALYSIS\n")
+		got = strings.ReplaceAll(got, "── tfcycle "+annotationRule+"\n", "")
+		got = strings.ReplaceAll(got, "───────────"+annotationRule+"\n", "")
+		if got != tt.want {
+			t.Errorf("%s: expected\n%s\ngot\n%s", name, tt.want, got)
+		}
+	}
+}
+
+func TestAnnotate_Command(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "plan.log")
+	output := filepath.Join(dir, "annotated.log")
+	log := "Refreshing state...\n╷\n│ Error: Cycle: aws_security_group.web, aws_security_group.db\n│ \n╵\n"
+	if err := os.WriteFile(input, []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	config, err := parseArgs([]string{"annotate", "--error-file", input, "--output", output})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if !strings.HasPrefix(text, "Refreshing state...\n╷\n│ Error: Cycle: [1] aws_security_group.web, [2] aws_security_group.db\n│ \n╵\n── tfcycle") {
+		t.Errorf("Expected the numbered diagnostic followed by the analysis, got:\n%s", text)
+	}
+	if !strings.Contains(text, "TERRAFORM CYCLE DETECTED") {
+		t.Errorf("Expected the analysis, got:\n%s", text)
+	}
+	
+	clean := "Plan: 1 to add, 0 to change, 0 to destroy.\n"
+	if err := os.WriteFile(input, []byte(clean), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if data, _ := os.ReadFile(output); string(data) != clean {
+		t.Errorf("Expected input without a cycle unchanged, got:\n%s", data)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+type artifact struct {
+	extension string
+	content   string
+}
+
+// writeArtifacts writes every report format to dir as basename.report.* and
+// basename.graph.*. The suffixes keep the artifacts apart from the input,
+// which shares basename; a path that is the input is still refused.
+func writeArtifacts(formatter *tfcycle.OutputFormatter, dir, basename, input string, visualOpts tfcycle.VisualOptions, compress bool) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory %s: %w", dir, err)
+	}
+	
+	jsonOutput, err := formatter.FormatAsJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to format as JSON: %w", err)
+	}
+	
+	artifacts := []artifact{
+		{extension: ".report.txt", content: formatter.FormatAnalysis()},
+		{extension: ".report.json", content: jsonOutput},
+		{extension: ".report.md", content: formatter.FormatAsMarkdown()},
+	}
+	
+	dotOutput := formatter.GenerateVisualization(visualOpts)
+	if dotOutput != "" {
+		artifacts = append(artifacts, artifact{extension: ".graph.dot", content: dotOutput})
+	}
+	
+	paths := make([]string, len(artifacts))
+	for i, a := range artifacts {
+		paths[i] = filepath.Join(dir, basename+a.extension)
+		if compress {
+			paths[i] += ".gz"
+		}
+	}
+	svgPath := filepath.Join(dir, basename+".graph.svg")
+	for _, path := range append(paths, svgPath) {
+		if isInputFile(path, input) {
+			return nil, inputErrorf("refusing to overwrite the input %s with a report; use another --output-dir", input)
+		}
+	}
+	
+	var written []string
+	for i, a := range artifacts {
+		path := paths[i]
+		if err := writeOutput(a.content, path); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	
+	if dotOutput != "" {
+		if err := renderSVG(dotOutput, svgPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping SVG output: %v\n", err)
+		} else {
+			written = append(written, svgPath)
+		}
+	}
+	
+	return written, nil
+}
+
+func renderSVG(dotOutput, path string) error {
+	dotPath, err := exec.LookPath("dot")
+	if err != nil {
+		return fmt.Errorf("graphviz 'dot' not found on PATH")
+	}
+	
+	var stderr bytes.Buffer
+	cmd := exec.Command(dotPath, "-Tsvg", "-o", path)
+	cmd.Stdin = strings.NewReader(dotOutput)
+	cmd.Stderr = &stderr
+	
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("dot failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	
+	return nil
+}
+
+// isInputFile reports whether path is the file input was read from.
+func isInputFile(path, input string) bool {
+	if input == "" || input == "-" {
+		return false
+	}
+	inputInfo, err := os.Stat(input)
+	if err != nil {
+		return false
+	}
+	pathInfo, err := os.Stat(path)
+	return err == nil && os.SameFile(inputInfo, pathInfo)
+}
+
+func artifactBasename(config Config) string {
+	if config.ErrorFile == "" {
+		return "tfcycle"
+	}
+	
+	base := filepath.Base(config.ErrorFile)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+func writeScript(content, filename string) error {
+	if err := os.WriteFile(filename, []byte(content), 0o755); err != nil {
+		return fmt.Errorf("failed to write script %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func TestWriteArtifacts_InputDir(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "err.txt")
+	cycleError := "Error: Cycle: aws_security_group.a, aws_security_group.b\n"
+	if err := os.WriteFile(input, []byte(cycleError), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	cycle, err := tfcycle.NewParser().ParseError(cycleError)
+	if err != nil {
+		t.Fatal(err)
+	}
+	formatter := tfcycle.NewOutputFormatter(tfcycle.NewCycleAnalyzer(cycle), false)
+	if _, err := writeArtifacts(formatter, dir, "err", input, tfcycle.DefaultVisualOptions(), false); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if data, _ := os.ReadFile(input); string(data) != cycleError {
+		t.Errorf("Expected the input to be left alone, got:\n%s", data)
+	}
+	for _, name := range []string{"err.report.txt", "err.report.json", "err.report.md", "err.graph.dot"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be written, got: %v", name, err)
+		}
+	}
+}
+
+func TestWriteArtifacts_RefusesInput(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "err.report.json")
+	if err := os.WriteFile(input, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	cycle, err := tfcycle.NewParser().ParseError("Error: Cycle: aws_security_group.a, aws_security_group.b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	formatter := tfcycle.NewOutputFormatter(tfcycle.NewCycleAnalyzer(cycle), false)
+	_, err = writeArtifacts(formatter, dir, "err", input, tfcycle.DefaultVisualOptions(), false)
+	if exitCode(err) != ExitInputError || !strings.Contains(err.Error(), "refusing to overwrite") {
+		t.Errorf("Expected an input error for a report that would replace the input, got: %v", err)
+	}
+	if data, _ := os.ReadFile(input); string(data) != `{}` {
+		t.Errorf("Expected the input to be left alone, got:\n%s", data)
+	}
+}
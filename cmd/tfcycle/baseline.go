@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+// BaselineSchemaVersion is the version of the baseline file format.
+const BaselineSchemaVersion = 1
+
+// Baseline records accepted cycles by their fingerprint (see
+// CycleFingerprint), so --baseline reports only the cycles that are new.
+type Baseline struct {
+	SchemaVersion int             `json:"schema_version"`
+	Created       time.Time       `json:"created"`
+	Cycles        []BaselineCycle `json:"cycles"`
+}
+
+// BaselineCycle is one accepted cycle. Cycle is informational; only the
+// fingerprint is compared.
+type BaselineCycle struct {
+	Fingerprint string   `json:"fingerprint"`
+	Cycle       []string `json:"cycle"`
+}
+
+// NewBaseline returns a baseline accepting cycles, skipping duplicates.
+func NewBaseline(cycles [][]string, now time.Time) Baseline {
+	baseline := Baseline{SchemaVersion: BaselineSchemaVersion, Created: now.UTC(), Cycles: []BaselineCycle{}}
+	for _, cycle := range cycles {
+		if !baseline.Contains(cycle) {
+			baseline.Cycles = append(baseline.Cycles, BaselineCycle{Fingerprint: CycleFingerprint(cycle), Cycle: cycle})
+		}
+	}
+	return baseline
+}
+
+// Contains reports whether the baseline accepts cycle.
+func (b Baseline) Contains(cycle []string) bool {
+	fingerprint := CycleFingerprint(cycle)
+	for _, accepted := range b.Cycles {
+		if accepted.Fingerprint == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+func loadBaseline(path string) (Baseline, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Baseline{}, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(content, &baseline); err != nil {
+		return Baseline{}, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if baseline.SchemaVersion > BaselineSchemaVersion {
+		return Baseline{}, fmt.Errorf("baseline %s has schema version %d; this tfcycle reads up to %d", path, baseline.SchemaVersion, BaselineSchemaVersion)
+	}
+	return baseline, nil
+}
+
+// applyBaseline removes the cycles that --baseline accepts from analyzer and
+// returns how many it removed.
+func applyBaseline(analyzer *tfcycle.CycleAnalyzer, config Config) (int, error) {
+	if config.Baseline == "" {
+		return 0, nil
+	}
+	baseline, err := loadBaseline(config.Baseline)
+	if err != nil {
+		return 0, inputError(err)
+	}
+	return analyzer.RemoveCycles(baseline.Contains), nil
+}
+
+// baselineLint removes the findings and near cycles that --baseline accepts
+// from report and returns how many it removed.
+func baselineLint(report *tfcycle.LintReport, config Config) (int, error) {
+	if config.Baseline == "" {
+		return 0, nil
+	}
+	baseline, err := loadBaseline(config.Baseline)
+	if err != nil {
+		return 0, inputError(err)
+	}
+	
+	removed := 0
+	keep := func(findings []tfcycle.LintFinding) []tfcycle.LintFinding {
+		kept := []tfcycle.LintFinding{}
+		for _, finding := range findings {
+			if baseline.Contains(lintBaselineCycle(finding)) {
+				removed++
+				continue
+			}
+			kept = append(kept, finding)
+		}
+		return kept
+	}
+	report.Findings = keep(report.Findings)
+	report.NearCycles = keep(report.NearCycles)
+	return removed, nil
+}
+
+// lintBaselineCycle returns the cycle of finding as a baseline records it. A
+// near cycle includes its standalone resource, so that it does not accept
+// the real cycle that inlining the resource would create.
+func lintBaselineCycle(finding tfcycle.LintFinding) []string {
+	if finding.Via == "" {
+		return finding.Cycle
+	}
+	return append(append([]string{}, finding.Cycle...), finding.Via)
+}
+
+// runBaseline writes a baseline of the current cycles: the minimal cycles of
+// the cycle error in INPUT or --error-file if given ("-" is stdin), analyzed
+// as analyze does, otherwise the lint findings of --config-dir.
+func runBaseline(ctx context.Context, config Config) error {
+	if len(config.Args) == 0 || config.Args[0] != "write" {
+		return inputErrorf("baseline requires the subcommand write FILE")
+	}
+	if len(config.Args) < 2 || len(config.Args) > 3 {
+		return inputErrorf("baseline write requires a FILE and reads at most one INPUT")
+	}
+	path, input := config.Args[1], config.ErrorFile
+	if len(config.Args) == 3 {
+		if input != "" {
+			return inputErrorf("input file %s given with --error-file %s", config.Args[2], input)
+		}
+		input = config.Args[2]
+	}
+	
+	var cycles [][]string
+	if input != "" {
+		errorText, versions, err := readInput(ctx, input, config)
+		if err != nil {
+			return inputErrorf("failed to read input: %w", err)
+		}
+		cycle, err := tfcycle.NewParser().ParseFormat(errorText, config.InputFormat)
+		if err != nil {
+			return inputErrorf("failed to parse cycle error: %w", err)
+		}
+		withInputVersions(cycle, versions)
+		if cycle, err = filterCycle(cycle, config); err != nil {
+			return err
+		}
+		var index *tfcycle.ConfigIndex
+		if config.ConfigDir != "" {
+			if index, err = scanConfig(config.ConfigDir, config); err != nil {
+				return inputError(err)
+			}
+		}
+		ruleOpts, err := suggestionRulesOptions(config)
+		if err != nil {
+			return err
+		}
+		// The same options as analyze, so that it finds the same cycles.
+		opts := append(analyzerOptions(config, index), feedbackOptions(config)...)
+		analyzer := tfcycle.NewCycleAnalyzer(cycle, append(opts, ruleOpts...)...)
+		analyzeWithTimeout(ctx, analyzer, config)
+		cycles = analyzer.FindMinimalCycles()
+	} else {
+		configDir := config.ConfigDir
+		if configDir == "" {
+			configDir = "."
+		}
+		report, err := lintDir(configDir, config)
+		if err != nil {
+			return err
+		}
+		for _, finding := range append(report.Findings, report.NearCycles...) {
+			cycles = append(cycles, lintBaselineCycle(finding))
+		}
+	}
+	
+	baseline := NewBaseline(cycles, time.Now())
+	jsonData, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(jsonData, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	if !config.Quiet {
+		fmt.Fprintf(os.Stderr, "Wrote %s with %d cycles\n", path, len(baseline.Cycles))
+	}
+	return nil
+}
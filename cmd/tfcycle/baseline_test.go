@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewBaseline(t *testing.T) {
+	baseline := NewBaseline([][]string{
+		{"aws_security_group.a", "aws_security_group.b"},
+		{"aws_security_group.b", "aws_security_group.a"},
+	}, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if len(baseline.Cycles) != 1 || baseline.SchemaVersion != BaselineSchemaVersion {
+		t.Fatalf("Expected one deduplicated cycle, got %+v", baseline)
+	}
+	if !baseline.Contains([]string{"aws_security_group.b", "aws_security_group.a"}) {
+		t.Error("Expected the baseline to accept the cycle in any order")
+	}
+	if baseline.Contains([]string{"aws_security_group.a", "aws_instance.web"}) {
+		t.Error("Expected the baseline not to accept another cycle")
+	}
+}
+
+func TestBaseline_Lint(t *testing.T) {
+	dir := t.TempDir()
+	main := `resource "aws_security_group" "a" {
+  ingress {
+    security_groups = [aws_security_group.b.id]
+  }
+}
+
+resource "aws_security_group" "b" {
+  ingress {
+    security_groups = [aws_security_group.a.id]
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(main), 0644); err != nil {
+		t.Fatal(err)
+	}
+	baselinePath := filepath.Join(dir, "baseline.json")
+	
+	config, err := parseArgs([]string{"baseline", "write", baselinePath, "--config-dir", dir, "--quiet"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	data, err := os.ReadFile(baselinePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil || len(baseline.Cycles) != 1 {
+		t.Fatalf("Expected one accepted cycle, got %s (%v)", data, err)
+	}
+	
+	output := filepath.Join(dir, "lint.json")
+	config, err = parseArgs([]string{"lint", "--config-dir", dir, "--json", "--output", output, "--baseline", baselinePath})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no new findings to pass, got: %v", err)
+	}
+	
+	main += `
+resource "aws_iam_role" "app" {
+  name = aws_iam_policy.app.name
+}
+
+resource "aws_iam_policy" "app" {
+  name = aws_iam_role.app.name
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(main), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runCommand(config); exitCode(err) != ExitCycleFound {
+		t.Fatalf("Expected the new cycle to fail, got: %v", err)
+	}
+	data, err = os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "aws_security_group.a") || !strings.Contains(string(data), "aws_iam_role.app") {
+		t.Errorf("Expected only the new IAM cycle, got:\n%s", data)
+	}
+}
+
+func TestBaseline_Analyze(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "cycle.txt")
+	if err := os.WriteFile(input, []byte("Error: Cycle: aws_security_group.a, aws_security_group.b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	baselinePath := filepath.Join(dir, "baseline.json")
+	
+	config, err := parseArgs([]string{"baseline", "write", baselinePath, input, "--quiet"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	output := filepath.Join(dir, "report.txt")
+	config, err = parseArgs([]string{"analyze", input, "--output", output, "--no-history", "--quiet", "--baseline", baselinePath})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected accepted cycles to pass, got: %v", err)
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("Expected no report without new cycles, got: %v", err)
+	}
+	
+	config.Baseline = filepath.Join(dir, "missing.json")
+	if err := runCommand(config); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error for a missing baseline, got: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+// benchResourceTypes are mixed so that every built-in heuristic has edges to
+// find, not only the shared-module fallback.
+var benchResourceTypes = []string{"aws_security_group", "aws_instance", "aws_iam_role", "aws_iam_policy", "aws_lb"}
+
+// benchModuleSize is the number of nodes per module in a synthetic cycle.
+const benchModuleSize = 10
+
+type BenchResult struct {
+	Nodes   int           `json:"nodes"`
+	Edges   int           `json:"edges"`
+	Cycles  int           `json:"cycles"`
+	Parse   time.Duration `json:"parse_ns"`
+	Analyze time.Duration `json:"analyze_ns"`
+	Format  time.Duration `json:"format_ns"`
+}
+
+// SyntheticCycleError returns a Terraform cycle error listing nodes
+// resources spread over modules of benchModuleSize.
+func SyntheticCycleError(nodes int) string {
+	members := make([]string, 0, nodes)
+	for i := 0; i < nodes; i++ {
+		resourceType := benchResourceTypes[i%len(benchResourceTypes)]
+		members = append(members, fmt.Sprintf("module.m%d.%s.r%d", i/benchModuleSize, resourceType, i))
+	}
+	return "Error: Cycle: " + strings.Join(members, ", ")
+}
+
+// RunBench times parsing, analyzing and formatting a synthetic cycle of
+// nodes resources.
+func RunBench(ctx context.Context, nodes int) (BenchResult, error) {
+	errorText := SyntheticCycleError(nodes)
+	result := BenchResult{Nodes: nodes}
+	
+	start := time.Now()
+	cycle, err := tfcycle.NewParser().ParseError(errorText)
+	if err != nil {
+		return result, fmt.Errorf("failed to parse synthetic cycle: %w", err)
+	}
+	result.Parse = time.Since(start)
+	
+	start = time.Now()
+	analyzer := tfcycle.NewCycleAnalyzer(cycle)
+	if err := analyzer.AnalyzeContext(ctx); err != nil {
+		return result, fmt.Errorf("analysis did not finish: %w", err)
+	}
+	result.Analyze = time.Since(start)
+	result.Cycles = len(cycle.Cycles)
+	for _, targets := range analyzer.HypothesizedGraph() {
+		result.Edges += len(targets)
+	}
+	
+	start = time.Now()
+	formatter := tfcycle.NewOutputFormatter(analyzer, true)
+	formatter.FormatAnalysis()
+	if _, err := formatter.FormatAsJSON(); err != nil {
+		return result, err
+	}
+	result.Format = time.Since(start)
+	
+	return result, nil
+}
+
+func FormatBenchResult(result BenchResult) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("synthetic cycle: %d nodes, %d hypothesized edges, %d minimal cycles\n", result.Nodes, result.Edges, result.Cycles))
+	output.WriteString(fmt.Sprintf("  parse:   %v\n", result.Parse))
+	output.WriteString(fmt.Sprintf("  analyze: %v\n", result.Analyze))
+	output.WriteString(fmt.Sprintf("  format:  %v\n", result.Format))
+	return output.String()
+}
+
+func runBench(ctx context.Context, config Config) error {
+	if config.BenchNodes < 2 {
+		return inputErrorf("--nodes must be at least 2")
+	}
+	
+	result, err := RunBench(ctx, config.BenchNodes)
+	if err != nil {
+		return err
+	}
+	if outputFormat(config) != "json" {
+		return writeOutput(FormatBenchResult(result), config.Output)
+	}
+	
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format as JSON: %w", err)
+	}
+	return writeOutput(string(jsonData)+"\n", config.Output)
+}
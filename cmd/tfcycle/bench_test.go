@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func TestSyntheticCycleError(t *testing.T) {
+	cycle, err := tfcycle.NewParser().ParseError(SyntheticCycleError(25))
+	if err != nil {
+		t.Fatalf("Expected the synthetic cycle to parse, got: %v", err)
+	}
+	if len(cycle.Nodes) != 25 {
+		t.Errorf("Expected 25 nodes, got %d", len(cycle.Nodes))
+	}
+	if got := cycle.Nodes[12].FullName(); got != "module.m1.aws_iam_role.r12" {
+		t.Errorf("Expected nodes spread over modules, got %s", got)
+	}
+}
+
+func TestRunBench(t *testing.T) {
+	result, err := RunBench(context.Background(), 20)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Nodes != 20 || result.Edges == 0 || result.Cycles == 0 {
+		t.Errorf("Expected counts for the synthetic cycle, got %+v", result)
+	}
+}
+
+func TestRunBench_TooFewNodes(t *testing.T) {
+	err := runBench(context.Background(), Config{BenchNodes: 1})
+	if exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error, got: %v", err)
+	}
+}
+
+func TestStartProfiling(t *testing.T) {
+	dir := t.TempDir()
+	config := Config{CPUProfile: filepath.Join(dir, "cpu.out"), MemProfile: filepath.Join(dir, "mem.out")}
+	stop, err := startProfiling(config)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	stop()
+	
+	for _, path := range []string{config.CPUProfile, config.MemProfile} {
+		info, err := os.Stat(path)
+		if err != nil || info.Size() == 0 {
+			t.Errorf("Expected a profile at %s, got: %v", path, err)
+		}
+	}
+}
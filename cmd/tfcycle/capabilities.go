@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const capabilitiesSchemaVersion = 1
+
+// Capabilities lists what this build supports so that wrappers and editor
+// plugins can feature-detect. SchemaVersion changes on incompatible changes.
+type Capabilities struct {
+	SchemaVersion  int                   `json:"schema_version"`
+	Version        string                `json:"version"`
+	Commands       []string              `json:"commands"`
+	InputFormats   []string              `json:"input_formats"`
+	OutputFormats  []string              `json:"output_formats"`
+	Heuristics     []HeuristicCapability `json:"heuristics"`
+	FixRules       []string              `json:"fix_rules"`
+	Languages      []string              `json:"languages"`
+	SchemaVersions map[string]string     `json:"schema_versions"`
+	Plugins        []Plugin              `json:"plugins"`
+}
+
+type HeuristicCapability struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+}
+
+func NewCapabilities() Capabilities {
+	caps := Capabilities{
+		SchemaVersion: capabilitiesSchemaVersion,
+		Version:       version,
+		InputFormats:  []string{"terraform", "terraform-stacks", "terragrunt", "terraform-validate-json", "terraform-json-log", "terraform-graph", "terraform-plan-json", "terraform-state", "tfcycle-json"},
+		OutputFormats: append(append([]string{}, flagValues["format"]...), "dot", "svg"),
+		FixRules:      tfcycle.FixKinds(),
+		Languages:     tfcycle.SupportedLanguages(),
+		SchemaVersions: map[string]string{
+			"analysis":     fmt.Sprint(tfcycle.AnalysisSchemaVersion),
+			"capabilities": fmt.Sprint(capabilitiesSchemaVersion),
+			"opa":          fmt.Sprint(tfcycle.OPASchemaVersion),
+			"mcp":          mcpProtocolVersion,
+			"grpc":         "tfcycle.v1",
+			"plugins":      fmt.Sprint(pluginProtocolVersion),
+		},
+		Plugins: discoverPlugins(os.Getenv("PATH")),
+	}
+	if caps.Plugins == nil {
+		caps.Plugins = []Plugin{}
+	}
+	for _, command := range commands {
+		caps.Commands = append(caps.Commands, command.Name)
+	}
+	for _, h := range tfcycle.DefaultHeuristics() {
+		caps.Heuristics = append(caps.Heuristics, HeuristicCapability{Name: h.Name, Confidence: h.Confidence})
+	}
+	return caps
+}
+
+func FormatCapabilities(caps Capabilities) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("tfcycle %s capabilities\n\n", caps.Version))
+	output.WriteString(fmt.Sprintf("commands:        %s\n", strings.Join(caps.Commands, ", ")))
+	output.WriteString(fmt.Sprintf("input formats:   %s\n", strings.Join(caps.InputFormats, ", ")))
+	output.WriteString(fmt.Sprintf("output formats:  %s\n", strings.Join(caps.OutputFormats, ", ")))
+	output.WriteString(fmt.Sprintf("fix rules:       %s\n", strings.Join(caps.FixRules, ", ")))
+	output.WriteString(fmt.Sprintf("languages:       %s\n", strings.Join(caps.Languages, ", ")))
+	output.WriteString("heuristics:\n")
+	for _, h := range caps.Heuristics {
+		output.WriteString(fmt.Sprintf("  %-24s confidence %.1f\n", h.Name, h.Confidence))
+	}
+	if len(caps.Plugins) > 0 {
+		output.WriteString("plugins:\n")
+		for _, plugin := range caps.Plugins {
+			output.WriteString(fmt.Sprintf("  %-24s %s\n", plugin.Kind+" "+plugin.Name, plugin.Path))
+		}
+	}
+	output.WriteString("schema versions:\n")
+	for _, name := range []string{"analysis", "capabilities", "opa", "mcp", "grpc", "plugins"} {
+		output.WriteString(fmt.Sprintf("  %-24s %s\n", name, caps.SchemaVersions[name]))
+	}
+	return output.String()
+}
+
+func runCapabilities(config Config) error {
+	caps := NewCapabilities()
+	if outputFormat(config) != "json" {
+		return writeOutput(FormatCapabilities(caps), config.Output)
+	}
+	
+	jsonData, err := json.MarshalIndent(caps, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format as JSON: %w", err)
+	}
+	return writeOutput(string(jsonData)+"\n", config.Output)
+}
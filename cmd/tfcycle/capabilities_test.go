@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCapabilities_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capabilities.json")
+	config, err := parseArgs([]string{"capabilities", "--json", "--output", path})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var caps Capabilities
+	if err := json.Unmarshal(data, &caps); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	
+	if caps.SchemaVersion != capabilitiesSchemaVersion || caps.Version != version {
+		t.Errorf("Expected schema %d and version %s, got %d and %s", capabilitiesSchemaVersion, version, caps.SchemaVersion, caps.Version)
+	}
+	if !slices.Contains(caps.Commands, "capabilities") || !slices.Contains(caps.OutputFormats, "opa") {
+		t.Errorf("Expected the capabilities command and opa format, got %v and %v", caps.Commands, caps.OutputFormats)
+	}
+	if len(caps.Heuristics) == 0 || caps.Heuristics[0].Name == "" {
+		t.Errorf("Expected named heuristics, got %v", caps.Heuristics)
+	}
+	if caps.SchemaVersions["opa"] != "2" {
+		t.Errorf("Expected opa schema version 2, got %q", caps.SchemaVersions["opa"])
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommands returns the commands that copy stdin to the system
+// clipboard, in order of preference for the platform.
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+	
+	var commands [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		commands = append(commands, []string{"wl-copy"})
+	}
+	commands = append(commands,
+		[]string{"xclip", "-selection", "clipboard"},
+		[]string{"xsel", "--clipboard", "--input"},
+		// WSL, where the Windows clipboard is reachable from Linux.
+		[]string{"clip.exe"},
+	)
+	return commands
+}
+
+func copyToClipboard(content string) error {
+	var tried []string
+	for _, command := range clipboardCommands() {
+		path, err := exec.LookPath(command[0])
+		if err != nil {
+			tried = append(tried, command[0])
+			continue
+		}
+		
+		var stderr bytes.Buffer
+		cmd := exec.Command(path, command[1:]...)
+		cmd.Stdin = strings.NewReader(content)
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %v: %s", command[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	}
+	return fmt.Errorf("no clipboard tool found on PATH (tried %s)", strings.Join(tried, ", "))
+}
+
+// writeReport writes content like writeOutput and, with --clipboard, also
+// copies it to the system clipboard.
+func writeReport(content string, config Config) error {
+	if err := writeOutput(content, config.Output); err != nil {
+		return err
+	}
+	if !config.Clipboard {
+		return nil
+	}
+	
+	if err := copyToClipboard(content); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	if !config.Quiet {
+		fmt.Fprintln(os.Stderr, "Copied to clipboard")
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestWriteReport_Clipboard(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("fake xclip only stands in for the Linux clipboard")
+	}
+	bin := t.TempDir()
+	copied := filepath.Join(bin, "copied.txt")
+	script := "#!/bin/sh\ncat > " + copied + "\n"
+	if err := os.WriteFile(filepath.Join(bin, "xclip"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("WAYLAND_DISPLAY", "")
+	
+	output := filepath.Join(bin, "report.txt")
+	if err := writeReport("report\n", Config{Output: output, Clipboard: true, Quiet: true}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if data, _ := os.ReadFile(copied); string(data) != "report\n" {
+		t.Errorf("Expected the report on the clipboard, got %q", data)
+	}
+	if data, _ := os.ReadFile(output); string(data) != "report\n" {
+		t.Errorf("Expected the report in the output file too, got %q", data)
+	}
+	
+	t.Setenv("PATH", t.TempDir())
+	err := writeReport("report\n", Config{Output: output, Clipboard: true})
+	if err == nil || !strings.Contains(err.Error(), "no clipboard tool found") {
+		t.Errorf("Expected a missing clipboard tool error, got: %v", err)
+	}
+}
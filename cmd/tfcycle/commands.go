@@ -0,0 +1,396 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+type stringList []string
+
+func (sl *stringList) String() string {
+	return strings.Join(*sl, ",")
+}
+
+func (sl *stringList) Set(value string) error {
+	*sl = append(*sl, value)
+	return nil
+}
+
+// byteSize is a size in bytes that accepts a KB, MB, GB (powers of 1000) or
+// KiB, MiB, GiB (powers of 1024) suffix.
+type byteSize int64
+
+var byteSizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9},
+	{"B", 1},
+}
+
+func (bs *byteSize) String() string {
+	return strconv.FormatInt(int64(*bs), 10)
+}
+
+func (bs *byteSize) Set(value string) error {
+	number := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range byteSizeUnits {
+		if strings.HasSuffix(number, unit.suffix) {
+			number = strings.TrimSpace(strings.TrimSuffix(number, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+	
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 0 || n > math.MaxInt64/multiplier {
+		return fmt.Errorf("invalid size %q", value)
+	}
+	*bs = byteSize(n * multiplier)
+	return nil
+}
+
+type flagGroup func(fs *flag.FlagSet, config *Config)
+
+type commandInfo struct {
+	Name    string
+	Args    string
+	Summary string
+	Flags   []flagGroup
+}
+
+var commands = []commandInfo{
+	{"analyze", "[FILE]", "Analyze Terraform cycle error (default)", []flagGroup{inputFlags, inputFormatFlags, outputFlags, clipboardFlags, formatFlags, quietFlags, displayFlags, filterFlags, configDirFlags, cdktfFlags, redactFlags, failOnFlags, historyFlags, notifyFlags, pluginFlags, feedbackFlags, suggestionRulesFlags, baselineFlags, analyzeFlags, contextFlags, visualFlags, tfVersionFlags}},
+	{"visualize", "[FILE]", "Generate DOT visualization of cycle", []flagGroup{inputFlags, inputFormatFlags, outputFlags, clipboardFlags, filterFlags, configDirFlags, redactFlags, visualFlags}},
+	{"fix", "[FILE]", "Generate HCL snippets and diffs for recognized cycle patterns", []flagGroup{inputFlags, inputFormatFlags, outputFlags, clipboardFlags, formatFlags, filterFlags, configDirFlags, suggestionRulesFlags, tfVersionFlags}},
+	{"lint", "", "Find cycles in the configuration's references and recorded state dependencies before terraform reports them", []flagGroup{outputFlags, clipboardFlags, formatFlags, configDirFlags, lintFlags, failOnFlags, suggestionRulesFlags, baselineFlags, tfVersionFlags}},
+	{"explain", "ADDRESS [FILE]", "Explain a single resource address: cycles, edges, suggestions", []flagGroup{inputFlags, inputFormatFlags, outputFlags, clipboardFlags, formatFlags, configDirFlags, cdktfFlags, feedbackFlags, suggestionRulesFlags, aiFlags, tfVersionFlags}},
+	{"blast-radius", "[FILE]", "List resources outside the minimal cycles that transitively depend on cycle members", []flagGroup{inputFlags, inputFormatFlags, outputFlags, clipboardFlags, formatFlags, filterFlags, configDirFlags, pluginFlags, feedbackFlags}},
+	{"aggregate", "[NAME=]FILE...", "Deduplicate cycle errors from many workspaces and report the modules and patterns causing them", []flagGroup{outputFlags, clipboardFlags, formatFlags, filterFlags, redactFlags, suggestionRulesFlags, aggregateFlags}},
+	{"stats", "FILE...", "Print statistics over many cycle errors or JSON analyses: frequent resource types, cycle lengths, modules", []flagGroup{outputFlags, clipboardFlags, formatFlags, filterFlags}},
+	{"annotate", "[FILE]", "Echo Terraform output with the analysis inserted after the cycle diagnostic and its members numbered", []flagGroup{inputFlags, outputFlags, displayFlags, configDirFlags, cdktfFlags, pluginFlags, feedbackFlags, suggestionRulesFlags, tfVersionFlags}},
+	{"diff", "OLD NEW", "Compare two cycle errors to see whether a fix changed anything", []flagGroup{outputFlags, clipboardFlags, formatFlags, failOnFlags}},
+	{"watch", "", "Re-run terraform plan on file changes and live-update the analysis", []flagGroup{watchFlags, displayFlags}},
+	{"run", "-- COMMAND [ARGS]", "Run a terraform command and append an analysis if it hits a cycle", []flagGroup{outputFlags, formatFlags, quietFlags, displayFlags, filterFlags, configDirFlags, cdktfFlags, redactFlags, failOnFlags, historyFlags, notifyFlags, pluginFlags, feedbackFlags, suggestionRulesFlags, contextFlags, tfVersionFlags}},
+	{"serve", "", "Serve a web UI, HTTP JSON API and HCP Terraform run task for cycle errors", []flagGroup{serveFlags, redactFlags, failOnFlags}},
+	{"mcp", "", "Serve tfcycle tools to AI assistants over the Model Context Protocol (stdio)", []flagGroup{configDirFlags, redactFlags}},
+	{"lsp", "", "Serve lint diagnostics and fixes to editors over the Language Server Protocol (stdio)", []flagGroup{configDirFlags, lintFlags, suggestionRulesFlags, tfVersionFlags}},
+	{"history", "list|show ID|top [N]|weekly", "List past analyses, show one, or summarize recurring resources and cycles per week", []flagGroup{outputFlags, formatFlags, historyDBFlags}},
+	{"doctor", "", "Check Graphviz, terraform, terragrunt, the config file and the cache directory", []flagGroup{outputFlags, formatFlags}},
+	{"capabilities", "", "List supported input and output formats, heuristics and schema versions", []flagGroup{outputFlags, formatFlags}},
+	{"self-update", "", "Replace this binary with the latest GitHub release after verifying its checksum and signature", []flagGroup{selfUpdateFlags}},
+	{"plugins", "", "List tfcycle-format-* and tfcycle-heuristic-* plugins found on PATH", []flagGroup{outputFlags, formatFlags}},
+	{"bench", "", "Time parsing, analysis and formatting of a synthetic cycle", []flagGroup{outputFlags, formatFlags, benchFlags}},
+	{"feedback", "confirm EDGE|deny EDGE|list", "Record whether a hypothesized edge is real; later analyses of this directory reuse the verdict", []flagGroup{outputFlags, formatFlags, quietFlags, configDirFlags}},
+	{"baseline", "write FILE [INPUT]", "Record the current cycles as accepted, so --baseline reports only new ones", []flagGroup{inputFlags, inputFormatFlags, quietFlags, filterFlags, configDirFlags, lintFlags, feedbackFlags, suggestionRulesFlags}},
+	{"rules", "list", "List the suggestion rules in effect, built-in and from --suggestion-rules", []flagGroup{outputFlags, formatFlags, suggestionRulesFlags}},
+	{"docs", "man|markdown", "Generate a man page (man) or CLI reference (markdown)", []flagGroup{outputFlags}},
+	{"completion", "bash|zsh|fish|powershell", "Generate shell completion script (bash, zsh, fish, powershell)", []flagGroup{outputFlags}},
+	{"version", "", "Show version, git commit, build date and Go version", []flagGroup{outputFlags, formatFlags}},
+	{"help", "[COMMAND]", "Show this help message", nil},
+}
+
+var allFlagGroups = []flagGroup{inputFlags, inputFormatFlags, outputFlags, clipboardFlags, formatFlags, quietFlags, displayFlags, filterFlags, configDirFlags, redactFlags, failOnFlags, historyFlags, notifyFlags, analyzeFlags, watchFlags, serveFlags, visualFlags, aiFlags, selfUpdateFlags, pluginFlags, feedbackFlags, suggestionRulesFlags, tfVersionFlags, lintFlags, cdktfFlags, benchFlags, aggregateFlags, contextFlags, baselineFlags}
+
+var flagValues = map[string][]string{
+	"format":       {"text", "json", "markdown", "plan", "opa", "jgf", "rdjson", "warnings-ng"},
+	"scope":        {"minimal", "full"},
+	"rankdir":      {"LR", "RL", "TB", "BT"},
+	"theme":        {"auto", "light", "dark"},
+	"label":        {"short", "full"},
+	"fail-on":      {"none", "low", "medium", "high"},
+	"input-format": tfcycle.InputFormats,
+	"log-level":    {"debug", "info", "warn", "error"},
+}
+
+var flagArgNames = map[string]string{
+	"filter":            "KEY=VALUE",
+	"exclude":           "PATTERN",
+	"listen":            "ADDR",
+	"grpc-listen":       "ADDR",
+	"run-task-hmac-key": "KEY",
+	"ai-endpoint":       "URL",
+	"ai-model":          "MODEL",
+	"ai-api-key":        "KEY",
+	"datadog-site":      "SITE",
+	"datadog-api-key":   "KEY",
+	"notify-webhook":    "URL",
+	"max-input-size":    "SIZE",
+	"cdktf":             "PATH",
+}
+
+var fileFlags = map[string]bool{
+	"error-file":       true,
+	"output":           true,
+	"emit-script":      true,
+	"config":           true,
+	"log-file":         true,
+	"cpuprofile":       true,
+	"memprofile":       true,
+	"history-db":       true,
+	"ai-prompt":        true,
+	"suggestion-rules": true,
+	"state":            true,
+	"css":              true,
+	"baseline":         true,
+	"payload-template": true,
+}
+
+var dirFlags = map[string]bool{
+	"output-dir":   true,
+	"config-dir":   true,
+	"dir":          true,
+	"template-dir": true,
+}
+
+func lookupCommand(name string) (commandInfo, bool) {
+	for _, command := range commands {
+		if command.Name == name {
+			return command, true
+		}
+	}
+	return commandInfo{}, false
+}
+
+func globalFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.Verbose, "verbose", false, "Show detailed analysis")
+	fs.StringVar(&config.Lang, "lang", "", "Report language (en, de, ja, pt-BR)")
+	fs.BoolVar(&config.NoColor, "no-color", false, "Disable colored output")
+	fs.StringVar(&config.ConfigFile, "config", "", "Configuration file (default .tfcycle.yaml or ~/.config/tfcycle/config.yaml)")
+	fs.DurationVar(&config.Timeout, "timeout", 0, "Give up on input reading and analysis after this long and report partial results (0 = no limit)")
+	fs.Var((*byteSize)(&config.MaxInputSize), "max-input-size", "Refuse input larger than this, e.g. 500MB or 1GiB (0 = no limit)")
+	fs.DurationVar(&config.StdinTimeout, "stdin-timeout", 0, "Fail if stdin or a named pipe gives no input within this long (0 = wait forever)")
+	fs.StringVar(&config.LogLevel, "log-level", "warn", "Log level (debug, info, warn, error)")
+	fs.StringVar(&config.LogFile, "log-file", "", "Write logs to this file instead of stderr")
+	fs.StringVar(&config.CPUProfile, "cpuprofile", "", "Write a CPU profile to this file")
+	fs.StringVar(&config.MemProfile, "memprofile", "", "Write a heap profile to this file when the command finishes")
+	fs.BoolVar(&config.ShowVersion, "version", false, "Show version information")
+	fs.BoolVar(&config.Help, "help", false, "Show help")
+}
+
+func inputFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.ErrorFile, "error-file", "", "Read error from file instead of stdin (- for stdin); also given as the FILE argument")
+}
+
+func inputFormatFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.InputFormat, "input-format", tfcycle.InputAuto, "Read the input as Terraform output (text), JSON output (tfjson), terraform graph (dot), plan or state JSON, or a tfcycle report; auto detects it")
+}
+
+func outputFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.Output, "output", "", "Write output to file instead of stdout")
+	fs.BoolVar(&config.Compress, "compress", false, "Gzip the output file, adding .gz to its name (implied by an --output ending in .gz)")
+}
+
+func clipboardFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.Clipboard, "clipboard", false, "Also copy the output to the system clipboard")
+}
+
+func formatFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.JSON, "json", false, "Output as JSON")
+	fs.StringVar(&config.Format, "format", "", "Output format (text, json, markdown, plan, opa, jgf, rdjson, warnings-ng, or a tfcycle-format-* plugin name)")
+}
+
+func quietFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.Quiet, "quiet", false, "Print only the minimal cycle paths; suppress headers, emoji and suggestions")
+}
+
+func displayFlags(fs *flag.FlagSet, config *Config) {
+	fs.IntVar(&config.MaxCyclesShown, "max-cycles-shown", tfcycle.DefaultMaxCyclesShown, "Show at most this many minimal cycles in the text report (0 = all)")
+	fs.IntVar(&config.MaxNodesShown, "max-nodes-shown", tfcycle.DefaultMaxNodesShown, "Show at most this many resources of each minimal cycle (0 = all)")
+	fs.BoolVar(&config.ShowAll, "all", false, "Show every minimal cycle and resource in the text report")
+	fs.StringVar(&config.TemplateDir, "template-dir", "", "Directory of SECTION.FORMAT.tmpl templates that replace the header, suggestions or footer of the text and markdown reports")
+}
+
+func historyFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.NoHistory, "no-history", false, "Do not record this analysis in the local history")
+	historyDBFlags(fs, config)
+}
+
+func historyDBFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.HistoryDB, "history-db", "", "Store history in this SQLite database instead of JSON files")
+}
+
+func notifyFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.Datadog, "datadog", false, "Send a Datadog event tagged with workspace, severity and fingerprint when a cycle is found")
+	fs.StringVar(&config.DatadogSite, "datadog-site", "", "Datadog site, e.g. datadoghq.eu (default $DD_SITE or datadoghq.com)")
+	fs.StringVar(&config.DatadogAPIKey, "datadog-api-key", "", "Datadog API key (default $DD_API_KEY)")
+	fs.StringVar(&config.NotifyWebhook, "notify-webhook", "", "POST a JSON payload to this URL when a cycle is found")
+	fs.StringVar(&config.PayloadTemplate, "payload-template", "", "Go template rendering the --notify-webhook payload (default the history entry and summary as JSON)")
+}
+
+func filterFlags(fs *flag.FlagSet, config *Config) {
+	fs.Var((*stringList)(&config.Filters), "filter", "Restrict analysis to nodes matching KEY=VALUE (module=NAME, type=TYPE); repeatable")
+	fs.Var((*stringList)(&config.Excludes), "exclude", "Exclude nodes whose address matches this glob pattern, e.g. 'null_resource.*'; repeatable")
+	fs.BoolVar(&config.OnlyDestroy, "only-destroy", false, "Restrict analysis to nodes with destroy or destroy-deposed actions")
+}
+
+func configDirFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.ConfigDir, "config-dir", "", "Terraform configuration directory")
+}
+
+func redactFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.Redact, "redact", false, "Replace resource names, module names and instance keys with pseudonyms")
+}
+
+func failOnFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.FailOn, "fail-on", "low", "Minimum cycle severity that causes exit code 1 (none, low, medium, high)")
+}
+
+func analyzeFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.Script, "emit-script", "", "Write a remediation shell script to this file")
+	fs.StringVar(&config.OutputDir, "output-dir", "", "Write all report formats to this directory")
+	fs.BoolVar(&config.Passthrough, "passthrough", false, "Echo input verbatim and append analysis only when a cycle is found")
+	fs.BoolVar(&config.CompareLast, "compare-last", false, "Compare with the most recent recorded analysis for this directory and workspace")
+	fs.BoolVar(&config.Interactive, "interactive", false, "Confirm each hypothesized dependency and recompute cycles from the answers")
+}
+
+func watchFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.Dir, "dir", ".", "Terraform directory for watch")
+	fs.DurationVar(&config.Interval, "interval", 0, "Re-run interval for watch (0 = only on file changes)")
+}
+
+func serveFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.Listen, "listen", "127.0.0.1:8080", "Address for serve to listen on")
+	fs.StringVar(&config.GRPCListen, "grpc-listen", "", "Also serve the gRPC API on this address")
+	fs.StringVar(&config.RunTaskHMACKey, "run-task-hmac-key", "", "HMAC key for verifying HCP Terraform run task requests; /run-task is served only with a key")
+	fs.StringVar(&config.Theme, "theme", "auto", "Web UI color theme (auto, light, dark)")
+	fs.StringVar(&config.CSSFile, "css", "", "CSS file appended to the web UI's styles")
+}
+
+func pluginFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.NoPlugins, "no-plugins", false, "Do not run tfcycle-heuristic-* plugins found on PATH")
+}
+
+func feedbackFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.NoFeedback, "no-feedback", false, "Ignore edge verdicts recorded with tfcycle feedback")
+}
+
+func suggestionRulesFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.SuggestionRules, "suggestion-rules", "", "YAML file of suggestion rules that override or extend the built-in rules")
+	fs.Var((*stringList)(&config.DisabledRules), "disable-rule", "Turn off the heuristic or suggestion with this rule ID or glob, e.g. 'TFC-AWS-*'; repeatable")
+	fs.Var((*stringList)(&config.EnabledRules), "enable-rule", "Keep the heuristic or suggestion with this rule ID or glob on despite --disable-rule; repeatable")
+	fs.Var((*stringList)(&config.Suppress), "suppress", "Hide the suggestion with this rule ID or glob, keeping its heuristic; repeatable")
+	fs.BoolVar(&config.NoSuggestions, "no-suggestions", false, "Report only the cycle, without suggestions, common solutions or an apply plan")
+}
+
+func lintFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.StateFile, "state", "", "State file (terraform state pull) or terraform show -json output whose dependencies lint adds")
+}
+
+func baselineFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.Baseline, "baseline", "", "Report only cycles not accepted in this baseline file (see tfcycle baseline write)")
+}
+
+func tfVersionFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.TFVersion, "tf-version", "", "Terraform or OpenTofu version for version-specific advice (default: detected from the input)")
+}
+
+func cdktfFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.CDKTF, "cdktf", "", "CDKTF output directory (cdktf.out), manifest.json or cdk.tf.json used to map resources to construct paths")
+}
+
+func aggregateFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.Analyses, "analyses", false, "Include the JSON analysis of each distinct cycle in the JSON report")
+}
+
+func contextFlags(fs *flag.FlagSet, config *Config) {
+	fs.IntVar(&config.ContextLines, "context-lines", tfcycle.DefaultContextLines, "Lines before the cycle error to keep as its context (0 = none)")
+}
+
+func benchFlags(fs *flag.FlagSet, config *Config) {
+	fs.IntVar(&config.BenchNodes, "nodes", 500, "Number of resources in the synthetic cycle")
+}
+
+func selfUpdateFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.CheckOnly, "check", false, "Only report whether a newer release is available")
+	fs.BoolVar(&config.InsecureSkipSignature, "insecure-skip-signature", false, "Install a release without verifying the signature of its checksums")
+}
+
+func aiFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.AI, "ai", false, "Add a narrative root-cause explanation and remediation plan from an OpenAI-compatible model")
+	fs.StringVar(&config.AIEndpoint, "ai-endpoint", defaultAIEndpoint, "Base URL of the OpenAI-compatible API used by --ai")
+	fs.StringVar(&config.AIModel, "ai-model", defaultAIModel, "Model used by --ai")
+	fs.StringVar(&config.AIAPIKey, "ai-api-key", "", "API key for --ai (default $OPENAI_API_KEY)")
+	fs.StringVar(&config.AIPrompt, "ai-prompt", "", "Go template file overriding the --ai prompt ({{.Address}}, {{.Analysis}})")
+}
+
+func visualFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.Scope, "scope", "minimal", "Visualize scope (minimal, full)")
+	fs.StringVar(&config.RankDir, "rankdir", "LR", "Graph direction for visualize (LR, RL, TB, BT)")
+	fs.StringVar(&config.NodeShape, "node-shape", "box", "Graphviz node shape for visualize")
+	fs.StringVar(&config.FontName, "font", "", "Font name for visualize labels")
+	fs.StringVar(&config.LabelMode, "label", "short", "Node label mode for visualize (short, full)")
+	fs.IntVar(&config.MaxLabelLength, "max-label-length", 0, "Truncate visualize labels longer than N characters")
+}
+
+func newCommandFlagSet(command commandInfo, config *Config) *flag.FlagSet {
+	fs := flag.NewFlagSet("tfcycle "+command.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	globalFlags(fs, config)
+	for _, group := range command.Flags {
+		group(fs, config)
+	}
+	return fs
+}
+
+func allFlagsSet(config *Config) *flag.FlagSet {
+	fs := flag.NewFlagSet("tfcycle", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	globalFlags(fs, config)
+	for _, group := range allFlagGroups {
+		group(fs, config)
+	}
+	return fs
+}
+
+func flagArgName(f *flag.Flag) string {
+	if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+		return ""
+	}
+	switch {
+	case flagArgNames[f.Name] != "":
+		return flagArgNames[f.Name]
+	case len(flagValues[f.Name]) > 0:
+		return strings.Join(flagValues[f.Name], "|")
+	case fileFlags[f.Name]:
+		return "FILE"
+	case dirFlags[f.Name]:
+		return "DIR"
+	}
+	arg, _ := flag.UnquoteUsage(f)
+	return strings.ToUpper(arg)
+}
+
+func CommandUsage(command commandInfo) string {
+	var output strings.Builder
+	config := &Config{}
+	fs := newCommandFlagSet(command, config)
+	
+	synopsis := "tfcycle " + command.Name + " [OPTIONS]"
+	if command.Args != "" {
+		synopsis += " " + command.Args
+	}
+	output.WriteString(fmt.Sprintf("USAGE:\n    %s\n\n%s\n\nOPTIONS:\n", synopsis, command.Summary))
+	
+	fs.VisitAll(func(f *flag.Flag) {
+		name := "--" + f.Name
+		if arg := flagArgName(f); arg != "" {
+			name += " " + arg
+		}
+		usage := f.Usage
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "0" && f.DefValue != "0s" {
+			usage += fmt.Sprintf(" (default %s)", f.DefValue)
+		}
+		output.WriteString(fmt.Sprintf("    %-24s %s\n", name, usage))
+	})
+	
+	return output.String()
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+type completionFlag struct {
+	Name   string
+	Usage  string
+	Bool   bool
+	File   bool
+	Dir    bool
+	Values []string
+}
+
+func completionFlags(fs *flag.FlagSet) []completionFlag {
+	var flags []completionFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		cf := completionFlag{
+			Name:   f.Name,
+			Usage:  f.Usage,
+			File:   fileFlags[f.Name],
+			Dir:    dirFlags[f.Name],
+			Values: flagValues[f.Name],
+		}
+		if f.Name == "lang" {
+			cf.Values = tfcycle.SupportedLanguages()
+		}
+		if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+			cf.Bool = true
+		}
+		flags = append(flags, cf)
+	})
+	return flags
+}
+
+func GenerateCompletion(shell string, fs *flag.FlagSet) (string, error) {
+	flags := completionFlags(fs)
+	
+	switch shell {
+	case "bash":
+		return bashCompletion(flags), nil
+	case "zsh":
+		return zshCompletion(flags), nil
+	case "fish":
+		return fishCompletion(flags), nil
+	case "powershell":
+		return powershellCompletion(flags), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q (use %s)", shell, strings.Join(completionShells, ", "))
+	}
+}
+
+func commandNames() []string {
+	names := make([]string, len(commands))
+	for i, command := range commands {
+		names[i] = command.Name
+	}
+	return names
+}
+
+func bashCompletion(flags []completionFlag) string {
+	var output strings.Builder
+	var names []string
+	
+	output.WriteString("# bash completion for tfcycle\n")
+	output.WriteString("_tfcycle() {\n")
+	output.WriteString("    local cur prev\n")
+	output.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	output.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+	output.WriteString("    case \"$prev\" in\n")
+	for _, f := range flags {
+		names = append(names, "--"+f.Name)
+		switch {
+		case len(f.Values) > 0:
+			output.WriteString(fmt.Sprintf("        --%s) COMPREPLY=( $(compgen -W %q -- \"$cur\") ); return ;;\n", f.Name, strings.Join(f.Values, " ")))
+		case f.File:
+			output.WriteString(fmt.Sprintf("        --%s) COMPREPLY=( $(compgen -f -- \"$cur\") ); return ;;\n", f.Name))
+		case f.Dir:
+			output.WriteString(fmt.Sprintf("        --%s) COMPREPLY=( $(compgen -d -- \"$cur\") ); return ;;\n", f.Name))
+		case !f.Bool:
+			output.WriteString(fmt.Sprintf("        --%s) return ;;\n", f.Name))
+		}
+	}
+	output.WriteString(fmt.Sprintf("        completion) COMPREPLY=( $(compgen -W %q -- \"$cur\") ); return ;;\n", strings.Join(completionShells, " ")))
+	output.WriteString("    esac\n\n")
+	output.WriteString("    if [[ $COMP_CWORD -eq 1 && \"$cur\" != -* ]]; then\n")
+	output.WriteString(fmt.Sprintf("        COMPREPLY=( $(compgen -W %q -- \"$cur\") )\n", strings.Join(commandNames(), " ")))
+	output.WriteString("        return\n")
+	output.WriteString("    fi\n\n")
+	output.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	output.WriteString(fmt.Sprintf("        COMPREPLY=( $(compgen -W %q -- \"$cur\") )\n", strings.Join(names, " ")))
+	output.WriteString("        return\n")
+	output.WriteString("    fi\n\n")
+	output.WriteString("    COMPREPLY=( $(compgen -f -- \"$cur\") )\n")
+	output.WriteString("}\n")
+	output.WriteString("complete -F _tfcycle tfcycle\n")
+	
+	return output.String()
+}
+
+func zshCompletion(flags []completionFlag) string {
+	var output strings.Builder
+	
+	output.WriteString("#compdef tfcycle\n\n")
+	output.WriteString("_tfcycle() {\n")
+	output.WriteString("    local -a commands\n")
+	output.WriteString("    commands=(\n")
+	for _, command := range commands {
+		output.WriteString(fmt.Sprintf("        '%s:%s'\n", command.Name, zshEscape(command.Summary)))
+	}
+	output.WriteString("    )\n\n")
+	output.WriteString("    _arguments -C \\\n")
+	for _, f := range flags {
+		spec := fmt.Sprintf("'--%s[%s]", f.Name, zshEscape(f.Usage))
+		switch {
+		case len(f.Values) > 0:
+			spec += fmt.Sprintf(":%s:(%s)", f.Name, strings.Join(f.Values, " "))
+		case f.File:
+			spec += ":file:_files"
+		case f.Dir:
+			spec += ":directory:_files -/"
+		case !f.Bool:
+			spec += ":" + f.Name + ": "
+		}
+		output.WriteString("        " + spec + "' \\\n")
+	}
+	output.WriteString("        '1: :->command' \\\n")
+	output.WriteString("        '*:file:_files'\n\n")
+	output.WriteString("    case $state in\n")
+	output.WriteString("        command) _describe 'command' commands ;;\n")
+	output.WriteString("    esac\n")
+	output.WriteString("}\n\n")
+	output.WriteString("_tfcycle \"$@\"\n")
+	
+	return output.String()
+}
+
+func zshEscape(text string) string {
+	text = strings.ReplaceAll(text, "'", "'\\''")
+	text = strings.ReplaceAll(text, "[", "\\[")
+	text = strings.ReplaceAll(text, "]", "\\]")
+	return strings.ReplaceAll(text, ":", "\\:")
+}
+
+func fishCompletion(flags []completionFlag) string {
+	var output strings.Builder
+	
+	output.WriteString("# fish completion for tfcycle\n")
+	output.WriteString("complete -c tfcycle -f\n")
+	for _, command := range commands {
+		output.WriteString(fmt.Sprintf("complete -c tfcycle -n __fish_use_subcommand -a %s -d %s\n", command.Name, fishQuote(command.Summary)))
+	}
+	output.WriteString(fmt.Sprintf("complete -c tfcycle -n '__fish_seen_subcommand_from completion' -a %s\n", fishQuote(strings.Join(completionShells, " "))))
+	
+	for _, f := range flags {
+		line := fmt.Sprintf("complete -c tfcycle -l %s -d %s", f.Name, fishQuote(f.Usage))
+		switch {
+		case len(f.Values) > 0:
+			line += " -x -a " + fishQuote(strings.Join(f.Values, " "))
+		case f.File:
+			line += " -r -F"
+		case f.Dir:
+			line += " -x -a '(__fish_complete_directories)'"
+		case !f.Bool:
+			line += " -x"
+		}
+		output.WriteString(line + "\n")
+	}
+	
+	return output.String()
+}
+
+func fishQuote(text string) string {
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(text, `\`, `\\`), "'", `\'`) + "'"
+}
+
+func powershellCompletion(flags []completionFlag) string {
+	var output strings.Builder
+	
+	output.WriteString("# PowerShell completion for tfcycle\n")
+	output.WriteString("Register-ArgumentCompleter -Native -CommandName tfcycle -ScriptBlock {\n")
+	output.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n\n")
+	output.WriteString("    $commands = @(" + powershellList(commandNames()) + ")\n")
+	
+	var names []string
+	output.WriteString("    $values = @{\n")
+	for _, f := range flags {
+		names = append(names, "--"+f.Name)
+		if len(f.Values) > 0 {
+			output.WriteString(fmt.Sprintf("        '--%s' = @(%s)\n", f.Name, powershellList(f.Values)))
+		}
+	}
+	output.WriteString("        'completion' = @(" + powershellList(completionShells) + ")\n")
+	output.WriteString("    }\n")
+	output.WriteString("    $flags = @(" + powershellList(names) + ")\n\n")
+	
+	output.WriteString("    $elements = $commandAst.CommandElements | ForEach-Object { $_.ToString() }\n")
+	output.WriteString("    $previous = if ($wordToComplete) { $elements[-2] } else { $elements[-1] }\n\n")
+	output.WriteString("    $candidates = if ($values.ContainsKey($previous)) {\n")
+	output.WriteString("        $values[$previous]\n")
+	output.WriteString("    } elseif ($elements.Count -le 2 -and -not $wordToComplete.StartsWith('-')) {\n")
+	output.WriteString("        $commands\n")
+	output.WriteString("    } else {\n")
+	output.WriteString("        $flags\n")
+	output.WriteString("    }\n\n")
+	output.WriteString("    $candidates | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	output.WriteString("        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n")
+	output.WriteString("    }\n")
+	output.WriteString("}\n")
+	
+	return output.String()
+}
+
+func powershellList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = "'" + strings.ReplaceAll(item, "'", "''") + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func newCompletionFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("format", "", "Output format")
+	fs.String("error-file", "", "Read error from file")
+	fs.Bool("verbose", false, "Show detailed analysis")
+	return fs
+}
+
+func TestGenerateCompletion_AllShells(t *testing.T) {
+	for _, shell := range completionShells {
+		script, err := GenerateCompletion(shell, newCompletionFlagSet())
+		if err != nil {
+			t.Fatalf("Expected no error for %s, got: %v", shell, err)
+		}
+		for _, expected := range []string{"analyze", "format", "markdown", "error-file"} {
+			if !strings.Contains(script, expected) {
+				t.Errorf("Expected %s completion to contain %q", shell, expected)
+			}
+		}
+	}
+}
+
+func TestGenerateCompletion_Bash(t *testing.T) {
+	script, _ := GenerateCompletion("bash", newCompletionFlagSet())
+	
+	if !strings.Contains(script, `--format) COMPREPLY=( $(compgen -W "text json markdown plan opa jgf rdjson warnings-ng" -- "$cur") )`) {
+		t.Errorf("Expected format values in bash completion, got:\n%s", script)
+	}
+	if !strings.Contains(script, `--error-file) COMPREPLY=( $(compgen -f -- "$cur") )`) {
+		t.Errorf("Expected file completion for --error-file")
+	}
+	if strings.Contains(script, "--verbose)") {
+		t.Errorf("Expected boolean flags not to consume a value")
+	}
+}
+
+func TestGenerateCompletion_UnknownShell(t *testing.T) {
+	if _, err := GenerateCompletion("tcsh", newCompletionFlagSet()); err == nil {
+		t.Errorf("Expected error for unsupported shell")
+	}
+}
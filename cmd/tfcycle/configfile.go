@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+var projectConfigFiles = []string{".tfcycle.yaml", ".tfcycle.yml"}
+
+// FileConfig holds the settings of .tfcycle.yaml. Settings that choose where
+// credentials or the analysis are sent (ai_endpoint, the webhook) are not
+// among them, so that a checked-in file cannot redirect them.
+type FileConfig struct {
+	Format        string   `yaml:"format"`
+	Color         *bool    `yaml:"color"`
+	OutputDir     string   `yaml:"output_dir"`
+	ConfigDir     string   `yaml:"config_dir"`
+	DisabledRules []string `yaml:"disabled_rules"`
+	EnabledRules  []string `yaml:"enabled_rules"`
+	Suppress      []string `yaml:"suppress"`
+	Baseline      string   `yaml:"baseline"`
+	Exclude       []string `yaml:"exclude"`
+	Lang          string   `yaml:"lang"`
+	FailOn        string   `yaml:"fail_on"`
+	Verbose       *bool    `yaml:"verbose"`
+	Redact        *bool    `yaml:"redact"`
+	Quiet         *bool    `yaml:"quiet"`
+	History       *bool    `yaml:"history"`
+	HistoryDB     string   `yaml:"history_db"`
+	AIModel       string   `yaml:"ai_model"`
+	AIPrompt      string   `yaml:"ai_prompt"`
+	Datadog       *bool    `yaml:"datadog"`
+	DatadogSite   string   `yaml:"datadog_site"`
+
+	SuggestionRules string `yaml:"suggestion_rules"`
+	TFVersion       string `yaml:"tf_version"`
+	CDKTF           string `yaml:"cdktf"`
+	TemplateDir     string `yaml:"template_dir"`
+}
+
+func configFileCandidates() []string {
+	candidates := append([]string{}, projectConfigFiles...)
+	
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+	if configHome != "" {
+		candidates = append(candidates, filepath.Join(configHome, "tfcycle", "config.yaml"))
+	}
+	
+	return candidates
+}
+
+func LoadFileConfig(explicit string) (*FileConfig, string, error) {
+	if explicit != "" {
+		config, err := readFileConfig(explicit)
+		return config, explicit, err
+	}
+	
+	for _, path := range configFileCandidates() {
+		config, err := readFileConfig(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		return config, path, err
+	}
+	
+	return &FileConfig{}, "", nil
+}
+
+func readFileConfig(path string) (*FileConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	
+	config := &FileConfig{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	
+	return config, nil
+}
+
+func (fc *FileConfig) Apply(config *Config, explicit map[string]bool) {
+	if fc.Format != "" && !explicit["format"] && !explicit["json"] {
+		config.Format = fc.Format
+	}
+	if fc.Color != nil && !explicit["no-color"] {
+		config.NoColor = !*fc.Color
+	}
+	if fc.OutputDir != "" && !explicit["output-dir"] {
+		config.OutputDir = fc.OutputDir
+	}
+	if fc.ConfigDir != "" && !explicit["config-dir"] {
+		config.ConfigDir = fc.ConfigDir
+	}
+	if fc.Lang != "" && !explicit["lang"] {
+		config.Lang = fc.Lang
+	}
+	if fc.FailOn != "" && !explicit["fail-on"] {
+		config.FailOn = fc.FailOn
+	}
+	if fc.Verbose != nil && !explicit["verbose"] {
+		config.Verbose = *fc.Verbose
+	}
+	if fc.Redact != nil && !explicit["redact"] {
+		config.Redact = *fc.Redact
+	}
+	if fc.Quiet != nil && !explicit["quiet"] {
+		config.Quiet = *fc.Quiet
+	}
+	if fc.History != nil && !explicit["no-history"] {
+		config.NoHistory = !*fc.History
+	}
+	if fc.HistoryDB != "" && !explicit["history-db"] {
+		config.HistoryDB = fc.HistoryDB
+	}
+	if fc.Datadog != nil && !explicit["datadog"] {
+		config.Datadog = *fc.Datadog
+	}
+	if fc.DatadogSite != "" && !explicit["datadog-site"] {
+		config.DatadogSite = fc.DatadogSite
+	}
+	if fc.AIModel != "" && !explicit["ai-model"] {
+		config.AIModel = fc.AIModel
+	}
+	if fc.AIPrompt != "" && !explicit["ai-prompt"] {
+		config.AIPrompt = fc.AIPrompt
+	}
+	if fc.SuggestionRules != "" && !explicit["suggestion-rules"] {
+		config.SuggestionRules = fc.SuggestionRules
+	}
+	if fc.Baseline != "" && !explicit["baseline"] {
+		config.Baseline = fc.Baseline
+	}
+	if fc.TFVersion != "" && !explicit["tf-version"] {
+		config.TFVersion = fc.TFVersion
+	}
+	if fc.CDKTF != "" && !explicit["cdktf"] {
+		config.CDKTF = fc.CDKTF
+	}
+	if fc.TemplateDir != "" && !explicit["template-dir"] {
+		config.TemplateDir = fc.TemplateDir
+	}
+	config.DisabledRules = append(config.DisabledRules, fc.DisabledRules...)
+	config.EnabledRules = append(config.EnabledRules, fc.EnabledRules...)
+	config.Suppress = append(config.Suppress, fc.Suppress...)
+	config.Excludes = append(config.Excludes, fc.Exclude...)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func TestLoadFileConfig_Apply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".tfcycle.yaml")
+	content := `format: markdown
+color: false
+output_dir: reports
+disabled_rules:
+  - split
+fail_on: high
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	
+	fileConfig, loaded, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if loaded != path {
+		t.Errorf("Expected %s to be loaded, got %s", path, loaded)
+	}
+	
+	config := Config{Format: "json", FailOn: "low"}
+	fileConfig.Apply(&config, map[string]bool{"format": true})
+	
+	if config.Format != "json" {
+		t.Errorf("Expected CLI format to win, got %s", config.Format)
+	}
+	if !config.NoColor {
+		t.Errorf("Expected color to be disabled")
+	}
+	if config.OutputDir != "reports" {
+		t.Errorf("Expected output dir 'reports', got %s", config.OutputDir)
+	}
+	if config.FailOn != "high" {
+		t.Errorf("Expected fail_on 'high', got %s", config.FailOn)
+	}
+	if len(config.DisabledRules) != 1 || config.DisabledRules[0] != "split" {
+		t.Errorf("Expected disabled rule 'split', got %v", config.DisabledRules)
+	}
+}
+
+func TestLoadFileConfig_UnknownKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	// ai_endpoint and the webhook are taken only from flags and the
+	// environment.
+	for _, content := range []string{"formatt: json\n", "ai_endpoint: http://evil.example\n", "notify_webhook: http://evil.example\n"} {
+		os.WriteFile(path, []byte(content), 0644)
+		
+		if _, _, err := LoadFileConfig(path); err == nil {
+			t.Errorf("Expected error for unknown key in %q", content)
+		}
+	}
+}
+
+func TestLoadFileConfig_Missing(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	
+	fileConfig, loaded, err := LoadFileConfig("")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if loaded != "" || fileConfig.Format != "" {
+		t.Errorf("Expected empty configuration, got %+v from %q", fileConfig, loaded)
+	}
+}
+
+func TestFilterFixes(t *testing.T) {
+	fixes := []tfcycle.Fix{{Kind: "split"}, {Kind: "depends_on"}}
+	
+	kept := tfcycle.FilterFixes(fixes, []string{"split"})
+	if len(kept) != 1 || kept[0].Kind != "depends_on" {
+		t.Errorf("Expected only depends_on fix, got %v", kept)
+	}
+	if err := tfcycle.ValidateFixKinds([]string{"nonsense"}); err == nil {
+		t.Errorf("Expected error for unknown rule")
+	}
+}
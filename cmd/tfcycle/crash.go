@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"unicode"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+// crashInput is the last cycle diagnostic read, kept so a crash report can
+// include the input that triggered it.
+var crashInput struct {
+	mu   sync.Mutex
+	text string
+}
+
+func recordCrashInput(text string) {
+	crashInput.mu.Lock()
+	defer crashInput.mu.Unlock()
+	crashInput.text = text
+}
+
+// withCrashRecovery runs fn, turning a panic into an internal error that
+// points at a diagnostics bundle for the bug report.
+func withCrashRecovery(command string, fn func() error) (err error) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		path, writeErr := writeCrashBundle(command, recovered, debug.Stack())
+		if writeErr != nil {
+			err = fmt.Errorf("internal error: %v (failed to write diagnostics: %v)", recovered, writeErr)
+			return
+		}
+		err = fmt.Errorf("internal error: %v\nDiagnostics written to %s; please attach it to a bug report", recovered, path)
+	}()
+	return fn()
+}
+
+// writeCrashBundle writes the version, redacted input and stack trace to a
+// temporary file and returns its path.
+func writeCrashBundle(command string, recovered any, stack []byte) (string, error) {
+	crashInput.mu.Lock()
+	input := crashInput.text
+	crashInput.mu.Unlock()
+	
+	file, err := os.CreateTemp("", "tfcycle-crash-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	
+	var bundle strings.Builder
+	bundle.WriteString(FormatBuildInfo(currentBuildInfo()))
+	bundle.WriteString(fmt.Sprintf("  command:    %s\n\n", command))
+	bundle.WriteString(fmt.Sprintf("panic: %v\n\n", recovered))
+	bundle.WriteString("input (redacted):\n")
+	if input == "" {
+		bundle.WriteString("(none read)\n")
+	} else {
+		bundle.WriteString(redactCrashInput(input) + "\n")
+	}
+	bundle.WriteString("\nstack:\n")
+	bundle.Write(stack)
+	
+	if _, err := file.WriteString(bundle.String()); err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// redactCrashInput pseudonymizes input the parser understands. Input that
+// fails to parse, which is likely what crashed, keeps only its shape: letters
+// become x and digits 0, so addresses stay reproducible without their names.
+func redactCrashInput(input string) (redacted string) {
+	defer func() {
+		if recover() != nil {
+			redacted = maskInput(input)
+		}
+	}()
+	
+	cycle, err := tfcycle.NewParser().ParseError(input)
+	if err != nil {
+		return maskInput(input)
+	}
+	return tfcycle.NewRedactor().RedactCycle(cycle).RawError
+}
+
+func maskInput(input string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r):
+			return 'x'
+		case unicode.IsDigit(r):
+			return '0'
+		}
+		return r
+	}, input)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWriteCrashBundle(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	recordCrashInput("Error: Cycle: aws_instance.web, aws_security_group.secret_sg")
+	defer recordCrashInput("")
+	
+	path, err := writeCrashBundle("analyze", "boom", []byte("goroutine 1 [running]:\n"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	bundle := string(data)
+	for _, want := range []string{"tfcycle version " + version, "command:    analyze", "panic: boom", "aws_instance.res_1", "goroutine 1 [running]:"} {
+		if !strings.Contains(bundle, want) {
+			t.Errorf("Expected bundle to contain %q, got:\n%s", want, bundle)
+		}
+	}
+	if strings.Contains(bundle, "secret_sg") {
+		t.Errorf("Expected resource names redacted, got:\n%s", bundle)
+	}
+}
+
+func TestRedactCrashInput_Unparseable(t *testing.T) {
+	got := redactCrashInput("Error: Cycle: db-1")
+	if got != "xxxxx: xxxxx: xx-0" {
+		t.Errorf("Expected unparseable input masked to its shape, got %q", got)
+	}
+}
+
+func TestWithCrashRecovery(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	err := withCrashRecovery("analyze", func() error {
+		var cycle *struct{ Nodes []string }
+		_ = cycle.Nodes
+		return nil
+	})
+	if err == nil || exitCode(err) != ExitInternalError {
+		t.Fatalf("Expected an internal error, got: %v", err)
+	}
+	
+	_, path, ok := strings.Cut(err.Error(), "Diagnostics written to ")
+	if !ok {
+		t.Fatalf("Expected the bundle path in the error, got: %v", err)
+	}
+	path, _, _ = strings.Cut(path, ";")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "nil pointer dereference") {
+		t.Errorf("Expected the panic in the bundle, got:\n%s", data)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const defaultDatadogSite = "datadoghq.com"
+
+// datadogSites are the Datadog sites events can be sent to. The API key is
+// sent to the site, so other values, e.g. from a checked-in .tfcycle.yaml,
+// are rejected.
+var datadogSites = []string{"datadoghq.com", "datadoghq.eu", "us3.datadoghq.com", "us5.datadoghq.com", "ap1.datadoghq.com", "ddog-gov.com"}
+
+type DatadogEvent struct {
+	Title          string   `json:"title"`
+	Text           string   `json:"text"`
+	Tags           []string `json:"tags"`
+	AlertType      string   `json:"alert_type"`
+	AggregationKey string   `json:"aggregation_key,omitempty"`
+	SourceTypeName string   `json:"source_type_name,omitempty"`
+}
+
+type DatadogNotifier struct {
+	APIKey   string
+	Endpoint string
+	client   *http.Client
+}
+
+func NewDatadogNotifier(config Config) (*DatadogNotifier, error) {
+	apiKey := firstNonEmpty(config.DatadogAPIKey, os.Getenv("DD_API_KEY"))
+	if apiKey == "" {
+		return nil, fmt.Errorf("--datadog requires an API key (--datadog-api-key, TFCYCLE_DATADOG_API_KEY or DD_API_KEY)")
+	}
+	
+	site := firstNonEmpty(config.DatadogSite, os.Getenv("DD_SITE"), defaultDatadogSite)
+	if !slices.Contains(datadogSites, site) {
+		return nil, fmt.Errorf("unknown Datadog site %q (use one of %s)", site, strings.Join(datadogSites, ", "))
+	}
+	return &DatadogNotifier{
+		APIKey:   apiKey,
+		Endpoint: "https://api." + site + "/api/v1/events",
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func NewDatadogEvent(entry HistoryEntry) DatadogEvent {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("tfcycle found a dependency cycle of %d resources", len(entry.Nodes)))
+	if entry.Dir != "" {
+		text.WriteString(" in " + entry.Dir)
+	}
+	text.WriteString(".\n\nMinimal cycles:\n")
+	for _, cycle := range entry.Cycles {
+		if len(cycle) > 0 {
+			text.WriteString(fmt.Sprintf("- %s → %s\n", strings.Join(cycle, " → "), cycle[0]))
+		}
+	}
+	
+	tags := []string{"severity:" + entry.Severity, "fingerprint:" + entry.Fingerprint}
+	if entry.Workspace != "" {
+		tags = append([]string{"workspace:" + entry.Workspace}, tags...)
+	}
+	
+	title := "Terraform dependency cycle (" + entry.Severity + ")"
+	if entry.Dir != "" {
+		title += " in " + filepath.Base(entry.Dir)
+	}
+	
+	return DatadogEvent{
+		Title:          title,
+		Text:           text.String(),
+		Tags:           tags,
+		AlertType:      datadogAlertType(entry.Severity),
+		AggregationKey: entry.Fingerprint,
+		SourceTypeName: "terraform",
+	}
+}
+
+func datadogAlertType(severity string) string {
+	switch severity {
+	case "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+func (dn *DatadogNotifier) Send(ctx context.Context, event DatadogEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, dn.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("DD-API-KEY", dn.APIKey)
+	
+	response, err := dn.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	
+	if response.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("Datadog returned %s: %s", response.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func notifyDatadog(entry HistoryEntry, config Config) {
+	if !config.Datadog {
+		return
+	}
+	
+	notifier, err := NewDatadogNotifier(config)
+	if err != nil {
+		logger.Warn("skipping Datadog event", "error", err)
+		return
+	}
+	
+	stop := showProgress(config, "Sending Datadog event")
+	err = notifier.Send(context.Background(), NewDatadogEvent(entry))
+	stop()
+	if err != nil {
+		logger.Warn("failed to send Datadog event", "error", err)
+		return
+	}
+	logger.Debug("sent Datadog event", "fingerprint", entry.Fingerprint)
+}
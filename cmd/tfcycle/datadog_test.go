@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewDatadogEvent(t *testing.T) {
+	entry := HistoryEntry{
+		Fingerprint: "0123456789ab",
+		Dir:         "/srv/infra/prod",
+		Workspace:   "prod",
+		Severity:    "high",
+		Nodes:       []string{"aws_instance.web", "aws_security_group.web"},
+		Cycles:      [][]string{{"aws_instance.web", "aws_security_group.web"}},
+	}
+	
+	event := NewDatadogEvent(entry)
+	
+	expectedTags := []string{"workspace:prod", "severity:high", "fingerprint:0123456789ab"}
+	if strings.Join(event.Tags, ",") != strings.Join(expectedTags, ",") {
+		t.Errorf("Expected tags %v, got %v", expectedTags, event.Tags)
+	}
+	if event.AlertType != "error" {
+		t.Errorf("Expected alert type error for high severity, got %s", event.AlertType)
+	}
+	if event.AggregationKey != "0123456789ab" {
+		t.Errorf("Expected fingerprint as aggregation key, got %s", event.AggregationKey)
+	}
+	if !strings.Contains(event.Title, "prod") || !strings.Contains(event.Text, "aws_instance.web → aws_security_group.web → aws_instance.web") {
+		t.Errorf("Expected directory in title and cycle in text, got %q / %q", event.Title, event.Text)
+	}
+}
+
+func TestDatadogNotifier_Send(t *testing.T) {
+	var received DatadogEvent
+	var apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("DD-API-KEY")
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	
+	t.Setenv("DD_SITE", "")
+	notifier, err := NewDatadogNotifier(Config{DatadogAPIKey: "secret"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if notifier.Endpoint != "https://api.datadoghq.com/api/v1/events" {
+		t.Errorf("Expected US1 endpoint by default, got %s", notifier.Endpoint)
+	}
+	notifier.Endpoint = server.URL
+	
+	if err := notifier.Send(context.Background(), DatadogEvent{Title: "cycle", Tags: []string{"severity:low"}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if apiKey != "secret" || received.Title != "cycle" {
+		t.Errorf("Expected event with API key, got key %q and %+v", apiKey, received)
+	}
+}
+
+func TestDatadogNotifier_Errors(t *testing.T) {
+	t.Setenv("DD_API_KEY", "")
+	if _, err := NewDatadogNotifier(Config{}); err == nil {
+		t.Errorf("Expected error without an API key")
+	}
+	
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors": ["Forbidden"]}`))
+	}))
+	defer server.Close()
+	
+	if _, err := NewDatadogNotifier(Config{DatadogAPIKey: "secret", DatadogSite: "evil.example/x?"}); err == nil {
+		t.Errorf("Expected error for an unknown Datadog site")
+	}
+	
+	notifier, _ := NewDatadogNotifier(Config{DatadogAPIKey: "bad", DatadogSite: "datadoghq.eu"})
+	if notifier.Endpoint != "https://api.datadoghq.eu/api/v1/events" {
+		t.Errorf("Expected EU endpoint, got %s", notifier.Endpoint)
+	}
+	notifier.Endpoint = server.URL
+	err := notifier.Send(context.Background(), DatadogEvent{Title: "cycle"})
+	if err == nil || !strings.Contains(err.Error(), "Forbidden") {
+		t.Errorf("Expected Datadog error message, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+var docFormats = []string{"man", "markdown"}
+
+type docFlag struct {
+	Name    string
+	Arg     string
+	Usage   string
+	Default string
+}
+
+func docFlags(fs *flag.FlagSet) []docFlag {
+	var flags []docFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		df := docFlag{Name: f.Name, Usage: f.Usage, Arg: flagArgName(f)}
+		if df.Arg != "" && f.DefValue != "" && f.DefValue != "0" && f.DefValue != "0s" {
+			df.Default = f.DefValue
+		}
+		flags = append(flags, df)
+	})
+	return flags
+}
+
+func GenerateDocs(format string, fs *flag.FlagSet) (string, error) {
+	switch format {
+	case "man":
+		return manPage(docFlags(fs)), nil
+	case "markdown":
+		return markdownReference(docFlags(fs)), nil
+	default:
+		return "", fmt.Errorf("unsupported docs format %q (use %s)", format, strings.Join(docFormats, " or "))
+	}
+}
+
+func manPage(flags []docFlag) string {
+	var output strings.Builder
+	
+	output.WriteString(fmt.Sprintf(".TH TFCYCLE 1 \"\" \"tfcycle %s\" \"User Commands\"\n", version))
+	output.WriteString(".SH NAME\n")
+	output.WriteString("tfcycle \\- Terraform cycle error analyzer\n")
+	output.WriteString(".SH SYNOPSIS\n")
+	output.WriteString(".B tfcycle\n")
+	output.WriteString("[\\fICOMMAND\\fR] [\\fIOPTIONS\\fR] [\\fIARGS\\fR]\n")
+	output.WriteString(".SH DESCRIPTION\n")
+	output.WriteString("tfcycle parses Terraform cycle error messages and provides clear, actionable\n")
+	output.WriteString("analysis of dependency cycles in Infrastructure as Code. It identifies minimal\n")
+	output.WriteString("cycles and suggests common solutions.\n")
+	
+	output.WriteString(".SH COMMANDS\n")
+	for _, command := range commands {
+		output.WriteString(".TP\n")
+		output.WriteString(fmt.Sprintf(".B %s\n", command.Name))
+		output.WriteString(roffLine(roffEscape(command.Summary)))
+	}
+	
+	output.WriteString(".SH OPTIONS\n")
+	for _, f := range flags {
+		output.WriteString(".TP\n")
+		if f.Arg != "" {
+			output.WriteString(fmt.Sprintf("\\fB\\-\\-%s\\fR \\fI%s\\fR\n", roffEscape(f.Name), roffEscape(f.Arg)))
+		} else {
+			output.WriteString(fmt.Sprintf("\\fB\\-\\-%s\\fR\n", roffEscape(f.Name)))
+		}
+		usage := roffEscape(f.Usage)
+		if f.Default != "" {
+			usage += fmt.Sprintf(" (default: %s)", roffEscape(f.Default))
+		}
+		output.WriteString(roffLine(usage))
+	}
+	
+	output.WriteString(".SH ENVIRONMENT\n")
+	output.WriteString("Every option can also be set as\n")
+	output.WriteString(".BR TFCYCLE_ \\fIOPTION\\fR,\n")
+	output.WriteString("for example\n")
+	output.WriteString(".B TFCYCLE_FORMAT=json\n")
+	output.WriteString("or\n")
+	output.WriteString(".BR TFCYCLE_NO_COLOR=1 .\n")
+	output.WriteString("Command-line flags override environment variables, which override the configuration file.\n")
+	
+	output.WriteString(".SH FILES\n")
+	output.WriteString(".TP\n")
+	output.WriteString(".I .tfcycle.yaml\n")
+	output.WriteString("Project configuration in the working directory.\n")
+	output.WriteString(".TP\n")
+	output.WriteString(".I ~/.config/tfcycle/config.yaml\n")
+	output.WriteString("User configuration, used when no project configuration exists.\n")
+	
+	output.WriteString(".SH EXIT STATUS\n")
+	for _, status := range exitStatuses {
+		output.WriteString(".TP\n")
+		output.WriteString(fmt.Sprintf(".B %d\n", status.Code))
+		output.WriteString(roffLine(roffEscape(status.Meaning)))
+	}
+	
+	return output.String()
+}
+
+func roffEscape(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\e`)
+	return strings.ReplaceAll(text, "-", `\-`)
+}
+
+func roffLine(text string) string {
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		text = `\&` + text
+	}
+	return text + "\n"
+}
+
+func markdownReference(flags []docFlag) string {
+	var output strings.Builder
+	
+	output.WriteString("# tfcycle CLI reference\n\n")
+	output.WriteString("```\ntfcycle [COMMAND] [OPTIONS] [ARGS]\n```\n\n")
+	
+	output.WriteString("## Commands\n\n")
+	output.WriteString("| Command | Description |\n")
+	output.WriteString("|---------|-------------|\n")
+	for _, command := range commands {
+		output.WriteString(fmt.Sprintf("| `%s` | %s |\n", command.Name, markdownEscape(command.Summary)))
+	}
+	
+	output.WriteString("\n## Options\n\n")
+	output.WriteString("| Flag | Environment | Default | Description |\n")
+	output.WriteString("|------|-------------|---------|-------------|\n")
+	for _, f := range flags {
+		name := "--" + f.Name
+		if f.Arg != "" {
+			name += " " + f.Arg
+		}
+		def := ""
+		if f.Default != "" {
+			def = "`" + f.Default + "`"
+		}
+		output.WriteString(fmt.Sprintf("| `%s` | `%s` | %s | %s |\n", markdownEscape(name), envVarName(f.Name), def, markdownEscape(f.Usage)))
+	}
+	
+	output.WriteString("\n## Exit Codes\n\n")
+	output.WriteString("| Code | Meaning |\n")
+	output.WriteString("|------|---------|\n")
+	for _, status := range exitStatuses {
+		output.WriteString(fmt.Sprintf("| %d | %s |\n", status.Code, markdownEscape(status.Meaning)))
+	}
+	
+	return output.String()
+}
+
+func markdownEscape(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func newDocsFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("format", "", "Output format")
+	fs.String("error-file", "", "Read error from file")
+	fs.String("dir", ".", "Terraform directory for watch")
+	fs.Bool("verbose", false, "Show detailed analysis")
+	return fs
+}
+
+func TestGenerateDocs_Man(t *testing.T) {
+	page, err := GenerateDocs("man", newDocsFlagSet())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	expected := []string{
+		".TH TFCYCLE 1",
+		".SH COMMANDS",
+		`\fB\-\-error\-file\fR \fIFILE\fR`,
+		`\fB\-\-verbose\fR`,
+		"(default: .)",
+		".SH EXIT STATUS",
+	}
+	for _, want := range expected {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected man page to contain %q", want)
+		}
+	}
+}
+
+func TestGenerateDocs_Markdown(t *testing.T) {
+	reference, err := GenerateDocs("markdown", newDocsFlagSet())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	if !strings.Contains(reference, `--format text\|json\|markdown\|plan`) {
+		t.Errorf("Expected format values in markdown reference, got:\n%s", reference)
+	}
+	if !strings.Contains(reference, "`TFCYCLE_ERROR_FILE`") {
+		t.Errorf("Expected environment variable names in markdown reference")
+	}
+	if !strings.Contains(reference, "| `analyze` |") {
+		t.Errorf("Expected commands in markdown reference")
+	}
+}
+
+func TestGenerateDocs_UnknownFormat(t *testing.T) {
+	if _, err := GenerateDocs("html", newDocsFlagSet()); err == nil {
+		t.Errorf("Expected error for unknown docs format")
+	}
+}
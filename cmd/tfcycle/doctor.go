@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+type CheckStatus string
+
+const (
+	CheckOK   CheckStatus = "ok"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+type DoctorCheck struct {
+	Name   string      `json:"name"`
+	Status CheckStatus `json:"status"`
+	Detail string      `json:"detail"`
+	Fix    string      `json:"fix,omitempty"`
+}
+
+type Doctor struct {
+	LookPath   func(file string) (string, error)
+	Output     func(name string, args ...string) (string, error)
+	ConfigFile string
+	CacheDir   string
+}
+
+func NewDoctor(configFile string) *Doctor {
+	return &Doctor{
+		LookPath: exec.LookPath,
+		Output: func(name string, args ...string) (string, error) {
+			output, err := exec.Command(name, args...).CombinedOutput()
+			return string(output), err
+		},
+		ConfigFile: configFile,
+		CacheDir:   cacheDir(),
+	}
+}
+
+func cacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tfcycle")
+}
+
+func (d *Doctor) Run() []DoctorCheck {
+	return []DoctorCheck{
+		d.checkTool("graphviz", "dot", []string{"-V"},
+			"install Graphviz (e.g. 'brew install graphviz' or 'apt-get install graphviz') to render SVG reports"),
+		d.checkTool("terraform", "terraform", []string{"version"},
+			"install Terraform and add it to PATH; watch and run need it"),
+		d.checkTool("terragrunt", "terragrunt", []string{"--version"},
+			"only needed for Terragrunt stacks; install it and add it to PATH if you use one"),
+		d.checkConfigFile(),
+		d.checkCacheDir(),
+	}
+}
+
+func (d *Doctor) checkTool(name, binary string, versionArgs []string, fix string) DoctorCheck {
+	path, err := d.LookPath(binary)
+	if err != nil {
+		return DoctorCheck{Name: name, Status: CheckWarn, Detail: fmt.Sprintf("'%s' not found on PATH", binary), Fix: fix}
+	}
+	
+	output, err := d.Output(path, versionArgs...)
+	if err != nil {
+		return DoctorCheck{
+			Name:   name,
+			Status: CheckFail,
+			Detail: fmt.Sprintf("%s failed to report its version: %v", path, err),
+			Fix:    fmt.Sprintf("check that %s is a working %s installation", path, name),
+		}
+	}
+	
+	version, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	return DoctorCheck{Name: name, Status: CheckOK, Detail: fmt.Sprintf("%s (%s)", version, path)}
+}
+
+func (d *Doctor) checkConfigFile() DoctorCheck {
+	fileConfig, path, err := LoadFileConfig(d.ConfigFile)
+	if err != nil {
+		return DoctorCheck{Name: "config", Status: CheckFail, Detail: strings.Join(strings.Fields(err.Error()), " "), Fix: "fix the YAML or remove unknown keys; see 'Configuration File' in the README"}
+	}
+	if path == "" {
+		return DoctorCheck{Name: "config", Status: CheckOK, Detail: "no configuration file found; using defaults"}
+	}
+	
+	var problems []string
+	if fileConfig.Format != "" && !slices.Contains(flagValues["format"], fileConfig.Format) {
+		if _, ok := findFormatPlugin(fileConfig.Format); !ok {
+			problems = append(problems, fmt.Sprintf("format: unknown format %q (use %s or a tfcycle-format-* plugin)", fileConfig.Format, strings.Join(flagValues["format"], ", ")))
+		}
+	}
+	if fileConfig.FailOn != "" {
+		if _, err := tfcycle.ParseSeverity(fileConfig.FailOn); err != nil {
+			problems = append(problems, "fail_on: "+err.Error())
+		}
+	}
+	if fileConfig.Lang != "" {
+		if _, err := tfcycle.ResolveLanguage(fileConfig.Lang); err != nil {
+			problems = append(problems, "lang: "+err.Error())
+		}
+	}
+	if err := tfcycle.ValidateRuleIDs(fileConfig.DisabledRules, nil); err != nil {
+		problems = append(problems, "disabled_rules: "+err.Error())
+	}
+	if err := tfcycle.ValidateRuleIDs(fileConfig.EnabledRules, nil); err != nil {
+		problems = append(problems, "enabled_rules: "+err.Error())
+	}
+	if err := tfcycle.ValidateRuleIDs(fileConfig.Suppress, nil); err != nil {
+		problems = append(problems, "suppress: "+err.Error())
+	}
+	if fileConfig.DatadogSite != "" && !slices.Contains(datadogSites, fileConfig.DatadogSite) {
+		problems = append(problems, fmt.Sprintf("datadog_site: unknown Datadog site %q (use one of %s)", fileConfig.DatadogSite, strings.Join(datadogSites, ", ")))
+	}
+	if _, err := tfcycle.ParseNodeFilter(nil, fileConfig.Exclude); err != nil {
+		problems = append(problems, "exclude: "+err.Error())
+	}
+	
+	if len(problems) > 0 {
+		return DoctorCheck{Name: "config", Status: CheckFail, Detail: path + ": " + strings.Join(problems, "; "), Fix: "correct the listed keys in " + path}
+	}
+	return DoctorCheck{Name: "config", Status: CheckOK, Detail: path + " is valid"}
+}
+
+func (d *Doctor) checkCacheDir() DoctorCheck {
+	if d.CacheDir == "" {
+		return DoctorCheck{Name: "cache", Status: CheckWarn, Detail: "cannot determine the user cache directory", Fix: "set HOME or XDG_CACHE_HOME"}
+	}
+	
+	info, err := os.Stat(d.CacheDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return DoctorCheck{Name: "cache", Status: CheckOK, Detail: d.CacheDir + " (not created yet)"}
+	}
+	if err != nil {
+		return DoctorCheck{Name: "cache", Status: CheckFail, Detail: err.Error(), Fix: "check the permissions of " + d.CacheDir}
+	}
+	if !info.IsDir() {
+		return DoctorCheck{Name: "cache", Status: CheckFail, Detail: d.CacheDir + " is not a directory", Fix: "remove " + d.CacheDir}
+	}
+	
+	probe, err := os.CreateTemp(d.CacheDir, ".doctor-*")
+	if err != nil {
+		return DoctorCheck{Name: "cache", Status: CheckFail, Detail: d.CacheDir + " is not writable", Fix: "run 'chmod u+w " + d.CacheDir + "' or remove it"}
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	
+	return DoctorCheck{Name: "cache", Status: CheckOK, Detail: d.CacheDir + " is writable"}
+}
+
+const (
+	colorReset = "\033[0m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorBold  = "\033[1m"
+)
+
+func FormatDoctorChecks(checks []DoctorCheck, color bool) string {
+	paint := func(code, text string) string {
+		if !color || code == "" {
+			return text
+		}
+		return code + text + colorReset
+	}
+	
+	var output strings.Builder
+	output.WriteString(paint(colorBold, "🩺 TFCYCLE DOCTOR") + "\n")
+	output.WriteString("=================\n\n")
+	
+	for _, check := range checks {
+		marker, code := "✓", colorGreen
+		switch check.Status {
+		case CheckWarn:
+			marker, code = "!", ""
+		case CheckFail:
+			marker, code = "✗", colorRed
+		}
+		output.WriteString(paint(code, fmt.Sprintf("%s %-11s %s", marker, check.Name, check.Detail)) + "\n")
+		if check.Fix != "" {
+			output.WriteString(fmt.Sprintf("    fix: %s\n", check.Fix))
+		}
+	}
+	
+	return output.String()
+}
+
+func doctorFailures(checks []DoctorCheck) int {
+	failures := 0
+	for _, check := range checks {
+		if check.Status == CheckFail {
+			failures++
+		}
+	}
+	return failures
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestDoctor(t *testing.T) *Doctor {
+	dir := t.TempDir()
+	return &Doctor{
+		LookPath: func(file string) (string, error) {
+			if file == "terragrunt" {
+				return "", errors.New("not found")
+			}
+			return "/usr/bin/" + file, nil
+		},
+		Output: func(name string, args ...string) (string, error) {
+			return filepath.Base(name) + " version 1.2.3\nmore details\n", nil
+		},
+		ConfigFile: filepath.Join(dir, "missing.yaml"),
+		CacheDir:   filepath.Join(dir, "cache"),
+	}
+}
+
+func findCheck(checks []DoctorCheck, name string) DoctorCheck {
+	for _, check := range checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	return DoctorCheck{}
+}
+
+func TestDoctor_Tools(t *testing.T) {
+	doctor := newTestDoctor(t)
+	doctor.ConfigFile = ""
+	checks := doctor.Run()
+	
+	terraform := findCheck(checks, "terraform")
+	if terraform.Status != CheckOK || terraform.Detail != "terraform version 1.2.3 (/usr/bin/terraform)" {
+		t.Errorf("Expected terraform version on the first output line, got %+v", terraform)
+	}
+	
+	terragrunt := findCheck(checks, "terragrunt")
+	if terragrunt.Status != CheckWarn || terragrunt.Fix == "" {
+		t.Errorf("Expected missing terragrunt to warn with a fix, got %+v", terragrunt)
+	}
+}
+
+func TestDoctor_ConfigFile(t *testing.T) {
+	doctor := newTestDoctor(t)
+	
+	if check := doctor.checkConfigFile(); check.Status != CheckFail {
+		t.Errorf("Expected missing explicit config to fail, got %+v", check)
+	}
+	
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("fail_on: extreme\nformat: json\n"), 0644)
+	doctor.ConfigFile = path
+	check := doctor.checkConfigFile()
+	if check.Status != CheckFail || !strings.Contains(check.Detail, "fail_on") {
+		t.Errorf("Expected invalid fail_on to be reported, got %+v", check)
+	}
+	
+	os.WriteFile(path, []byte("fail_on: high\n"), 0644)
+	if check := doctor.checkConfigFile(); check.Status != CheckOK {
+		t.Errorf("Expected valid config to pass, got %+v", check)
+	}
+}
+
+func TestDoctor_CacheDir(t *testing.T) {
+	doctor := newTestDoctor(t)
+	
+	if check := doctor.checkCacheDir(); check.Status != CheckOK || !strings.Contains(check.Detail, "not created yet") {
+		t.Errorf("Expected missing cache dir to be fine, got %+v", check)
+	}
+	
+	os.WriteFile(doctor.CacheDir, []byte("x"), 0644)
+	if check := doctor.checkCacheDir(); check.Status != CheckFail {
+		t.Errorf("Expected file in place of cache dir to fail, got %+v", check)
+	}
+	
+	os.Remove(doctor.CacheDir)
+	os.Mkdir(doctor.CacheDir, 0755)
+	if check := doctor.checkCacheDir(); check.Status != CheckOK || check.Detail != doctor.CacheDir+" is writable" {
+		t.Errorf("Expected writable cache dir, got %+v", check)
+	}
+}
+
+func TestFormatDoctorChecks(t *testing.T) {
+	output := FormatDoctorChecks([]DoctorCheck{
+		{Name: "graphviz", Status: CheckWarn, Detail: "'dot' not found on PATH", Fix: "install Graphviz"},
+		{Name: "config", Status: CheckFail, Detail: "broken"},
+	}, false)
+	
+	if !strings.Contains(output, "! graphviz    'dot' not found on PATH\n    fix: install Graphviz\n") {
+		t.Errorf("Expected warning with fix, got:\n%s", output)
+	}
+	if !strings.Contains(output, "✗ config      broken\n") {
+		t.Errorf("Expected failure marker, got:\n%s", output)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestApplyEnvironment(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	format := fs.String("format", "", "")
+	noColor := fs.Bool("no-color", false, "")
+	configDir := fs.String("config-dir", "", "")
+	if err := fs.Parse([]string{"--config-dir", "cli"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	
+	env := map[string]string{
+		"TFCYCLE_FORMAT":     "json",
+		"TFCYCLE_NO_COLOR":   "1",
+		"TFCYCLE_CONFIG_DIR": "env",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	
+	if err := applyEnvironment(fs, lookup); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if *format != "json" {
+		t.Errorf("Expected format from environment, got %s", *format)
+	}
+	if !*noColor {
+		t.Errorf("Expected no-color from environment")
+	}
+	if *configDir != "cli" {
+		t.Errorf("Expected command-line flag to win, got %s", *configDir)
+	}
+}
+
+func TestApplyEnvironment_InvalidValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("max-label-length", 0, "")
+	
+	lookup := func(name string) (string, bool) {
+		return "many", name == "TFCYCLE_MAX_LABEL_LENGTH"
+	}
+	if err := applyEnvironment(fs, lookup); err == nil {
+		t.Errorf("Expected error for invalid integer")
+	}
+}
+
+func TestApplyEnvironment_SkipsVersion(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	showVersion := fs.Bool("version", false, "")
+	lookup := func(name string) (string, bool) {
+		return "1.4.0", name == "TFCYCLE_VERSION"
+	}
+	
+	if err := applyEnvironment(fs, lookup); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if *showVersion {
+		t.Errorf("Expected TFCYCLE_VERSION to be ignored")
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	ExitNoCycle       = 0
+	ExitCycleFound    = 1
+	ExitInputError    = 2
+	ExitInternalError = 3
+)
+
+var exitStatuses = []struct {
+	Code    int
+	Meaning string
+}{
+	{ExitNoCycle, "No cycle found, or the command succeeded"},
+	{ExitCycleFound, "A cycle at or above the --fail-on severity was found"},
+	{ExitInputError, "Invalid input, arguments or configuration"},
+	{ExitInternalError, "Internal error"},
+}
+
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+func inputError(err error) error {
+	return &ExitError{Code: ExitInputError, Err: err}
+}
+
+func inputErrorf(format string, args ...any) error {
+	return inputError(fmt.Errorf(format, args...))
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return ExitNoCycle
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitInternalError
+}
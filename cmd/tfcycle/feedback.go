@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+// EdgeFeedback is a recorded verdict about a hypothesized edge between two
+// resources of the configuration in Dir.
+type EdgeFeedback struct {
+	Dir       string             `json:"dir"`
+	From      string             `json:"from"`
+	To        string             `json:"to"`
+	Verdict   tfcycle.EdgeAnswer `json:"verdict"`
+	Timestamp time.Time          `json:"timestamp"`
+}
+
+// FeedbackStore keeps edge verdicts in a JSON file, one verdict per directory
+// and edge.
+type FeedbackStore struct {
+	Path string
+}
+
+func NewFeedbackStore() *FeedbackStore {
+	dir := dataDir()
+	if dir == "" {
+		return &FeedbackStore{}
+	}
+	return &FeedbackStore{Path: filepath.Join(dir, "feedback.json")}
+}
+
+func (fs *FeedbackStore) List() ([]EdgeFeedback, error) {
+	content, err := os.ReadFile(fs.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	
+	var entries []EdgeFeedback
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", fs.Path, err)
+	}
+	return entries, nil
+}
+
+// Record saves feedback, replacing any earlier verdict about the same edge in
+// the same directory.
+func (fs *FeedbackStore) Record(feedback EdgeFeedback) error {
+	if fs.Path == "" {
+		return fmt.Errorf("cannot determine the data directory")
+	}
+	entries, err := fs.List()
+	if err != nil {
+		return err
+	}
+	
+	kept := []EdgeFeedback{}
+	for _, entry := range entries {
+		if entry.Dir != feedback.Dir || entry.From != feedback.From || entry.To != feedback.To {
+			kept = append(kept, entry)
+		}
+	}
+	kept = append(kept, feedback)
+	
+	if err := os.MkdirAll(filepath.Dir(fs.Path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	jsonData, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode feedback: %w", err)
+	}
+	return os.WriteFile(fs.Path, append(jsonData, '\n'), 0600)
+}
+
+// Verdicts returns the verdicts recorded for dir.
+func (fs *FeedbackStore) Verdicts(dir string) (tfcycle.EdgeVerdicts, error) {
+	entries, err := fs.List()
+	if err != nil {
+		return nil, err
+	}
+	
+	verdicts := make(tfcycle.EdgeVerdicts)
+	for _, entry := range entries {
+		if entry.Dir == dir {
+			verdicts[tfcycle.Edge{From: entry.From, To: entry.To}] = entry.Verdict
+		}
+	}
+	return verdicts, nil
+}
+
+// feedbackDir returns the directory verdicts are recorded for: --config-dir
+// or the working directory.
+func feedbackDir(config Config) string {
+	dir := config.ConfigDir
+	if dir == "" {
+		dir = "."
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return dir
+}
+
+// feedbackOptions returns the analyzer option applying the verdicts recorded
+// for the current directory, unless --no-feedback is set.
+func feedbackOptions(config Config) []tfcycle.Option {
+	if config.NoFeedback {
+		return nil
+	}
+	
+	verdicts, err := NewFeedbackStore().Verdicts(feedbackDir(config))
+	if err != nil {
+		logger.Warn("ignoring recorded edge feedback", "error", err)
+		return nil
+	}
+	if len(verdicts) == 0 {
+		return nil
+	}
+	logger.Debug("applying recorded edge feedback", "verdicts", len(verdicts))
+	return []tfcycle.Option{tfcycle.WithEdgeVerdicts(verdicts)}
+}
+
+func runFeedback(config Config) error {
+	if len(config.Args) == 0 {
+		return inputErrorf("feedback requires a subcommand: confirm \"FROM -> TO\", deny \"FROM -> TO\" or list")
+	}
+	
+	store := NewFeedbackStore()
+	dir := feedbackDir(config)
+	
+	var verdict tfcycle.EdgeAnswer
+	switch config.Args[0] {
+	case "confirm":
+		verdict = tfcycle.EdgeConfirmed
+	case "deny":
+		verdict = tfcycle.EdgeRejected
+	case "list":
+		entries, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to read feedback: %w", err)
+		}
+		return writeFeedbackList(entries, dir, config)
+	default:
+		return inputErrorf("unknown feedback subcommand %q (use confirm, deny or list)", config.Args[0])
+	}
+	
+	if len(config.Args) != 2 {
+		return inputErrorf("feedback %s requires one edge, e.g. \"aws_instance.web -> aws_security_group.web\"", config.Args[0])
+	}
+	edge, err := tfcycle.ParseEdge(config.Args[1])
+	if err != nil {
+		return inputError(err)
+	}
+	
+	feedback := EdgeFeedback{Dir: dir, From: edge.From, To: edge.To, Verdict: verdict, Timestamp: time.Now().UTC()}
+	if err := store.Record(feedback); err != nil {
+		return fmt.Errorf("failed to record feedback: %w", err)
+	}
+	if !config.Quiet {
+		fmt.Fprintf(os.Stderr, "Recorded %s as %s for %s\n", edge, verdict, dir)
+	}
+	return nil
+}
+
+func writeFeedbackList(entries []EdgeFeedback, dir string, config Config) error {
+	current := []EdgeFeedback{}
+	for _, entry := range entries {
+		if entry.Dir == dir {
+			current = append(current, entry)
+		}
+	}
+	
+	if outputFormat(config) == "json" {
+		jsonData, err := json.MarshalIndent(current, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		return writeOutput(string(jsonData)+"\n", config.Output)
+	}
+	
+	var output strings.Builder
+	if len(current) == 0 {
+		output.WriteString(fmt.Sprintf("No edge feedback recorded for %s\n", dir))
+	}
+	for _, entry := range current {
+		output.WriteString(fmt.Sprintf("%-10s %s -> %s\n", entry.Verdict, entry.From, entry.To))
+	}
+	return writeOutput(output.String(), config.Output)
+}
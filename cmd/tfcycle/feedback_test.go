@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func runFeedbackArgs(t *testing.T, args ...string) {
+	t.Helper()
+	config, err := parseArgs(append([]string{"feedback"}, args...))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+}
+
+func TestFeedback_RecordAndApply(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	configDir := t.TempDir()
+	
+	runFeedbackArgs(t, "confirm", "aws_security_group.web -> aws_security_group.db", "--config-dir", configDir, "--quiet")
+	runFeedbackArgs(t, "deny", "aws_security_group.web -> aws_security_group.db", "--config-dir", configDir, "--quiet")
+	runFeedbackArgs(t, "confirm", "aws_security_group.db -> aws_instance.app", "--config-dir", configDir, "--quiet")
+	
+	output := filepath.Join(t.TempDir(), "feedback.json")
+	runFeedbackArgs(t, "list", "--json", "--config-dir", configDir, "--output", output)
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []EdgeFeedback
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Verdict != tfcycle.EdgeRejected {
+		t.Errorf("Expected the later verdict to replace the earlier one, got %+v", entries)
+	}
+	
+	cycle, err := tfcycle.NewParser().ParseError("Error: Cycle: aws_security_group.web, aws_security_group.db, aws_instance.app")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	graph := tfcycle.NewCycleAnalyzer(cycle, feedbackOptions(Config{ConfigDir: configDir})...).HypothesizedGraph()
+	if slices.Contains(graph["aws_security_group.web"], "aws_security_group.db") || !slices.Contains(graph["aws_security_group.db"], "aws_instance.app") {
+		t.Errorf("Expected the recorded verdicts to be applied, got %v", graph)
+	}
+	
+	if opts := feedbackOptions(Config{ConfigDir: configDir, NoFeedback: true}); len(opts) != 0 {
+		t.Errorf("Expected no options with --no-feedback, got %d", len(opts))
+	}
+	if opts := feedbackOptions(Config{ConfigDir: t.TempDir()}); len(opts) != 0 {
+		t.Errorf("Expected no options for a directory without feedback, got %d", len(opts))
+	}
+}
+
+func TestFeedback_InvalidEdge(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	config, err := parseArgs([]string{"feedback", "deny", "aws_instance.web"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error, got: %v", err)
+	}
+}
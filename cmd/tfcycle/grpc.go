@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"tfcycle/pkg/tfcycle"
+
+	tfcyclev1 "tfcycle/proto/tfcycle/v1"
+)
+
+//go:generate protoc -I ../../proto --go_out=../../proto --go_opt=paths=source_relative --go-grpc_out=../../proto --go-grpc_opt=paths=source_relative ../../proto/tfcycle/v1/tfcycle.proto
+
+type grpcService struct {
+	tfcyclev1.UnimplementedTfcycleServiceServer
+	config Config
+}
+
+func NewGRPCServer(config Config) *grpc.Server {
+	server := grpc.NewServer(grpc.MaxRecvMsgSize(maxRequestBytes))
+	tfcyclev1.RegisterTfcycleServiceServer(server, &grpcService{config: config})
+	return server
+}
+
+func (gs *grpcService) analyze(ctx context.Context, text string) (*tfcycle.CycleAnalyzer, error) {
+	analyzer, err := analyzeServeInput(ctx, gs.config, text)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return analyzer, nil
+}
+
+func (gs *grpcService) Analyze(ctx context.Context, req *tfcyclev1.AnalyzeRequest) (*tfcyclev1.AnalyzeResponse, error) {
+	analyzer, err := gs.analyze(ctx, req.GetErrorText())
+	if err != nil {
+		return nil, err
+	}
+	
+	formatter := tfcycle.NewOutputFormatter(analyzer, gs.config.Verbose)
+	lang, err := tfcycle.ResolveLanguage(firstNonEmpty(req.GetLang(), gs.config.Lang))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	formatter.SetLanguage(lang)
+	
+	cycles := analyzer.FindMinimalCycles()
+	resp := &tfcyclev1.AnalyzeResponse{
+		MinimalCycles: protoCycles(cycles),
+		Severity:      protoSeverity(analyzer.Severity()),
+		Report:        formatter.FormatAnalysis(),
+		Partial:       analyzer.Partial,
+	}
+	for _, node := range analyzer.Cycle().Nodes {
+		resp.Nodes = append(resp.Nodes, protoNode(node))
+	}
+	if len(cycles) > 0 {
+		resp.Suggestions = protoSuggestions(analyzer.GenerateSuggestions(cycles[0]))
+		resp.Documentation = analyzer.DocumentationLinks(cycles[0])
+	}
+	return resp, nil
+}
+
+func (gs *grpcService) Visualize(ctx context.Context, req *tfcyclev1.VisualizeRequest) (*tfcyclev1.VisualizeResponse, error) {
+	opts := tfcycle.DefaultVisualOptions()
+	if req.GetScope() != "" {
+		opts.Scope = req.GetScope()
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	
+	analyzer, err := gs.analyze(ctx, req.GetErrorText())
+	if err != nil {
+		return nil, err
+	}
+	return &tfcyclev1.VisualizeResponse{Dot: tfcycle.NewOutputFormatter(analyzer, false).GenerateVisualization(opts)}, nil
+}
+
+func (gs *grpcService) Explain(ctx context.Context, req *tfcyclev1.ExplainRequest) (*tfcyclev1.ExplainResponse, error) {
+	if req.GetAddress() == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+	
+	analyzer, err := gs.analyze(ctx, req.GetErrorText())
+	if err != nil {
+		return nil, err
+	}
+	
+	nodes := analyzer.MatchNodes(req.GetAddress())
+	if len(nodes) == 0 {
+		return nil, status.Errorf(codes.NotFound, "resource %s is not part of the cycle", req.GetAddress())
+	}
+	
+	resp := &tfcyclev1.ExplainResponse{}
+	for _, node := range nodes {
+		explanation := analyzer.ExplainNode(node)
+		resp.Explanations = append(resp.Explanations, &tfcyclev1.Explanation{
+			Node:          protoNode(explanation.Node),
+			Cycles:        protoCycles(explanation.Cycles),
+			DependsOn:     protoEdges(explanation.Outgoing),
+			DependedOnBy:  protoEdges(explanation.Incoming),
+			Suggestions:   protoSuggestions(explanation.Suggestions),
+			Documentation: explanation.Documentation,
+		})
+	}
+	return resp, nil
+}
+
+func protoNode(node *tfcycle.CycleNode) *tfcyclev1.Node {
+	result := &tfcyclev1.Node{
+		Address:      node.Address(),
+		ResourceType: node.ResourceType,
+		ResourceName: node.ResourceName,
+		ModulePath:   node.ModulePath,
+		InstanceKey:  node.InstanceKey,
+		Action:       node.Action.String(),
+	}
+	if node.Location != nil {
+		result.Location = node.Location.String()
+	}
+	return result
+}
+
+func protoCycles(cycles [][]string) []*tfcyclev1.Cycle {
+	result := make([]*tfcyclev1.Cycle, len(cycles))
+	for i, cycle := range cycles {
+		result[i] = &tfcyclev1.Cycle{Addresses: cycle}
+	}
+	return result
+}
+
+func protoEdges(edges []tfcycle.EdgeReason) []*tfcyclev1.Edge {
+	result := make([]*tfcyclev1.Edge, len(edges))
+	for i, edge := range edges {
+		result[i] = &tfcyclev1.Edge{From: edge.From, To: edge.To, Reason: edge.Reason}
+	}
+	return result
+}
+
+// protoSuggestions returns the suggestion titles; the v1 API carries
+// suggestions as plain strings.
+func protoSuggestions(suggestions []tfcycle.Suggestion) []string {
+	result := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		result[i] = suggestion.Title
+	}
+	return result
+}
+
+func protoSeverity(severity tfcycle.Severity) tfcyclev1.Severity {
+	switch severity {
+	case tfcycle.SeverityLow:
+		return tfcyclev1.Severity_SEVERITY_LOW
+	case tfcycle.SeverityMedium:
+		return tfcyclev1.Severity_SEVERITY_MEDIUM
+	case tfcycle.SeverityHigh:
+		return tfcyclev1.Severity_SEVERITY_HIGH
+	default:
+		return tfcyclev1.Severity_SEVERITY_UNSPECIFIED
+	}
+}
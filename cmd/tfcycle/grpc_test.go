@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	tfcyclev1 "tfcycle/proto/tfcycle/v1"
+)
+
+func newTestGRPCClient(t *testing.T) tfcyclev1.TfcycleServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(Config{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Expected no error dialing, got: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	
+	return tfcyclev1.NewTfcycleServiceClient(conn)
+}
+
+func TestGRPC_Analyze(t *testing.T) {
+	client := newTestGRPCClient(t)
+	
+	resp, err := client.Analyze(context.Background(), &tfcyclev1.AnalyzeRequest{ErrorText: serveTestError})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(resp.GetNodes()) != 2 || len(resp.GetMinimalCycles()) != 1 {
+		t.Errorf("Expected 2 nodes and 1 minimal cycle, got %v", resp)
+	}
+	if resp.GetSeverity() != tfcyclev1.Severity_SEVERITY_LOW {
+		t.Errorf("Expected low severity, got %v", resp.GetSeverity())
+	}
+	if resp.GetDocumentation()["aws_security_group"] == "" {
+		t.Errorf("Expected documentation link for aws_security_group, got %v", resp.GetDocumentation())
+	}
+	
+	_, err = client.Analyze(context.Background(), &tfcyclev1.AnalyzeRequest{ErrorText: "Plan: 1 to add"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for input without a cycle, got %v", err)
+	}
+}
+
+func TestGRPC_VisualizeAndExplain(t *testing.T) {
+	client := newTestGRPCClient(t)
+	
+	visual, err := client.Visualize(context.Background(), &tfcyclev1.VisualizeRequest{ErrorText: serveTestError, Scope: "full"})
+	if err != nil || visual.GetDot() == "" {
+		t.Errorf("Expected DOT output, got %v (%v)", visual, err)
+	}
+	
+	explained, err := client.Explain(context.Background(), &tfcyclev1.ExplainRequest{ErrorText: serveTestError, Address: "aws_security_group.a"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(explained.GetExplanations()) != 1 || explained.GetExplanations()[0].GetNode().GetAddress() != "aws_security_group.a" {
+		t.Errorf("Expected explanation for aws_security_group.a, got %v", explained)
+	}
+	
+	_, err = client.Explain(context.Background(), &tfcyclev1.ExplainRequest{ErrorText: serveTestError, Address: "aws_instance.web"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for unknown address, got %v", err)
+	}
+}
@@ -0,0 +1,444 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const historyTimeFormat = "20060102T150405Z"
+
+type HistoryEntry struct {
+	ID          string     `json:"id"`
+	Fingerprint string     `json:"fingerprint"`
+	Timestamp   time.Time  `json:"timestamp"`
+	Dir         string     `json:"dir,omitempty"`
+	Workspace   string     `json:"workspace,omitempty"`
+	Severity    string     `json:"severity"`
+	Nodes       []string   `json:"nodes"`
+	Cycles      [][]string `json:"cycles"`
+}
+
+type HistoryStore interface {
+	Save(entry HistoryEntry) error
+	List() ([]HistoryEntry, error)
+}
+
+type History struct {
+	Store HistoryStore
+}
+
+func NewHistory(config Config) (*History, error) {
+	if config.HistoryDB != "" {
+		store, err := OpenSQLiteHistory(config.HistoryDB)
+		if err != nil {
+			return nil, err
+		}
+		return &History{Store: store}, nil
+	}
+	return &History{Store: &FileHistoryStore{Dir: historyDir()}}, nil
+}
+
+func (h *History) Close() error {
+	if closer, ok := h.Store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// dataDir returns the directory for tfcycle's local data, or "" if there is
+// no home directory.
+func dataDir() string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "tfcycle")
+}
+
+func historyDir() string {
+	dir := dataDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "history")
+}
+
+func CycleFingerprint(nodes []string) string {
+	sorted := append([]string{}, nodes...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+func NewHistoryEntry(analyzer *tfcycle.CycleAnalyzer, dir, workspace string, now time.Time) HistoryEntry {
+	nodes := analyzer.NodeNames()
+	fingerprint := CycleFingerprint(nodes)
+	now = now.UTC()
+	
+	return HistoryEntry{
+		ID:          now.Format(historyTimeFormat) + "-" + fingerprint,
+		Fingerprint: fingerprint,
+		Timestamp:   now,
+		Dir:         dir,
+		Workspace:   workspace,
+		Severity:    analyzer.Severity().String(),
+		Nodes:       nodes,
+		Cycles:      analyzer.FindMinimalCycles(),
+	}
+}
+
+func terraformWorkspace(dir string) string {
+	if workspace := os.Getenv("TF_WORKSPACE"); workspace != "" {
+		return workspace
+	}
+	if content, err := os.ReadFile(filepath.Join(dir, ".terraform", "environment")); err == nil {
+		return strings.TrimSpace(string(content))
+	}
+	if info, err := os.Stat(filepath.Join(dir, ".terraform")); err == nil && info.IsDir() {
+		return "default"
+	}
+	return ""
+}
+
+type FileHistoryStore struct {
+	Dir string
+}
+
+func (fs *FileHistoryStore) Save(entry HistoryEntry) error {
+	if fs.Dir == "" {
+		return fmt.Errorf("cannot determine the history directory")
+	}
+	if err := os.MkdirAll(fs.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	
+	jsonData, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	return os.WriteFile(filepath.Join(fs.Dir, entry.ID+".json"), append(jsonData, '\n'), 0600)
+}
+
+func (fs *FileHistoryStore) List() ([]HistoryEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(fs.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	
+	entries := make([]HistoryEntry, 0, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var entry HistoryEntry
+		if err := json.Unmarshal(content, &entry); err != nil {
+			logger.Warn("skipping unreadable history entry", "file", path, "error", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (h *History) Save(entry HistoryEntry) error {
+	return h.Store.Save(entry)
+}
+
+func (h *History) List() ([]HistoryEntry, error) {
+	entries, err := h.Store.List()
+	if err != nil {
+		return nil, err
+	}
+	
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+	return entries, nil
+}
+
+func (h *History) Find(ref string) (HistoryEntry, error) {
+	entries, err := h.List()
+	if err != nil {
+		return HistoryEntry{}, err
+	}
+	
+	var matches []HistoryEntry
+	for _, entry := range entries {
+		if entry.ID == ref {
+			return entry, nil
+		}
+		if strings.HasPrefix(entry.ID, ref) {
+			matches = append(matches, entry)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if len(matches) > 1 {
+		return HistoryEntry{}, fmt.Errorf("%q matches %d entries; use a longer ID", ref, len(matches))
+	}
+	
+	for _, entry := range entries {
+		if entry.Fingerprint == ref {
+			return entry, nil
+		}
+	}
+	return HistoryEntry{}, fmt.Errorf("no history entry matches %q", ref)
+}
+
+func (h *History) Last(dir, workspace string) (HistoryEntry, bool, error) {
+	entries, err := h.List()
+	if err != nil {
+		return HistoryEntry{}, false, err
+	}
+	
+	for _, entry := range entries {
+		if entry.Dir == dir && entry.Workspace == workspace {
+			return entry, true, nil
+		}
+	}
+	return HistoryEntry{}, false, nil
+}
+
+func CompareWithHistory(previous, current HistoryEntry) tfcycle.HistoryComparison {
+	diff := tfcycle.DiffCycleSets(previous.Nodes, current.Nodes, previous.Cycles, current.Cycles)
+	
+	trend := "changed"
+	switch {
+	case diff.NewNodes < diff.OldNodes:
+		trend = "progress"
+	case diff.NewNodes > diff.OldNodes:
+		trend = "regression"
+	case !diff.Changed():
+		trend = "unchanged"
+	}
+	
+	return tfcycle.HistoryComparison{
+		PreviousID:        previous.ID,
+		PreviousTimestamp: previous.Timestamp,
+		Trend:             trend,
+		Diff:              diff,
+	}
+}
+
+func currentHistoryEntry(analyzer *tfcycle.CycleAnalyzer, config Config) HistoryEntry {
+	dir := config.ConfigDir
+	if dir == "" {
+		dir = "."
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	
+	return NewHistoryEntry(analyzer, dir, terraformWorkspace(dir), time.Now())
+}
+
+func compareLast(entry HistoryEntry, config Config) *tfcycle.HistoryComparison {
+	history, err := NewHistory(config)
+	if err != nil {
+		logger.Warn("failed to open history", "error", err)
+		return nil
+	}
+	defer history.Close()
+	
+	previous, found, err := history.Last(entry.Dir, entry.Workspace)
+	if err != nil {
+		logger.Warn("failed to read history", "error", err)
+		return nil
+	}
+	if !found {
+		if !config.Quiet {
+			fmt.Fprintf(os.Stderr, "No previous analysis recorded for %s; nothing to compare against\n", entry.Dir)
+		}
+		return nil
+	}
+	
+	comparison := CompareWithHistory(previous, entry)
+	return &comparison
+}
+
+func recordHistory(entry HistoryEntry, config Config) {
+	if config.NoHistory {
+		return
+	}
+	
+	history, err := NewHistory(config)
+	if err != nil {
+		logger.Warn("failed to open history", "error", err)
+		return
+	}
+	defer history.Close()
+	
+	if err := history.Save(entry); err != nil {
+		logger.Warn("failed to record analysis in history", "error", err)
+		return
+	}
+	logger.Debug("recorded analysis in history", "id", entry.ID)
+}
+
+func FormatHistoryList(entries []HistoryEntry) string {
+	if len(entries) == 0 {
+		return "No analyses recorded yet\n"
+	}
+	
+	seen := make(map[string]int)
+	for _, entry := range entries {
+		seen[entry.Fingerprint]++
+	}
+	
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%-29s  %-20s  %5s  %4s  %-8s  %s\n", "ID", "TIME", "NODES", "SEEN", "SEVERITY", "WORKSPACE"))
+	for _, entry := range entries {
+		workspace := entry.Workspace
+		if workspace == "" {
+			workspace = "-"
+		}
+		output.WriteString(fmt.Sprintf("%-29s  %-20s  %5d  %4d  %-8s  %s\n",
+			entry.ID, entry.Timestamp.Local().Format("2006-01-02 15:04:05"), len(entry.Nodes), seen[entry.Fingerprint], entry.Severity, workspace))
+	}
+	return output.String()
+}
+
+func FormatHistoryEntry(entry HistoryEntry, occurrences int) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("ID:          %s\n", entry.ID))
+	output.WriteString(fmt.Sprintf("Fingerprint: %s (seen %d times)\n", entry.Fingerprint, occurrences))
+	output.WriteString(fmt.Sprintf("Time:        %s\n", entry.Timestamp.Local().Format(time.RFC3339)))
+	if entry.Dir != "" {
+		output.WriteString(fmt.Sprintf("Directory:   %s\n", entry.Dir))
+	}
+	if entry.Workspace != "" {
+		output.WriteString(fmt.Sprintf("Workspace:   %s\n", entry.Workspace))
+	}
+	output.WriteString(fmt.Sprintf("Severity:    %s\n", entry.Severity))
+	
+	output.WriteString(fmt.Sprintf("\nResources (%d):\n", len(entry.Nodes)))
+	for _, node := range entry.Nodes {
+		output.WriteString("  • " + node + "\n")
+	}
+	
+	if len(entry.Cycles) > 0 {
+		output.WriteString(fmt.Sprintf("\nMinimal cycles (%d):\n", len(entry.Cycles)))
+		for _, cycle := range tfcycle.CycleStrings(entry.Cycles) {
+			output.WriteString("  " + cycle + "\n")
+		}
+	}
+	return output.String()
+}
+
+func countFingerprint(entries []HistoryEntry, fingerprint string) int {
+	count := 0
+	for _, entry := range entries {
+		if entry.Fingerprint == fingerprint {
+			count++
+		}
+	}
+	return count
+}
+
+type ResourceCount struct {
+	Address  string `json:"address"`
+	Analyses int    `json:"analyses"`
+}
+
+type WeekCount struct {
+	Week     string `json:"week"`
+	Analyses int    `json:"analyses"`
+	Cycles   int    `json:"distinct_cycles"`
+}
+
+func TopResources(entries []HistoryEntry, limit int) []ResourceCount {
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		seen := make(map[string]bool)
+		for _, address := range entry.Nodes {
+			if !seen[address] {
+				seen[address] = true
+				counts[address]++
+			}
+		}
+	}
+	
+	top := make([]ResourceCount, 0, len(counts))
+	for address, count := range counts {
+		top = append(top, ResourceCount{Address: address, Analyses: count})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Analyses != top[j].Analyses {
+			return top[i].Analyses > top[j].Analyses
+		}
+		return top[i].Address < top[j].Address
+	})
+	
+	if limit > 0 && len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
+
+func CyclesPerWeek(entries []HistoryEntry) []WeekCount {
+	analyses := make(map[string]int)
+	fingerprints := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		year, week := entry.Timestamp.UTC().ISOWeek()
+		key := fmt.Sprintf("%d-W%02d", year, week)
+		analyses[key]++
+		if fingerprints[key] == nil {
+			fingerprints[key] = make(map[string]bool)
+		}
+		fingerprints[key][entry.Fingerprint] = true
+	}
+	
+	keys := make([]string, 0, len(analyses))
+	for key := range analyses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	
+	weeks := make([]WeekCount, 0, len(analyses))
+	for _, key := range keys {
+		weeks = append(weeks, WeekCount{Week: key, Analyses: analyses[key], Cycles: len(fingerprints[key])})
+	}
+	return weeks
+}
+
+func FormatTopResources(top []ResourceCount) string {
+	if len(top) == 0 {
+		return "No analyses recorded yet\n"
+	}
+	
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%8s  %s\n", "ANALYSES", "RESOURCE"))
+	for _, resource := range top {
+		output.WriteString(fmt.Sprintf("%8d  %s\n", resource.Analyses, resource.Address))
+	}
+	return output.String()
+}
+
+func FormatCyclesPerWeek(weeks []WeekCount) string {
+	if len(weeks) == 0 {
+		return "No analyses recorded yet\n"
+	}
+	
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%-8s  %8s  %6s\n", "WEEK", "ANALYSES", "CYCLES"))
+	for _, week := range weeks {
+		output.WriteString(fmt.Sprintf("%-8s  %8d  %6d\n", week.Week, week.Analyses, week.Cycles))
+	}
+	return output.String()
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const historySchema = `
+CREATE TABLE IF NOT EXISTS analyses (
+	id          TEXT PRIMARY KEY,
+	fingerprint TEXT NOT NULL,
+	timestamp   TEXT NOT NULL,
+	dir         TEXT NOT NULL DEFAULT '',
+	workspace   TEXT NOT NULL DEFAULT '',
+	severity    TEXT NOT NULL DEFAULT '',
+	nodes       TEXT NOT NULL,
+	cycles      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS analyses_fingerprint ON analyses (fingerprint);
+CREATE INDEX IF NOT EXISTS analyses_location ON analyses (dir, workspace, timestamp);
+CREATE TABLE IF NOT EXISTS analysis_nodes (
+	analysis_id TEXT NOT NULL REFERENCES analyses (id) ON DELETE CASCADE,
+	address     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS analysis_nodes_address ON analysis_nodes (address);
+`
+
+type SQLiteHistoryStore struct {
+	db *sql.DB
+}
+
+func OpenSQLiteHistory(path string) (*SQLiteHistoryStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create history database directory: %w", err)
+	}
+	
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database %s: %w", path, err)
+	}
+	if _, err := db.Exec(historySchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize history database %s: %w", path, err)
+	}
+	
+	return &SQLiteHistoryStore{db: db}, nil
+}
+
+func (s *SQLiteHistoryStore) Save(entry HistoryEntry) error {
+	nodes, err := json.Marshal(entry.Nodes)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	cycles, err := json.Marshal(entry.Cycles)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO analyses (id, fingerprint, timestamp, dir, workspace, severity, nodes, cycles)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.ID, entry.Fingerprint, entry.Timestamp.UTC().Format(time.RFC3339Nano),
+		entry.Dir, entry.Workspace, entry.Severity, string(nodes), string(cycles)); err != nil {
+		return fmt.Errorf("failed to record analysis: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM analysis_nodes WHERE analysis_id = ?`, entry.ID); err != nil {
+		return fmt.Errorf("failed to record analysis: %w", err)
+	}
+	for _, node := range entry.Nodes {
+		if _, err := tx.Exec(`INSERT INTO analysis_nodes (analysis_id, address) VALUES (?, ?)`, entry.ID, node); err != nil {
+			return fmt.Errorf("failed to record analysis: %w", err)
+		}
+	}
+	
+	return tx.Commit()
+}
+
+func (s *SQLiteHistoryStore) List() ([]HistoryEntry, error) {
+	rows, err := s.db.Query(`SELECT id, fingerprint, timestamp, dir, workspace, severity, nodes, cycles FROM analyses`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+	defer rows.Close()
+	
+	entries := []HistoryEntry{}
+	for rows.Next() {
+		var entry HistoryEntry
+		var timestamp, nodes, cycles string
+		if err := rows.Scan(&entry.ID, &entry.Fingerprint, &timestamp, &entry.Dir, &entry.Workspace, &entry.Severity, &nodes, &cycles); err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+		if entry.Timestamp, err = time.Parse(time.RFC3339Nano, timestamp); err != nil {
+			logger.Warn("skipping unreadable history entry", "id", entry.ID, "error", err)
+			continue
+		}
+		if err := json.Unmarshal([]byte(nodes), &entry.Nodes); err != nil {
+			logger.Warn("skipping unreadable history entry", "id", entry.ID, "error", err)
+			continue
+		}
+		if err := json.Unmarshal([]byte(cycles), &entry.Cycles); err != nil {
+			logger.Warn("skipping unreadable history entry", "id", entry.ID, "error", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (s *SQLiteHistoryStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func newHistoryAnalyzer(names ...string) *tfcycle.CycleAnalyzer {
+	cycle := &tfcycle.TfCycle{}
+	for _, name := range names {
+		cycle.Nodes = append(cycle.Nodes, &tfcycle.CycleNode{ResourceType: "aws_security_group", ResourceName: name})
+	}
+	return tfcycle.NewCycleAnalyzer(cycle)
+}
+
+func TestCycleFingerprint_OrderIndependent(t *testing.T) {
+	a := CycleFingerprint([]string{"aws_security_group.a", "aws_security_group.b"})
+	b := CycleFingerprint([]string{"aws_security_group.b", "aws_security_group.a"})
+	if a != b {
+		t.Errorf("Expected fingerprint to ignore node order, got %s and %s", a, b)
+	}
+	if len(a) != 12 {
+		t.Errorf("Expected 12 character fingerprint, got %q", a)
+	}
+}
+
+func TestHistory_SaveListFind(t *testing.T) {
+	history := &History{Store: &FileHistoryStore{Dir: filepath.Join(t.TempDir(), "history")}}
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	
+	first := NewHistoryEntry(newHistoryAnalyzer("a", "b"), "/work", "prod", start)
+	second := NewHistoryEntry(newHistoryAnalyzer("a", "b", "c"), "/work", "", start.Add(time.Hour))
+	third := NewHistoryEntry(newHistoryAnalyzer("b", "a"), "/work", "prod", start.Add(2*time.Hour))
+	for _, entry := range []HistoryEntry{first, second, third} {
+		if err := history.Save(entry); err != nil {
+			t.Fatalf("Expected no error saving entry, got: %v", err)
+		}
+	}
+	
+	entries, err := history.List()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(entries) != 3 || entries[0].ID != third.ID || entries[2].ID != first.ID {
+		t.Fatalf("Expected entries newest first, got %+v", entries)
+	}
+	
+	entry, err := history.Find(first.Fingerprint)
+	if err != nil || entry.ID != third.ID {
+		t.Errorf("Expected fingerprint to resolve to the latest entry %s, got %s (%v)", third.ID, entry.ID, err)
+	}
+	
+	entry, err = history.Find("20261001T13")
+	if err != nil || entry.ID != second.ID {
+		t.Errorf("Expected unique prefix to resolve to %s, got %s (%v)", second.ID, entry.ID, err)
+	}
+	
+	if _, err := history.Find("20261001"); err == nil {
+		t.Errorf("Expected ambiguous prefix to fail")
+	}
+	
+	list := FormatHistoryList(entries)
+	if !strings.Contains(list, first.ID) || !strings.Contains(list, "prod") {
+		t.Errorf("Expected list to include IDs and workspaces, got:\n%s", list)
+	}
+}
+
+func TestHistory_ListMissingDir(t *testing.T) {
+	history := &History{Store: &FileHistoryStore{Dir: filepath.Join(t.TempDir(), "missing")}}
+	entries, err := history.List()
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected empty history, got %v (%v)", entries, err)
+	}
+}
+
+func TestTerraformWorkspace(t *testing.T) {
+	t.Setenv("TF_WORKSPACE", "")
+	dir := t.TempDir()
+	
+	if workspace := terraformWorkspace(dir); workspace != "" {
+		t.Errorf("Expected unknown workspace, got %q", workspace)
+	}
+	
+	os.Mkdir(filepath.Join(dir, ".terraform"), 0755)
+	if workspace := terraformWorkspace(dir); workspace != "default" {
+		t.Errorf("Expected default workspace, got %q", workspace)
+	}
+	
+	os.WriteFile(filepath.Join(dir, ".terraform", "environment"), []byte("staging\n"), 0644)
+	if workspace := terraformWorkspace(dir); workspace != "staging" {
+		t.Errorf("Expected staging workspace, got %q", workspace)
+	}
+	
+	t.Setenv("TF_WORKSPACE", "prod")
+	if workspace := terraformWorkspace(dir); workspace != "prod" {
+		t.Errorf("Expected TF_WORKSPACE to win, got %q", workspace)
+	}
+}
+
+func TestHistory_Last(t *testing.T) {
+	history := &History{Store: &FileHistoryStore{Dir: t.TempDir()}}
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	
+	history.Save(NewHistoryEntry(newHistoryAnalyzer("a", "b"), "/work", "prod", start))
+	staging := NewHistoryEntry(newHistoryAnalyzer("a", "b", "c"), "/work", "staging", start.Add(time.Hour))
+	history.Save(staging)
+	history.Save(NewHistoryEntry(newHistoryAnalyzer("x", "y"), "/other", "staging", start.Add(2*time.Hour)))
+	
+	entry, found, err := history.Last("/work", "staging")
+	if err != nil || !found || entry.ID != staging.ID {
+		t.Errorf("Expected %s for /work staging, got %s (found %v, %v)", staging.ID, entry.ID, found, err)
+	}
+	
+	if _, found, _ := history.Last("/work", "dev"); found {
+		t.Errorf("Expected no entry for an unseen workspace")
+	}
+}
+
+func TestCompareWithHistory(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	large := NewHistoryEntry(newHistoryAnalyzer("a", "b", "c", "d"), "/work", "", now)
+	small := NewHistoryEntry(newHistoryAnalyzer("a", "b"), "/work", "", now.Add(time.Hour))
+	other := NewHistoryEntry(newHistoryAnalyzer("a", "c"), "/work", "", now.Add(time.Hour))
+	
+	tests := []struct {
+		previous, current HistoryEntry
+		trend             string
+	}{
+		{large, small, "progress"},
+		{small, large, "regression"},
+		{small, small, "unchanged"},
+		{small, other, "changed"},
+	}
+	
+	for _, tt := range tests {
+		comparison := CompareWithHistory(tt.previous, tt.current)
+		if comparison.Trend != tt.trend {
+			t.Errorf("Expected trend %s for %d -> %d nodes, got %s", tt.trend, len(tt.previous.Nodes), len(tt.current.Nodes), comparison.Trend)
+		}
+	}
+	
+	comparison := CompareWithHistory(large, small)
+	formatter := tfcycle.NewOutputFormatter(newHistoryAnalyzer("a", "b"), false)
+	formatter.SetComparison(&comparison)
+	if output := formatter.FormatAnalysis(); !strings.Contains(output, "cycle shrank from 4 to 2 nodes") {
+		t.Errorf("Expected progress summary in report, got:\n%s", output)
+	}
+}
+
+func TestSQLiteHistoryStore(t *testing.T) {
+	store, err := OpenSQLiteHistory(filepath.Join(t.TempDir(), "nested", "history.db"))
+	if err != nil {
+		t.Fatalf("Expected no error opening database, got: %v", err)
+	}
+	history := &History{Store: store}
+	defer history.Close()
+	
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	first := NewHistoryEntry(newHistoryAnalyzer("a", "b"), "/work", "prod", start)
+	second := NewHistoryEntry(newHistoryAnalyzer("a", "b", "c"), "/work", "", start.Add(time.Hour))
+	for _, entry := range []HistoryEntry{first, second, first} {
+		if err := history.Save(entry); err != nil {
+			t.Fatalf("Expected no error saving entry, got: %v", err)
+		}
+	}
+	
+	entries, err := history.List()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != second.ID {
+		t.Fatalf("Expected 2 entries newest first, got %+v", entries)
+	}
+	if !entries[1].Timestamp.Equal(first.Timestamp) || !reflect.DeepEqual(entries[1].Cycles, first.Cycles) || entries[1].Workspace != "prod" {
+		t.Errorf("Expected entry to round-trip, got %+v, want %+v", entries[1], first)
+	}
+	
+	var nodes int
+	store.db.QueryRow(`SELECT COUNT(*) FROM analysis_nodes WHERE analysis_id = ?`, first.ID).Scan(&nodes)
+	if nodes != 2 {
+		t.Errorf("Expected 2 node rows for %s, got %d", first.ID, nodes)
+	}
+}
+
+func TestHistoryAggregates(t *testing.T) {
+	start := time.Date(2026, 10, 5, 12, 0, 0, 0, time.UTC)
+	entries := []HistoryEntry{
+		NewHistoryEntry(newHistoryAnalyzer("a", "b"), "/work", "", start),
+		NewHistoryEntry(newHistoryAnalyzer("a", "b"), "/work", "", start.Add(24*time.Hour)),
+		NewHistoryEntry(newHistoryAnalyzer("a", "c"), "/work", "", start.Add(7*24*time.Hour)),
+	}
+	
+	top := TopResources(entries, 2)
+	expected := []ResourceCount{{"aws_security_group.a", 3}, {"aws_security_group.b", 2}}
+	if !reflect.DeepEqual(top, expected) {
+		t.Errorf("Expected %v, got %v", expected, top)
+	}
+	
+	weeks := CyclesPerWeek(entries)
+	expectedWeeks := []WeekCount{{"2026-W41", 2, 1}, {"2026-W42", 1, 1}}
+	if !reflect.DeepEqual(weeks, expectedWeeks) {
+		t.Errorf("Expected %v, got %v", expectedWeeks, weeks)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func confirmInteractively(analyzer *tfcycle.CycleAnalyzer, config Config) error {
+	in := io.Reader(os.Stdin)
+	if config.ErrorFile == "" {
+		tty, err := os.Open("/dev/tty")
+		if err != nil {
+			return inputErrorf("--interactive needs a terminal when the error is read from stdin; use --error-file: %w", err)
+		}
+		defer tty.Close()
+		in = tty
+	}
+	
+	graph := analyzer.ConfirmEdges(in, os.Stderr)
+	if !tfcycle.GraphHasCycle(graph) {
+		fmt.Fprintln(os.Stderr, "Warning: the confirmed dependencies contain no cycle; one of the rejected edges must be real")
+	}
+	analyzer.SetGraph(graph)
+	return nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+var lintKinds = map[string]struct{ icon, title, note string }{
+	tfcycle.LintCycle:       {"❌", "Cycle", "terraform plan fails on this cycle"},
+	tfcycle.LintModuleCycle: {"⚠️ ", "Module cycle", "fails if the module outputs involved depend on the inputs"},
+	tfcycle.LintStateCycle:  {"⚠️ ", "State cycle", "appears when these resources are destroyed or replaced"},
+	tfcycle.LintNearCycle:   {"🟡", "Near cycle", "not a cycle while %s stays a standalone resource"},
+}
+
+func runLint(config Config) error {
+	configDir := config.ConfigDir
+	if configDir == "" {
+		configDir = "."
+	}
+	report, err := lintDir(configDir, config)
+	if err != nil {
+		return err
+	}
+	accepted, err := baselineLint(&report, config)
+	if err != nil {
+		return err
+	}
+	if accepted > 0 {
+		fmt.Fprintf(os.Stderr, "Left out %d cycles accepted in %s\n", accepted, config.Baseline)
+	}
+	
+	var output string
+	switch outputFormat(config) {
+	case "json":
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		output = string(jsonData) + "\n"
+	case "markdown":
+		output = FormatLintMarkdown(report)
+	case "rdjson":
+		if output, err = tfcycle.FormatRDJSON(LintRDJSON(report)); err != nil {
+			return err
+		}
+	case "warnings-ng":
+		if output, err = tfcycle.FormatWarningsNG(LintWarningsNG(report)); err != nil {
+			return err
+		}
+	default:
+		output = FormatLintReport(report)
+	}
+	if err := writeReport(output, config); err != nil {
+		return err
+	}
+	printSummary(lintSummary(report))
+	
+	threshold, _ := tfcycle.ParseSeverity(config.FailOn)
+	for _, finding := range report.Findings {
+		if threshold != tfcycle.SeverityNone && finding.Severity >= threshold {
+			return &ExitError{Code: ExitCycleFound}
+		}
+	}
+	return nil
+}
+
+// lintDir lints the configuration in dir together with --state.
+func lintDir(dir string, config Config) (tfcycle.LintReport, error) {
+	index, err := scanConfig(dir, config)
+	if err != nil {
+		return tfcycle.LintReport{}, inputError(err)
+	}
+	
+	var state map[string][]string
+	if config.StateFile != "" {
+		data, err := os.ReadFile(config.StateFile)
+		if err != nil {
+			return tfcycle.LintReport{}, inputErrorf("failed to read state: %w", err)
+		}
+		if state, err = tfcycle.ParseStateDependencies(data); err != nil {
+			return tfcycle.LintReport{}, inputError(err)
+		}
+	}
+	
+	ruleOpts, err := suggestionRulesOptions(config)
+	if err != nil {
+		return tfcycle.LintReport{}, err
+	}
+	return tfcycle.Lint(index, state, append(versionOptions(config), ruleOpts...)...), nil
+}
+
+func FormatLintReport(report tfcycle.LintReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("🔍 LINT OF %s: %d blocks, %d dependencies\n", report.Root, report.Blocks, report.Edges))
+	if report.Ignored > 0 {
+		output.WriteString(fmt.Sprintf("   %d ignored by tfcycle:ignore comments\n", report.Ignored))
+	}
+	if len(report.Findings) == 0 {
+		output.WriteString("\n✅ No cycles found\n")
+	}
+	
+	for _, finding := range append(report.Findings, report.NearCycles...) {
+		kind := lintKinds[finding.Kind]
+		if finding.Kind == tfcycle.LintNearCycle {
+			output.WriteString(fmt.Sprintf("\n%s %s: %s\n", kind.icon, strings.ToUpper(kind.title), lintCyclePath(finding.Cycle)))
+		} else {
+			output.WriteString(fmt.Sprintf("\n%s %s (%s): %s\n", kind.icon, strings.ToUpper(kind.title), finding.Severity, lintCyclePath(finding.Cycle)))
+		}
+		output.WriteString(fmt.Sprintf("   %s\n", lintKindNote(finding)))
+		for _, edge := range finding.Edges {
+			line := fmt.Sprintf("   %s → %s (%s", edge.From, edge.To, edge.Source)
+			if edge.Location != "" {
+				line += ", " + edge.Location
+			}
+			output.WriteString(line + ")\n")
+		}
+		for _, suggestion := range finding.Suggestions {
+			output.WriteString(fmt.Sprintf("   💡 %s\n", suggestion.Title))
+		}
+	}
+	return output.String()
+}
+
+func FormatLintMarkdown(report tfcycle.LintReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("# Lint of %s\n\n", report.Root))
+	output.WriteString(fmt.Sprintf("%d blocks, %d dependencies.\n", report.Blocks, report.Edges))
+	if report.Ignored > 0 {
+		output.WriteString(fmt.Sprintf("%d ignored by `tfcycle:ignore` comments.\n", report.Ignored))
+	}
+	if len(report.Findings) == 0 {
+		output.WriteString("\nNo cycles found.\n")
+	}
+	
+	for _, finding := range append(report.Findings, report.NearCycles...) {
+		kind := lintKinds[finding.Kind]
+		if finding.Kind == tfcycle.LintNearCycle {
+			output.WriteString(fmt.Sprintf("\n## %s\n\n", kind.title))
+		} else {
+			output.WriteString(fmt.Sprintf("\n## %s (%s)\n\n", kind.title, finding.Severity))
+		}
+		output.WriteString(fmt.Sprintf("`%s`: %s.\n\n", lintCyclePath(finding.Cycle), lintKindNote(finding)))
+		output.WriteString("| From | To | Source | Location |\n|---|---|---|---|\n")
+		for _, edge := range finding.Edges {
+			output.WriteString(fmt.Sprintf("| `%s` | `%s` | %s | %s |\n", edge.From, edge.To, edge.Source, edge.Location))
+		}
+		if len(finding.Suggestions) > 0 {
+			output.WriteString("\n")
+		}
+		for _, suggestion := range finding.Suggestions {
+			output.WriteString(fmt.Sprintf("- %s\n", suggestion.Title))
+		}
+	}
+	return output.String()
+}
+
+func lintKindNote(finding tfcycle.LintFinding) string {
+	note := lintKinds[finding.Kind].note
+	if finding.Kind == tfcycle.LintNearCycle {
+		return fmt.Sprintf(note, finding.Via)
+	}
+	return note
+}
+
+func lintCyclePath(cycle []string) string {
+	if len(cycle) == 0 {
+		return ""
+	}
+	return strings.Join(append(append([]string{}, cycle...), cycle[0]), " → ")
+}
+
+// LintRDJSON reports each located edge of the findings as a reviewdog
+// diagnostic. The line-based fixes of a finding are attached as suggestions
+// to its first diagnostic in the file they edit.
+func LintRDJSON(report tfcycle.LintReport) tfcycle.RDJSONResult {
+	result := tfcycle.RDJSONResult{Source: tfcycle.RDSource{Name: "tfcycle"}, Diagnostics: []tfcycle.RDDiagnostic{}}
+	for _, finding := range append(report.Findings, report.NearCycles...) {
+		kind := lintKinds[finding.Kind]
+		message := fmt.Sprintf("%s: %s\n%s", kind.title, lintCyclePath(finding.Cycle), lintKindNote(finding))
+		for _, suggestion := range finding.Suggestions {
+			message += "\n💡 " + suggestion.Title
+		}
+		
+		severity := tfcycle.RDWarning
+		switch finding.Kind {
+		case tfcycle.LintCycle:
+			severity = tfcycle.RDError
+		case tfcycle.LintNearCycle:
+			severity = tfcycle.RDInfo
+		}
+		
+		suggested := make(map[string]bool)
+		for _, edge := range finding.Edges {
+			file, line, ok := splitLocation(edge.Location)
+			if !ok {
+				continue
+			}
+			diagnostic := tfcycle.RDDiagnostic{
+				Message:  fmt.Sprintf("%s\n%s → %s (%s)", message, edge.From, edge.To, edge.Source),
+				Location: tfcycle.RDLocation{Path: filepath.ToSlash(file), Range: &tfcycle.RDRange{Start: tfcycle.RDPosition{Line: line}}},
+				Severity: severity,
+				Code:     &tfcycle.RDCode{Value: finding.Kind},
+			}
+			if rel, err := filepath.Rel(report.Root, file); err == nil && !suggested[file] {
+				suggested[file] = true
+				for _, fix := range finding.Fixes {
+					diagnostic.Suggestions = append(diagnostic.Suggestions, tfcycle.RDSuggestions(fix, filepath.ToSlash(rel))...)
+				}
+			}
+			result.Diagnostics = append(result.Diagnostics, diagnostic)
+		}
+	}
+	return result
+}
+
+// LintWarningsNG returns an issue for each finding, located at its first
+// located dependency. Near cycles have the lowest severity.
+func LintWarningsNG(report tfcycle.LintReport) tfcycle.WarningsNGReport {
+	result := tfcycle.WarningsNGReport{Issues: []tfcycle.WarningsNGIssue{}}
+	for _, finding := range append(report.Findings, report.NearCycles...) {
+		issue := tfcycle.WarningsNGIssue{
+			Severity:    tfcycle.WarningsNGSeverity(finding.Severity),
+			Message:     fmt.Sprintf("%s: %s", lintKinds[finding.Kind].title, lintCyclePath(finding.Cycle)),
+			Description: tfcycle.WarningsNGDescription(lintKindNote(finding), finding.Suggestions),
+			Category:    finding.Kind,
+			Type:        finding.Kind,
+		}
+		if finding.Kind == tfcycle.LintNearCycle {
+			issue.Severity = tfcycle.WarningsNGLow
+		}
+		if len(finding.Suggestions) > 0 {
+			issue.Type = finding.Suggestions[0].ID
+		}
+		for _, edge := range finding.Edges {
+			if file, line, ok := splitLocation(edge.Location); ok {
+				issue.FileName = filepath.ToSlash(file)
+				issue.LineStart = line
+				break
+			}
+		}
+		result.Issues = append(result.Issues, issue)
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func TestLint_Text(t *testing.T) {
+	dir := t.TempDir()
+	config := `resource "aws_iam_role" "app" {
+  managed_policy_arns = [aws_iam_policy.app.arn]
+}
+
+resource "aws_iam_policy" "app" {
+  policy = jsonencode({ Resource = aws_iam_role.app.arn })
+}
+
+resource "aws_s3_bucket" "logs" {}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	output := filepath.Join(dir, "lint.txt")
+	args, err := parseArgs([]string{"lint", "--config-dir", dir, "--output", output})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(args); exitCode(err) != ExitCycleFound {
+		t.Errorf("Expected exit code %d for a cycle, got: %v", ExitCycleFound, err)
+	}
+	
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if !strings.Contains(text, "3 blocks, 2 dependencies") || !strings.Contains(text, "❌ CYCLE (low)") {
+		t.Errorf("Expected one configuration cycle, got:\n%s", text)
+	}
+	if !strings.Contains(text, "main.tf:2)") || !strings.Contains(text, "💡 IAM cycle detected") {
+		t.Errorf("Expected the reference location and IAM suggestions, got:\n%s", text)
+	}
+	
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`resource "aws_s3_bucket" "logs" {}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runCommand(args); err != nil {
+		t.Fatalf("Expected no error without a cycle, got: %v", err)
+	}
+	if data, _ := os.ReadFile(output); !strings.Contains(string(data), "No cycles found") {
+		t.Errorf("Expected no cycles, got:\n%s", data)
+	}
+}
+
+func TestLint_InvalidState(t *testing.T) {
+	dir := t.TempDir()
+	state := filepath.Join(dir, "terraform.tfstate")
+	if err := os.WriteFile(state, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := parseArgs([]string{"lint", "--config-dir", dir, "--state", state})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error, got: %v", err)
+	}
+}
+
+func TestLint_NearCycleExitCode(t *testing.T) {
+	dir := t.TempDir()
+	config := `resource "aws_security_group" "web" {}
+
+resource "aws_instance" "web" {
+  vpc_security_group_ids = [aws_security_group.web.id]
+}
+
+resource "aws_security_group_rule" "web_from_instance" {
+  security_group_id = aws_security_group.web.id
+  cidr_blocks       = ["${aws_instance.web.private_ip}/32"]
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	output := filepath.Join(dir, "lint.txt")
+	args, err := parseArgs([]string{"lint", "--config-dir", dir, "--output", output})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(args); err != nil {
+		t.Fatalf("Expected near cycles not to fail lint, got: %v", err)
+	}
+	if data, _ := os.ReadFile(output); !strings.Contains(string(data), "🟡 NEAR CYCLE") {
+		t.Errorf("Expected a near cycle warning, got:\n%s", data)
+	}
+}
+
+func TestLint_RDJSON(t *testing.T) {
+	dir := t.TempDir()
+	config := `resource "aws_security_group" "web" {
+  ingress {
+    security_groups = [aws_security_group.db.id]
+  }
+}
+
+resource "aws_security_group" "db" {
+  ingress {
+    security_groups = [aws_security_group.web.id]
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	output := filepath.Join(dir, "lint.json")
+	args, err := parseArgs([]string{"lint", "--config-dir", dir, "--format", "rdjson", "--fail-on", "none", "--output", output})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(args); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result tfcycle.RDJSONResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Expected rdjson, got: %v\n%s", err, data)
+	}
+	if len(result.Diagnostics) != 2 {
+		t.Fatalf("Expected a diagnostic per reference, got %+v", result.Diagnostics)
+	}
+	first := result.Diagnostics[0]
+	if first.Severity != tfcycle.RDError || first.Code.Value != "cycle" || !strings.HasSuffix(first.Location.Path, "/main.tf") {
+		t.Errorf("Expected a cycle error in main.tf, got %+v", first)
+	}
+	if len(first.Suggestions) == 0 || len(result.Diagnostics[1].Suggestions) != 0 {
+		t.Errorf("Expected the fix on the first diagnostic only, got %+v", result.Diagnostics)
+	}
+}
+
+func TestLint_WarningsNG(t *testing.T) {
+	dir := t.TempDir()
+	config := `resource "aws_iam_role" "app" {
+  managed_policy_arns = [aws_iam_policy.app.arn]
+}
+
+resource "aws_iam_policy" "app" {
+  policy = jsonencode({ Resource = aws_iam_role.app.arn })
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	output := filepath.Join(dir, "issues.json")
+	args, err := parseArgs([]string{"lint", "--config-dir", dir, "--format", "warnings-ng", "--fail-on", "none", "--output", output})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(args); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report tfcycle.WarningsNGReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Expected an issue report, got: %v\n%s", err, data)
+	}
+	if len(report.Issues) != 1 {
+		t.Fatalf("Expected one issue, got %+v", report.Issues)
+	}
+	issue := report.Issues[0]
+	if issue.Category != "cycle" || !strings.HasSuffix(issue.FileName, "/main.tf") || issue.LineStart != 6 {
+		t.Errorf("Expected the cycle at main.tf:6, got %+v", issue)
+	}
+}
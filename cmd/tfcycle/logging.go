@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+var logger = newLogger(os.Stderr, slog.LevelWarn)
+
+func init() {
+	tfcycle.SetLogger(logger)
+}
+
+func setLogger(l *slog.Logger) {
+	logger = l
+	tfcycle.SetLogger(l)
+}
+
+func newLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+func parseLogLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return level, fmt.Errorf("invalid log level %q (use %s)", value, strings.Join(logLevels, ", "))
+	}
+	return level, nil
+}
+
+func setupLogging(levelName, file string) (func() error, error) {
+	level, err := parseLogLevel(levelName)
+	if err != nil {
+		return nil, err
+	}
+	
+	if file == "" {
+		setLogger(newLogger(os.Stderr, level))
+		return func() error { return nil }, nil
+	}
+	
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file %s: %w", file, err)
+	}
+	setLogger(newLogger(f, level))
+	return f.Close, nil
+}
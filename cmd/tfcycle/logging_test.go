@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func TestSetupLogging_File(t *testing.T) {
+	previous := logger
+	defer setLogger(previous)
+	
+	path := filepath.Join(t.TempDir(), "tfcycle.log")
+	closeLog, err := setupLogging("debug", path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	cycle, _ := tfcycle.NewParser().ParseError(`Error: Cycle: aws_security_group.a, aws_security_group.b`)
+	tfcycle.NewCycleAnalyzer(cycle).FindMinimalCycles()
+	closeLog()
+	
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	for _, expected := range []string{"level=DEBUG", "heuristic matched", "hypothesized graph built"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected log to contain %q, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestSetupLogging_InvalidLevel(t *testing.T) {
+	if _, err := setupLogging("verbose", ""); err == nil {
+		t.Errorf("Expected error for invalid log level")
+	}
+}
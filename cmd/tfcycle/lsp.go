@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+// LSP diagnostic severities.
+const (
+	lspError       = 1
+	lspWarning     = 2
+	lspInformation = 3
+)
+
+// LSP window/logMessage types.
+const lspLogError = 1
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspCodeAction struct {
+	Title       string          `json:"title"`
+	Kind        string          `json:"kind"`
+	Diagnostics []lspDiagnostic `json:"diagnostics,omitempty"`
+	Edit        struct {
+		Changes map[string][]lspTextEdit `json:"changes"`
+	} `json:"edit"`
+}
+
+type lspNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// lspResponse is rpcResponse with a result that is present even when null,
+// as the Language Server Protocol requires for shutdown.
+type lspResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// lspFinding is a lint finding with the diagnostics published for it.
+type lspFinding struct {
+	finding     tfcycle.LintFinding
+	diagnostics map[string][]lspDiagnostic
+}
+
+// LSPServer publishes lint findings of a workspace as diagnostics and offers
+// the fixes tfcycle fix proposes for them as code actions.
+type LSPServer struct {
+	config    Config
+	root      string
+	out       io.Writer
+	findings  []lspFinding
+	published map[string]bool
+	exit      bool
+}
+
+func NewLSPServer(config Config) *LSPServer {
+	root := config.ConfigDir
+	if root == "" {
+		root = "."
+	}
+	return &LSPServer{config: config, root: root, published: make(map[string]bool)}
+}
+
+func (s *LSPServer) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+	reader := bufio.NewReader(in)
+	
+	for !s.exit {
+		body, err := readLSPMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if resp := s.handleMessage(ctx, body); resp != nil {
+			if err := s.write(resp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readLSPMessage reads one message framed by a Content-Length header.
+func readLSPMessage(reader *bufio.Reader) ([]byte, error) {
+	header, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read message header: %w", err)
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+	}
+	
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, fmt.Errorf("failed to read message body: %w", err)
+	}
+	return body, nil
+}
+
+func (s *LSPServer) write(message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	return err
+}
+
+func (s *LSPServer) notify(method string, params interface{}) {
+	if err := s.write(lspNotification{JSONRPC: "2.0", Method: method, Params: params}); err != nil {
+		logger.Warn("failed to send notification", "method", method, "error", err)
+	}
+}
+
+func (s *LSPServer) handleMessage(ctx context.Context, body []byte) *lspResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return &lspResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: err.Error()}}
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return &lspResponse{JSONRPC: "2.0", ID: nullID(req.ID), Error: &rpcError{Code: rpcInvalidRequest, Message: "invalid JSON-RPC request"}}
+	}
+	
+	result, rpcErr := s.dispatch(ctx, req)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	if rpcErr != nil {
+		return &lspResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return &lspResponse{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: rpcInvalidRequest, Message: err.Error()}}
+	}
+	return &lspResponse{JSONRPC: "2.0", ID: req.ID, Result: data}
+}
+
+func (s *LSPServer) dispatch(ctx context.Context, req rpcRequest) (interface{}, *rpcError) {
+	logger.Debug("lsp request", "method", req.Method)
+	
+	switch req.Method {
+	case "initialize":
+		return s.initialize(req.Params)
+	case "initialized":
+		s.lint()
+		return nil, nil
+	case "textDocument/didOpen", "textDocument/didSave":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+		}
+		if strings.HasSuffix(params.TextDocument.URI, ".tf") {
+			s.lint()
+		}
+		return nil, nil
+	case "textDocument/codeAction":
+		return s.codeActions(req.Params)
+	case "shutdown":
+		return nil, nil
+	case "exit":
+		s.exit = true
+		return nil, nil
+	default:
+		if len(req.ID) == 0 {
+			// Notifications such as didChange and $/cancelRequest need no reply.
+			return nil, nil
+		}
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+func (s *LSPServer) initialize(params json.RawMessage) (interface{}, *rpcError) {
+	var init struct {
+		RootURI          string `json:"rootUri"`
+		RootPath         string `json:"rootPath"`
+		WorkspaceFolders []struct {
+			URI string `json:"uri"`
+		} `json:"workspaceFolders"`
+	}
+	if err := json.Unmarshal(params, &init); err != nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	
+	switch {
+	case init.RootURI != "":
+		s.root = uriToPath(init.RootURI)
+	case len(init.WorkspaceFolders) > 0:
+		s.root = uriToPath(init.WorkspaceFolders[0].URI)
+	case init.RootPath != "":
+		s.root = init.RootPath
+	}
+	
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync":   map[string]interface{}{"openClose": true, "change": 0, "save": true},
+			"codeActionProvider": map[string]interface{}{"codeActionKinds": []string{"quickfix"}},
+		},
+		"serverInfo": map[string]string{"name": "tfcycle", "version": version},
+	}, nil
+}
+
+// lint reruns lint on the workspace and publishes its diagnostics, clearing
+// those of files that no longer have any.
+func (s *LSPServer) lint() {
+	report, err := lintDir(s.root, s.config)
+	if err != nil {
+		s.notify("window/logMessage", map[string]interface{}{"type": lspLogError, "message": "tfcycle: " + err.Error()})
+		return
+	}
+	
+	s.findings = nil
+	byURI := make(map[string][]lspDiagnostic)
+	for _, finding := range append(report.Findings, report.NearCycles...) {
+		diagnostics := lspDiagnostics(finding)
+		for uri, list := range diagnostics {
+			byURI[uri] = append(byURI[uri], list...)
+		}
+		s.findings = append(s.findings, lspFinding{finding: finding, diagnostics: diagnostics})
+	}
+	
+	for uri := range s.published {
+		if _, ok := byURI[uri]; !ok {
+			byURI[uri] = []lspDiagnostic{}
+		}
+	}
+	uris := make([]string, 0, len(byURI))
+	for uri := range byURI {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	
+	s.published = make(map[string]bool)
+	for _, uri := range uris {
+		s.notify("textDocument/publishDiagnostics", map[string]interface{}{"uri": uri, "diagnostics": byURI[uri]})
+		if len(byURI[uri]) > 0 {
+			s.published[uri] = true
+		}
+	}
+}
+
+// lspDiagnostics returns a diagnostic for each located edge of finding, by
+// document URI.
+func lspDiagnostics(finding tfcycle.LintFinding) map[string][]lspDiagnostic {
+	kind := lintKinds[finding.Kind]
+	message := fmt.Sprintf("%s: %s\n%s", kind.title, lintCyclePath(finding.Cycle), lintKindNote(finding))
+	for _, suggestion := range finding.Suggestions {
+		message += "\n💡 " + suggestion.Title
+	}
+	
+	severity := lspWarning
+	switch finding.Kind {
+	case tfcycle.LintCycle:
+		severity = lspError
+	case tfcycle.LintNearCycle:
+		severity = lspInformation
+	}
+	
+	diagnostics := make(map[string][]lspDiagnostic)
+	for _, edge := range finding.Edges {
+		file, line, ok := splitLocation(edge.Location)
+		if !ok {
+			continue
+		}
+		uri := pathToURI(file)
+		diagnostics[uri] = append(diagnostics[uri], lspDiagnostic{
+			Range:    lineRange(file, line-1),
+			Severity: severity,
+			Code:     finding.Kind,
+			Source:   "tfcycle",
+			Message:  fmt.Sprintf("%s\n%s → %s (%s)", message, edge.From, edge.To, edge.Source),
+		})
+	}
+	return diagnostics
+}
+
+// codeActions offers the fixes of the findings with diagnostics in the
+// requested range.
+func (s *LSPServer) codeActions(params json.RawMessage) (interface{}, *rpcError) {
+	var request struct {
+		TextDocument struct {
+			URI string `json:"uri"`
+		} `json:"textDocument"`
+		Range lspRange `json:"range"`
+	}
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	uri := pathToURI(uriToPath(request.TextDocument.URI))
+	
+	actions := []lspCodeAction{}
+	for _, entry := range s.findings {
+		var diagnostics []lspDiagnostic
+		for _, diagnostic := range entry.diagnostics[uri] {
+			if diagnostic.Range.Start.Line >= request.Range.Start.Line && diagnostic.Range.Start.Line <= request.Range.End.Line {
+				diagnostics = append(diagnostics, diagnostic)
+			}
+		}
+		if len(diagnostics) == 0 {
+			continue
+		}
+		
+		for _, fix := range entry.finding.Fixes {
+			if len(fix.Edits) == 0 {
+				continue
+			}
+			action := lspCodeAction{Title: "tfcycle: " + fix.Title, Kind: "quickfix", Diagnostics: diagnostics}
+			action.Edit.Changes = make(map[string][]lspTextEdit)
+			for _, edit := range fix.Edits {
+				editURI := pathToURI(filepath.Join(s.root, filepath.FromSlash(edit.File)))
+				newText := ""
+				if len(edit.Lines) > 0 {
+					newText = strings.Join(edit.Lines, "\n") + "\n"
+				}
+				action.Edit.Changes[editURI] = append(action.Edit.Changes[editURI], lspTextEdit{
+					Range: lspRange{
+						Start: lspPosition{Line: edit.Line - 1},
+						End:   lspPosition{Line: edit.Line - 1 + edit.Count},
+					},
+					NewText: newText,
+				})
+			}
+			actions = append(actions, action)
+		}
+	}
+	return actions, nil
+}
+
+// splitLocation splits a lint location such as modules/app/main.tf:12.
+func splitLocation(location string) (string, int, bool) {
+	i := strings.LastIndex(location, ":")
+	if i < 0 {
+		return "", 0, false
+	}
+	line, err := strconv.Atoi(location[i+1:])
+	if err != nil || line < 1 {
+		return "", 0, false
+	}
+	return filepath.FromSlash(location[:i]), line, true
+}
+
+// lineRange spans line of file, whose length LSP counts in UTF-16 units.
+func lineRange(file string, line int) lspRange {
+	r := lspRange{Start: lspPosition{Line: line}, End: lspPosition{Line: line}}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return r
+	}
+	lines := strings.Split(string(data), "\n")
+	if line < len(lines) {
+		text := strings.TrimRight(lines[line], "\r")
+		r.Start.Character = len(utf16.Encode([]rune(text[:len(text)-len(strings.TrimLeft(text, " \t"))])))
+		r.End.Character = len(utf16.Encode([]rune(text)))
+	}
+	return r
+}
+
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	// file:///C:/work on Windows.
+	if runtime.GOOS == "windows" && len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
+
+func runLSP(config Config) error {
+	return NewLSPServer(config).Serve(context.Background(), os.Stdin, os.Stdout)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func lspMessage(message string) string {
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(message), message)
+}
+
+func runLSPSession(t *testing.T, config Config, messages ...string) []map[string]interface{} {
+	var input strings.Builder
+	for _, message := range messages {
+		input.WriteString(lspMessage(message))
+	}
+	var out bytes.Buffer
+	if err := NewLSPServer(config).Serve(context.Background(), strings.NewReader(input.String()), &out); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	var replies []map[string]interface{}
+	reader := bufio.NewReader(&out)
+	for {
+		body, err := readLSPMessage(reader)
+		if err != nil {
+			break
+		}
+		var reply map[string]interface{}
+		if err := json.Unmarshal(body, &reply); err != nil {
+			t.Fatalf("Expected a JSON message, got: %v", err)
+		}
+		replies = append(replies, reply)
+	}
+	return replies
+}
+
+func TestLSPServer(t *testing.T) {
+	dir := t.TempDir()
+	config := `resource "aws_security_group" "web" {}
+
+resource "aws_instance" "web" {
+  vpc_security_group_ids = [aws_security_group.web.id]
+}
+
+resource "aws_security_group_rule" "web_from_instance" {
+  security_group_id = aws_security_group.web.id
+  cidr_blocks       = ["${aws_instance.web.private_ip}/32"]
+}
+`
+	main := filepath.Join(dir, "main.tf")
+	if err := os.WriteFile(main, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	uri := pathToURI(main)
+	
+	replies := runLSPSession(t, Config{},
+		fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"rootUri":%q}}`, pathToURI(dir)),
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/codeAction","params":{"textDocument":{"uri":"`+uri+`"},"range":{"start":{"line":0,"character":0},"end":{"line":20,"character":0}},"context":{"diagnostics":[]}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	)
+	if len(replies) != 4 {
+		t.Fatalf("Expected 4 messages, got %d: %v", len(replies), replies)
+	}
+	
+	capabilities := replies[0]["result"].(map[string]interface{})["capabilities"].(map[string]interface{})
+	if capabilities["codeActionProvider"] == nil {
+		t.Errorf("Expected code actions to be advertised, got %v", capabilities)
+	}
+	
+	if replies[1]["method"] != "textDocument/publishDiagnostics" {
+		t.Fatalf("Expected diagnostics after initialized, got %v", replies[1])
+	}
+	params := replies[1]["params"].(map[string]interface{})
+	diagnostics := params["diagnostics"].([]interface{})
+	if params["uri"] != uri || len(diagnostics) == 0 {
+		t.Fatalf("Expected diagnostics for main.tf, got %v", params)
+	}
+	diagnostic := diagnostics[0].(map[string]interface{})
+	if diagnostic["code"] != "near_cycle" || diagnostic["source"] != "tfcycle" || diagnostic["severity"] != float64(lspInformation) {
+		t.Errorf("Expected a near-cycle diagnostic from tfcycle, got %v", diagnostic)
+	}
+	
+	if replies[2]["error"] != nil {
+		t.Fatalf("Expected code actions, got %v", replies[2])
+	}
+	if _, ok := replies[3]["result"]; !ok || replies[3]["result"] != nil {
+		t.Errorf("Expected a null shutdown result, got %v", replies[3])
+	}
+}
+
+func TestLSPServer_CycleCodeAction(t *testing.T) {
+	dir := t.TempDir()
+	config := `resource "aws_security_group" "a" {
+  ingress {
+    security_groups = [aws_security_group.b.id]
+  }
+}
+
+resource "aws_security_group" "b" {
+  ingress {
+    security_groups = [aws_security_group.a.id]
+  }
+}
+`
+	main := filepath.Join(dir, "main.tf")
+	if err := os.WriteFile(main, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	uri := pathToURI(main)
+	
+	replies := runLSPSession(t, Config{ConfigDir: dir},
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"`+uri+`","languageId":"terraform","version":1,"text":""}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/codeAction","params":{"textDocument":{"uri":"`+uri+`"},"range":{"start":{"line":2,"character":0},"end":{"line":2,"character":0}},"context":{"diagnostics":[]}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"textDocument/hover","params":{}}`,
+	)
+	if len(replies) != 4 {
+		t.Fatalf("Expected 4 messages, got %d: %v", len(replies), replies)
+	}
+	
+	diagnostics := replies[1]["params"].(map[string]interface{})["diagnostics"].([]interface{})
+	if len(diagnostics) != 2 || diagnostics[0].(map[string]interface{})["severity"] != float64(lspError) {
+		t.Fatalf("Expected two error diagnostics, got %v", diagnostics)
+	}
+	message := diagnostics[0].(map[string]interface{})["message"].(string)
+	if !strings.HasPrefix(message, "Cycle: ") {
+		t.Errorf("Expected the cycle in the message, got %q", message)
+	}
+	
+	actions := replies[2]["result"].([]interface{})
+	if len(actions) == 0 {
+		t.Fatalf("Expected a code action for the security group fix, got %v", replies[2])
+	}
+	action := actions[0].(map[string]interface{})
+	changes := action["edit"].(map[string]interface{})["changes"].(map[string]interface{})
+	if action["kind"] != "quickfix" || len(changes[uri].([]interface{})) == 0 {
+		t.Errorf("Expected a quick fix editing main.tf, got %v", action)
+	}
+	
+	if code := replies[3]["error"].(map[string]interface{})["code"]; code != float64(rpcMethodNotFound) {
+		t.Errorf("Expected method not found for hover, got %v", replies[3])
+	}
+}
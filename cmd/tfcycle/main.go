@@ -0,0 +1,1665 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const (
+	version = tfcycle.Version
+	usage   = `tfcycle - Terraform Cycle Error Analyzer
+
+USAGE:
+    tfcycle [COMMAND] [OPTIONS]
+    
+    Options may appear before or after the command, as --flag value or
+    --flag=value. Run tfcycle COMMAND --help for the options a command accepts.
+
+COMMANDS:
+    analyze     Analyze Terraform cycle error (default)
+    visualize   Generate DOT visualization of cycle
+    fix         Generate HCL snippets and diffs for recognized cycle patterns
+    lint        Find cycles in the configuration's references (and --state)
+                before terraform reports them (--config-dir, default .)
+    explain     Explain a single resource address: cycles, edges, suggestions
+    blast-radius  List resources outside the minimal cycles that depend on its
+                members, directly or transitively (--config-dir adds the
+                configuration's references)
+    aggregate   Deduplicate the cycle errors of many workspaces (NAME=FILE ...)
+                and report the modules and patterns behind them
+    stats       Print statistics over many error files or JSON analyses: the most
+                frequent resource types, cycle lengths and modules (FILE ...)
+    annotate    Echo Terraform output with the analysis inserted right after the
+                cycle diagnostic and the cycle members numbered inline
+    diff        Compare two cycle errors to see whether a fix changed anything
+    watch       Re-run terraform plan on file changes and live-update the analysis
+    run         Run a terraform command and append an analysis if it hits a cycle
+    serve       Serve a web UI, HTTP JSON API and HCP Terraform run task endpoint
+    mcp         Serve tfcycle tools to AI assistants over MCP on stdin/stdout
+    lsp         Serve lint diagnostics and fixes to editors over the Language
+                Server Protocol on stdin/stdout
+    history     List past analyses (history list), show one (history show ID), or
+                summarize recurring resources (history top) and cycles per week
+                (history weekly)
+    doctor      Check Graphviz, terraform, terragrunt, the config file and the cache directory
+    capabilities  List supported formats, heuristics and schema versions (--json)
+    self-update Install the latest GitHub release after verifying its checksum and signature
+    plugins     List tfcycle-format-* and tfcycle-heuristic-* plugins found on PATH
+    bench       Time parsing, analysis and formatting of a synthetic cycle
+                (bench --nodes 5000)
+    rules       List the suggestion rules in effect (rules list)
+    baseline    Record the current cycles as accepted (baseline write FILE
+                [INPUT]): those of a cycle error, or lint's without one
+    feedback    Record whether a hypothesized edge is real (feedback confirm
+                "A -> B", feedback deny "A -> B") or list verdicts (feedback list)
+    completion  Generate shell completion script (bash, zsh, fish, powershell)
+    docs        Generate a man page (man) or CLI reference (markdown)
+    version     Show version, git commit, build date and Go version (--json)
+    help        Show this help message
+
+OPTIONS:
+    --error-file FILE    Read error from file instead of stdin (- for stdin);
+                         analyze, visualize, fix, explain, blast-radius and
+                         annotate also take FILE as their last argument
+    --input-format F     Read the input as text (Terraform output), tfjson
+                         (plan -json lines, validate -json), dot (terraform
+                         graph), plan or state JSON, or report (tfcycle JSON);
+                         auto (default) detects it from the content
+    --output FILE        Write output to file instead of stdout
+    --compress           Gzip the --output file (adding .gz) and --output-dir
+                         reports; an --output ending in .gz is always gzipped
+    --clipboard          Also copy the report, DOT source or fixes to the
+                         clipboard (pbcopy, wl-copy, xclip, xsel or clip.exe)
+    --emit-script FILE   Write a remediation shell script with confirmation prompts
+    --config-dir DIR     Terraform configuration directory; analyze and visualize
+                         use it to report file:line, fix defaults to .
+    --output-dir DIR     Write text, JSON, markdown, DOT and SVG reports to DIR
+    --verbose           Show detailed analysis
+    --json              Output as JSON (same as --format json)
+    --format FORMAT      Output format for analyze: text, json, markdown, plan,
+                         opa (policy input document), jgf (JSON Graph Format),
+                         rdjson (reviewdog), warnings-ng (Jenkins; both also
+                         for lint), or NAME to run the tfcycle-format-NAME
+                         plugin
+    --no-plugins         Do not run tfcycle-heuristic-* plugins found on PATH
+    --no-feedback        Ignore edge verdicts recorded with tfcycle feedback
+    --suggestion-rules FILE  YAML rules that replace, disable or add to the
+                         built-in suggestion rules
+    --disable-rule ID    Turn off the heuristic or suggestion with this rule ID
+                         or glob, e.g. 'TFC-AWS-*' (see tfcycle rules list)
+    --enable-rule ID     Keep a rule on even if --disable-rule matches it
+    --suppress ID        Hide the suggestion with this rule ID or glob, or the
+                         fixes of this kind; the heuristic still runs
+                         (repeatable)
+    --no-suggestions     Report only the cycle, without suggestions, common
+                         solutions or an apply plan
+    --baseline FILE      analyze and lint report only cycles not accepted in
+                         this file (written by tfcycle baseline write)
+    --tf-version VERSION Terraform or OpenTofu version for version-specific
+                         advice (default: detected from the input)
+    --state FILE         State file or terraform show -json output whose recorded
+                         dependencies lint adds to the configuration's
+    --cdktf PATH         Show the CDKTF construct path of each resource, read
+                         from cdktf.out, its manifest.json or a cdk.tf.json
+    --analyses           Include the JSON analysis of each distinct cycle in the
+                         aggregate --json report
+    --redact             Replace names, modules and keys with stable pseudonyms
+    --lang LANG          Report language: en, de, ja, pt-BR (default from LANG)
+    --passthrough        Echo the input verbatim; append analysis only on a cycle
+    --context-lines N    Keep the N lines before the cycle error as its context
+                         and use the destroys and replacements they show
+                         (default 10, 0 = none)
+    --compare-last       Compare with the previous analysis of the same directory
+                         and workspace and report progress or regressions
+    --interactive        Ask whether each hypothesized dependency is real and
+                         recompute cycles from the answers
+    --quiet              Print only the minimal cycle paths (JSON is unchanged)
+    --max-cycles-shown N Show at most N minimal cycles in the text report
+                         (default 3, 0 = all)
+    --max-nodes-shown N  Show at most N resources of each minimal cycle
+                         (default 10, 0 = all)
+    --all                Show every minimal cycle and resource
+    --template-dir DIR   Replace report sections with SECTION.FORMAT.tmpl
+                         templates, e.g. footer.markdown.tmpl (sections header,
+                         suggestions, footer; formats text, markdown)
+    --no-history         Do not record the analysis under ~/.local/share/tfcycle
+    --datadog            Send a Datadog event when a cycle is found (API key from
+                         TFCYCLE_DATADOG_API_KEY or DD_API_KEY)
+    --datadog-site SITE  Datadog site, e.g. datadoghq.eu (default $DD_SITE or
+                         datadoghq.com)
+    --notify-webhook URL POST a JSON payload to URL when a cycle is found
+    --payload-template FILE  Render the webhook payload with this Go template
+    --history-db FILE    Keep history in a SQLite database instead of JSON files
+    --no-color           Disable colored output
+    --config FILE        Configuration file (default .tfcycle.yaml, then
+                         ~/.config/tfcycle/config.yaml)
+    --timeout DURATION   Stop reading input and searching for minimal cycles after
+                         DURATION (e.g. 30s) and report partial results
+    --max-input-size SIZE  Refuse input larger than SIZE, e.g. 500MB or 1GiB
+    --stdin-timeout DURATION  Fail if stdin or a named pipe (--error-file
+                         FIFO) gives no input within DURATION, e.g. 1m
+    --log-level LEVEL    Log level: debug, info, warn, error (default warn)
+    --log-file FILE      Write logs to FILE instead of stderr
+    --cpuprofile FILE    Write a CPU profile for go tool pprof to FILE
+    --memprofile FILE    Write a heap profile to FILE when the command finishes
+    --filter KEY=VALUE   Restrict the analysis to matching nodes; repeatable.
+                         Keys: module (e.g. module=vpc), type (e.g.
+                         type=aws_security_group); values may be globs
+    --exclude PATTERN    Drop nodes matching the address glob, e.g.
+                         'null_resource.*'; repeatable
+    --only-destroy       Keep only destroy and destroy-deposed nodes
+    --fail-on LEVEL      Exit 1 only for cycles of at least this severity:
+                         none, low, medium, high (default low)
+    --version           Show version information, like the version command
+    --help              Show help for command
+
+WATCH OPTIONS:
+    --dir DIR                Terraform directory to plan and watch (default .)
+    --interval DURATION      Also re-run plan on this interval, e.g. 30s
+
+SERVE OPTIONS:
+    --listen ADDR            Address to listen on (default 127.0.0.1:8080)
+    --grpc-listen ADDR       Also serve the gRPC API (proto/tfcycle/v1) on ADDR
+    --run-task-hmac-key KEY  Serve HCP Terraform run tasks at /run-task and
+                             verify their requests with KEY (prefer
+                             TFCYCLE_RUN_TASK_HMAC_KEY)
+    --theme THEME            Web UI colors: auto (follow the browser), light or
+                             dark (default auto)
+    --css FILE               CSS appended to the web UI's styles, e.g. to match
+                             a developer portal it is embedded in
+
+EXPLAIN OPTIONS:
+    --ai                     Append a narrative root cause and remediation plan
+                             from an OpenAI-compatible model
+    --ai-endpoint URL        API base URL (default https://api.openai.com/v1;
+                             e.g. http://localhost:11434/v1 for Ollama)
+    --ai-model MODEL         Model name (default gpt-4o-mini)
+    --ai-api-key KEY         API key (prefer TFCYCLE_AI_API_KEY or OPENAI_API_KEY)
+    --ai-prompt FILE         Go template replacing the built-in prompt
+
+VISUALIZE OPTIONS:
+    --scope SCOPE            minimal (smallest cycle) or full (all nodes and
+                             hypothesized edges, default minimal)
+    --rankdir DIR            Graph direction: LR, RL, TB or BT (default LR)
+    --node-shape SHAPE       Graphviz node shape (default box)
+    --font NAME              Font used for node and edge labels
+    --label MODE             Node labels: short (type.name) or full (address)
+    --max-label-length N     Truncate node labels longer than N characters
+
+EXAMPLES:
+    # Analyze error from terraform output
+    terraform plan 2>&1 | tfcycle analyze
+    
+    # Analyze error from file
+    tfcycle analyze cycle_error.txt
+    
+    # Generate DOT visualization
+    tfcycle visualize --output cycle.dot
+    
+    # Visualize every node and hypothesized edge, highlighting the minimal cycle
+    tfcycle visualize --scope full --output cycle.dot
+    
+    # Top-to-bottom graph with full resource addresses
+    tfcycle visualize --rankdir TB --label full --max-label-length 40
+    
+    # Verbose JSON output
+    tfcycle analyze --verbose --json
+    
+    # Generate HCL patches for the configuration in the current directory
+    terraform plan 2>&1 | tfcycle fix --config-dir .
+    
+    # Ordered targeted applies that break the deadlock
+    tfcycle analyze --error-file cycle_error.txt --format plan
+    
+    # Write a runnable remediation script with confirmation prompts
+    tfcycle analyze --error-file cycle_error.txt --config-dir . --emit-script fix.sh
+    
+    # Explain one resource from an error or a saved JSON analysis
+    tfcycle explain aws_security_group.sg1 --error-file cycle_error.txt
+    
+    # Ask a local model for a root-cause narrative and remediation plan
+    tfcycle explain aws_security_group.sg1 --error-file cycle_error.txt --ai \
+        --ai-endpoint http://localhost:11434/v1 --ai-model llama3.1
+    
+    # Check whether a fix attempt changed the cycle
+    tfcycle diff before.txt after.txt
+    
+    # Re-plan and re-analyze whenever a .tf file changes
+    tfcycle watch --dir ./env/prod
+    
+    # Wrap terraform: output streams through, analysis is appended on a cycle
+    tfcycle run -- terraform plan -out=tfplan
+    
+    # Report cycles in CI but only fail the build on high-severity ones
+    tfcycle analyze --error-file cycle_error.txt --fail-on high
+    
+    # Only the cycle path, for scripts
+    tfcycle analyze --error-file cycle_error.txt --quiet
+    
+    # Focus on one module's security groups in a large cycle
+    tfcycle analyze --error-file cycle_error.txt --filter module=vpc --filter type=aws_security_group
+    
+    # Look only at the destroy side of a replacement cycle
+    tfcycle analyze --error-file cycle_error.txt --only-destroy
+    
+    # Drop into an existing pipeline unconditionally
+    terraform plan 2>&1 | tfcycle analyze --passthrough
+    
+    # Confirm or reject each guessed dependency, then re-analyze
+    tfcycle analyze --error-file cycle_error.txt --interactive
+    
+    # Enable shell completion
+    source <(tfcycle completion bash)
+    
+    # Generate the man page for packaging
+    tfcycle docs man --output tfcycle.1
+    
+    # Share a report publicly without leaking infrastructure names
+    tfcycle analyze --error-file cycle_error.txt --redact
+    
+    # List every minimal cycle of a large cycle in full
+    tfcycle analyze --error-file cycle_error.txt --all
+    
+    # Write every report format to reports/ in one run
+    tfcycle analyze --error-file cycle_error.txt --output-dir reports/
+    
+    # Profile minimal cycle enumeration on a large synthetic graph
+    tfcycle bench --nodes 5000 --cpuprofile cpu.out
+
+ENVIRONMENT:
+    Every option can also be set as TFCYCLE_<OPTION>, e.g. TFCYCLE_FORMAT=json,
+    TFCYCLE_NO_COLOR=1 or TFCYCLE_CONFIG_DIR=./env/prod. Command-line flags
+    override environment variables, which override the configuration file.
+
+EXIT CODES:
+    0    No cycle found (or the command succeeded)
+    1    A cycle at or above --fail-on severity was found (analyze, run, diff)
+    2    Invalid input, arguments or configuration directory
+    3    Internal error
+    run passes the wrapped command's exit code through when no cycle is found.
+
+DESCRIPTION:
+    tfcycle parses Terraform cycle error messages and provides clear, 
+    actionable analysis of dependency cycles in Infrastructure as Code.
+    It identifies minimal cycles and suggests common solutions.
+`
+)
+
+type Config struct {
+	Command   string
+	ErrorFile string
+	Output    string
+	Compress  bool
+	Clipboard bool
+	OutputDir string
+	ConfigDir string
+	Script    string
+	Verbose   bool
+	JSON      bool
+	Format    string
+	Lang      string
+	Redact    bool
+	Help      bool
+	Args      []string
+	
+	ShowVersion bool
+	
+	commandGiven bool
+	
+	Passthrough bool
+	Interactive bool
+	NoHistory   bool
+	CompareLast bool
+	HistoryDB   string
+	FailOn      string
+	NoColor     bool
+	Quiet       bool
+	
+	Timeout      time.Duration
+	MaxInputSize int64
+	StdinTimeout time.Duration
+	LogLevel     string
+	LogFile      string
+	CPUProfile   string
+	MemProfile   string
+	configUsed   string
+	
+	ConfigFile    string
+	DisabledRules []string
+	EnabledRules  []string
+	Suppress      []string
+	NoSuggestions bool
+	Baseline      string
+	Filters       []string
+	Excludes      []string
+	OnlyDestroy   bool
+	
+	Dir      string
+	Interval time.Duration
+	
+	Listen         string
+	GRPCListen     string
+	RunTaskHMACKey string
+	Theme          string
+	CSSFile        string
+	
+	AI         bool
+	AIEndpoint string
+	AIModel    string
+	AIAPIKey   string
+	AIPrompt   string
+	
+	Datadog       bool
+	DatadogSite   string
+	DatadogAPIKey string
+	
+	NotifyWebhook   string
+	PayloadTemplate string
+	
+	CheckOnly       bool
+	NoPlugins       bool
+	NoFeedback      bool
+	SuggestionRules string
+	TFVersion       string
+	StateFile       string
+	CDKTF           string
+	BenchNodes      int
+	Analyses        bool
+	ContextLines    int
+	InputFormat     string
+	
+	InsecureSkipSignature bool
+	
+	Scope          string
+	RankDir        string
+	NodeShape      string
+	FontName       string
+	LabelMode      string
+	MaxLabelLength int
+	
+	MaxCyclesShown int
+	MaxNodesShown  int
+	ShowAll        bool
+	TemplateDir    string
+}
+
+func main() {
+	config, err := parseArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitInputError)
+	}
+	
+	if config.Help || config.Command == "help" {
+		printHelp(config)
+		return
+	}
+	
+	closeLog, err := setupLogging(config.LogLevel, config.LogFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitInputError)
+	}
+	defer closeLog()
+	if config.configUsed != "" {
+		logger.Info("loaded configuration file", "path", config.configUsed)
+	}
+	
+	stopProfiling, err := startProfiling(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitInputError)
+	}
+	defer stopProfiling()
+	
+	if config.Command == "version" || config.ShowVersion {
+		if err := runVersion(config); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitInputError)
+		}
+		return
+	}
+	
+	if err := withCrashRecovery(config.Command, func() error { return runCommand(config) }); err != nil {
+		var exitErr *ExitError
+		if !errors.As(err, &exitErr) || exitErr.Err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		stopProfiling()
+		closeLog()
+		os.Exit(exitCode(err))
+	}
+}
+
+func printHelp(config Config) {
+	name := ""
+	switch {
+	case config.Command == "help" && len(config.Args) > 0:
+		name = config.Args[0]
+	case config.Command != "help" && config.commandGiven:
+		name = config.Command
+	}
+	
+	if command, ok := lookupCommand(name); ok {
+		fmt.Print(CommandUsage(command))
+		return
+	}
+	fmt.Print(usage)
+}
+
+func parseArgs(args []string) (Config, error) {
+	config := Config{
+		Command: "analyze",
+		FailOn:   "low",
+		LogLevel: "warn",
+	}
+	
+	var passthrough []string
+	for i, arg := range args {
+		if arg == "--" {
+			passthrough = args[i+1:]
+			args = args[:i]
+			break
+		}
+	}
+	
+	leading := allFlagsSet(&Config{})
+	if err := leading.Parse(args); err != nil && err != flag.ErrHelp {
+		return config, err
+	}
+	if rest := leading.Args(); len(rest) > 0 && !isInputFileArg(rest[0]) {
+		config.Command = rest[0]
+		config.commandGiven = true
+		consumed := len(args) - len(rest)
+		args = append(append([]string{}, args[:consumed]...), rest[1:]...)
+	}
+	
+	command, ok := lookupCommand(config.Command)
+	if !ok {
+		return config, fmt.Errorf("unknown command: %s", config.Command)
+	}
+	
+	fs := newCommandFlagSet(command, &config)
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				config.Help = true
+				return config, nil
+			}
+			return config, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		config.Args = append(config.Args, rest[0])
+		args = rest[1:]
+	}
+	if err := applyInputArg(command, &config); err != nil {
+		return config, err
+	}
+	config.Args = append(config.Args, passthrough...)
+	
+	if err := applyEnvironment(fs, os.LookupEnv); err != nil {
+		return config, err
+	}
+	if config.ErrorFile == "-" {
+		config.ErrorFile = ""
+	}
+	
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	
+	fileConfig, path, err := LoadFileConfig(config.ConfigFile)
+	if err != nil {
+		if config.Command == "doctor" {
+			return config, nil
+		}
+		return config, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	fileConfig.Apply(&config, explicit)
+	config.configUsed = path
+	
+	return config, nil
+}
+
+// isInputFileArg reports whether arg, given where a command is expected, is
+// an input for the default analyze command instead: "-" or an existing file
+// that is not named like a command.
+func isInputFileArg(arg string) bool {
+	if _, ok := lookupCommand(arg); ok {
+		return false
+	}
+	if arg == "-" {
+		return true
+	}
+	stat, err := os.Stat(arg)
+	return err == nil && !stat.IsDir()
+}
+
+// applyInputArg moves the optional FILE argument of commands whose synopsis
+// ends in "[FILE]" to ErrorFile, so "tfcycle analyze error.txt" works like
+// --error-file error.txt. "-" is stdin.
+func applyInputArg(command commandInfo, config *Config) error {
+	if !strings.HasSuffix(command.Args, "[FILE]") {
+		return nil
+	}
+	fixed := len(strings.Fields(command.Args)) - 1
+	if len(config.Args) <= fixed {
+		return nil
+	}
+	if len(config.Args) > fixed+1 {
+		return fmt.Errorf("%s reads one input file, got %s", command.Name, strings.Join(config.Args[fixed:], " "))
+	}
+	if config.ErrorFile != "" {
+		return fmt.Errorf("input file %s given with --error-file %s", config.Args[fixed], config.ErrorFile)
+	}
+	config.ErrorFile = config.Args[fixed]
+	config.Args = config.Args[:fixed]
+	return nil
+}
+
+func runCommand(config Config) error {
+	if config.Command == "doctor" {
+		return runDoctor(config)
+	}
+	
+	if _, err := tfcycle.ParseSeverity(config.FailOn); err != nil {
+		return inputErrorf("--fail-on: %w", err)
+	}
+	if err := validateRuleIDs(config); err != nil {
+		return err
+	}
+	if config.TFVersion != "" {
+		if err := tfcycle.ValidateVersion(config.TFVersion); err != nil {
+			return inputErrorf("--tf-version: %w", err)
+		}
+	}
+	if config.InputFormat != "" && !slices.Contains(tfcycle.InputFormats, config.InputFormat) {
+		return inputErrorf("--input-format: unknown input format %q (use %s)", config.InputFormat, strings.Join(tfcycle.InputFormats, ", "))
+	}
+	if config.Compress {
+		if config.Output == "" && config.OutputDir == "" {
+			return inputErrorf("--compress needs --output or --output-dir")
+		}
+		if config.Output != "" && !strings.HasSuffix(config.Output, ".gz") {
+			config.Output += ".gz"
+		}
+	}
+	if config.Passthrough && config.Redact {
+		return inputErrorf("--passthrough echoes the input unredacted and cannot be used with --redact")
+	}
+	
+	ctx, cancel := commandContext(config)
+	defer cancel()
+	
+	switch config.Command {
+	case "analyze":
+		return runAnalyze(ctx, config)
+	case "visualize":
+		return runVisualize(ctx, config)
+	case "fix":
+		return runFix(ctx, config)
+	case "explain":
+		return runExplain(ctx, config)
+	case "blast-radius":
+		return runBlastRadius(ctx, config)
+	case "aggregate":
+		return runAggregate(ctx, config)
+	case "stats":
+		return runStats(ctx, config)
+	case "lint":
+		return runLint(config)
+	case "annotate":
+		return runAnnotate(ctx, config)
+	case "diff":
+		return runDiff(ctx, config)
+	case "watch":
+		return runWatch(config)
+	case "run":
+		return runRun(config)
+	case "serve":
+		return runServe(config)
+	case "mcp":
+		return runMCP(config)
+	case "lsp":
+		return runLSP(config)
+	case "history":
+		return runHistory(config)
+	case "completion":
+		return runCompletion(config)
+	case "docs":
+		return runDocs(config)
+	case "capabilities":
+		return runCapabilities(config)
+	case "self-update":
+		return runSelfUpdate(ctx, config)
+	case "plugins":
+		return runPlugins(config)
+	case "bench":
+		return runBench(ctx, config)
+	case "rules":
+		return runRules(config)
+	case "baseline":
+		return runBaseline(ctx, config)
+	case "feedback":
+		return runFeedback(config)
+	default:
+		return inputErrorf("unknown command: %s", config.Command)
+	}
+}
+
+// setDisplayLimits applies --max-cycles-shown, --max-nodes-shown and --all to
+// the text report.
+func setDisplayLimits(formatter *tfcycle.OutputFormatter, config Config) {
+	if config.ShowAll {
+		formatter.SetDisplayLimits(0, 0)
+		return
+	}
+	formatter.SetDisplayLimits(config.MaxCyclesShown, config.MaxNodesShown)
+}
+
+// setTemplates applies --template-dir to the text and markdown reports.
+func setTemplates(formatter *tfcycle.OutputFormatter, config Config) error {
+	if config.TemplateDir == "" {
+		return nil
+	}
+	templates, err := tfcycle.LoadReportTemplates(config.TemplateDir)
+	if err != nil {
+		return inputErrorf("--template-dir: %w", err)
+	}
+	formatter.SetTemplates(templates)
+	return nil
+}
+
+// versionOptions returns the analyzer options for --tf-version, or none if it
+// is not set and the version is detected from the input.
+func versionOptions(config Config) []tfcycle.Option {
+	if config.TFVersion == "" {
+		return nil
+	}
+	return []tfcycle.Option{tfcycle.WithTerraformVersion(config.TFVersion)}
+}
+
+func commandContext(config Config) (context.Context, context.CancelFunc) {
+	if config.Timeout > 0 {
+		return context.WithTimeout(context.Background(), config.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+func filterCycle(cycle *tfcycle.TfCycle, config Config) (*tfcycle.TfCycle, error) {
+	filter, err := tfcycle.ParseNodeFilter(config.Filters, config.Excludes)
+	if err != nil {
+		return nil, inputError(err)
+	}
+	filter.OnlyDestroy = config.OnlyDestroy
+	if filter.Empty() {
+		return cycle, nil
+	}
+	
+	filtered := filter.Apply(cycle)
+	if len(filtered.Nodes) == 0 {
+		return nil, inputErrorf("no cycle nodes remain after applying filters and exclusions")
+	}
+	return filtered, nil
+}
+
+// resolveConstructPaths sets the CDKTF construct paths of the nodes of cycle
+// if --cdktf is given.
+func resolveConstructPaths(cycle *tfcycle.TfCycle, config Config) error {
+	if config.CDKTF == "" {
+		return nil
+	}
+	paths, err := tfcycle.LoadConstructPaths(config.CDKTF)
+	if err != nil {
+		return inputErrorf("failed to read CDKTF output: %w", err)
+	}
+	logger.Debug("resolved CDKTF construct paths", "nodes", paths.Resolve(cycle))
+	return nil
+}
+
+// scanConfig scans the Terraform configuration in dir, showing progress on
+// a terminal.
+func scanConfig(dir string, config Config) (*tfcycle.ConfigIndex, error) {
+	defer showProgress(config, "Scanning "+dir)()
+	return tfcycle.ScanConfig(dir)
+}
+
+func analyzeWithTimeout(ctx context.Context, analyzer *tfcycle.CycleAnalyzer, config Config) {
+	defer showProgress(config, "Searching for minimal cycles")()
+	if err := analyzer.AnalyzeContext(ctx); err != nil {
+		logger.Warn("analysis timed out, reporting the cycle as listed by Terraform", "timeout", config.Timeout)
+	}
+}
+
+func runAnalyze(ctx context.Context, config Config) error {
+	var echo io.Writer
+	if config.Passthrough {
+		echo = os.Stdout
+	}
+	
+	errorText, versions, err := readInputEcho(ctx, config.ErrorFile, echo, config, config.ContextLines)
+	if err != nil {
+		return inputErrorf("failed to read input: %w", err)
+	}
+	
+	parser := tfcycle.NewParser()
+	parser.SetContextLines(config.ContextLines)
+	if !parser.ContainsCycleFormat(errorText, config.InputFormat) {
+		if !config.Passthrough && !config.Quiet {
+			fmt.Fprintln(os.Stderr, "No Terraform cycle error found in input")
+		}
+		printSummary(analyzerSummary(nil))
+		if outputFormat(config) == "opa" {
+			output, err := tfcycle.FormatOPAInput(tfcycle.NewOPAInput(nil))
+			if err != nil {
+				return err
+			}
+			return writeReport(output, config)
+		}
+		return nil
+	}
+	if config.Passthrough && config.Output == "" && config.OutputDir == "" {
+		fmt.Println()
+	}
+	
+	cycle, err := parser.ParseFormat(errorText, config.InputFormat)
+	if err != nil {
+		return inputErrorf("failed to parse cycle error: %w", err)
+	}
+	withInputVersions(cycle, versions)
+	
+	cycle, err = filterCycle(cycle, config)
+	if err != nil {
+		return err
+	}
+	
+	var index *tfcycle.ConfigIndex
+	if config.ConfigDir != "" {
+		index, err = scanConfig(config.ConfigDir, config)
+		if err != nil {
+			return inputError(err)
+		}
+		index.ResolveLocations(cycle)
+	}
+	if err := resolveConstructPaths(cycle, config); err != nil {
+		return err
+	}
+	
+	if config.Redact {
+		cycle = tfcycle.NewRedactor().RedactCycle(cycle)
+	}
+	
+	ruleOpts, err := suggestionRulesOptions(config)
+	if err != nil {
+		return err
+	}
+	opts := append(analyzerOptions(config, index), feedbackOptions(config)...)
+	opts = append(opts, versionOptions(config)...)
+	analyzer := tfcycle.NewCycleAnalyzer(cycle, append(opts, ruleOpts...)...)
+	if config.Interactive {
+		if err := confirmInteractively(analyzer, config); err != nil {
+			return err
+		}
+	}
+	analyzeWithTimeout(ctx, analyzer, config)
+	accepted, err := applyBaseline(analyzer, config)
+	if err != nil {
+		return err
+	}
+	if accepted > 0 && len(analyzer.FindMinimalCycles()) == 0 {
+		if !config.Passthrough && !config.Quiet {
+			fmt.Fprintf(os.Stderr, "No new cycles: all %d are accepted in %s\n", accepted, config.Baseline)
+		}
+		printSummary(analyzerSummary(analyzer))
+		return nil
+	}
+	if accepted > 0 && !config.Quiet {
+		fmt.Fprintf(os.Stderr, "Left out %d cycles accepted in %s\n", accepted, config.Baseline)
+	}
+	formatter := tfcycle.NewOutputFormatter(analyzer, config.Verbose)
+	setDisplayLimits(formatter, config)
+	if err := setTemplates(formatter, config); err != nil {
+		return err
+	}
+	
+	entry := currentHistoryEntry(analyzer, config)
+	if config.CompareLast {
+		formatter.SetComparison(compareLast(entry, config))
+	}
+	recordHistory(entry, config)
+	notifyDatadog(entry, config)
+	notifyWebhook(analyzer, entry, config)
+	
+	if config.Script != "" {
+		var fixes []tfcycle.Fix
+		if index != nil && !config.Redact && !config.NoSuggestions {
+			fixes = generateFixes(analyzer, index, config)
+		}
+		if err := writeScript(formatter.FormatRemediationScript(fixes), config.Script); err != nil {
+			return err
+		}
+		if !config.Quiet {
+			fmt.Fprintf(os.Stderr, "Wrote %s\n", config.Script)
+		}
+	}
+	
+	lang, err := tfcycle.ResolveLanguage(config.Lang)
+	if err != nil {
+		return inputError(err)
+	}
+	if err := formatter.SetLanguage(lang); err != nil {
+		return err
+	}
+	
+	if config.OutputDir != "" {
+		visualOpts, err := visualOptionsFromConfig(config)
+		if err != nil {
+			return inputError(err)
+		}
+		
+		written, err := writeArtifacts(formatter, config.OutputDir, artifactBasename(config), config.ErrorFile, visualOpts, config.Compress)
+		if err != nil {
+			return err
+		}
+		
+		if !config.Quiet {
+			for _, path := range written {
+				fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+			}
+		}
+		printSummary(analyzerSummary(analyzer))
+		return cycleFoundError(analyzer, config, ExitNoCycle)
+	}
+	
+	output, err := formatReport(formatter, config)
+	if err != nil {
+		return err
+	}
+	
+	if err := writeReport(output, config); err != nil {
+		return err
+	}
+	printSummary(analyzerSummary(analyzer))
+	return cycleFoundError(analyzer, config, ExitNoCycle)
+}
+
+func cycleFoundError(analyzer *tfcycle.CycleAnalyzer, config Config, fallback int) error {
+	threshold, _ := tfcycle.ParseSeverity(config.FailOn)
+	if threshold != tfcycle.SeverityNone && analyzer.Severity() >= threshold {
+		return &ExitError{Code: ExitCycleFound}
+	}
+	if fallback != ExitNoCycle {
+		return &ExitError{Code: fallback}
+	}
+	return nil
+}
+
+func formatReport(formatter *tfcycle.OutputFormatter, config Config) (string, error) {
+	switch outputFormat(config) {
+	case "json":
+		output, err := formatter.FormatAsJSON()
+		if err != nil {
+			return "", fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		return output, nil
+	case "markdown":
+		return formatter.FormatAsMarkdown(), nil
+	case "plan":
+		return formatter.FormatApplyPlan(), nil
+	case "opa":
+		return tfcycle.FormatOPAInput(tfcycle.NewOPAInput(formatter.Analyzer()))
+	case "jgf":
+		return tfcycle.FormatJGF(tfcycle.NewJGFDocument(formatter.Analyzer()))
+	case "rdjson":
+		return tfcycle.FormatRDJSON(tfcycle.NewRDJSONResult(formatter.Analyzer()))
+	case "warnings-ng":
+		return tfcycle.FormatWarningsNG(tfcycle.NewWarningsNGReport(formatter.Analyzer()))
+	case "text":
+		if config.Quiet {
+			return formatter.FormatCyclePaths(), nil
+		}
+		return formatter.FormatAnalysis(), nil
+	default:
+		if plugin, ok := findFormatPlugin(outputFormat(config)); ok {
+			return runFormatPlugin(plugin, formatter)
+		}
+		return "", inputErrorf("unknown format: %s (no %s%s plugin on PATH)", config.Format, formatPluginPrefix, config.Format)
+	}
+}
+
+func outputFormat(config Config) string {
+	if config.Format != "" {
+		return config.Format
+	}
+	if config.JSON {
+		return "json"
+	}
+	return "text"
+}
+
+func runVisualize(ctx context.Context, config Config) error {
+	visualOpts, err := visualOptionsFromConfig(config)
+	if err != nil {
+		return inputError(err)
+	}
+	
+	errorText, versions, err := readInput(ctx, config.ErrorFile, config)
+	if err != nil {
+		return inputErrorf("failed to read input: %w", err)
+	}
+	
+	parser := tfcycle.NewParser()
+	cycle, err := parser.ParseFormat(errorText, config.InputFormat)
+	if err != nil {
+		return inputErrorf("failed to parse cycle error: %w", err)
+	}
+	withInputVersions(cycle, versions)
+	
+	cycle, err = filterCycle(cycle, config)
+	if err != nil {
+		return err
+	}
+	
+	if config.ConfigDir != "" {
+		index, err := scanConfig(config.ConfigDir, config)
+		if err != nil {
+			return inputError(err)
+		}
+		index.ResolveLocations(cycle)
+	}
+	
+	if config.Redact {
+		cycle = tfcycle.NewRedactor().RedactCycle(cycle)
+	}
+	
+	analyzer := tfcycle.NewCycleAnalyzer(cycle)
+	analyzeWithTimeout(ctx, analyzer, config)
+	formatter := tfcycle.NewOutputFormatter(analyzer, false)
+	
+	dotOutput := formatter.GenerateVisualization(visualOpts)
+	if dotOutput == "" {
+		return inputErrorf("no cycles found to visualize")
+	}
+	
+	return writeReport(dotOutput, config)
+}
+
+func runFix(ctx context.Context, config Config) error {
+	errorText, versions, err := readInput(ctx, config.ErrorFile, config)
+	if err != nil {
+		return inputErrorf("failed to read input: %w", err)
+	}
+	
+	parser := tfcycle.NewParser()
+	cycle, err := parser.ParseFormat(errorText, config.InputFormat)
+	if err != nil {
+		return inputErrorf("failed to parse cycle error: %w", err)
+	}
+	withInputVersions(cycle, versions)
+	
+	cycle, err = filterCycle(cycle, config)
+	if err != nil {
+		return err
+	}
+	
+	configDir := config.ConfigDir
+	if configDir == "" {
+		configDir = "."
+	}
+	
+	index, err := scanConfig(configDir, config)
+	if err != nil {
+		return inputError(err)
+	}
+	index.ResolveLocations(cycle)
+	
+	ruleOpts, err := suggestionRulesOptions(config)
+	if err != nil {
+		return err
+	}
+	analyzer := tfcycle.NewCycleAnalyzer(cycle, append(versionOptions(config), ruleOpts...)...)
+	analyzeWithTimeout(ctx, analyzer, config)
+	formatter := tfcycle.NewOutputFormatter(analyzer, config.Verbose)
+	fixes := generateFixes(analyzer, index, config)
+	
+	var output string
+	if outputFormat(config) == "json" {
+		output, err = formatter.FormatFixesAsJSON(fixes)
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+	} else {
+		output = formatter.FormatFixes(fixes)
+	}
+	
+	return writeReport(output, config)
+}
+
+func runBlastRadius(ctx context.Context, config Config) error {
+	errorText, versions, err := readInput(ctx, config.ErrorFile, config)
+	if err != nil {
+		return inputErrorf("failed to read input: %w", err)
+	}
+	
+	cycle, err := tfcycle.NewParser().ParseFormat(errorText, config.InputFormat)
+	if err != nil {
+		return inputErrorf("failed to parse cycle error: %w", err)
+	}
+	withInputVersions(cycle, versions)
+	
+	cycle, err = filterCycle(cycle, config)
+	if err != nil {
+		return err
+	}
+	
+	var index *tfcycle.ConfigIndex
+	if config.ConfigDir != "" {
+		index, err = scanConfig(config.ConfigDir, config)
+		if err != nil {
+			return inputError(err)
+		}
+		index.ResolveLocations(cycle)
+	}
+	
+	analyzer := tfcycle.NewCycleAnalyzer(cycle, append(analyzerOptions(config, index), feedbackOptions(config)...)...)
+	analyzeWithTimeout(ctx, analyzer, config)
+	formatter := tfcycle.NewOutputFormatter(analyzer, config.Verbose)
+	radius := analyzer.BlastRadius(index)
+	
+	var output string
+	if outputFormat(config) == "json" {
+		output, err = formatter.FormatBlastRadiusAsJSON(radius)
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		output += "\n"
+	} else {
+		output = formatter.FormatBlastRadius(radius)
+	}
+	
+	return writeReport(output, config)
+}
+
+func runExplain(ctx context.Context, config Config) error {
+	if len(config.Args) != 1 {
+		return inputErrorf("explain requires exactly one resource address, e.g. tfcycle explain aws_security_group.sg1")
+	}
+	
+	inputText, versions, err := readInput(ctx, config.ErrorFile, config)
+	if err != nil {
+		return inputErrorf("failed to read input: %w", err)
+	}
+	
+	cycle, err := tfcycle.NewParser().ParseFormat(inputText, config.InputFormat)
+	if err != nil {
+		return inputErrorf("failed to parse cycle error: %w", err)
+	}
+	withInputVersions(cycle, versions)
+	
+	if config.ConfigDir != "" {
+		index, err := scanConfig(config.ConfigDir, config)
+		if err != nil {
+			return inputError(err)
+		}
+		index.ResolveLocations(cycle)
+	}
+	if err := resolveConstructPaths(cycle, config); err != nil {
+		return err
+	}
+	
+	ruleOpts, err := suggestionRulesOptions(config)
+	if err != nil {
+		return err
+	}
+	opts := append(feedbackOptions(config), versionOptions(config)...)
+	analyzer := tfcycle.NewCycleAnalyzer(cycle, append(opts, ruleOpts...)...)
+	analyzeWithTimeout(ctx, analyzer, config)
+	formatter := tfcycle.NewOutputFormatter(analyzer, config.Verbose)
+	
+	nodes := analyzer.MatchNodes(config.Args[0])
+	if len(nodes) == 0 {
+		return inputErrorf("resource %s is not part of the cycle", config.Args[0])
+	}
+	
+	explanations := make([]tfcycle.NodeExplanation, len(nodes))
+	for i, node := range nodes {
+		explanations[i] = analyzer.ExplainNode(node)
+	}
+	
+	var aiExplanation string
+	if config.AI {
+		explainer, err := NewAIExplainer(config)
+		if err != nil {
+			return inputError(err)
+		}
+		stop := showProgress(config, "Waiting for "+config.AIModel)
+		aiExplanation, err = explainer.Explain(ctx, NewAIAnalysis(analyzer, config.Args[0], explanations))
+		stop()
+		if err != nil {
+			return err
+		}
+	}
+	
+	var output string
+	if outputFormat(config) == "json" {
+		var value interface{} = explanations
+		if config.AI {
+			value = map[string]interface{}{"explanations": explanations, "ai_explanation": aiExplanation}
+		}
+		jsonData, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		output = string(jsonData) + "\n"
+	} else {
+		var text strings.Builder
+		for _, explanation := range explanations {
+			text.WriteString(formatter.FormatExplanation(explanation))
+		}
+		if config.AI {
+			text.WriteString("🤖 AI explanation:\n\n" + aiExplanation + "\n")
+		}
+		output = text.String()
+	}
+	
+	return writeReport(output, config)
+}
+
+func runDiff(ctx context.Context, config Config) error {
+	if len(config.Args) != 2 {
+		return inputErrorf("diff requires two error files, e.g. tfcycle diff old.txt new.txt")
+	}
+	
+	cycles := make([]*tfcycle.TfCycle, 2)
+	for i, filename := range config.Args {
+		inputText, versions, err := readInput(ctx, filename, config)
+		if err != nil {
+			return inputErrorf("failed to read input: %w", err)
+		}
+		
+		if !tfcycle.IsCycleInput(inputText) {
+			cycles[i] = &tfcycle.TfCycle{RawError: inputText, Nodes: []*tfcycle.CycleNode{}}
+			continue
+		}
+		
+		cycles[i], err = tfcycle.ParseInput(inputText)
+		if err != nil {
+			return inputErrorf("failed to parse %s: %w", filename, err)
+		}
+		withInputVersions(cycles[i], versions)
+	}
+	
+	diff := tfcycle.DiffCycles(cycles[0], cycles[1])
+	
+	var output string
+	if outputFormat(config) == "json" {
+		jsonData, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		output = string(jsonData) + "\n"
+	} else {
+		output = tfcycle.FormatCycleDiff(diff, colorEnabled(config))
+	}
+	
+	if err := writeReport(output, config); err != nil {
+		return err
+	}
+	if len(cycles[1].Nodes) > 0 {
+		return cycleFoundError(tfcycle.NewCycleAnalyzer(cycles[1]), config, ExitNoCycle)
+	}
+	return nil
+}
+
+func colorEnabled(config Config) bool {
+	if config.NoColor || config.Output != "" || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+func runWatch(config Config) error {
+	lang, err := tfcycle.ResolveLanguage(config.Lang)
+	if err != nil {
+		return inputError(err)
+	}
+	
+	var templates *tfcycle.ReportTemplates
+	if config.TemplateDir != "" {
+		if templates, err = tfcycle.LoadReportTemplates(config.TemplateDir); err != nil {
+			return inputErrorf("--template-dir: %w", err)
+		}
+	}
+	
+	ctx, stop := watchContext()
+	defer stop()
+	
+	watcher := NewWatcher(config.Dir, config.Interval, os.Stdout)
+	watcher.Clear = colorEnabled(config)
+	watcher.Render = func(cycle *tfcycle.TfCycle) string {
+		analyzer := tfcycle.NewCycleAnalyzer(cycle)
+		formatter := tfcycle.NewOutputFormatter(analyzer, config.Verbose)
+		formatter.SetLanguage(lang)
+		setDisplayLimits(formatter, config)
+		formatter.SetTemplates(templates)
+		return formatter.FormatAnalysis()
+	}
+	
+	if err := watcher.Run(ctx); err != nil {
+		return inputError(err)
+	}
+	return nil
+}
+
+func runRun(config Config) error {
+	if len(config.Args) == 0 {
+		return inputErrorf("run requires a command, e.g. tfcycle run -- terraform plan")
+	}
+	
+	captured, exitCode, err := runWrapped(config.Args, os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		return inputError(err)
+	}
+	
+	parser := tfcycle.NewParser()
+	parser.SetContextLines(config.ContextLines)
+	if !parser.ContainsCycle(captured) {
+		printSummary(analyzerSummary(nil))
+		if exitCode != 0 {
+			return &ExitError{Code: exitCode}
+		}
+		return nil
+	}
+	
+	cycle, err := parser.ParseError(captured)
+	if err != nil {
+		return inputErrorf("failed to parse cycle error: %w", err)
+	}
+	
+	cycle, err = filterCycle(cycle, config)
+	if err != nil {
+		return err
+	}
+	
+	var index *tfcycle.ConfigIndex
+	if config.ConfigDir != "" {
+		index, err = scanConfig(config.ConfigDir, config)
+		if err != nil {
+			return inputError(err)
+		}
+		index.ResolveLocations(cycle)
+	}
+	if err := resolveConstructPaths(cycle, config); err != nil {
+		return err
+	}
+	
+	if config.Redact {
+		cycle = tfcycle.NewRedactor().RedactCycle(cycle)
+	}
+	
+	ruleOpts, err := suggestionRulesOptions(config)
+	if err != nil {
+		return err
+	}
+	opts := append(analyzerOptions(config, index), feedbackOptions(config)...)
+	opts = append(opts, versionOptions(config)...)
+	analyzer := tfcycle.NewCycleAnalyzer(cycle, append(opts, ruleOpts...)...)
+	entry := currentHistoryEntry(analyzer, config)
+	recordHistory(entry, config)
+	notifyDatadog(entry, config)
+	notifyWebhook(analyzer, entry, config)
+	formatter := tfcycle.NewOutputFormatter(analyzer, config.Verbose)
+	setDisplayLimits(formatter, config)
+	if err := setTemplates(formatter, config); err != nil {
+		return err
+	}
+	lang, err := tfcycle.ResolveLanguage(config.Lang)
+	if err != nil {
+		return inputError(err)
+	}
+	if err := formatter.SetLanguage(lang); err != nil {
+		return err
+	}
+	
+	output, err := formatReport(formatter, config)
+	if err != nil {
+		return err
+	}
+	if config.Output == "" {
+		output = "\n" + output
+	}
+	if err := writeOutput(output, config.Output); err != nil {
+		return err
+	}
+	printSummary(analyzerSummary(analyzer))
+	
+	return cycleFoundError(analyzer, config, exitCode)
+}
+
+func runHistory(config Config) error {
+	if len(config.Args) == 0 {
+		return inputErrorf("history requires a subcommand: list, show ID, top or weekly")
+	}
+	
+	history, err := NewHistory(config)
+	if err != nil {
+		return inputError(err)
+	}
+	defer history.Close()
+	
+	entries, err := history.List()
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+	
+	var value interface{}
+	var output string
+	switch config.Args[0] {
+	case "list":
+		value = entries
+		output = FormatHistoryList(entries)
+	case "show":
+		if len(config.Args) != 2 {
+			return inputErrorf("history show requires an ID or fingerprint, see tfcycle history list")
+		}
+		entry, err := history.Find(config.Args[1])
+		if err != nil {
+			return inputError(err)
+		}
+		value = entry
+		output = FormatHistoryEntry(entry, countFingerprint(entries, entry.Fingerprint))
+	case "top":
+		limit := 10
+		if len(config.Args) > 1 {
+			limit, err = strconv.Atoi(config.Args[1])
+			if err != nil || limit < 1 {
+				return inputErrorf("history top expects a positive number, got %q", config.Args[1])
+			}
+		}
+		top := TopResources(entries, limit)
+		value = top
+		output = FormatTopResources(top)
+	case "weekly":
+		weeks := CyclesPerWeek(entries)
+		value = weeks
+		output = FormatCyclesPerWeek(weeks)
+	default:
+		return inputErrorf("unknown history subcommand %q (use list, show, top or weekly)", config.Args[0])
+	}
+	
+	if outputFormat(config) == "json" {
+		jsonData, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		output = string(jsonData) + "\n"
+	}
+	
+	return writeOutput(output, config.Output)
+}
+
+func runDoctor(config Config) error {
+	checks := NewDoctor(config.ConfigFile).Run()
+	
+	var output string
+	if outputFormat(config) == "json" {
+		jsonData, err := json.MarshalIndent(checks, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		output = string(jsonData) + "\n"
+	} else {
+		output = FormatDoctorChecks(checks, colorEnabled(config))
+	}
+	
+	if err := writeOutput(output, config.Output); err != nil {
+		return err
+	}
+	if failures := doctorFailures(checks); failures > 0 {
+		return inputErrorf("doctor found %d problem(s)", failures)
+	}
+	return nil
+}
+
+func runCompletion(config Config) error {
+	if len(config.Args) != 1 {
+		return inputErrorf("completion requires a shell: bash, zsh, fish or powershell")
+	}
+	
+	script, err := GenerateCompletion(config.Args[0], allFlagsSet(&Config{}))
+	if err != nil {
+		return inputError(err)
+	}
+	
+	return writeOutput(script, config.Output)
+}
+
+func runDocs(config Config) error {
+	if len(config.Args) != 1 {
+		return inputErrorf("docs requires a format: man or markdown")
+	}
+	
+	docs, err := GenerateDocs(config.Args[0], allFlagsSet(&Config{}))
+	if err != nil {
+		return inputError(err)
+	}
+	
+	return writeOutput(docs, config.Output)
+}
+
+func visualOptionsFromConfig(config Config) (tfcycle.VisualOptions, error) {
+	opts := tfcycle.DefaultVisualOptions()
+	opts.Scope = config.Scope
+	opts.RankDir = config.RankDir
+	opts.NodeShape = config.NodeShape
+	opts.FontName = config.FontName
+	opts.MaxLabelLength = config.MaxLabelLength
+	
+	switch config.LabelMode {
+	case "short", "":
+		opts.FullLabels = false
+	case "full":
+		opts.FullLabels = true
+	default:
+		return opts, fmt.Errorf("invalid label mode %q (expected short or full)", config.LabelMode)
+	}
+	
+	if err := opts.Validate(); err != nil {
+		return opts, err
+	}
+	
+	return opts, nil
+}
+
+// withInputVersions records the versions that readInput saw before the
+// cycle diagnostic when the diagnostic and its context mention none, so that
+// version-specific advice does not depend on --context-lines.
+func withInputVersions(cycle *tfcycle.TfCycle, versions *tfcycle.Versions) {
+	if cycle.Versions == nil {
+		cycle.Versions = versions
+	}
+}
+
+func readInput(ctx context.Context, filename string, config Config) (string, *tfcycle.Versions, error) {
+	return readInputEcho(ctx, filename, nil, config, 0)
+}
+
+// readInputEcho streams the input and returns only the cycle diagnostic (or a
+// JSON report), so large CI logs are not held in memory, preceded by up to
+// contextLines lines of its context, and the versions mentioned anywhere
+// before the diagnostic (see withInputVersions). It returns "" for input
+// without a cycle error. Every byte is still copied to echo. Reading stdin or
+// a named pipe fails if nothing arrives within config.StdinTimeout; opening a
+// named pipe waits for a writer, so it happens under the same timeout.
+func readInputEcho(ctx context.Context, filename string, echo io.Writer, config Config, contextLines int) (string, *tfcycle.Versions, error) {
+	maxSize := config.MaxInputSize
+	source := "stdin"
+	waitsForInput := true
+	if filename != "" && filename != "-" {
+		stat, err := os.Stat(filename)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+		}
+		source = "named pipe " + filename
+		waitsForInput = stat.Mode()&os.ModeNamedPipe != 0
+	} else if isTerminal(os.Stdin) {
+		return "", nil, fmt.Errorf("no input provided. Use --error-file or pipe input to stdin")
+	}
+	
+	var mu sync.Mutex
+	section := tfcycle.CycleSection{ContextLines: contextLines}
+	arrived := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		var reader io.Reader = os.Stdin
+		if filename != "" && filename != "-" {
+			file, err := os.Open(filename)
+			if err != nil {
+				done <- fmt.Errorf("failed to open file %s: %w", filename, err)
+				return
+			}
+			defer file.Close()
+			reader = file
+		}
+		reader = &arrivalReader{reader: reader, arrived: arrived}
+		if maxSize > 0 {
+			reader = io.LimitReader(reader, maxSize+1)
+		}
+		if echo != nil {
+			reader = io.TeeReader(reader, echo)
+		}
+		
+		// bufio.Reader rather than bufio.Scanner: Terraform prints a cycle on
+		// one line, which exceeds the scanner's 64KB limit for large graphs.
+		buffered := bufio.NewReader(tfcycle.InputReader(reader))
+		var size int64
+		for {
+			line, err := buffered.ReadString('\n')
+			size += int64(len(line))
+			if maxSize > 0 && size > maxSize {
+				done <- fmt.Errorf("input exceeds --max-input-size of %d bytes", maxSize)
+				return
+			}
+			if line != "" {
+				mu.Lock()
+				section.AddLine(line)
+				mu.Unlock()
+			}
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				done <- err
+				return
+			}
+		}
+	}()
+	
+	var waiting <-chan time.Time
+	if waitsForInput && config.StdinTimeout > 0 {
+		timer := time.NewTimer(config.StdinTimeout)
+		defer timer.Stop()
+		waiting = timer.C
+	}
+	
+	timedOut := false
+wait:
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				return "", nil, err
+			}
+			break wait
+		case <-ctx.Done():
+			timedOut = true
+			break wait
+		case <-arrived:
+			arrived, waiting = nil, nil
+		case <-waiting:
+			return "", nil, fmt.Errorf("no input from %s within --stdin-timeout %s. Pipe Terraform's output to tfcycle (terraform plan 2>&1 | tfcycle) or use --error-file", source, config.StdinTimeout)
+		}
+	}
+	
+	mu.Lock()
+	defer mu.Unlock()
+	if timedOut {
+		if section.Empty() {
+			return "", nil, fmt.Errorf("timed out waiting for input")
+		}
+		logger.Warn("timed out reading input, analyzing partial input", "bytes", len(section.String()))
+	}
+	if section.Empty() {
+		return "", nil, fmt.Errorf("input is empty")
+	}
+	
+	recordCrashInput(section.String())
+	return section.Context() + section.String(), section.Versions(), nil
+}
+
+// arrivalReader closes arrived once the first byte has been read.
+type arrivalReader struct {
+	reader  io.Reader
+	arrived chan struct{}
+	closed  bool
+}
+
+func (r *arrivalReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if (n > 0 || err != nil) && !r.closed {
+		r.closed = true
+		close(r.arrived)
+	}
+	return n, err
+}
+
+func writeOutput(content, filename string) error {
+	var writer io.Writer
+	
+	if filename != "" {
+		file, err := os.Create(filename)
+		if err != nil {
+			return fmt.Errorf("failed to create file %s: %w", filename, err)
+		}
+		defer file.Close()
+		writer = file
+	} else {
+		writer = os.Stdout
+	}
+	
+	if strings.HasSuffix(filename, ".gz") {
+		gz := gzip.NewWriter(writer)
+		if _, err := gz.Write([]byte(content)); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	}
+	
+	_, err := writer.Write([]byte(content))
+	if err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,538 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func TestReadInputEcho_Verbatim(t *testing.T) {
+	input := "Plan: 1 to add\r\n\tindented line\nError: Cycle: aws_instance.a, aws_instance.b\r\n\nno trailing newline"
+	path := filepath.Join(t.TempDir(), "plan.txt")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	
+	var echo strings.Builder
+	text, _, err := readInputEcho(context.Background(), path, &echo, Config{}, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	if echo.String() != input {
+		t.Errorf("Expected input echoed verbatim, got %q", echo.String())
+	}
+	if text != "Error: Cycle: aws_instance.a, aws_instance.b\n" {
+		t.Errorf("Expected only the cycle diagnostic to be returned, got %q", text)
+	}
+}
+
+func TestReadInputEcho_ContextLines(t *testing.T) {
+	input := "Plan: 1 to add\naws_instance.a: Destroying... [id=i-1]\n\nError: Cycle: aws_instance.a, aws_instance.b\n"
+	path := filepath.Join(t.TempDir(), "apply.txt")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	
+	text, _, err := readInputEcho(context.Background(), path, nil, Config{}, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text != "aws_instance.a: Destroying... [id=i-1]\nError: Cycle: aws_instance.a, aws_instance.b\n" {
+		t.Errorf("Expected one line of context before the diagnostic, got %q", text)
+	}
+}
+
+func TestReadInputEcho_UTF16(t *testing.T) {
+	input := []byte{0xff, 0xfe}
+	for _, r := range "Error: Cycle: aws_instance.a, aws_instance.b\r\n" {
+		input = append(input, byte(r), 0)
+	}
+	path := filepath.Join(t.TempDir(), "error.txt")
+	if err := os.WriteFile(path, input, 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	
+	var echo strings.Builder
+	text, _, err := readInputEcho(context.Background(), path, &echo, Config{}, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if echo.String() != string(input) {
+		t.Errorf("Expected input echoed undecoded, got %q", echo.String())
+	}
+	if text != "Error: Cycle: aws_instance.a, aws_instance.b\n" {
+		t.Errorf("Expected the diagnostic decoded from UTF-16, got %q", text)
+	}
+}
+
+func TestReadInput_MaxInputSize(t *testing.T) {
+	input := strings.Repeat("module.app: Refreshing state...\n", 100) + "Error: Cycle: aws_instance.a, aws_instance.b\n"
+	path := filepath.Join(t.TempDir(), "plan.log")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	
+	if _, _, err := readInput(context.Background(), path, Config{MaxInputSize: int64(len(input))}); err != nil {
+		t.Errorf("Expected input of exactly the limit to be read, got: %v", err)
+	}
+	_, _, err := readInput(context.Background(), path, Config{MaxInputSize: int64(len(input) - 1)})
+	if err == nil || !strings.Contains(err.Error(), "--max-input-size") {
+		t.Errorf("Expected a --max-input-size error, got %v", err)
+	}
+}
+
+func TestReadInput_NoCycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.log")
+	if err := os.WriteFile(path, []byte("Plan: 1 to add, 0 to change, 0 to destroy.\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	
+	text, _, err := readInput(context.Background(), path, Config{})
+	if err != nil || text != "" {
+		t.Errorf("Expected no text and no error, got %q (%v)", text, err)
+	}
+}
+
+func TestByteSize_Set(t *testing.T) {
+	tests := map[string]int64{
+		"1024":   1024,
+		"500MB":  500_000_000,
+		"1GiB":   1 << 30,
+		"64 kib": 64 << 10,
+		"0":      0,
+	}
+	for value, want := range tests {
+		var size byteSize
+		if err := size.Set(value); err != nil || int64(size) != want {
+			t.Errorf("Set(%q): expected %d, got %d (%v)", value, want, size, err)
+		}
+	}
+	
+	for _, value := range []string{"", "MB", "-1", "1.5GB", "10TB", "99999999999GiB"} {
+		var size byteSize
+		if err := size.Set(value); err == nil {
+			t.Errorf("Set(%q): expected an error", value)
+		}
+	}
+}
+
+func TestReadInput_LongLine(t *testing.T) {
+	var resources []string
+	for size := 0; size < 1<<20; size += len(resources[len(resources)-1]) + 2 {
+		resources = append(resources, fmt.Sprintf("module.app.aws_security_group.sg_%d", len(resources)))
+	}
+	input := "Error: Cycle: " + strings.Join(resources, ", ") + "\n"
+	path := filepath.Join(t.TempDir(), "cycle.txt")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	
+	text, _, err := readInput(context.Background(), path, Config{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text != input {
+		t.Fatalf("Expected the %d byte line to be read in full, got %d bytes", len(input), len(text))
+	}
+	
+	cycle, err := tfcycle.NewParser().ParseError(text)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cycle.Nodes) != len(resources) {
+		t.Errorf("Expected %d nodes, got %d", len(resources), len(cycle.Nodes))
+	}
+}
+
+func TestFix_FormatJSON(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "cycle.txt")
+	if err := os.WriteFile(input, []byte("Error: Cycle: aws_security_group.a, aws_security_group.b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "fixes.json")
+	config, err := parseArgs([]string{"fix", "--error-file", input, "--format", "json", "--output", output})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(data) {
+		t.Errorf("Expected --format json to write JSON, got:\n%s", data)
+	}
+}
+
+func TestPassthrough_Redact(t *testing.T) {
+	config, err := parseArgs([]string{"analyze", "--passthrough", "--redact"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error for --passthrough with --redact, got: %v", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, ExitNoCycle},
+		{&ExitError{Code: ExitCycleFound}, ExitCycleFound},
+		{inputErrorf("bad input"), ExitInputError},
+		{os.ErrPermission, ExitInternalError},
+	}
+	
+	for _, test := range tests {
+		if code := exitCode(test.err); code != test.expected {
+			t.Errorf("Expected exit code %d for %v, got %d", test.expected, test.err, code)
+		}
+	}
+}
+
+func TestCycleFoundError_Threshold(t *testing.T) {
+	cycle, _ := tfcycle.NewParser().ParseError(`Error: Cycle: aws_security_group.a, aws_security_group.b`)
+	analyzer := tfcycle.NewCycleAnalyzer(cycle)
+	
+	if code := exitCode(cycleFoundError(analyzer, Config{FailOn: "low"}, ExitNoCycle)); code != ExitCycleFound {
+		t.Errorf("Expected exit code %d, got %d", ExitCycleFound, code)
+	}
+	if code := exitCode(cycleFoundError(analyzer, Config{FailOn: "high"}, ExitNoCycle)); code != ExitNoCycle {
+		t.Errorf("Expected exit code %d, got %d", ExitNoCycle, code)
+	}
+	if code := exitCode(cycleFoundError(analyzer, Config{FailOn: "none"}, 7)); code != 7 {
+		t.Errorf("Expected fallback exit code 7, got %d", code)
+	}
+}
+
+func TestFormatReport_Quiet(t *testing.T) {
+	cycle, _ := tfcycle.NewParser().ParseError(`Error: Cycle: aws_security_group.sg1, aws_security_group.sg2`)
+	formatter := tfcycle.NewOutputFormatter(tfcycle.NewCycleAnalyzer(cycle), false)
+	
+	output, err := formatReport(formatter, Config{Quiet: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.Contains(output, "TERRAFORM CYCLE DETECTED") || strings.Contains(output, "SUGGESTIONS") {
+		t.Errorf("Expected quiet output without headers or suggestions, got:\n%s", output)
+	}
+	
+	output, err = formatReport(formatter, Config{Quiet: true, JSON: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.HasPrefix(output, "{") {
+		t.Errorf("Expected JSON output to be unchanged in quiet mode, got:\n%s", output)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := os.WriteFile("e.txt", []byte("Error: Cycle: aws_instance.a, aws_instance.b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	tests := []struct {
+		args       []string
+		command    string
+		errorFile  string
+		json       bool
+		positional []string
+	}{
+		{[]string{"--json", "analyze", "--error-file", "e.txt"}, "analyze", "e.txt", true, nil},
+		{[]string{"analyze", "--error-file=e.txt", "--json"}, "analyze", "e.txt", true, nil},
+		{[]string{"--error-file", "e.txt"}, "analyze", "e.txt", false, nil},
+		{[]string{"explain", "aws_vpc.main", "--error-file", "e.txt"}, "explain", "e.txt", false, []string{"aws_vpc.main"}},
+		{[]string{"run", "--json", "--", "terraform", "plan", "-target=x"}, "run", "", true, []string{"terraform", "plan", "-target=x"}},
+		{[]string{"analyze", "e.txt", "--json"}, "analyze", "e.txt", true, nil},
+		{[]string{"e.txt"}, "analyze", "e.txt", false, nil},
+		{[]string{"explain", "aws_vpc.main", "e.txt"}, "explain", "e.txt", false, []string{"aws_vpc.main"}},
+		{[]string{"fix", "-"}, "fix", "", false, nil},
+		{[]string{"visualize", "--error-file", "-"}, "visualize", "", false, nil},
+	}
+	
+	for _, test := range tests {
+		config, err := parseArgs(test.args)
+		if err != nil {
+			t.Errorf("Expected no error for %v, got: %v", test.args, err)
+			continue
+		}
+		if config.Command != test.command {
+			t.Errorf("Expected command %s for %v, got %s", test.command, test.args, config.Command)
+		}
+		if config.ErrorFile != test.errorFile {
+			t.Errorf("Expected error file %q for %v, got %q", test.errorFile, test.args, config.ErrorFile)
+		}
+		if config.JSON != test.json {
+			t.Errorf("Expected json=%v for %v, got %v", test.json, test.args, config.JSON)
+		}
+		if strings.Join(config.Args, " ") != strings.Join(test.positional, " ") {
+			t.Errorf("Expected args %v for %v, got %v", test.positional, test.args, config.Args)
+		}
+	}
+}
+
+func TestParseArgs_Errors(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	
+	if _, err := parseArgs([]string{"bogus"}); err == nil {
+		t.Errorf("Expected error for unknown command")
+	}
+	if _, err := parseArgs([]string{"diff", "--scope", "full"}); err == nil {
+		t.Errorf("Expected error for flag not accepted by diff")
+	}
+	if _, err := parseArgs([]string{"analyze", "a.txt", "b.txt"}); err == nil {
+		t.Errorf("Expected error for two input files")
+	}
+	if _, err := parseArgs([]string{"analyze", "a.txt", "--error-file", "b.txt"}); err == nil {
+		t.Errorf("Expected error for an input file given twice")
+	}
+	
+	config, err := parseArgs([]string{"fix", "--help"})
+	if err != nil || !config.Help || config.Command != "fix" {
+		t.Errorf("Expected help for fix, got %+v (%v)", config, err)
+	}
+}
+
+func TestReadInputEcho_TimeoutReturnsPartialInput(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	defer writer.Close()
+	
+	stdin := os.Stdin
+	os.Stdin = reader
+	defer func() { os.Stdin = stdin }()
+	
+	writer.WriteString("Error: Cycle: aws_security_group.a, aws_security_group.b\n")
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	
+	text, _, err := readInputEcho(ctx, "", nil, Config{}, 0)
+	if err != nil {
+		t.Fatalf("Expected partial input, got error: %v", err)
+	}
+	if !strings.Contains(text, "aws_security_group.b") {
+		t.Errorf("Expected partial input to be returned, got %q", text)
+	}
+}
+
+func TestReadInputEcho_StdinTimeout(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	defer writer.Close()
+	
+	stdin := os.Stdin
+	os.Stdin = reader
+	defer func() { os.Stdin = stdin }()
+	
+	config := Config{StdinTimeout: 50 * time.Millisecond}
+	if _, _, err := readInputEcho(context.Background(), "", nil, config, 0); err == nil || !strings.Contains(err.Error(), "--stdin-timeout") {
+		t.Fatalf("Expected a stdin timeout, got: %v", err)
+	}
+	
+	// Once input arrives, a slow writer is no longer cut off.
+	reader, writer, err = os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	os.Stdin = reader
+	writer.WriteString("Error: Cycle: aws_security_group.a,\n")
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		writer.WriteString("aws_security_group.b\n")
+		writer.Close()
+	}()
+	text, _, err := readInputEcho(context.Background(), "", nil, config, 0)
+	if err != nil || !strings.Contains(text, "aws_security_group.b") {
+		t.Errorf("Expected the whole input, got %q (%v)", text, err)
+	}
+}
+
+func TestTFVersion(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "cycle.txt")
+	if err := os.WriteFile(input, []byte("Error: Cycle: aws_instance.a, aws_instance.b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	output := filepath.Join(dir, "report.json")
+	config, err := parseArgs([]string{"analyze", "--error-file", input, "--output", output, "--json", "--fail-on", "none", "--no-history",
+		"--tf-version", "1.0.11"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"core": "1.0.11"`) || strings.Contains(string(data), `"moved-blocks"`) {
+		t.Errorf("Expected the version and no moved blocks advice for Terraform 1.0, got:\n%s", data)
+	}
+	
+	config.TFVersion = "latest"
+	if err := runCommand(config); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error for an invalid version, got: %v", err)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "cycle.txt")
+	if err := os.WriteFile(input, []byte("Error: Cycle: aws_instance.a, aws_instance.b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	readGzip := func(path string) string {
+		t.Helper()
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("Expected gzip data in %s, got: %v", path, err)
+		}
+		data, err := io.ReadAll(gz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	
+	tests := map[string][]string{
+		"report.json.gz": {"--output", filepath.Join(dir, "report.json")},
+		"auto.json.gz":   {"--output", filepath.Join(dir, "auto.json.gz")},
+	}
+	for name, flags := range tests {
+		args := append([]string{"analyze", "--error-file", input, "--json", "--fail-on", "none", "--no-history"}, flags...)
+		if name == "report.json.gz" {
+			args = append(args, "--compress")
+		}
+		config, err := parseArgs(args)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if err := runCommand(config); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if data := readGzip(filepath.Join(dir, name)); !strings.Contains(data, `"aws_instance.a"`) {
+			t.Errorf("Expected the JSON analysis in %s, got:\n%s", name, data)
+		}
+	}
+	
+	config, err := parseArgs([]string{"analyze", "--error-file", input, "--fail-on", "none", "--no-history", "--compress"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error without --output, got: %v", err)
+	}
+}
+
+func TestAnalyze_VersionsBeforeContext(t *testing.T) {
+	dir := t.TempDir()
+	analyze := func(log string) string {
+		input := filepath.Join(dir, "plan.txt")
+		if err := os.WriteFile(input, []byte(log), 0644); err != nil {
+			t.Fatal(err)
+		}
+		output := filepath.Join(dir, "report.json")
+		config, err := parseArgs([]string{"analyze", "--error-file", input, "--json", "--output", output, "--no-history", "--quiet", "--fail-on", "none"})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if err := runCommand(config); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		report, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(report)
+	}
+	
+	cycleError := "Error: Cycle: aws_security_group.web, aws_security_group.db\n"
+	if report := analyze(cycleError); !strings.Contains(report, "aws_vpc_security_group_ingress_rule") {
+		t.Fatalf("Expected aws_vpc_security_group_ingress_rule advice without versions, got:\n%s", report)
+	}
+	
+	// The provider version is further above the diagnostic than
+	// --context-lines keeps.
+	log := "- Installed hashicorp/aws v4.67.0 (signed by HashiCorp)\n" + strings.Repeat("aws_instance.app: Refreshing state...\n", 12) + cycleError
+	if report := analyze(log); strings.Contains(report, "aws_vpc_security_group_ingress_rule") {
+		t.Errorf("Expected no aws_vpc_security_group_ingress_rule advice on AWS provider 4, got:\n%s", report)
+	}
+}
+
+func TestFix_Suppress(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "cycle.txt")
+	if err := os.WriteFile(input, []byte("Error: Cycle: aws_security_group.a, aws_security_group.b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := `resource "aws_security_group" "a" {
+  ingress {
+    security_groups = [aws_security_group.b.id]
+  }
+}
+
+resource "aws_security_group" "b" {
+  ingress {
+    security_groups = [aws_security_group.a.id]
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	fix := func(extra ...string) string {
+		output := filepath.Join(dir, "fixes.txt")
+		config, err := parseArgs(append([]string{"fix", input, "--config-dir", dir, "--output", output}, extra...))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if err := runCommand(config); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	
+	if output := fix(); !strings.Contains(output, "aws_security_group_rule") {
+		t.Fatalf("Expected a security group rule fix, got:\n%s", output)
+	}
+	if output := fix("--suppress", "security_group_rule"); strings.Contains(output, "aws_security_group_rule") {
+		t.Errorf("Expected --suppress to hide the security group rule fix, got:\n%s", output)
+	}
+}
@@ -0,0 +1,45 @@
+//go:build unix
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReadInputEcho_NamedPipe(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "idle.pipe")
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Skipf("Cannot create a named pipe: %v", err)
+	}
+	
+	config := Config{StdinTimeout: 50 * time.Millisecond}
+	_, _, err := readInputEcho(context.Background(), path, nil, config, 0)
+	if err == nil || !strings.Contains(err.Error(), "named pipe") {
+		t.Fatalf("Expected a timeout waiting for a writer, got: %v", err)
+	}
+	
+	path = filepath.Join(dir, "plan.pipe")
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	
+	go func() {
+		writer, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+		writer.WriteString("Error: Cycle: aws_instance.a, aws_instance.b\n")
+		writer.Close()
+	}()
+	text, _, err := readInputEcho(context.Background(), path, nil, Config{StdinTimeout: 5 * time.Second}, 0)
+	if err != nil || !strings.Contains(text, "aws_instance.b") {
+		t.Errorf("Expected the input written to the pipe, got %q (%v)", text, err)
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const mcpProtocolVersion = "2024-11-05"
+
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type mcpTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	handler     func(ctx context.Context, args mcpArguments) (string, error)
+}
+
+type mcpArguments struct {
+	ErrorText string `json:"error_text"`
+	Address   string `json:"address"`
+	ConfigDir string `json:"config_dir"`
+	Lang      string `json:"lang"`
+}
+
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type MCPServer struct {
+	config Config
+	tools  []mcpTool
+}
+
+func NewMCPServer(config Config) *MCPServer {
+	s := &MCPServer{config: config}
+	errorText := map[string]interface{}{
+		"type":        "string",
+		"description": "Terraform output containing an \"Error: Cycle:\" message, or a tfcycle JSON analysis",
+	}
+	s.tools = []mcpTool{
+		{
+			Name:        "analyze_cycle_error",
+			Description: "Analyze a Terraform cycle error: minimal cycles, severity, suggestions and a staged apply plan. Returns a text report followed by the JSON analysis.",
+			InputSchema: mcpSchema(map[string]interface{}{
+				"error_text": errorText,
+				"lang":       map[string]interface{}{"type": "string", "enum": tfcycle.SupportedLanguages(), "description": "Report language"},
+			}, "error_text"),
+			handler: s.analyzeTool,
+		},
+		{
+			Name:        "explain_node",
+			Description: "Explain one resource in a Terraform cycle: the cycles it is in, which edges point in and out and why, and suggestions.",
+			InputSchema: mcpSchema(map[string]interface{}{
+				"error_text": errorText,
+				"address":    map[string]interface{}{"type": "string", "description": "Resource address, e.g. aws_security_group.app or module.vpc.aws_subnet.a"},
+			}, "error_text", "address"),
+			handler: s.explainTool,
+		},
+		{
+			Name:        "suggest_fix",
+			Description: "Suggest how to break a Terraform cycle. With config_dir, returns concrete HCL snippets and diffs for recognized patterns.",
+			InputSchema: mcpSchema(map[string]interface{}{
+				"error_text": errorText,
+				"config_dir": map[string]interface{}{"type": "string", "description": "Terraform configuration directory to generate HCL fixes from"},
+			}, "error_text"),
+			handler: s.suggestFixTool,
+		},
+	}
+	return s
+}
+
+func mcpSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func (s *MCPServer) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			if resp := s.handleMessage(ctx, line); resp != nil {
+				if err := encoder.Encode(resp); err != nil {
+					return err
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *MCPServer) handleMessage(ctx context.Context, line []byte) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: err.Error()}}
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return &rpcResponse{JSONRPC: "2.0", ID: nullID(req.ID), Error: &rpcError{Code: rpcInvalidRequest, Message: "invalid JSON-RPC request"}}
+	}
+	
+	result, rpcErr := s.dispatch(ctx, req)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr}
+}
+
+func nullID(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+func (s *MCPServer) dispatch(ctx context.Context, req rpcRequest) (interface{}, *rpcError) {
+	logger.Debug("mcp request", "method", req.Method)
+	
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": "tfcycle", "version": version},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": s.tools}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	default:
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+func (s *MCPServer) callTool(ctx context.Context, params json.RawMessage) (interface{}, *rpcError) {
+	var call struct {
+		Name      string       `json:"name"`
+		Arguments mcpArguments `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	
+	for _, tool := range s.tools {
+		if tool.Name != call.Name {
+			continue
+		}
+		text, err := tool.handler(ctx, call.Arguments)
+		if err != nil {
+			return map[string]interface{}{"content": []mcpContent{{Type: "text", Text: err.Error()}}, "isError": true}, nil
+		}
+		return map[string]interface{}{"content": []mcpContent{{Type: "text", Text: text}}}, nil
+	}
+	return nil, &rpcError{Code: rpcInvalidParams, Message: "unknown tool: " + call.Name}
+}
+
+func (s *MCPServer) analyzeTool(ctx context.Context, args mcpArguments) (string, error) {
+	analyzer, err := analyzeServeInput(ctx, s.config, args.ErrorText)
+	if err != nil {
+		return "", err
+	}
+	
+	formatter := tfcycle.NewOutputFormatter(analyzer, false)
+	lang, err := tfcycle.ResolveLanguage(firstNonEmpty(args.Lang, s.config.Lang))
+	if err != nil {
+		return "", err
+	}
+	formatter.SetLanguage(lang)
+	
+	analysis, err := formatter.FormatAsJSON()
+	if err != nil {
+		return "", err
+	}
+	return formatter.FormatAnalysis() + "\n" + analysis, nil
+}
+
+func (s *MCPServer) explainTool(ctx context.Context, args mcpArguments) (string, error) {
+	if args.Address == "" {
+		return "", errors.New("address is required")
+	}
+	
+	analyzer, err := analyzeServeInput(ctx, s.config, args.ErrorText)
+	if err != nil {
+		return "", err
+	}
+	
+	nodes := analyzer.MatchNodes(args.Address)
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("resource %s is not part of the cycle", args.Address)
+	}
+	
+	formatter := tfcycle.NewOutputFormatter(analyzer, false)
+	var output strings.Builder
+	for _, node := range nodes {
+		output.WriteString(formatter.FormatExplanation(analyzer.ExplainNode(node)))
+	}
+	return output.String(), nil
+}
+
+func (s *MCPServer) suggestFixTool(ctx context.Context, args mcpArguments) (string, error) {
+	configDir := firstNonEmpty(args.ConfigDir, s.config.ConfigDir)
+	if configDir == "" {
+		analyzer, err := analyzeServeInput(ctx, s.config, args.ErrorText)
+		if err != nil {
+			return "", err
+		}
+		
+		var output strings.Builder
+		output.WriteString("No config_dir given; general suggestions for the minimal cycles:\n")
+		for i, cycle := range analyzer.FindMinimalCycles() {
+			output.WriteString(fmt.Sprintf("\nCycle #%d: %s\n", i+1, tfcycle.CycleStrings([][]string{cycle})[0]))
+			for _, suggestion := range analyzer.GenerateSuggestions(cycle) {
+				output.WriteString("  - " + suggestion.Title + "\n")
+			}
+		}
+		return output.String(), nil
+	}
+	
+	if s.config.Redact {
+		return "", errors.New("HCL fixes quote the real configuration and are not available with --redact; omit config_dir")
+	}
+	if !tfcycle.IsCycleInput(args.ErrorText) {
+		return "", tfcycle.ErrNoCycleFound
+	}
+	index, err := tfcycle.ScanConfig(configDir)
+	if err != nil {
+		return "", err
+	}
+	cycle, err := tfcycle.ParseInput(args.ErrorText)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse cycle error: %w", err)
+	}
+	index.ResolveLocations(cycle)
+	
+	analyzer := tfcycle.NewCycleAnalyzer(cycle)
+	analyzeWithTimeout(ctx, analyzer, s.config)
+	fixes := generateFixes(analyzer, index, s.config)
+	return tfcycle.NewOutputFormatter(analyzer, false).FormatFixes(fixes), nil
+}
+
+func runMCP(config Config) error {
+	return NewMCPServer(config).Serve(context.Background(), os.Stdin, os.Stdout)
+}
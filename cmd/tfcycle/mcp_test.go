@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func runMCPSession(t *testing.T, messages ...string) []rpcResponse {
+	var out bytes.Buffer
+	input := strings.Join(messages, "\n") + "\n"
+	if err := NewMCPServer(Config{}).Serve(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	var responses []rpcResponse
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var resp rpcResponse
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("Expected JSON-RPC response, got: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func toolText(t *testing.T, resp rpcResponse) (string, bool) {
+	result := resp.Result.(map[string]interface{})
+	content := result["content"].([]interface{})
+	isError, _ := result["isError"].(bool)
+	return content[0].(map[string]interface{})["text"].(string), isError
+}
+
+func TestMCPServer_Handshake(t *testing.T) {
+	responses := runMCPSession(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/list"}`,
+		`not json`,
+	)
+	
+	if len(responses) != 4 {
+		t.Fatalf("Expected 4 responses (notifications get none), got %d", len(responses))
+	}
+	
+	info := responses[0].Result.(map[string]interface{})
+	if info["protocolVersion"] != mcpProtocolVersion {
+		t.Errorf("Expected protocol version %s, got %v", mcpProtocolVersion, info["protocolVersion"])
+	}
+	
+	tools := responses[1].Result.(map[string]interface{})["tools"].([]interface{})
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.(map[string]interface{})["name"].(string))
+	}
+	if strings.Join(names, ",") != "analyze_cycle_error,explain_node,suggest_fix" {
+		t.Errorf("Expected the three tfcycle tools, got %v", names)
+	}
+	
+	if responses[2].Error == nil || responses[2].Error.Code != rpcMethodNotFound {
+		t.Errorf("Expected method not found, got %+v", responses[2])
+	}
+	if responses[3].Error == nil || responses[3].Error.Code != rpcParseError {
+		t.Errorf("Expected parse error, got %+v", responses[3])
+	}
+}
+
+func TestMCPServer_Tools(t *testing.T) {
+	responses := runMCPSession(t,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"analyze_cycle_error","arguments":{"error_text":"Error: Cycle: aws_security_group.a, aws_security_group.b"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"explain_node","arguments":{"error_text":"Error: Cycle: aws_security_group.a, aws_security_group.b","address":"aws_security_group.b"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"suggest_fix","arguments":{"error_text":"no cycle here"}}}`,
+	)
+	
+	if text, isError := toolText(t, responses[0]); isError || !strings.Contains(text, "TERRAFORM CYCLE DETECTED") || !strings.Contains(text, `"severity": "low"`) {
+		t.Errorf("Expected report and JSON analysis, got:\n%s", text)
+	}
+	if text, isError := toolText(t, responses[1]); isError || !strings.Contains(text, "aws_security_group.b") {
+		t.Errorf("Expected explanation of aws_security_group.b, got:\n%s", text)
+	}
+	if text, isError := toolText(t, responses[2]); !isError || !strings.Contains(text, "no Terraform cycle error") {
+		t.Errorf("Expected tool error for input without a cycle, got:\n%s", text)
+	}
+}
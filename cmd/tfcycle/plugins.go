@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const (
+	formatPluginPrefix    = "tfcycle-format-"
+	heuristicPluginPrefix = "tfcycle-heuristic-"
+	pluginProtocolVersion = 1
+	pluginTimeout         = 30 * time.Second
+)
+
+// Plugin is an executable on PATH named tfcycle-format-NAME or
+// tfcycle-heuristic-NAME. It reads one JSON request on stdin and answers on
+// stdout.
+type Plugin struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+type pluginRequest struct {
+	ProtocolVersion int                  `json:"protocol_version"`
+	Kind            string               `json:"kind"`
+	Version         string               `json:"tfcycle_version"`
+	Analysis        json.RawMessage      `json:"analysis,omitempty"`
+	Nodes           []*tfcycle.CycleNode `json:"nodes,omitempty"`
+}
+
+type pluginEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type heuristicResponse struct {
+	Edges []pluginEdge `json:"edges"`
+}
+
+// discoverPlugins lists the plugins on pathList, sorted by kind and name. When
+// several directories hold the same plugin, the first one wins, as with
+// exec.LookPath.
+func discoverPlugins(pathList string) []Plugin {
+	seen := make(map[string]bool)
+	var plugins []Plugin
+	for _, dir := range filepath.SplitList(pathList) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			plugin, ok := pluginFromFile(dir, entry)
+			if !ok || seen[plugin.Kind+"/"+plugin.Name] {
+				continue
+			}
+			seen[plugin.Kind+"/"+plugin.Name] = true
+			plugins = append(plugins, plugin)
+		}
+	}
+	
+	sort.Slice(plugins, func(i, j int) bool {
+		if plugins[i].Kind != plugins[j].Kind {
+			return plugins[i].Kind < plugins[j].Kind
+		}
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins
+}
+
+func pluginFromFile(dir string, entry os.DirEntry) (Plugin, bool) {
+	name := entry.Name()
+	if runtime.GOOS == "windows" {
+		if !strings.EqualFold(filepath.Ext(name), ".exe") {
+			return Plugin{}, false
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	
+	var kind string
+	switch {
+	case strings.HasPrefix(name, formatPluginPrefix):
+		kind, name = "format", strings.TrimPrefix(name, formatPluginPrefix)
+	case strings.HasPrefix(name, heuristicPluginPrefix):
+		kind, name = "heuristic", strings.TrimPrefix(name, heuristicPluginPrefix)
+	default:
+		return Plugin{}, false
+	}
+	if name == "" {
+		return Plugin{}, false
+	}
+	
+	info, err := entry.Info()
+	if err != nil || info.IsDir() || (runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0) {
+		return Plugin{}, false
+	}
+	return Plugin{Kind: kind, Name: name, Path: filepath.Join(dir, entry.Name())}, true
+}
+
+func pluginsOfKind(kind string) []Plugin {
+	var plugins []Plugin
+	for _, plugin := range discoverPlugins(os.Getenv("PATH")) {
+		if plugin.Kind == kind {
+			plugins = append(plugins, plugin)
+		}
+	}
+	return plugins
+}
+
+func findFormatPlugin(name string) (Plugin, bool) {
+	for _, plugin := range pluginsOfKind("format") {
+		if plugin.Name == name {
+			return plugin, true
+		}
+	}
+	return Plugin{}, false
+}
+
+func (p Plugin) call(ctx context.Context, request pluginRequest) ([]byte, error) {
+	request.ProtocolVersion = pluginProtocolVersion
+	request.Kind = p.Kind
+	request.Version = version
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	
+	ctx, cancel := context.WithTimeout(ctx, pluginTimeout)
+	defer cancel()
+	
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%s plugin %s failed: %w: %s", p.Kind, p.Name, err, message)
+		}
+		return nil, fmt.Errorf("%s plugin %s failed: %w", p.Kind, p.Name, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+func runFormatPlugin(plugin Plugin, formatter *tfcycle.OutputFormatter) (string, error) {
+	analysis, err := formatter.FormatAsJSON()
+	if err != nil {
+		return "", fmt.Errorf("failed to format as JSON: %w", err)
+	}
+	
+	output, err := plugin.call(context.Background(), pluginRequest{Analysis: json.RawMessage(analysis)})
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// Edges runs a heuristic plugin so it can be used as a tfcycle.EdgeSource. A
+// failing plugin is logged and contributes no edges. Edges may name nodes by
+// Address or FullName.
+func (p Plugin) Edges(cycle *tfcycle.TfCycle) map[string][]string {
+	names := make(map[string]string, 2*len(cycle.Nodes))
+	for _, node := range cycle.Nodes {
+		names[node.FullName()] = node.FullName()
+		names[node.Address()] = node.FullName()
+	}
+	
+	output, err := p.call(context.Background(), pluginRequest{Nodes: cycle.Nodes})
+	if err != nil {
+		logger.Warn("ignoring heuristic plugin", "plugin", p.Name, "error", err)
+		return nil
+	}
+	
+	var response heuristicResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		logger.Warn("ignoring heuristic plugin", "plugin", p.Name, "error", fmt.Errorf("invalid response: %w", err))
+		return nil
+	}
+	
+	edges := make(map[string][]string)
+	for _, edge := range response.Edges {
+		from, to := names[edge.From], names[edge.To]
+		if from == "" || to == "" {
+			logger.Debug("ignoring plugin edge outside the cycle", "plugin", p.Name, "from", edge.From, "to", edge.To)
+			continue
+		}
+		logger.Debug("plugin edge", "plugin", p.Name, "from", from, "to", to, "reason", edge.Reason)
+		edges[from] = append(edges[from], to)
+	}
+	return edges
+}
+
+func analyzerOptions(config Config, index *tfcycle.ConfigIndex) []tfcycle.Option {
+	var opts []tfcycle.Option
+	if index != nil {
+		opts = append(opts, tfcycle.WithEdgeSources(index))
+	}
+	if !config.NoPlugins {
+		for _, plugin := range pluginsOfKind("heuristic") {
+			logger.Debug("using heuristic plugin", "plugin", plugin.Name, "path", plugin.Path)
+			opts = append(opts, tfcycle.WithEdgeSources(plugin))
+		}
+	}
+	return opts
+}
+
+func runPlugins(config Config) error {
+	plugins := discoverPlugins(os.Getenv("PATH"))
+	if outputFormat(config) == "json" {
+		if plugins == nil {
+			plugins = []Plugin{}
+		}
+		jsonData, err := json.MarshalIndent(plugins, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		return writeOutput(string(jsonData)+"\n", config.Output)
+	}
+	
+	var output strings.Builder
+	if len(plugins) == 0 {
+		output.WriteString("No tfcycle-format-* or tfcycle-heuristic-* plugins found on PATH\n")
+	}
+	for _, plugin := range plugins {
+		output.WriteString(fmt.Sprintf("%-10s %-20s %s\n", plugin.Kind, plugin.Name, plugin.Path))
+	}
+	return writeOutput(output.String(), config.Output)
+}
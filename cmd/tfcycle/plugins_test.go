@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func writePlugin(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func pluginTestDir(t *testing.T) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin tests use shell scripts")
+	}
+	dir := t.TempDir()
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	dir := pluginTestDir(t)
+	writePlugin(t, dir, "tfcycle-format-sarif", "cat\n")
+	writePlugin(t, dir, "tfcycle-heuristic-azure", "cat\n")
+	writePlugin(t, dir, "tfcycle-unrelated", "cat\n")
+	if err := os.WriteFile(filepath.Join(dir, "tfcycle-format-noexec"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	shadowed := t.TempDir()
+	writePlugin(t, shadowed, "tfcycle-format-sarif", "cat\n")
+	
+	plugins := discoverPlugins(dir + string(os.PathListSeparator) + shadowed)
+	if len(plugins) != 2 {
+		t.Fatalf("Expected 2 plugins, got %v", plugins)
+	}
+	if plugins[0].Kind != "format" || plugins[0].Name != "sarif" || filepath.Dir(plugins[0].Path) != dir {
+		t.Errorf("Expected the first sarif format plugin on PATH, got %+v", plugins[0])
+	}
+	if plugins[1].Kind != "heuristic" || plugins[1].Name != "azure" {
+		t.Errorf("Expected the azure heuristic plugin, got %+v", plugins[1])
+	}
+}
+
+func TestFormatPlugin(t *testing.T) {
+	dir := pluginTestDir(t)
+	writePlugin(t, dir, "tfcycle-format-count", `grep -o '"resource_name"' | wc -l | tr -d ' '`+"\n")
+	
+	cycle, err := tfcycle.NewParser().ParseError("Error: Cycle: aws_instance.a, aws_instance.b")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	formatter := tfcycle.NewOutputFormatter(tfcycle.NewCycleAnalyzer(cycle), false)
+	
+	output, err := formatReport(formatter, Config{Format: "count"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.TrimSpace(output) != "2" {
+		t.Errorf("Expected the plugin to see 2 resources, got %q", output)
+	}
+	
+	if _, err := formatReport(formatter, Config{Format: "missing"}); err == nil || !strings.Contains(err.Error(), "tfcycle-format-missing") {
+		t.Errorf("Expected an unknown format error naming the plugin, got %v", err)
+	}
+}
+
+func TestHeuristicPlugin(t *testing.T) {
+	dir := pluginTestDir(t)
+	writePlugin(t, dir, "tfcycle-heuristic-pair", `grep -q '"address":"aws_instance.c"' || exit 1
+echo '{"edges": [{"from": "aws_instance.a", "to": "aws_instance.b", "reason": "test"}, {"from": "aws_instance.b", "to": "aws_instance.a"}, {"from": "aws_instance.c", "to": "aws_instance.a"}]}'
+`)
+	writePlugin(t, dir, "tfcycle-heuristic-broken", "echo oops >&2; exit 3\n")
+	
+	cycle, err := tfcycle.NewParser().ParseError("Error: Cycle: aws_instance.a, aws_instance.b, aws_instance.c")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	opts := append(analyzerOptions(Config{}, nil), tfcycle.WithHeuristics())
+	minimal := tfcycle.NewCycleAnalyzer(cycle, opts...).FindMinimalCycles()
+	if len(minimal) == 0 || len(minimal[0]) != 2 {
+		t.Errorf("Expected the plugin's 2-node cycle first, got %v", minimal)
+	}
+	
+	if opts := analyzerOptions(Config{NoPlugins: true}, nil); len(opts) != 0 {
+		t.Errorf("Expected no options with --no-plugins, got %d", len(opts))
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfiling starts the CPU profile requested with --cpuprofile. The
+// returned function stops it and writes the --memprofile heap profile; it
+// must run before the process exits for either file to be complete.
+func startProfiling(config Config) (func(), error) {
+	var cpuFile *os.File
+	if config.CPUProfile != "" {
+		file, err := os.Create(config.CPUProfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		cpuFile = file
+	}
+	
+	return func() {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			cpuFile.Close()
+		}
+		if config.MemProfile != "" {
+			if err := writeHeapProfile(config.MemProfile); err != nil {
+				logger.Warn("failed to write memory profile", "error", err)
+			}
+		}
+	}, nil
+}
+
+func writeHeapProfile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	
+	runtime.GC()
+	return pprof.WriteHeapProfile(file)
+}
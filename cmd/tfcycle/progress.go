@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const (
+	// progressDelay keeps the spinner from flickering for operations that
+	// finish quickly.
+	progressDelay    = 300 * time.Millisecond
+	progressInterval = 100 * time.Millisecond
+)
+
+// spinner redraws a frame and a message on one terminal line until stopped.
+type spinner struct {
+	w       io.Writer
+	message string
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func startSpinner(w io.Writer, message string, delay time.Duration) *spinner {
+	s := &spinner{w: w, message: message, stop: make(chan struct{}), done: make(chan struct{})}
+	go s.run(delay)
+	return s
+}
+
+func (s *spinner) run(delay time.Duration) {
+	defer close(s.done)
+	
+	select {
+	case <-s.stop:
+		return
+	case <-time.After(delay):
+	}
+	
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		fmt.Fprintf(s.w, "\r%s %s", spinnerFrames[frame%len(spinnerFrames)], s.message)
+		select {
+		case <-s.stop:
+			fmt.Fprint(s.w, "\r\033[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop clears the spinner's line and waits for it to finish.
+func (s *spinner) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+// showProgress shows a spinner with message on stderr until the returned
+// function is called. It shows nothing with --quiet or when stderr is not a
+// terminal, so logs and pipes stay clean.
+func showProgress(config Config, message string) func() {
+	if config.Quiet || !stderrIsTerminal() {
+		return func() {}
+	}
+	return startSpinner(os.Stderr, message, progressDelay).Stop
+}
+
+func stderrIsTerminal() bool {
+	return isTerminal(os.Stderr)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpinner(t *testing.T) {
+	var output lockedBuffer
+	s := startSpinner(&output, "Scanning .", 0)
+	time.Sleep(3 * progressInterval / 2)
+	s.Stop()
+	
+	text := output.String()
+	if !strings.HasPrefix(text, "\r"+spinnerFrames[0]+" Scanning .") {
+		t.Errorf("Expected the first frame and the message, got %q", text)
+	}
+	if !strings.HasSuffix(text, "\r\033[K") {
+		t.Errorf("Expected the line cleared on stop, got %q", text)
+	}
+}
+
+func TestSpinner_StopBeforeDelay(t *testing.T) {
+	var output lockedBuffer
+	startSpinner(&output, "Scanning .", time.Hour).Stop()
+	if output.String() != "" {
+		t.Errorf("Expected nothing for an operation faster than the delay, got %q", output.String())
+	}
+}
+
+func TestShowProgress_NotTerminal(t *testing.T) {
+	// Test binaries write stderr to a pipe or file, never a terminal.
+	showProgress(Config{}, "Scanning .")()
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+// loadSuggestionRules returns the built-in suggestion rules merged with those
+// from --suggestion-rules.
+func loadSuggestionRules(config Config) ([]tfcycle.SuggestionRule, error) {
+	rules := tfcycle.DefaultSuggestionRules()
+	if config.SuggestionRules == "" {
+		return rules, nil
+	}
+	
+	f, err := os.Open(config.SuggestionRules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suggestion rules: %w", err)
+	}
+	defer f.Close()
+	
+	overrides, err := tfcycle.ParseSuggestionRules(f, config.SuggestionRules)
+	if err != nil {
+		return nil, err
+	}
+	return tfcycle.MergeSuggestionRules(rules, overrides), nil
+}
+
+// suggestionRulesOptions returns the analyzer options for --suggestion-rules,
+// --enable-rule, --disable-rule, --suppress and --no-suggestions, or none if
+// they are not set.
+func suggestionRulesOptions(config Config) ([]tfcycle.Option, error) {
+	var opts []tfcycle.Option
+	if len(config.EnabledRules) > 0 || len(config.DisabledRules) > 0 {
+		opts = append(opts, tfcycle.WithRuleSelection(config.EnabledRules, config.DisabledRules))
+	}
+	if len(config.Suppress) > 0 {
+		opts = append(opts, tfcycle.WithSuppressedSuggestions(config.Suppress...))
+	}
+	if config.NoSuggestions {
+		opts = append(opts, tfcycle.WithoutSuggestions())
+	}
+	if config.SuggestionRules == "" {
+		return opts, nil
+	}
+	rules, err := loadSuggestionRules(config)
+	if err != nil {
+		return nil, inputError(err)
+	}
+	return append(opts, tfcycle.WithSuggestionRules(rules...)), nil
+}
+
+// generateFixes returns the fixes for the cycles of analyzer, leaving out
+// those of rules turned off with --disable-rule or --suppress.
+func generateFixes(analyzer *tfcycle.CycleAnalyzer, index *tfcycle.ConfigIndex, config Config) []tfcycle.Fix {
+	rules := append(append([]string{}, config.DisabledRules...), config.Suppress...)
+	return tfcycle.FilterFixes(tfcycle.NewFixer(analyzer, index).GenerateFixes(), rules)
+}
+
+// validateRuleIDs checks the rule IDs and fix kinds of --enable-rule,
+// --disable-rule, --suppress and the configuration file against the rules in
+// effect.
+func validateRuleIDs(config Config) error {
+	if len(config.DisabledRules) == 0 && len(config.EnabledRules) == 0 && len(config.Suppress) == 0 {
+		return nil
+	}
+	var rules []tfcycle.SuggestionRule
+	if config.SuggestionRules != "" {
+		loaded, err := loadSuggestionRules(config)
+		if err != nil {
+			return inputError(err)
+		}
+		rules = loaded
+	}
+	if err := tfcycle.ValidateRuleIDs(config.DisabledRules, rules); err != nil {
+		return inputErrorf("disabled_rules: %w", err)
+	}
+	if err := tfcycle.ValidateRuleIDs(config.EnabledRules, rules); err != nil {
+		return inputErrorf("enabled_rules: %w", err)
+	}
+	if err := tfcycle.ValidateRuleIDs(config.Suppress, rules); err != nil {
+		return inputErrorf("suppress: %w", err)
+	}
+	return nil
+}
+
+func runRules(config Config) error {
+	if len(config.Args) != 1 || config.Args[0] != "list" {
+		return inputErrorf("rules requires the subcommand list")
+	}
+	
+	rules, err := loadSuggestionRules(config)
+	if err != nil {
+		return inputError(err)
+	}
+	
+	if outputFormat(config) == "json" {
+		jsonData, err := json.MarshalIndent(rules, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		return writeOutput(string(jsonData)+"\n", config.Output)
+	}
+	return writeOutput(FormatRulesList(rules), config.Output)
+}
+
+// FormatRulesList renders one line per rule with its source and description,
+// followed by the rule IDs and IDs of the suggestions it yields, and then the
+// built-in heuristics with their rule IDs.
+func FormatRulesList(rules []tfcycle.SuggestionRule) string {
+	var output strings.Builder
+	for _, rule := range rules {
+		description := rule.Description
+		if rule.Fallback {
+			description += " (fallback)"
+		}
+		output.WriteString(fmt.Sprintf("%-20s %-10s %s\n", rule.ID, rule.Source, description))
+		
+		for _, suggestion := range rule.Suggestions {
+			output.WriteString(fmt.Sprintf("%-20s %-16s %s\n", "", suggestion.RuleID, suggestion.ID))
+		}
+	}
+	
+	output.WriteString("\nHEURISTICS\n")
+	for _, h := range tfcycle.DefaultHeuristics() {
+		output.WriteString(fmt.Sprintf("%-20s %-16s %s (confidence %.1f)\n", "", h.RuleID, h.Name, h.Confidence))
+	}
+	return output.String()
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func TestRulesList(t *testing.T) {
+	dir := t.TempDir()
+	rulesPath := filepath.Join(dir, "rules.yaml")
+	rulesYAML := "rules:\n  - id: general\n    disabled: true\n  - id: split-inline-blocks\n    disabled: true\n  - id: nat\n    description: NAT gateways\n    suggestions: [{id: nat-eip, title: Allocate the EIP separately}]\n"
+	if err := os.WriteFile(rulesPath, []byte(rulesYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	output := filepath.Join(dir, "rules.json")
+	config, err := parseArgs([]string{"rules", "list", "--json", "--suggestion-rules", rulesPath, "--output", output})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rules []tfcycle.SuggestionRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	last := rules[len(rules)-1]
+	if last.ID != "nat" || last.Source != rulesPath {
+		t.Errorf("Expected the file's rule last with its source, got %+v", last)
+	}
+	
+	text := FormatRulesList(rules)
+	if strings.Contains(text, "general") || !strings.Contains(text, "nat-eip") {
+		t.Errorf("Expected general removed and nat listed, got:\n%s", text)
+	}
+}
+
+func TestSuggestionRulesOptions_Invalid(t *testing.T) {
+	rulesPath := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(rulesPath, []byte("rules:\n  - id: broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	_, err := suggestionRulesOptions(Config{SuggestionRules: rulesPath})
+	if exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error, got: %v", err)
+	}
+	if opts, err := suggestionRulesOptions(Config{}); err != nil || len(opts) != 0 {
+		t.Errorf("Expected no options without --suggestion-rules, got %d, %v", len(opts), err)
+	}
+}
+
+func TestRuleSelection(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "cycle.txt")
+	if err := os.WriteFile(input, []byte("Error: Cycle: aws_security_group.a, aws_security_group.b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	output := filepath.Join(dir, "report.txt")
+	config, err := parseArgs([]string{"analyze", "--error-file", input, "--output", output, "--fail-on", "none", "--no-history",
+		"--disable-rule", "TFC-AWS-SG-*", "--enable-rule", "TFC-AWS-SG-003"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "aws_security_group_rule resources") || !strings.Contains(string(data), "data sources for existing security groups") {
+		t.Errorf("Expected only the re-enabled data source suggestion, got:\n%s", data)
+	}
+	
+	config.DisabledRules = []string{"TFC-NOPE-*"}
+	if err := runCommand(config); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error for an unknown rule, got: %v", err)
+	}
+}
+
+func TestSuppressSuggestions(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "cycle.txt")
+	if err := os.WriteFile(input, []byte("Error: Cycle: aws_security_group.a, aws_security_group.b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	output := filepath.Join(dir, "report.txt")
+	config, err := parseArgs([]string{"analyze", "--error-file", input, "--output", output, "--fail-on", "none", "--no-history",
+		"--suppress", "TFC-AWS-SG-002"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "aws_security_group_rule resources") || !strings.Contains(string(data), "data sources for existing security groups") {
+		t.Errorf("Expected TFC-AWS-SG-002 to be hidden, got:\n%s", data)
+	}
+	
+	config.Suppress = nil
+	config.NoSuggestions = true
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	data, err = os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "aws_security_group.a") || strings.Contains(string(data), "SUGGESTIONS") {
+		t.Errorf("Expected only the cycle, got:\n%s", data)
+	}
+	
+	config.Suppress = []string{"TFC-NOPE-*"}
+	if err := runCommand(config); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error for an unknown rule, got: %v", err)
+	}
+}
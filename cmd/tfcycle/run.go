@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+)
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (lb *lockedBuffer) Write(p []byte) (int, error) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return lb.buf.Write(p)
+}
+
+func (lb *lockedBuffer) String() string {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return lb.buf.String()
+}
+
+func runWrapped(args []string, stdin io.Reader, stdout, stderr io.Writer) (string, int, error) {
+	var captured lockedBuffer
+	
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = stdin
+	cmd.Stdout = io.MultiWriter(stdout, &captured)
+	cmd.Stderr = io.MultiWriter(stderr, &captured)
+	
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+	
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return captured.String(), 0, nil
+	case errors.As(err, &exitErr):
+		return captured.String(), exitErr.ExitCode(), nil
+	default:
+		return "", 0, fmt.Errorf("failed to run %s: %w", args[0], err)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunWrapped_CapturesAndStreams(t *testing.T) {
+	var stdout, stderr strings.Builder
+	captured, code, err := runWrapped([]string{"sh", "-c", "echo out; echo 'Error: Cycle: a.b, c.d' >&2; exit 4"}, strings.NewReader(""), &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	if code != 4 {
+		t.Errorf("Expected exit code 4, got %d", code)
+	}
+	if stdout.String() != "out\n" {
+		t.Errorf("Expected stdout to stream through unchanged, got %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "Error: Cycle") {
+		t.Errorf("Expected stderr to stream through, got %q", stderr.String())
+	}
+	if !strings.Contains(captured, "out") || !strings.Contains(captured, "Error: Cycle") {
+		t.Errorf("Expected both streams captured, got %q", captured)
+	}
+}
+
+func TestRunWrapped_MissingCommand(t *testing.T) {
+	var stdout, stderr strings.Builder
+	if _, _, err := runWrapped([]string{"tfcycle-no-such-command"}, nil, &stdout, &stderr); err == nil {
+		t.Errorf("Expected error for missing command")
+	}
+}
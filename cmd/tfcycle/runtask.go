@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const (
+	runTaskSignatureHeader = "X-Tfc-Task-Signature"
+	runTaskVerifyToken     = "test-token"
+	runTaskMediaType       = "application/vnd.api+json"
+)
+
+type RunTaskRequest struct {
+	PayloadVersion        int    `json:"payload_version"`
+	AccessToken           string `json:"access_token"`
+	Stage                 string `json:"stage"`
+	IsSpeculative         bool   `json:"is_speculative"`
+	TaskResultID          string `json:"task_result_id"`
+	TaskResultCallbackURL string `json:"task_result_callback_url"`
+	RunAppURL             string `json:"run_app_url"`
+	RunID                 string `json:"run_id"`
+	OrganizationName      string `json:"organization_name"`
+	WorkspaceID           string `json:"workspace_id"`
+	WorkspaceName         string `json:"workspace_name"`
+}
+
+type RunTaskTag struct {
+	Label string `json:"label"`
+	Level string `json:"level"`
+}
+
+type RunTaskOutcome struct {
+	OutcomeID   string                  `json:"outcome-id"`
+	Description string                  `json:"description"`
+	Body        string                  `json:"body,omitempty"`
+	URL         string                  `json:"url,omitempty"`
+	Tags        map[string][]RunTaskTag `json:"tags,omitempty"`
+}
+
+type RunTaskResult struct {
+	Status   string
+	Message  string
+	URL      string
+	Outcomes []RunTaskOutcome
+}
+
+type RunTaskHandler struct {
+	config Config
+	client *http.Client
+	cycles *cycleRegistry
+}
+
+func NewRunTaskHandler(config Config) *RunTaskHandler {
+	return &RunTaskHandler{config: config, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (h *RunTaskHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("failed to read request: %w", err))
+		return
+	}
+	
+	// Run tasks make requests to the callback URL of the payload with its
+	// access token, so only signed payloads are accepted.
+	if h.config.RunTaskHMACKey == "" {
+		writeJSONError(w, http.StatusForbidden, fmt.Errorf("run tasks are disabled without --run-task-hmac-key"))
+		return
+	}
+	if !validRunTaskSignature(h.config.RunTaskHMACKey, body, r.Header.Get(runTaskSignatureHeader)) {
+		writeJSONError(w, http.StatusUnauthorized, fmt.Errorf("invalid %s", runTaskSignatureHeader))
+		return
+	}
+	
+	var req RunTaskRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("invalid run task payload: %w", err))
+		return
+	}
+	
+	w.WriteHeader(http.StatusOK)
+	if req.AccessToken == runTaskVerifyToken {
+		logger.Info("run task verification request received", "organization", req.OrganizationName)
+		return
+	}
+	
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		if err := h.Process(ctx, req); err != nil {
+			logger.Error("run task failed", "run", req.RunID, "workspace", req.WorkspaceName, "error", err)
+		}
+	}()
+}
+
+// runTaskSource names the workspace of a run task request as
+// ORGANIZATION/WORKSPACE.
+func runTaskSource(req RunTaskRequest) string {
+	if req.OrganizationName == "" {
+		return req.WorkspaceName
+	}
+	return req.OrganizationName + "/" + req.WorkspaceName
+}
+
+func validRunTaskSignature(key string, body []byte, signature string) bool {
+	mac := hmac.New(sha512.New, []byte(key))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
+
+func (h *RunTaskHandler) Process(ctx context.Context, req RunTaskRequest) error {
+	logger.Info("run task received", "run", req.RunID, "workspace", req.WorkspaceName, "stage", req.Stage)
+	
+	logs, err := h.fetchPlanLog(ctx, req)
+	if err != nil {
+		logger.Warn("could not read plan log", "run", req.RunID, "error", err)
+		return h.sendResult(ctx, req, RunTaskResult{
+			Status:  "passed",
+			Message: "tfcycle could not read the plan log, so the run was not checked: " + err.Error(),
+		})
+	}
+	
+	result, err := h.analyzePlanLog(ctx, logs, req)
+	if err != nil {
+		return h.sendResult(ctx, req, RunTaskResult{Status: "failed", Message: err.Error()})
+	}
+	return h.sendResult(ctx, req, result)
+}
+
+func (h *RunTaskHandler) analyzePlanLog(ctx context.Context, logs string, req RunTaskRequest) (RunTaskResult, error) {
+	analyzer, err := analyzeServeInput(ctx, h.config, planLogText(logs))
+	if errors.Is(err, tfcycle.ErrNoCycleFound) {
+		return RunTaskResult{Status: "passed", Message: "No Terraform cycle error found"}, nil
+	}
+	if err != nil {
+		return RunTaskResult{}, err
+	}
+	h.cycles.record(analyzer, runTaskSource(req), time.Now().UTC())
+	
+	severity := analyzer.Severity()
+	threshold, _ := tfcycle.ParseSeverity(h.config.FailOn)
+	status := "passed"
+	if threshold != tfcycle.SeverityNone && severity >= threshold {
+		status = "failed"
+	}
+	
+	cycles := analyzer.FindMinimalCycles()
+	result := RunTaskResult{
+		Status:  status,
+		Message: fmt.Sprintf("Terraform cycle across %d resources (severity %s, %d minimal cycles)", len(analyzer.Cycle().Nodes), severity, len(cycles)),
+		URL:     req.RunAppURL,
+	}
+	
+	for i, cycle := range cycles {
+		cycleSeverity := analyzer.CycleSeverity(cycle)
+		var body strings.Builder
+		body.WriteString("```\n" + strings.Join(cycle, " -> ") + " -> " + cycle[0] + "\n```\n\n")
+		for _, suggestion := range analyzer.GenerateSuggestions(cycle) {
+			body.WriteString("- " + suggestion.Title + "\n")
+		}
+		
+		result.Outcomes = append(result.Outcomes, RunTaskOutcome{
+			OutcomeID:   fmt.Sprintf("cycle-%d", i+1),
+			Description: fmt.Sprintf("Minimal cycle #%d (%d resources)", i+1, len(cycle)),
+			Body:        body.String(),
+			Tags: map[string][]RunTaskTag{
+				"Severity": {{Label: cycleSeverity.String(), Level: runTaskLevel(cycleSeverity, threshold)}},
+			},
+		})
+	}
+	return result, nil
+}
+
+func planLogText(logs string) string {
+	var text strings.Builder
+	for _, line := range strings.Split(logs, "\n") {
+		var entry struct {
+			Message string `json:"@message"`
+		}
+		if strings.HasPrefix(line, "{") && json.Unmarshal([]byte(line), &entry) == nil && entry.Message != "" {
+			line = entry.Message
+		}
+		text.WriteString(line + "\n")
+	}
+	return text.String()
+}
+
+func runTaskLevel(severity, threshold tfcycle.Severity) string {
+	if threshold != tfcycle.SeverityNone && severity >= threshold {
+		return "error"
+	}
+	return "warning"
+}
+
+func (h *RunTaskHandler) fetchPlanLog(ctx context.Context, req RunTaskRequest) (string, error) {
+	callback, err := url.Parse(req.TaskResultCallbackURL)
+	if err != nil || callback.Host == "" {
+		return "", fmt.Errorf("invalid task_result_callback_url %q", req.TaskResultCallbackURL)
+	}
+	planURL := fmt.Sprintf("%s://%s/api/v2/runs/%s/plan", callback.Scheme, callback.Host, url.PathEscape(req.RunID))
+	
+	var plan struct {
+		Data struct {
+			Attributes struct {
+				LogReadURL string `json:"log-read-url"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	body, err := h.get(ctx, planURL, req.AccessToken)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(body, &plan); err != nil {
+		return "", fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if plan.Data.Attributes.LogReadURL == "" {
+		return "", fmt.Errorf("plan has no log-read-url")
+	}
+	
+	logs, err := h.get(ctx, plan.Data.Attributes.LogReadURL, "")
+	if err != nil {
+		return "", err
+	}
+	return string(logs), nil
+}
+
+func (h *RunTaskHandler) get(ctx context.Context, target, token string) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		httpReq.Header.Set("Content-Type", runTaskMediaType)
+	}
+	
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRequestBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", httpReq.URL.Redacted(), resp.Status)
+	}
+	if len(body) > maxRequestBytes {
+		return nil, fmt.Errorf("GET %s: response larger than %d bytes", httpReq.URL.Redacted(), maxRequestBytes)
+	}
+	return body, nil
+}
+
+func (h *RunTaskHandler) sendResult(ctx context.Context, req RunTaskRequest, result RunTaskResult) error {
+	outcomes := make([]map[string]interface{}, len(result.Outcomes))
+	for i, outcome := range result.Outcomes {
+		outcomes[i] = map[string]interface{}{"type": "task-result-outcomes", "attributes": outcome}
+	}
+	
+	payload := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type": "task-results",
+			"attributes": map[string]string{
+				"status":  result.Status,
+				"message": result.Message,
+				"url":     result.URL,
+			},
+			"relationships": map[string]interface{}{
+				"outcomes": map[string]interface{}{"data": outcomes},
+			},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPatch, req.TaskResultCallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+req.AccessToken)
+	httpReq.Header.Set("Content-Type", runTaskMediaType)
+	
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send run task result: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to send run task result: %s", resp.Status)
+	}
+	
+	logger.Info("run task result sent", "run", req.RunID, "status", result.Status)
+	return nil
+}
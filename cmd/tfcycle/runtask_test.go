@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func signRunTask(key, body string) string {
+	mac := hmac.New(sha512.New, []byte(key))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestRunTaskHandler_Signature(t *testing.T) {
+	handler := NewRunTaskHandler(Config{RunTaskHMACKey: "secret", FailOn: "low"})
+	body := `{"payload_version": 1, "access_token": "test-token"}`
+	
+	req := httptest.NewRequest(http.MethodPost, "/run-task", strings.NewReader(body))
+	req.Header.Set(runTaskSignatureHeader, signRunTask("wrong", body))
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a bad signature, got %d", resp.Code)
+	}
+	
+	req = httptest.NewRequest(http.MethodPost, "/run-task", strings.NewReader(body))
+	req.Header.Set(runTaskSignatureHeader, signRunTask("secret", body))
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected 200 for the verification request, got %d", resp.Code)
+	}
+}
+
+func TestRunTaskHandler_RequiresKey(t *testing.T) {
+	body := `{"payload_version": 1, "access_token": "run-token", "task_result_callback_url": "http://127.0.0.1:1/callback"}`
+	
+	resp := httptest.NewRecorder()
+	NewRunTaskHandler(Config{FailOn: "low"}).ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/run-task", strings.NewReader(body)))
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 without an HMAC key, got %d", resp.Code)
+	}
+	
+	resp = httptest.NewRecorder()
+	NewServer(Config{FailOn: "low"}).ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/run-task", strings.NewReader(body)))
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected /run-task not to be served without an HMAC key, got %d", resp.Code)
+	}
+}
+
+func TestRunTaskHandler_GetLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, maxRequestBytes+1))
+	}))
+	defer server.Close()
+	
+	_, err := NewRunTaskHandler(Config{}).get(context.Background(), server.URL, "")
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("Expected an error for an oversized response, got %v", err)
+	}
+}
+
+func newFakeTerraformCloud(t *testing.T, logs string) (*httptest.Server, chan map[string]interface{}) {
+	results := make(chan map[string]interface{}, 1)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/runs/run-1/plan":
+			if r.Header.Get("Authorization") != "Bearer run-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			io.WriteString(w, `{"data": {"attributes": {"log-read-url": "`+server.URL+`/logs/plan"}}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/logs/plan":
+			io.WriteString(w, logs)
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v2/task-results/tr-1/callback":
+			var result map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&result)
+			results <- result
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, results
+}
+
+func runTaskRequest(server *httptest.Server) RunTaskRequest {
+	return RunTaskRequest{
+		PayloadVersion:        1,
+		AccessToken:           "run-token",
+		Stage:                 "post_plan",
+		RunID:                 "run-1",
+		WorkspaceName:         "network",
+		TaskResultCallbackURL: server.URL + "/api/v2/task-results/tr-1/callback",
+	}
+}
+
+func TestRunTaskHandler_ProcessCycle(t *testing.T) {
+	logs := `{"@level":"info","@message":"Terraform 1.9.5"}
+{"@level":"error","@message":"Error: Cycle: aws_security_group.a, aws_security_group.b"}
+`
+	server, results := newFakeTerraformCloud(t, logs)
+	handler := NewRunTaskHandler(Config{FailOn: "low"})
+	
+	if err := handler.Process(context.Background(), runTaskRequest(server)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	result := <-results
+	data := result["data"].(map[string]interface{})
+	attributes := data["attributes"].(map[string]interface{})
+	if attributes["status"] != "failed" {
+		t.Errorf("Expected failed status, got %v", attributes)
+	}
+	
+	outcomes := data["relationships"].(map[string]interface{})["outcomes"].(map[string]interface{})["data"].([]interface{})
+	if len(outcomes) != 1 {
+		t.Fatalf("Expected one outcome per minimal cycle, got %v", outcomes)
+	}
+	outcome := outcomes[0].(map[string]interface{})["attributes"].(map[string]interface{})
+	if !strings.Contains(outcome["body"].(string), "aws_security_group.a -> aws_security_group.b") {
+		t.Errorf("Expected cycle path in outcome body, got %v", outcome["body"])
+	}
+}
+
+func TestRunTaskHandler_ProcessNoCycle(t *testing.T) {
+	server, results := newFakeTerraformCloud(t, "Plan: 1 to add, 0 to change, 0 to destroy.\n")
+	handler := NewRunTaskHandler(Config{FailOn: "low"})
+	
+	if err := handler.Process(context.Background(), runTaskRequest(server)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	attributes := (<-results)["data"].(map[string]interface{})["attributes"].(map[string]interface{})
+	if attributes["status"] != "passed" {
+		t.Errorf("Expected passed status, got %v", attributes)
+	}
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	releaseRepo        = "raimdev/tfcycle"
+	defaultGitHubAPI   = "https://api.github.com"
+	checksumsAsset     = "checksums.txt"
+	checksumsSignature = "checksums.txt.sig"
+)
+
+// releasePublicKey is the base64 ed25519 key that signs checksums.txt. Release
+// builds set it with -ldflags "-X main.releasePublicKey=...".
+var releasePublicKey = ""
+
+type githubRelease struct {
+	TagName string        `json:"tag_name"`
+	Assets  []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+func (r githubRelease) asset(name string) (githubAsset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return githubAsset{}, false
+}
+
+type Updater struct {
+	APIBase    string
+	Repo       string
+	Current    string
+	Executable string
+	AssetName  string
+	PublicKey  ed25519.PublicKey
+	// SkipSignature installs releases without checking checksums.txt.sig,
+	// which a build without PublicKey cannot do (--insecure-skip-signature).
+	SkipSignature bool
+	Token         string
+	Out           io.Writer
+	client        *http.Client
+}
+
+func NewUpdater(out io.Writer) (*Updater, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the tfcycle binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+	
+	var publicKey ed25519.PublicKey
+	if releasePublicKey != "" {
+		key, err := base64.StdEncoding.DecodeString(releasePublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid release public key built into this binary")
+		}
+		publicKey = key
+	}
+	
+	return &Updater{
+		APIBase:    defaultGitHubAPI,
+		Repo:       releaseRepo,
+		Current:    version,
+		Executable: executable,
+		AssetName:  releaseAssetName(runtime.GOOS, runtime.GOARCH),
+		PublicKey:  publicKey,
+		Token:      os.Getenv("GITHUB_TOKEN"),
+		Out:        out,
+		client:     &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+func releaseAssetName(goos, goarch string) string {
+	name := "tfcycle_" + goos + "_" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Update replaces the running binary with the latest release if it is newer.
+// With checkOnly it only reports whether an update is available.
+func (u *Updater) Update(ctx context.Context, checkOnly bool) error {
+	release, err := u.latestRelease(ctx)
+	if err != nil {
+		return err
+	}
+	
+	latest := strings.TrimPrefix(release.TagName, "v")
+	if compareVersions(latest, u.Current) <= 0 {
+		fmt.Fprintf(u.Out, "tfcycle %s is up to date\n", u.Current)
+		return nil
+	}
+	if checkOnly {
+		fmt.Fprintf(u.Out, "tfcycle %s is available (installed: %s); run 'tfcycle self-update' to install it\n", latest, u.Current)
+		return nil
+	}
+	
+	binaryAsset, ok := release.asset(u.AssetName)
+	if !ok {
+		return fmt.Errorf("release %s has no %s binary", release.TagName, u.AssetName)
+	}
+	checksumsFile, ok := release.asset(checksumsAsset)
+	if !ok {
+		return fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.TagName, checksumsAsset)
+	}
+	
+	checksums, err := u.download(ctx, checksumsFile.URL)
+	if err != nil {
+		return err
+	}
+	if err := u.verifySignature(ctx, release, checksums); err != nil {
+		return err
+	}
+	want, err := checksumFor(checksums, u.AssetName)
+	if err != nil {
+		return err
+	}
+	
+	binary, err := u.download(ctx, binaryAsset.URL)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(binary)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", u.AssetName, want, got)
+	}
+	
+	if err := replaceExecutable(u.Executable, binary); err != nil {
+		return err
+	}
+	fmt.Fprintf(u.Out, "Updated tfcycle %s → %s (%s)\n", u.Current, latest, u.Executable)
+	return nil
+}
+
+func (u *Updater) verifySignature(ctx context.Context, release githubRelease, checksums []byte) error {
+	if u.SkipSignature {
+		fmt.Fprintln(u.Out, "⚠️  Skipping the signature check (--insecure-skip-signature); verifying the checksum only")
+		return nil
+	}
+	// checksums.txt comes from the same release as the binary, so without a
+	// signature it proves nothing about who published it.
+	if u.PublicKey == nil {
+		return fmt.Errorf("this build has no release public key to verify %s; install a release build or pass --insecure-skip-signature", checksumsSignature)
+	}
+	
+	signatureFile, ok := release.asset(checksumsSignature)
+	if !ok {
+		return fmt.Errorf("release %s has no %s; refusing to install an unsigned release", release.TagName, checksumsSignature)
+	}
+	encoded, err := u.download(ctx, signatureFile.URL)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", checksumsSignature, err)
+	}
+	if !ed25519.Verify(u.PublicKey, checksums, signature) {
+		return fmt.Errorf("signature of %s does not match the release public key", checksumsAsset)
+	}
+	return nil
+}
+
+func (u *Updater) latestRelease(ctx context.Context) (githubRelease, error) {
+	var release githubRelease
+	body, err := u.get(ctx, strings.TrimSuffix(u.APIBase, "/")+"/repos/"+u.Repo+"/releases/latest", "application/vnd.github+json")
+	if err != nil {
+		return release, fmt.Errorf("failed to check for releases: %w", err)
+	}
+	if err := json.Unmarshal(body, &release); err != nil {
+		return release, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return release, nil
+}
+
+func (u *Updater) download(ctx context.Context, url string) ([]byte, error) {
+	body, err := u.get(ctx, url, "application/octet-stream")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return body, nil
+}
+
+func (u *Updater) get(ctx context.Context, url, accept string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", accept)
+	request.Header.Set("User-Agent", "tfcycle/"+u.Current)
+	if u.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+u.Token)
+	}
+	
+	response, err := u.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	
+	if response.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return nil, fmt.Errorf("GitHub returned %s: %s", response.Status, strings.TrimSpace(string(message)))
+	}
+	return io.ReadAll(response.Body)
+}
+
+// checksumFor finds name in sha256sum output.
+func checksumFor(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s lists no checksum for %s", checksumsAsset, name)
+}
+
+// replaceExecutable writes binary next to path and swaps it in. The old
+// binary is moved aside first because Windows cannot overwrite a running
+// executable.
+func replaceExecutable(path string, binary []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tfcycle-update-*")
+	if err != nil {
+		return fmt.Errorf("cannot write to %s: %w", filepath.Dir(path), err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0111); err != nil {
+		return err
+	}
+	
+	old := path + ".old"
+	os.Remove(old)
+	if err := os.Rename(path, old); err != nil {
+		return fmt.Errorf("failed to move the current binary aside: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Rename(old, path)
+		return fmt.Errorf("failed to install the new binary: %w", err)
+	}
+	os.Remove(old)
+	return nil
+}
+
+// compareVersions compares dotted numeric versions such as 1.2.0, ignoring
+// pre-release and build suffixes.
+func compareVersions(a, b string) int {
+	partsA, partsB := versionParts(a), versionParts(b)
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x = partsA[i]
+		}
+		if i < len(partsB) {
+			y = partsB[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, field := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(field)
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+func runSelfUpdate(ctx context.Context, config Config) error {
+	updater, err := NewUpdater(os.Stdout)
+	if err != nil {
+		return err
+	}
+	updater.SkipSignature = config.InsecureSkipSignature
+	return updater.Update(ctx, config.CheckOnly)
+}
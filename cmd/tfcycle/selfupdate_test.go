@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestRelease(t *testing.T, binary []byte, sign func(checksums []byte) []byte) *httptest.Server {
+	sum := sha256.Sum256(binary)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  tfcycle_linux_amd64\n")
+	
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/raimdev/tfcycle/releases/latest":
+			json.NewEncoder(w).Encode(githubRelease{
+				TagName: "v1.1.0",
+				Assets: []githubAsset{
+					{Name: "tfcycle_linux_amd64", URL: server.URL + "/download/tfcycle_linux_amd64"},
+					{Name: "checksums.txt", URL: server.URL + "/download/checksums.txt"},
+					{Name: "checksums.txt.sig", URL: server.URL + "/download/checksums.txt.sig"},
+				},
+			})
+		case "/download/tfcycle_linux_amd64":
+			w.Write(binary)
+		case "/download/checksums.txt":
+			w.Write(checksums)
+		case "/download/checksums.txt.sig":
+			w.Write(sign(checksums))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestUpdater(t *testing.T, server *httptest.Server, current string, publicKey ed25519.PublicKey) (*Updater, *bytes.Buffer) {
+	executable := filepath.Join(t.TempDir(), "tfcycle")
+	if err := os.WriteFile(executable, []byte("old binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	return &Updater{
+		APIBase:    server.URL,
+		Repo:       releaseRepo,
+		Current:    current,
+		Executable: executable,
+		AssetName:  "tfcycle_linux_amd64",
+		PublicKey:  publicKey,
+		Out:        &out,
+		client:     server.Client(),
+	}, &out
+}
+
+func TestUpdater_VerifiesAndReplaces(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTestRelease(t, []byte("new binary"), func(checksums []byte) []byte {
+		return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, checksums)))
+	})
+	updater, out := newTestUpdater(t, server, "1.0.0", publicKey)
+	
+	if err := updater.Update(context.Background(), false); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	data, err := os.ReadFile(updater.Executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "new binary" {
+		t.Errorf("Expected the binary to be replaced, got %q", data)
+	}
+	if !strings.Contains(out.String(), "1.0.0 → 1.1.0") {
+		t.Errorf("Expected an update message, got %q", out.String())
+	}
+}
+
+func TestUpdater_RejectsBadSignature(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTestRelease(t, []byte("new binary"), func(checksums []byte) []byte {
+		return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(otherKey, checksums)))
+	})
+	updater, _ := newTestUpdater(t, server, "1.0.0", publicKey)
+	
+	if err := updater.Update(context.Background(), false); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Fatalf("Expected a signature error, got %v", err)
+	}
+	if data, _ := os.ReadFile(updater.Executable); string(data) != "old binary" {
+		t.Errorf("Expected the binary to be left alone, got %q", data)
+	}
+}
+
+func TestUpdater_RequiresSignature(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTestRelease(t, []byte("new binary"), func([]byte) []byte { return nil })
+	
+	updater, _ := newTestUpdater(t, server, "1.0.0", publicKey)
+	if err := updater.Update(context.Background(), false); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("Expected an empty signature to abort the update, got %v", err)
+	}
+	
+	updater, _ = newTestUpdater(t, server, "1.0.0", nil)
+	if err := updater.Update(context.Background(), false); err == nil || !strings.Contains(err.Error(), "--insecure-skip-signature") {
+		t.Errorf("Expected a build without a public key to refuse the update, got %v", err)
+	}
+	if data, _ := os.ReadFile(updater.Executable); string(data) != "old binary" {
+		t.Errorf("Expected the binary to be left alone, got %q", data)
+	}
+	
+	updater.SkipSignature = true
+	if err := updater.Update(context.Background(), false); err != nil {
+		t.Fatalf("Expected --insecure-skip-signature to install on the checksum alone, got: %v", err)
+	}
+	if data, _ := os.ReadFile(updater.Executable); string(data) != "new binary" {
+		t.Errorf("Expected the binary to be replaced, got %q", data)
+	}
+}
+
+func TestUpdater_UpToDateAndCheck(t *testing.T) {
+	server := newTestRelease(t, []byte("new binary"), func([]byte) []byte { return nil })
+	
+	updater, out := newTestUpdater(t, server, "1.1.0", nil)
+	if err := updater.Update(context.Background(), false); err != nil || !strings.Contains(out.String(), "up to date") {
+		t.Errorf("Expected up to date, got %q (%v)", out.String(), err)
+	}
+	
+	updater, out = newTestUpdater(t, server, "1.0.0", nil)
+	if err := updater.Update(context.Background(), true); err != nil || !strings.Contains(out.String(), "1.1.0 is available") {
+		t.Errorf("Expected an available update, got %q (%v)", out.String(), err)
+	}
+	if data, _ := os.ReadFile(updater.Executable); string(data) != "old binary" {
+		t.Errorf("Expected --check to leave the binary alone, got %q", data)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.10.0", "1.9.3", 1},
+		{"1.0", "1.0.1", -1},
+		{"2.0.0-rc.1", "2.0.0", 0},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q): expected %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"tfcycle/pkg/tfcycle"
+)
+
+const maxRequestBytes = 10 << 20
+
+// maxServedCycles bounds the distinct cycles a server remembers; the least
+// recently seen is forgotten first.
+const maxServedCycles = 10000
+
+//go:embed web/index.html
+var indexPage []byte
+
+type serveRequest struct {
+	Error   string `json:"error"`
+	Address string `json:"address,omitempty"`
+	Scope   string `json:"scope,omitempty"`
+	Lang    string `json:"lang,omitempty"`
+	Source  string `json:"source,omitempty"`
+}
+
+// ServedCycle is a distinct cycle that a server has analyzed, deduplicated
+// by fingerprint (see CycleFingerprint). Count is how often it was analyzed
+// and Sources the distinct sources that sent it.
+type ServedCycle struct {
+	Fingerprint string    `json:"fingerprint"`
+	Severity    string    `json:"severity"`
+	Nodes       []string  `json:"nodes"`
+	Count       int       `json:"count"`
+	Sources     []string  `json:"sources"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// cycleRegistry counts the cycles a server analyzes. A nil registry records
+// nothing.
+type cycleRegistry struct {
+	mu     sync.Mutex
+	cycles map[string]*ServedCycle
+}
+
+func newCycleRegistry() *cycleRegistry {
+	return &cycleRegistry{cycles: make(map[string]*ServedCycle)}
+}
+
+// record counts an analysis of the cycle of analyzer sent by source ("" if
+// unknown) and returns the updated entry.
+func (cr *cycleRegistry) record(analyzer *tfcycle.CycleAnalyzer, source string, now time.Time) ServedCycle {
+	if cr == nil {
+		return ServedCycle{}
+	}
+	nodes := analyzer.NodeNames()
+	fingerprint := CycleFingerprint(nodes)
+	
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cycle := cr.cycles[fingerprint]
+	if cycle == nil {
+		if len(cr.cycles) >= maxServedCycles {
+			cr.forgetOldest()
+		}
+		cycle = &ServedCycle{Fingerprint: fingerprint, Nodes: nodes, Sources: []string{}, FirstSeen: now}
+		cr.cycles[fingerprint] = cycle
+	}
+	cycle.Severity = analyzer.Severity().String()
+	cycle.Count++
+	cycle.LastSeen = now
+	if source != "" && !slices.Contains(cycle.Sources, source) {
+		cycle.Sources = append(cycle.Sources, source)
+	}
+	served := *cycle
+	served.Sources = slices.Clone(cycle.Sources)
+	return served
+}
+
+func (cr *cycleRegistry) forgetOldest() {
+	var oldest *ServedCycle
+	for _, cycle := range cr.cycles {
+		if oldest == nil || cycle.LastSeen.Before(oldest.LastSeen) {
+			oldest = cycle
+		}
+	}
+	if oldest != nil {
+		delete(cr.cycles, oldest.Fingerprint)
+	}
+}
+
+// list returns the recorded cycles, most frequent first.
+func (cr *cycleRegistry) list() []ServedCycle {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cycles := make([]ServedCycle, 0, len(cr.cycles))
+	for _, cycle := range cr.cycles {
+		served := *cycle
+		served.Sources = slices.Clone(cycle.Sources)
+		cycles = append(cycles, served)
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		if cycles[i].Count != cycles[j].Count {
+			return cycles[i].Count > cycles[j].Count
+		}
+		return cycles[i].LastSeen.After(cycles[j].LastSeen)
+	})
+	return cycles
+}
+
+type Server struct {
+	config Config
+	mux    *http.ServeMux
+	cycles *cycleRegistry
+}
+
+func NewServer(config Config) *Server {
+	s := &Server{config: config, mux: http.NewServeMux(), cycles: newCycleRegistry()}
+	s.mux.HandleFunc("GET /{$}", s.handleIndex)
+	s.mux.HandleFunc("POST /api/analyze", s.handleAnalyze)
+	s.mux.HandleFunc("POST /api/visualize", s.handleVisualize)
+	s.mux.HandleFunc("POST /api/explain", s.handleExplain)
+	s.mux.HandleFunc("GET /api/cycles", s.handleCycles)
+	if config.RunTaskHMACKey != "" {
+		runTask := NewRunTaskHandler(config)
+		runTask.cycles = s.cycles
+		s.mux.Handle("POST /run-task", runTask)
+	}
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	page, err := themedIndexPage(s.config)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
+
+// themedIndexPage returns the web UI with the configured theme and custom
+// CSS. The CSS file is read on every request so edits show on reload.
+func themedIndexPage(config Config) ([]byte, error) {
+	page := indexPage
+	if config.Theme != "" && config.Theme != "auto" {
+		page = bytes.Replace(page, []byte(`data-theme="auto"`), []byte(`data-theme="`+config.Theme+`"`), 1)
+	}
+	if config.CSSFile == "" {
+		return page, nil
+	}
+	
+	css, err := os.ReadFile(config.CSSFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSS: %w", err)
+	}
+	if bytes.Contains(bytes.ToLower(css), []byte("</style")) {
+		return nil, fmt.Errorf("%s must not close the style element", config.CSSFile)
+	}
+	custom := append([]byte("<style>\n"), css...)
+	custom = append(custom, "\n</style>\n</head>"...)
+	return bytes.Replace(page, []byte("</head>"), custom, 1), nil
+}
+
+func (s *Server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	req, analyzer, ok := s.analyzeRequest(w, r)
+	if !ok {
+		return
+	}
+	
+	formatter := tfcycle.NewOutputFormatter(analyzer, s.config.Verbose)
+	lang, err := tfcycle.ResolveLanguage(firstNonEmpty(req.Lang, s.config.Lang))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	formatter.SetLanguage(lang)
+	
+	analysis, err := formatter.FormatAsJSON()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+	
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"analysis":   json.RawMessage(analysis),
+		"report":     formatter.FormatAnalysis(),
+		"graph":      analyzer.GraphData(),
+		"occurrence": s.cycles.record(analyzer, req.Source, time.Now().UTC()),
+	})
+}
+
+// handleCycles lists the distinct cycles analyzed since the server started,
+// so one that keeps recurring shows up once with its count.
+func (s *Server) handleCycles(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"cycles": s.cycles.list()})
+}
+
+func (s *Server) handleVisualize(w http.ResponseWriter, r *http.Request) {
+	req, analyzer, ok := s.analyzeRequest(w, r)
+	if !ok {
+		return
+	}
+	
+	opts := tfcycle.DefaultVisualOptions()
+	if req.Scope != "" {
+		opts.Scope = req.Scope
+	}
+	if err := opts.Validate(); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+	io.WriteString(w, tfcycle.NewOutputFormatter(analyzer, false).GenerateVisualization(opts))
+}
+
+func (s *Server) handleExplain(w http.ResponseWriter, r *http.Request) {
+	req, analyzer, ok := s.analyzeRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.Address == "" {
+		writeJSONError(w, http.StatusBadRequest, errors.New("address is required"))
+		return
+	}
+	
+	nodes := analyzer.MatchNodes(req.Address)
+	if len(nodes) == 0 {
+		writeJSONError(w, http.StatusNotFound, fmt.Errorf("resource %s is not part of the cycle", req.Address))
+		return
+	}
+	
+	explanations := make([]tfcycle.NodeExplanation, len(nodes))
+	for i, node := range nodes {
+		explanations[i] = analyzer.ExplainNode(node)
+	}
+	writeJSON(w, http.StatusOK, explanations)
+}
+
+func (s *Server) analyzeRequest(w http.ResponseWriter, r *http.Request) (serveRequest, *tfcycle.CycleAnalyzer, bool) {
+	req, err := decodeServeRequest(w, r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return req, nil, false
+	}
+	
+	analyzer, err := analyzeServeInput(r.Context(), s.config, req.Error)
+	if err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err)
+		return req, nil, false
+	}
+	return req, analyzer, true
+}
+
+func analyzeServeInput(ctx context.Context, config Config, text string) (*tfcycle.CycleAnalyzer, error) {
+	cycle, err := tfcycle.Parse(ctx, strings.NewReader(text))
+	if errors.Is(err, tfcycle.ErrEmptyInput) {
+		return nil, tfcycle.ErrNoCycleFound
+	}
+	if errors.Is(err, tfcycle.ErrNoCycleFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cycle error: %w", err)
+	}
+	if config.Redact {
+		cycle = tfcycle.NewRedactor().RedactCycle(cycle)
+	}
+	
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+	
+	analyzer, err := tfcycle.Analyze(ctx, cycle)
+	if analyzer == nil {
+		return nil, err
+	}
+	if err != nil {
+		logger.Warn("analysis timed out, reporting the cycle as listed by Terraform", "timeout", config.Timeout)
+	}
+	return analyzer, nil
+}
+
+func decodeServeRequest(w http.ResponseWriter, r *http.Request) (serveRequest, error) {
+	var req serveRequest
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		return req, fmt.Errorf("failed to read request: %w", err)
+	}
+	
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		req.Error = string(body)
+		req.Address = r.URL.Query().Get("address")
+		req.Scope = r.URL.Query().Get("scope")
+		req.Lang = r.URL.Query().Get("lang")
+		req.Source = r.URL.Query().Get("source")
+		return req, nil
+	}
+	
+	if err := json.Unmarshal(body, &req); err != nil {
+		return req, fmt.Errorf("invalid JSON request: %w", err)
+	}
+	return req, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func runServe(config Config) error {
+	if !slices.Contains(flagValues["theme"], config.Theme) {
+		return inputErrorf("--theme: unknown theme %q (use %s)", config.Theme, strings.Join(flagValues["theme"], ", "))
+	}
+	if _, err := themedIndexPage(config); err != nil {
+		return inputErrorf("--css: %w", err)
+	}
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	server := &http.Server{
+		Addr:              config.Listen,
+		Handler:           NewServer(config),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	
+	errs := make(chan error, 2)
+	go func() {
+		if err := server.ListenAndServe(); err != nil {
+			errs <- inputErrorf("failed to listen on %s: %w", config.Listen, err)
+		}
+	}()
+	fmt.Fprintf(os.Stderr, "Serving tfcycle on http://%s\n", displayAddr(config.Listen))
+	
+	var grpcServer *grpc.Server
+	if config.GRPCListen != "" {
+		listener, err := net.Listen("tcp", config.GRPCListen)
+		if err != nil {
+			server.Close()
+			return inputErrorf("failed to listen on %s: %w", config.GRPCListen, err)
+		}
+		grpcServer = NewGRPCServer(config)
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				errs <- fmt.Errorf("gRPC server failed: %w", err)
+			}
+		}()
+		fmt.Fprintf(os.Stderr, "Serving tfcycle gRPC on %s\n", displayAddr(config.GRPCListen))
+	}
+	
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+	}
+	
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if shutdownErr := server.Shutdown(shutdownCtx); err == nil {
+		err = shutdownErr
+	}
+	return err
+}
+
+func displayAddr(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "localhost" + addr
+	}
+	return addr
+}
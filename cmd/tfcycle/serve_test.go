@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const serveTestError = "Error: Cycle: aws_security_group.a, aws_security_group.b"
+
+func serveRequestTest(t *testing.T, method, path, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	recorder := httptest.NewRecorder()
+	NewServer(Config{FailOn: "low"}).ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestServer_Index(t *testing.T) {
+	resp := serveRequestTest(t, http.MethodGet, "/", "", "")
+	if resp.Code != http.StatusOK || !strings.Contains(resp.Body.String(), "<textarea") {
+		t.Errorf("Expected index page, got %d:\n%s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestServer_Theme(t *testing.T) {
+	css := filepath.Join(t.TempDir(), "portal.css")
+	if err := os.WriteFile(css, []byte("body { font-family: Inter; }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	NewServer(Config{Theme: "dark", CSSFile: css}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	page := recorder.Body.String()
+	if !strings.Contains(page, `data-theme="dark"`) || !strings.Contains(page, "<style>\nbody { font-family: Inter; }\n</style>\n</head>") {
+		t.Errorf("Expected the dark theme and custom CSS, got:\n%s", page)
+	}
+	
+	if err := runServe(Config{Theme: "sepia"}); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error for an unknown theme, got: %v", err)
+	}
+	if err := runServe(Config{Theme: "auto", CSSFile: filepath.Join(t.TempDir(), "missing.css")}); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error for a missing CSS file, got: %v", err)
+	}
+}
+
+func TestServer_Analyze(t *testing.T) {
+	body, _ := json.Marshal(map[string]string{"error": serveTestError})
+	resp := serveRequestTest(t, http.MethodPost, "/api/analyze", "application/json", string(body))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d:\n%s", resp.Code, resp.Body.String())
+	}
+	
+	var result struct {
+		Analysis map[string]interface{} `json:"analysis"`
+		Report   string                 `json:"report"`
+		Graph    tfcycle.GraphData      `json:"graph"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Expected JSON response, got: %v", err)
+	}
+	if !strings.Contains(result.Report, "TERRAFORM CYCLE DETECTED") {
+		t.Errorf("Expected text report, got:\n%s", result.Report)
+	}
+	if result.Analysis["severity"] != "low" {
+		t.Errorf("Expected low severity in analysis, got %v", result.Analysis["severity"])
+	}
+	if len(result.Graph.Nodes) != 2 || len(result.Graph.Edges) != 2 || !result.Graph.Edges[0].InCycle {
+		t.Errorf("Expected 2 nodes and 2 cycle edges, got %+v", result.Graph)
+	}
+}
+
+func TestServer_Cycles(t *testing.T) {
+	server := NewServer(Config{})
+	post := func(path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return recorder
+	}
+	post("/api/analyze?source=prod", serveTestError)
+	post("/api/analyze?source=staging", "Error: Cycle: aws_security_group.b, aws_security_group.a")
+	resp := post("/api/analyze?source=prod", serveTestError)
+	
+	var result struct {
+		Occurrence ServedCycle `json:"occurrence"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Expected JSON response, got: %v", err)
+	}
+	if result.Occurrence.Count != 3 || strings.Join(result.Occurrence.Sources, ",") != "prod,staging" {
+		t.Errorf("Expected the third occurrence from prod and staging, got %+v", result.Occurrence)
+	}
+	
+	post("/api/analyze", "Error: Cycle: aws_iam_role.r, aws_iam_policy.p")
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/cycles", nil))
+	var list struct {
+		Cycles []ServedCycle `json:"cycles"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Expected JSON response, got: %v", err)
+	}
+	if len(list.Cycles) != 2 || list.Cycles[0].Count != 3 || list.Cycles[1].Count != 1 || len(list.Cycles[1].Sources) != 0 {
+		t.Errorf("Expected two distinct cycles, the recurring one first, got %+v", list.Cycles)
+	}
+}
+
+func TestServer_PlainTextBody(t *testing.T) {
+	resp := serveRequestTest(t, http.MethodPost, "/api/visualize?scope=full", "text/plain", serveTestError)
+	if resp.Code != http.StatusOK || !strings.HasPrefix(resp.Body.String(), "digraph terraform_cycle") {
+		t.Errorf("Expected DOT output, got %d:\n%s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	resp := serveRequestTest(t, http.MethodPost, "/api/analyze", "text/plain", "Plan: 1 to add")
+	if resp.Code != http.StatusUnprocessableEntity || !strings.Contains(resp.Body.String(), "no Terraform cycle error") {
+		t.Errorf("Expected 422 for input without a cycle, got %d:\n%s", resp.Code, resp.Body.String())
+	}
+	
+	resp = serveRequestTest(t, http.MethodPost, "/api/explain?address=aws_instance.web", "text/plain", serveTestError)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown address, got %d:\n%s", resp.Code, resp.Body.String())
+	}
+	
+	resp = serveRequestTest(t, http.MethodPost, "/api/analyze", "application/json", "{")
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid JSON, got %d", resp.Code)
+	}
+}
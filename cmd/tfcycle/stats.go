@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+// StatsCount counts how often a resource type or module appears in the
+// minimal cycles of all inputs, and in how many inputs.
+type StatsCount struct {
+	Name        string `json:"name"`
+	Occurrences int    `json:"occurrences"`
+	Files       int    `json:"files"`
+}
+
+// StatsReport summarizes the cycles of many error files or JSON analyses.
+type StatsReport struct {
+	Files              int          `json:"files"`
+	FilesWithCycle     int          `json:"files_with_cycle"`
+	DistinctCycles     int          `json:"distinct_cycles"`
+	MinimalCycles      int          `json:"minimal_cycles"`
+	AverageCycleLength float64      `json:"average_cycle_length"`
+	LongestCycle       int          `json:"longest_cycle"`
+	AverageResources   float64      `json:"average_resources"`
+	ResourceTypes      []StatsCount `json:"resource_types"`
+	Modules            []StatsCount `json:"modules"`
+}
+
+func runStats(ctx context.Context, config Config) error {
+	if len(config.Args) == 0 {
+		return inputErrorf("stats requires at least one input, e.g. tfcycle stats logs/*.txt")
+	}
+	
+	var analyzers []*tfcycle.CycleAnalyzer
+	for _, file := range config.Args {
+		text, versions, err := readInput(ctx, file, config)
+		if err != nil {
+			return inputErrorf("failed to read %s: %w", file, err)
+		}
+		if text == "" {
+			logger.Debug("no cycle error in input", "input", file)
+			analyzers = append(analyzers, nil)
+			continue
+		}
+		
+		cycle, err := tfcycle.ParseInput(text)
+		if err != nil {
+			return inputErrorf("failed to parse cycle error in %s: %w", file, err)
+		}
+		withInputVersions(cycle, versions)
+		cycle, err = filterCycle(cycle, config)
+		if err != nil {
+			return err
+		}
+		analyzer := tfcycle.NewCycleAnalyzer(cycle)
+		analyzeWithTimeout(ctx, analyzer, config)
+		analyzers = append(analyzers, analyzer)
+	}
+	
+	report := NewStatsReport(analyzers)
+	switch outputFormat(config) {
+	case "json":
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		return writeReport(string(jsonData)+"\n", config)
+	case "markdown":
+		return writeReport(FormatStatsMarkdown(report), config)
+	default:
+		return writeReport(FormatStatsReport(report), config)
+	}
+}
+
+// NewStatsReport computes statistics over the minimal cycles of analyzers, one
+// per input. A nil analyzer is an input without a cycle.
+func NewStatsReport(analyzers []*tfcycle.CycleAnalyzer) StatsReport {
+	report := StatsReport{Files: len(analyzers)}
+	
+	fingerprints := make(map[string]bool)
+	resourceTypes := newStatsCounter()
+	modules := newStatsCounter()
+	cycleLength, resources := 0, 0
+	for i, analyzer := range analyzers {
+		if analyzer == nil {
+			continue
+		}
+		report.FilesWithCycle++
+		resources += len(analyzer.Cycle().Nodes)
+		fingerprints[CycleFingerprint(analyzer.NodeNames())] = true
+		
+		for _, minimal := range analyzer.FindMinimalCycles() {
+			report.MinimalCycles++
+			cycleLength += len(minimal)
+			report.LongestCycle = max(report.LongestCycle, len(minimal))
+			for _, name := range minimal {
+				node := analyzer.Cycle().GetNodeByName(name)
+				if node == nil {
+					continue
+				}
+				module := strings.Join(node.ModulePath, ".")
+				if module == "" {
+					module = rootModuleName
+				}
+				modules.add(module, i)
+				resourceTypes.add(node.ResourceType, i)
+			}
+		}
+	}
+	
+	report.DistinctCycles = len(fingerprints)
+	if report.MinimalCycles > 0 {
+		report.AverageCycleLength = float64(cycleLength) / float64(report.MinimalCycles)
+	}
+	if report.FilesWithCycle > 0 {
+		report.AverageResources = float64(resources) / float64(report.FilesWithCycle)
+	}
+	report.ResourceTypes = resourceTypes.counts()
+	report.Modules = modules.counts()
+	return report
+}
+
+type statsCounter struct {
+	occurrences map[string]int
+	files       map[string]map[int]bool
+}
+
+func newStatsCounter() *statsCounter {
+	return &statsCounter{
+		occurrences: make(map[string]int),
+		files:       make(map[string]map[int]bool),
+	}
+}
+
+func (sc *statsCounter) add(name string, file int) {
+	if sc.files[name] == nil {
+		sc.files[name] = make(map[int]bool)
+	}
+	sc.occurrences[name]++
+	sc.files[name][file] = true
+}
+
+func (sc *statsCounter) counts() []StatsCount {
+	counts := make([]StatsCount, 0, len(sc.occurrences))
+	for name, occurrences := range sc.occurrences {
+		counts = append(counts, StatsCount{Name: name, Occurrences: occurrences, Files: len(sc.files[name])})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Occurrences != counts[j].Occurrences {
+			return counts[i].Occurrences > counts[j].Occurrences
+		}
+		if counts[i].Files != counts[j].Files {
+			return counts[i].Files > counts[j].Files
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+func FormatStatsReport(report StatsReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("📊 CYCLE STATISTICS FOR %d FILES\n\n", report.Files))
+	output.WriteString(fmt.Sprintf("Files with a cycle:    %d\n", report.FilesWithCycle))
+	output.WriteString(fmt.Sprintf("Distinct cycles:       %d\n", report.DistinctCycles))
+	output.WriteString(fmt.Sprintf("Minimal cycles:        %d\n", report.MinimalCycles))
+	output.WriteString(fmt.Sprintf("Average cycle length:  %.1f\n", report.AverageCycleLength))
+	output.WriteString(fmt.Sprintf("Longest cycle:         %d\n", report.LongestCycle))
+	output.WriteString(fmt.Sprintf("Average resources:     %.1f\n", report.AverageResources))
+	if report.MinimalCycles == 0 {
+		return output.String()
+	}
+	
+	for _, section := range []struct {
+		title  string
+		counts []StatsCount
+	}{
+		{"RESOURCE TYPES", report.ResourceTypes},
+		{"MODULES", report.Modules},
+	} {
+		output.WriteString(fmt.Sprintf("\n%-40s  %11s  %5s\n", section.title, "OCCURRENCES", "FILES"))
+		for _, count := range section.counts {
+			output.WriteString(fmt.Sprintf("%-40s  %11d  %5d\n", count.Name, count.Occurrences, count.Files))
+		}
+	}
+	return output.String()
+}
+
+func FormatStatsMarkdown(report StatsReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("# Cycle statistics for %d files\n\n", report.Files))
+	output.WriteString("| Metric | Value |\n|---|---|\n")
+	output.WriteString(fmt.Sprintf("| Files with a cycle | %d |\n", report.FilesWithCycle))
+	output.WriteString(fmt.Sprintf("| Distinct cycles | %d |\n", report.DistinctCycles))
+	output.WriteString(fmt.Sprintf("| Minimal cycles | %d |\n", report.MinimalCycles))
+	output.WriteString(fmt.Sprintf("| Average cycle length | %.1f |\n", report.AverageCycleLength))
+	output.WriteString(fmt.Sprintf("| Longest cycle | %d |\n", report.LongestCycle))
+	output.WriteString(fmt.Sprintf("| Average resources | %.1f |\n", report.AverageResources))
+	if report.MinimalCycles == 0 {
+		return output.String()
+	}
+	
+	for _, section := range []struct {
+		title  string
+		counts []StatsCount
+	}{
+		{"Resource types", report.ResourceTypes},
+		{"Modules", report.Modules},
+	} {
+		output.WriteString(fmt.Sprintf("\n## %s\n\n| Name | Occurrences | Files |\n|---|---|---|\n", section.title))
+		for _, count := range section.counts {
+			output.WriteString(fmt.Sprintf("| `%s` | %d | %d |\n", count.Name, count.Occurrences, count.Files))
+		}
+	}
+	return output.String()
+}
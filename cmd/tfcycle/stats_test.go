@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStats_JSON(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"prod.log":    "Error: Cycle: module.net.aws_security_group.a, module.net.aws_security_group.b\n",
+		"staging.log": "Error: Cycle: module.net.aws_security_group.a, module.net.aws_security_group.b\n",
+		"dev.log":     "Error: Cycle: aws_iam_role.r, aws_iam_policy.p, aws_lambda_function.f\n",
+		"qa.log":      "Plan: 1 to add, 0 to change, 0 to destroy.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	
+	output := filepath.Join(dir, "stats.json")
+	args := []string{"stats", "--json", "--output", output}
+	for _, name := range []string{"dev", "prod", "staging", "qa"} {
+		args = append(args, filepath.Join(dir, name+".log"))
+	}
+	config, err := parseArgs(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report StatsReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	
+	if report.Files != 4 || report.FilesWithCycle != 3 || report.DistinctCycles != 2 {
+		t.Errorf("Expected 3 of 4 files with 2 distinct cycles, got %+v", report)
+	}
+	if report.MinimalCycles != 3 || report.LongestCycle != 3 {
+		t.Errorf("Expected 3 minimal cycles of up to 3 resources, got %+v", report)
+	}
+	if top := report.ResourceTypes[0]; top.Name != "aws_security_group" || top.Occurrences != 4 || top.Files != 2 {
+		t.Errorf("Expected aws_security_group first, got %+v", top)
+	}
+	if top := report.Modules[0]; top.Name != "module.net" || top.Files != 2 {
+		t.Errorf("Expected module.net first, got %+v", top)
+	}
+}
+
+func TestStats_NoInput(t *testing.T) {
+	config, err := parseArgs([]string{"stats"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); exitCode(err) != ExitInputError {
+		t.Errorf("Expected an input error, got: %v", err)
+	}
+}
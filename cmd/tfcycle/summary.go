@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+// summaryOutput is where analyze, run and lint print their summary line.
+var summaryOutput io.Writer = os.Stderr
+
+// FormatSummary returns the one-line summary of cycles with the given
+// severities, the longest of them longest nodes long, e.g.
+// "tfcycle: 2 cycles (1 high, 1 low), longest=12 nodes". Its fixed shape
+// lets CI log searches find results whatever the output format.
+func FormatSummary(severities []tfcycle.Severity, longest int) string {
+	if len(severities) == 0 {
+		return "tfcycle: 0 cycles"
+	}
+	
+	counts := make(map[tfcycle.Severity]int)
+	for _, severity := range severities {
+		counts[severity]++
+	}
+	var parts []string
+	for _, severity := range []tfcycle.Severity{tfcycle.SeverityHigh, tfcycle.SeverityMedium, tfcycle.SeverityLow, tfcycle.SeverityNone} {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	
+	noun := "cycles"
+	if len(severities) == 1 {
+		noun = "cycle"
+	}
+	return fmt.Sprintf("tfcycle: %d %s (%s), longest=%d nodes", len(severities), noun, strings.Join(parts, ", "), longest)
+}
+
+// analyzerSummary summarizes the minimal cycles of analyzer, or no cycles if
+// analyzer is nil.
+func analyzerSummary(analyzer *tfcycle.CycleAnalyzer) string {
+	if analyzer == nil {
+		return FormatSummary(nil, 0)
+	}
+	var severities []tfcycle.Severity
+	longest := 0
+	for _, cycle := range analyzer.FindMinimalCycles() {
+		severities = append(severities, analyzer.CycleSeverity(cycle))
+		longest = max(longest, len(cycle))
+	}
+	return FormatSummary(severities, longest)
+}
+
+// lintSummary summarizes the findings of report; near cycles are warnings
+// and not counted.
+func lintSummary(report tfcycle.LintReport) string {
+	var severities []tfcycle.Severity
+	longest := 0
+	for _, finding := range report.Findings {
+		severities = append(severities, finding.Severity)
+		longest = max(longest, len(finding.Cycle))
+	}
+	return FormatSummary(severities, longest)
+}
+
+func printSummary(summary string) {
+	fmt.Fprintln(summaryOutput, summary)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func TestFormatSummary(t *testing.T) {
+	tests := []struct {
+		severities []tfcycle.Severity
+		longest    int
+		want       string
+	}{
+		{nil, 0, "tfcycle: 0 cycles"},
+		{[]tfcycle.Severity{tfcycle.SeverityLow}, 2, "tfcycle: 1 cycle (1 low), longest=2 nodes"},
+		{[]tfcycle.Severity{tfcycle.SeverityLow, tfcycle.SeverityHigh}, 12, "tfcycle: 2 cycles (1 high, 1 low), longest=12 nodes"},
+	}
+	for _, test := range tests {
+		if got := FormatSummary(test.severities, test.longest); got != test.want {
+			t.Errorf("Expected %q, got %q", test.want, got)
+		}
+	}
+}
+
+func TestSummaryLine(t *testing.T) {
+	var summary bytes.Buffer
+	summaryOutput = &summary
+	t.Cleanup(func() { summaryOutput = os.Stderr })
+	
+	dir := t.TempDir()
+	input := filepath.Join(dir, "cycle.txt")
+	if err := os.WriteFile(input, []byte("Error: Cycle: aws_security_group.a, aws_security_group.b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := parseArgs([]string{"analyze", input, "--json", "--output", filepath.Join(dir, "report.json"), "--no-history", "--fail-on", "none"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := summary.String(); got != "tfcycle: 1 cycle (1 low), longest=2 nodes\n" {
+		t.Errorf("Expected the summary line with JSON output to a file, got %q", got)
+	}
+}
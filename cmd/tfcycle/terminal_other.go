@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import "os"
+
+// isTerminal reports whether file is a terminal.
+func isTerminal(file *os.File) bool {
+	stat, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,42 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"regexp"
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// ptyPipeRegex matches the named pipes that MSYS2 and Cygwin terminals such
+// as mintty (Git Bash) connect to a program instead of a console.
+var ptyPipeRegex = regexp.MustCompile(`\\(?:cygwin|msys)-[0-9a-f]+-pty[0-9]+-(?:from|to)-master`)
+
+// isTerminal reports whether file is a console, including a ConPTY session,
+// or the pty pipe of an MSYS2 or Cygwin terminal. The NUL device is a
+// character device too, but not a terminal.
+func isTerminal(file *os.File) bool {
+	handle := windows.Handle(file.Fd())
+	var mode uint32
+	if windows.GetConsoleMode(handle, &mode) == nil {
+		return true
+	}
+	if fileType, err := windows.GetFileType(handle); err != nil || fileType != windows.FILE_TYPE_PIPE {
+		return false
+	}
+	
+	// FILE_NAME_INFO: a uint32 length in bytes followed by the UTF-16 name.
+	var info [4 + windows.MAX_PATH*2]byte
+	if err := windows.GetFileInformationByHandleEx(handle, windows.FileNameInfo, &info[0], uint32(len(info))); err != nil {
+		return false
+	}
+	length := *(*uint32)(unsafe.Pointer(&info[0])) / 2
+	if length > windows.MAX_PATH {
+		return false
+	}
+	name := unsafe.Slice((*uint16)(unsafe.Pointer(&info[4])), length)
+	return ptyPipeRegex.MatchString(string(utf16.Decode(name)))
+}
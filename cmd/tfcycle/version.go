@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Release builds set these with
+// -ldflags "-X main.commit=$(git rev-parse HEAD) -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)".
+// Otherwise they are read from the VCS stamp the Go toolchain embeds.
+var (
+	commit    = ""
+	buildDate = ""
+)
+
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	Modified  bool   `json:"modified"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+func currentBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	return info
+}
+
+func FormatBuildInfo(info BuildInfo) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("tfcycle version %s\n", info.Version))
+	commitLine := info.Commit
+	if info.Modified {
+		commitLine += " (modified)"
+	}
+	output.WriteString(fmt.Sprintf("  commit:     %s\n", commitLine))
+	output.WriteString(fmt.Sprintf("  built:      %s\n", info.BuildDate))
+	output.WriteString(fmt.Sprintf("  go version: %s %s\n", info.GoVersion, info.Platform))
+	return output.String()
+}
+
+func runVersion(config Config) error {
+	info := currentBuildInfo()
+	if outputFormat(config) != "json" {
+		return writeOutput(FormatBuildInfo(info), config.Output)
+	}
+	
+	jsonData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format as JSON: %w", err)
+	}
+	return writeOutput(string(jsonData)+"\n", config.Output)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestVersion_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "version.json")
+	config, err := parseArgs([]string{"--version", "--json", "--output", path})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !config.ShowVersion {
+		t.Fatal("Expected --version to be accepted as a flag")
+	}
+	if err := runVersion(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info BuildInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if info.Version != version || info.GoVersion != runtime.Version() || info.Commit == "" || info.BuildDate == "" {
+		t.Errorf("Expected complete build information, got %+v", info)
+	}
+}
+
+func TestVersion_LinkerFlags(t *testing.T) {
+	oldCommit, oldDate := commit, buildDate
+	commit, buildDate = "abc1234", "2026-01-02T03:04:05Z"
+	defer func() { commit, buildDate = oldCommit, oldDate }()
+	
+	output := FormatBuildInfo(currentBuildInfo())
+	if !strings.HasPrefix(output, "tfcycle version "+version+"\n") || !strings.Contains(output, "abc1234") || !strings.Contains(output, "2026-01-02T03:04:05Z") {
+		t.Errorf("Expected version, commit and build date, got:\n%s", output)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+const watchPollInterval = time.Second
+
+type Watcher struct {
+	Dir      string
+	Interval time.Duration
+	Command  []string
+	Out      io.Writer
+	Clear    bool
+	Render   func(cycle *tfcycle.TfCycle) string
+}
+
+func NewWatcher(dir string, interval time.Duration, out io.Writer) *Watcher {
+	return &Watcher{
+		Dir:      dir,
+		Interval: interval,
+		Command:  []string{"terraform", "plan", "-no-color", "-input=false", "-lock=false"},
+		Out:      out,
+	}
+}
+
+func (w *Watcher) Run(ctx context.Context) error {
+	if info, err := os.Stat(w.Dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("watch directory %s does not exist", w.Dir)
+	}
+	
+	snapshot := configSnapshot(w.Dir)
+	lastRun := time.Now()
+	w.runOnce(ctx)
+	
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		
+		current := configSnapshot(w.Dir)
+		changed := !snapshotsEqual(snapshot, current)
+		due := w.Interval > 0 && time.Since(lastRun) >= w.Interval
+		if !changed && !due {
+			continue
+		}
+		
+		snapshot = current
+		lastRun = time.Now()
+		w.runOnce(ctx)
+	}
+}
+
+func (w *Watcher) runOnce(ctx context.Context) {
+	cmd := exec.CommandContext(ctx, w.Command[0], w.Command[1:]...)
+	cmd.Dir = w.Dir
+	
+	// Stream the command's output into the parser instead of waiting for it
+	// to exit, keeping a copy for the failure message.
+	var output bytes.Buffer
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	exited := make(chan error, 1)
+	if err := cmd.Start(); err != nil {
+		writer.Close()
+		exited <- err
+	} else {
+		go func() {
+			err := cmd.Wait()
+			writer.Close()
+			exited <- err
+		}()
+	}
+	
+	cycle, parseErr := tfcycle.Parse(ctx, io.TeeReader(reader, &output))
+	if ctx.Err() != nil {
+		reader.Close()
+		return
+	}
+	io.Copy(io.Discard, reader)
+	err := <-exited
+	
+	if w.Clear {
+		fmt.Fprint(w.Out, "\033[H\033[2J")
+	}
+	fmt.Fprintf(w.Out, "[%s] %s in %s\n\n", time.Now().Format("15:04:05"), strings.Join(w.Command, " "), w.Dir)
+	
+	text := output.String()
+	if errors.Is(parseErr, tfcycle.ErrNoCycleFound) || errors.Is(parseErr, tfcycle.ErrEmptyInput) {
+		if err != nil {
+			fmt.Fprintf(w.Out, "❌ %s failed without a cycle error: %v\n\n%s\n", w.Command[0], err, strings.TrimSpace(text))
+		} else {
+			fmt.Fprintln(w.Out, "✅ No dependency cycle detected")
+		}
+		fmt.Fprintln(w.Out, "\nWatching for changes... (Ctrl+C to stop)")
+		return
+	}
+	
+	if parseErr != nil {
+		fmt.Fprintf(w.Out, "❌ failed to parse cycle error: %v\n", parseErr)
+		return
+	}
+	fmt.Fprint(w.Out, w.Render(cycle))
+	fmt.Fprintln(w.Out, "Watching for changes... (Ctrl+C to stop)")
+}
+
+func configSnapshot(dir string) map[string]time.Time {
+	snapshot := make(map[string]time.Time)
+	filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if path != dir && (entry.Name() == ".terraform" || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".tf") || strings.HasSuffix(path, ".tfvars") || strings.HasSuffix(path, ".tf.json") {
+			if info, err := entry.Info(); err == nil {
+				snapshot[path] = info.ModTime()
+			}
+		}
+		return nil
+	})
+	return snapshot
+}
+
+func snapshotsEqual(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, modTime := range a {
+		if other, ok := b[path]; !ok || !other.Equal(modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+func watchContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+func TestConfigSnapshot_DetectsChanges(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`resource "aws_vpc" "main" {}`), 0644)
+	os.MkdirAll(filepath.Join(dir, ".terraform"), 0755)
+	os.WriteFile(filepath.Join(dir, ".terraform", "ignored.tf"), []byte(""), 0644)
+	
+	before := configSnapshot(dir)
+	if len(before) != 1 {
+		t.Fatalf("Expected 1 watched file, got %d", len(before))
+	}
+	if !snapshotsEqual(before, configSnapshot(dir)) {
+		t.Errorf("Expected unchanged snapshots to be equal")
+	}
+	
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(filepath.Join(dir, "main.tf"), later, later)
+	if snapshotsEqual(before, configSnapshot(dir)) {
+		t.Errorf("Expected modified file to change the snapshot")
+	}
+	
+	os.WriteFile(filepath.Join(dir, "vars.tfvars"), []byte(""), 0644)
+	if len(configSnapshot(dir)) != 2 {
+		t.Errorf("Expected new .tfvars file to be watched")
+	}
+}
+
+func TestWatcher_RunOnce(t *testing.T) {
+	dir := t.TempDir()
+	var output strings.Builder
+	
+	watcher := NewWatcher(dir, 0, &output)
+	watcher.Command = []string{"sh", "-c", "echo 'Error: Cycle: aws_security_group.a, aws_security_group.b'; exit 1"}
+	watcher.Render = func(cycle *tfcycle.TfCycle) string {
+		return "rendered " + cycle.Nodes[0].FullName() + "\n"
+	}
+	
+	watcher.runOnce(context.Background())
+	if !strings.Contains(output.String(), "rendered aws_security_group.a") {
+		t.Errorf("Expected rendered analysis, got:\n%s", output.String())
+	}
+	
+	output.Reset()
+	watcher.Command = []string{"sh", "-c", "echo 'No changes.'"}
+	watcher.runOnce(context.Background())
+	if !strings.Contains(output.String(), "No dependency cycle detected") {
+		t.Errorf("Expected no-cycle notice, got:\n%s", output.String())
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"tfcycle/pkg/tfcycle"
+)
+
+// WebhookPayload is what --payload-template renders: the history entry of
+// the analysis and its summary line (see FormatSummary). Without a template
+// it is posted as JSON.
+//
+// The webhook URL and template are taken only from flags and the
+// environment, not from a configuration file, so that a checked-in
+// .tfcycle.yaml cannot post the analysis to a host of its choosing.
+type WebhookPayload struct {
+	HistoryEntry
+	Summary string `json:"summary"`
+}
+
+var webhookTemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+type WebhookNotifier struct {
+	URL      string
+	Template *template.Template
+	client   *http.Client
+}
+
+func NewWebhookNotifier(config Config) (*WebhookNotifier, error) {
+	notifier := &WebhookNotifier{
+		URL:    config.NotifyWebhook,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+	if config.PayloadTemplate != "" {
+		text, err := os.ReadFile(config.PayloadTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payload template: %w", err)
+		}
+		notifier.Template, err = template.New("payload").Funcs(webhookTemplateFuncs).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", config.PayloadTemplate, err)
+		}
+	}
+	return notifier, nil
+}
+
+// Render returns the JSON body for payload. A template that does not render
+// valid JSON is an error, so that a typo is not posted to the webhook.
+func (wn *WebhookNotifier) Render(payload WebhookPayload) ([]byte, error) {
+	if wn.Template == nil {
+		return json.Marshal(payload)
+	}
+	var body bytes.Buffer
+	if err := wn.Template.Execute(&body, payload); err != nil {
+		return nil, err
+	}
+	if !json.Valid(body.Bytes()) {
+		return nil, fmt.Errorf("payload template did not render valid JSON: %s", strings.TrimSpace(body.String()))
+	}
+	return body.Bytes(), nil
+}
+
+func (wn *WebhookNotifier) Send(ctx context.Context, payload WebhookPayload) error {
+	body, err := wn.Render(payload)
+	if err != nil {
+		return err
+	}
+	
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "tfcycle/"+version)
+	
+	response, err := wn.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	
+	if response.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("webhook returned %s: %s", response.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func notifyWebhook(analyzer *tfcycle.CycleAnalyzer, entry HistoryEntry, config Config) {
+	if config.NotifyWebhook == "" {
+		return
+	}
+	
+	notifier, err := NewWebhookNotifier(config)
+	if err != nil {
+		logger.Warn("skipping webhook notification", "error", err)
+		return
+	}
+	
+	stop := showProgress(config, "Sending webhook notification")
+	err = notifier.Send(context.Background(), WebhookPayload{HistoryEntry: entry, Summary: analyzerSummary(analyzer)})
+	stop()
+	if err != nil {
+		logger.Warn("failed to send webhook notification", "error", err)
+		return
+	}
+	logger.Debug("sent webhook notification", "fingerprint", entry.Fingerprint)
+}
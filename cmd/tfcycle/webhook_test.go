@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWebhookNotifier_Render(t *testing.T) {
+	payload := WebhookPayload{
+		HistoryEntry: HistoryEntry{
+			Fingerprint: "0123456789ab",
+			Dir:         "/srv/infra/prod",
+			Severity:    "high",
+			Nodes:       []string{"aws_instance.web", "aws_security_group.web"},
+		},
+		Summary: "tfcycle: 1 cycle (1 high), longest=2 nodes",
+	}
+	
+	notifier, err := NewWebhookNotifier(Config{NotifyWebhook: "http://example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	body, err := notifier.Render(payload)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded["fingerprint"] != "0123456789ab" || decoded["summary"] != payload.Summary {
+		t.Errorf("Expected the entry and summary as JSON by default, got %s (%v)", body, err)
+	}
+	
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "slack.tmpl")
+	if err := os.WriteFile(tmpl, []byte(`{"text": {{printf "%s: %s" .Summary (join .Nodes ", ") | json}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	notifier, err = NewWebhookNotifier(Config{NotifyWebhook: "http://example.com", PayloadTemplate: tmpl})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	body, err = notifier.Render(payload)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	expected := `{"text": "tfcycle: 1 cycle (1 high), longest=2 nodes: aws_instance.web, aws_security_group.web"}`
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+	
+	if err := os.WriteFile(tmpl, []byte(`{"text": {{env "HOME" | json}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewWebhookNotifier(Config{NotifyWebhook: "http://example.com", PayloadTemplate: tmpl}); err == nil {
+		t.Error("Expected payload templates not to read the environment")
+	}
+	
+	if err := os.WriteFile(tmpl, []byte(`{"text": {{.Summary}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	notifier, _ = NewWebhookNotifier(Config{NotifyWebhook: "http://example.com", PayloadTemplate: tmpl})
+	if _, err := notifier.Render(payload); err == nil || !strings.Contains(err.Error(), "valid JSON") {
+		t.Errorf("Expected an error for an invalid JSON payload, got %v", err)
+	}
+}
+
+func TestNotifyWebhook(t *testing.T) {
+	var body []byte
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	
+	dir := t.TempDir()
+	input := filepath.Join(dir, "cycle.txt")
+	if err := os.WriteFile(input, []byte("Error: Cycle: aws_security_group.a, aws_security_group.b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := parseArgs([]string{"analyze", input, "--output", filepath.Join(dir, "report.txt"), "--no-history", "--quiet", "--fail-on", "none", "--notify-webhook", server.URL})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := runCommand(config); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	var payload WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Expected a JSON payload, got %s (%v)", body, err)
+	}
+	if contentType != "application/json" || payload.Fingerprint != CycleFingerprint([]string{"aws_security_group.a", "aws_security_group.b"}) {
+		t.Errorf("Expected the cycle fingerprint as JSON, got %q and %+v", contentType, payload)
+	}
+	if payload.Summary != "tfcycle: 1 cycle (1 low), longest=2 nodes" {
+		t.Errorf("Expected the summary line, got %q", payload.Summary)
+	}
+}
@@ -0,0 +1,387 @@
+package tfcycle
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CycleAnalyzer hypothesizes the dependencies between the nodes of a TfCycle
+// and finds the minimal cycles among them. Terraform only reports the members
+// of a cycle, so every edge is inferred from resource types and module paths.
+type CycleAnalyzer struct {
+	cycle           *TfCycle
+	minimal         [][]string
+	graph           map[string][]string
+	heuristics      []Heuristic
+	edgeSources     []EdgeSource
+	suggestionRules []SuggestionRule
+	rules           ruleSelection
+	suppressed      []string
+	noSuggestions   bool
+	verdicts        EdgeVerdicts
+	minConfidence   float64
+	maxCycles       int
+	Partial         bool
+}
+
+// NewCycleAnalyzer returns an analyzer for cycle configured by opts. The
+// analysis runs lazily on the first call that needs it and records the
+// minimal cycles in cycle.Cycles.
+func NewCycleAnalyzer(cycle *TfCycle, opts ...Option) *CycleAnalyzer {
+	ca := &CycleAnalyzer{cycle: cycle}
+	for _, opt := range opts {
+		opt(ca)
+	}
+	return ca
+}
+
+// Cycle returns the analyzed cycle.
+func (ca *CycleAnalyzer) Cycle() *TfCycle {
+	return ca.cycle
+}
+
+// FindMinimalCycles returns the minimal cycles, shortest first. Each cycle
+// lists node addresses in dependency order without repeating the first node.
+func (ca *CycleAnalyzer) FindMinimalCycles() [][]string {
+	if ca.minimal != nil {
+		return ca.minimal
+	}
+	
+	ca.setMinimalCycles(ca.computeMinimalCycles())
+	return ca.minimal
+}
+
+// AnalyzeContext finds the minimal cycles unless ctx is done first. On
+// cancellation it falls back to a single cycle of all nodes, sets Partial and
+// returns ctx.Err().
+func (ca *CycleAnalyzer) AnalyzeContext(ctx context.Context) error {
+	if ca.minimal != nil || ctx.Done() == nil {
+		ca.FindMinimalCycles()
+		return nil
+	}
+	
+	if err := ctx.Err(); err != nil {
+		ca.Partial = true
+		ca.setMinimalCycles([][]string{ca.NodeNames()})
+		return err
+	}
+	
+	result := make(chan [][]string, 1)
+	go func() {
+		result <- ca.computeMinimalCycles()
+	}()
+	
+	select {
+	case cycles := <-result:
+		ca.setMinimalCycles(cycles)
+		return nil
+	case <-ctx.Done():
+		ca.Partial = true
+		ca.setMinimalCycles([][]string{ca.NodeNames()})
+		return ctx.Err()
+	}
+}
+
+// RemoveCycles drops the minimal cycles for which accepted returns true, such
+// as those recorded in a baseline, so reports, severities and fixes cover the
+// rest only. It returns how many it dropped.
+func (ca *CycleAnalyzer) RemoveCycles(accepted func(cycle []string) bool) int {
+	kept := [][]string{}
+	for _, cycle := range ca.FindMinimalCycles() {
+		if !accepted(cycle) {
+			kept = append(kept, cycle)
+		}
+	}
+	removed := len(ca.minimal) - len(kept)
+	ca.setMinimalCycles(kept)
+	return removed
+}
+
+func (ca *CycleAnalyzer) setMinimalCycles(cycles [][]string) {
+	ca.minimal = cycles
+	ca.cycle.Cycles = cycles
+}
+
+func (ca *CycleAnalyzer) computeMinimalCycles() [][]string {
+	nodeNames := ca.NodeNames()
+	graph := ca.buildHypotheticalGraph(nodeNames)
+	
+	cycles := ca.findCyclesInGraph(graph, nodeNames)
+	logger.Debug("minimal cycles found", "count", len(cycles))
+	
+	sort.Slice(cycles, func(i, j int) bool {
+		return len(cycles[i]) < len(cycles[j])
+	})
+	
+	if ca.maxCycles > 0 && len(cycles) > ca.maxCycles {
+		cycles = cycles[:ca.maxCycles]
+	}
+	
+	return cycles
+}
+
+// SetGraph replaces the hypothesized dependency graph, for example with edges
+// a user has confirmed, and discards previously computed cycles.
+func (ca *CycleAnalyzer) SetGraph(graph map[string][]string) {
+	ca.graph = graph
+	ca.minimal = nil
+	ca.Partial = false
+}
+
+// HypothesizedGraph returns the inferred dependency graph as adjacency lists
+// keyed by node address.
+func (ca *CycleAnalyzer) HypothesizedGraph() map[string][]string {
+	if ca.Partial {
+		return ca.buildSequentialFallback(ca.NodeNames())
+	}
+	return ca.buildHypotheticalGraph(ca.NodeNames())
+}
+
+// NodeNames returns the addresses of all nodes in the order Terraform listed
+// them.
+func (ca *CycleAnalyzer) NodeNames() []string {
+	nodeNames := make([]string, len(ca.cycle.Nodes))
+	for i, node := range ca.cycle.Nodes {
+		nodeNames[i] = node.FullName()
+	}
+	return nodeNames
+}
+
+func (ca *CycleAnalyzer) buildHypotheticalGraph(nodeNames []string) map[string][]string {
+	if ca.graph != nil {
+		return ca.graph
+	}
+	if ca.cycle.Terragrunt() {
+		// Terragrunt lists the dependency chain itself.
+		return ca.buildSequentialFallback(nodeNames)
+	}
+	
+	graph := make(map[string][]string)
+	
+	for _, name := range nodeNames {
+		graph[name] = []string{}
+	}
+	
+	resourceTypes := make(map[string][]*CycleNode)
+	for _, node := range ca.cycle.Nodes {
+		resourceTypes[node.ResourceType] = append(resourceTypes[node.ResourceType], node)
+	}
+	
+	for i, nodeA := range ca.cycle.Nodes {
+		for j, nodeB := range ca.cycle.Nodes {
+			if i == j {
+				continue
+			}
+			
+			if reason := ca.dependencyReason(nodeA, nodeB); reason != "" {
+				logger.Debug("heuristic matched", "from", nodeA.FullName(), "to", nodeB.FullName(), "reason", reason)
+				graph[nodeA.FullName()] = append(graph[nodeA.FullName()], nodeB.FullName())
+			}
+		}
+	}
+	ca.addSourceEdges(graph)
+	ca.applyVerdicts(graph)
+	
+	if ca.allNodesHaveConnections(graph) {
+		logger.Debug("hypothesized graph built", "nodes", len(graph), "edges", countEdges(graph))
+		return graph
+	}
+	
+	logger.Debug("heuristics left nodes without edges, using sequential fallback", "nodes", len(nodeNames))
+	fallback := ca.buildSequentialFallback(nodeNames)
+	ca.addSourceEdges(fallback)
+	ca.applyVerdicts(fallback)
+	return fallback
+}
+
+// addSourceEdges adds the edges of the configured edge sources to graph,
+// skipping duplicates and addresses that are not nodes of the cycle.
+func (ca *CycleAnalyzer) addSourceEdges(graph map[string][]string) {
+	for _, source := range ca.edgeSources {
+		for from, targets := range source.Edges(ca.cycle) {
+			if _, ok := graph[from]; !ok {
+				continue
+			}
+			for _, to := range targets {
+				if _, ok := graph[to]; !ok || from == to || slices.Contains(graph[from], to) {
+					continue
+				}
+				graph[from] = append(graph[from], to)
+			}
+		}
+	}
+}
+
+func (ca *CycleAnalyzer) likelyDependency(from, to *CycleNode) bool {
+	return ca.dependencyReason(from, to) != ""
+}
+
+func (ca *CycleAnalyzer) dependencyReason(from, to *CycleNode) string {
+	_, reason := ca.dependencyHeuristic(from, to)
+	return reason
+}
+
+// dependencyHeuristic returns the rule ID and reason of the first heuristic
+// that explains why from depends on to, or "" for both.
+func (ca *CycleAnalyzer) dependencyHeuristic(from, to *CycleNode) (string, string) {
+	heuristics := ca.heuristics
+	if heuristics == nil {
+		heuristics = builtinHeuristics
+	}
+	for _, h := range heuristics {
+		if h.Confidence < ca.minConfidence || !ca.rules.allows(h.RuleID) {
+			continue
+		}
+		if reason := h.Reason(from, to); reason != "" {
+			return h.RuleID, reason
+		}
+	}
+	return "", ""
+}
+
+func countEdges(graph map[string][]string) int {
+	edges := 0
+	for _, targets := range graph {
+		edges += len(targets)
+	}
+	return edges
+}
+
+func commonModulePrefix(pathA, pathB []string) []string {
+	var prefix []string
+	for i := 0; i < len(pathA) && i < len(pathB) && pathA[i] == pathB[i]; i++ {
+		prefix = append(prefix, pathA[i])
+	}
+	return prefix
+}
+
+func shareModulePath(pathA, pathB []string) bool {
+	minLen := len(pathA)
+	if len(pathB) < minLen {
+		minLen = len(pathB)
+	}
+	
+	if minLen == 0 {
+		return false
+	}
+	
+	for i := 0; i < minLen; i++ {
+		if pathA[i] != pathB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (ca *CycleAnalyzer) allNodesHaveConnections(graph map[string][]string) bool {
+	for _, connections := range graph {
+		if len(connections) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (ca *CycleAnalyzer) buildSequentialFallback(nodeNames []string) map[string][]string {
+	graph := make(map[string][]string)
+	
+	for i, name := range nodeNames {
+		nextIndex := (i + 1) % len(nodeNames)
+		graph[name] = []string{nodeNames[nextIndex]}
+	}
+	
+	return graph
+}
+
+func (ca *CycleAnalyzer) findCyclesInGraph(graph map[string][]string, nodeNames []string) [][]string {
+	visited := make(map[string]bool)
+	recStack := make(map[string]bool)
+	var cycles [][]string
+	
+	var dfs func(node string, path []string) bool
+	dfs = func(node string, path []string) bool {
+		visited[node] = true
+		recStack[node] = true
+		path = append(path, node)
+		
+		for _, neighbor := range graph[node] {
+			if !visited[neighbor] {
+				if dfs(neighbor, path) {
+					return true
+				}
+			} else if recStack[neighbor] {
+				cycleStart := -1
+				for i, pathNode := range path {
+					if pathNode == neighbor {
+						cycleStart = i
+						break
+					}
+				}
+				if cycleStart != -1 {
+					cycle := make([]string, len(path)-cycleStart)
+					copy(cycle, path[cycleStart:])
+					cycles = append(cycles, cycle)
+				}
+				return true
+			}
+		}
+		
+		recStack[node] = false
+		return false
+	}
+	
+	for _, node := range nodeNames {
+		if !visited[node] {
+			dfs(node, []string{})
+		}
+	}
+	
+	if len(cycles) == 0 {
+		cycles = append(cycles, nodeNames)
+	}
+	
+	return ca.deduplicateCycles(cycles)
+}
+
+func (ca *CycleAnalyzer) deduplicateCycles(cycles [][]string) [][]string {
+	seen := make(map[string]bool)
+	var unique [][]string
+	
+	for _, cycle := range cycles {
+		if len(cycle) < 2 {
+			continue
+		}
+		
+		normalized := ca.normalizeCycle(cycle)
+		key := strings.Join(normalized, ",")
+		
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, cycle)
+		}
+	}
+	
+	return unique
+}
+
+func (ca *CycleAnalyzer) normalizeCycle(cycle []string) []string {
+	if len(cycle) == 0 {
+		return cycle
+	}
+	
+	minIndex := 0
+	for i, node := range cycle {
+		if node < cycle[minIndex] {
+			minIndex = i
+		}
+	}
+	
+	normalized := make([]string, len(cycle))
+	for i := 0; i < len(cycle); i++ {
+		normalized[i] = cycle[(minIndex+i)%len(cycle)]
+	}
+	
+	return normalized
+}
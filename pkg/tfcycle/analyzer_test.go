@@ -1,6 +1,7 @@
-package main
+package tfcycle
 
 import (
+	"context"
 	"reflect"
 	"testing"
 )
@@ -26,6 +27,32 @@ func TestCycleAnalyzer_FindMinimalCycles(t *testing.T) {
 	}
 }
 
+func TestCycleAnalyzer_RemoveCycles(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_security_group.a, aws_security_group.b, aws_instance.web")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	analyzer := NewCycleAnalyzer(cycle)
+	total := len(analyzer.FindMinimalCycles())
+	removed := analyzer.RemoveCycles(func(cycle []string) bool {
+		return len(cycle) == 2
+	})
+	if removed == 0 || len(analyzer.FindMinimalCycles()) != total-removed {
+		t.Fatalf("Expected the two-node cycles removed from %d, removed %d", total, removed)
+	}
+	for _, remaining := range analyzer.FindMinimalCycles() {
+		if len(remaining) == 2 {
+			t.Errorf("Expected no two-node cycle left, got %v", remaining)
+		}
+	}
+	
+	analyzer.RemoveCycles(func([]string) bool { return true })
+	if analyzer.Severity() != SeverityNone {
+		t.Errorf("Expected no severity without cycles, got %s", analyzer.Severity())
+	}
+}
+
 func TestCycleAnalyzer_LikelyDependency(t *testing.T) {
 	analyzer := &CycleAnalyzer{}
 	
@@ -58,7 +85,7 @@ func TestCycleAnalyzer_GenerateSuggestions_SecurityGroups(t *testing.T) {
 	
 	found := false
 	for _, suggestion := range suggestions {
-		if contains(suggestion, "security group") || contains(suggestion, "Security group") {
+		if contains(suggestion.Title, "security group") || contains(suggestion.Title, "Security group") {
 			found = true
 			break
 		}
@@ -85,7 +112,7 @@ func TestCycleAnalyzer_GenerateSuggestions_IAM(t *testing.T) {
 	
 	found := false
 	for _, suggestion := range suggestions {
-		if contains(suggestion, "IAM") {
+		if contains(suggestion.Title, "IAM") {
 			found = true
 			break
 		}
@@ -112,7 +139,7 @@ func TestCycleAnalyzer_GenerateSuggestions_DestroyAction(t *testing.T) {
 	
 	found := false
 	for _, suggestion := range suggestions {
-		if contains(suggestion, "create_before_destroy") {
+		if contains(suggestion.Title, "create_before_destroy") {
 			found = true
 			break
 		}
@@ -124,17 +151,15 @@ func TestCycleAnalyzer_GenerateSuggestions_DestroyAction(t *testing.T) {
 }
 
 func TestCycleAnalyzer_ShareModulePath(t *testing.T) {
-	analyzer := &CycleAnalyzer{}
-	
 	pathA := []string{"module", "vpc", "module", "security"}
 	pathB := []string{"module", "vpc"}
 	pathC := []string{"module", "app"}
 	
-	if !analyzer.shareModulePath(pathA, pathB) {
+	if !shareModulePath(pathA, pathB) {
 		t.Errorf("Paths sharing 'module.vpc' should return true")
 	}
 	
-	if analyzer.shareModulePath(pathA, pathC) {
+	if shareModulePath(pathA, pathC) {
 		t.Errorf("Paths not sharing prefix should return false")
 	}
 }
@@ -215,4 +240,24 @@ func findSubstring(s, substr string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+func TestAnalyzeContext_Expired(t *testing.T) {
+	cycle, _ := NewParser().ParseError(`Error: Cycle: aws_security_group.a, aws_security_group.b, aws_instance.c`)
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	
+	if err := analyzer.AnalyzeContext(ctx); err == nil {
+		t.Errorf("Expected error for expired context")
+	}
+	if !analyzer.Partial {
+		t.Errorf("Expected partial analysis")
+	}
+	
+	cycles := analyzer.FindMinimalCycles()
+	if len(cycles) != 1 || len(cycles[0]) != 3 {
+		t.Errorf("Expected the full reported cycle as partial result, got %v", cycles)
+	}
+}
@@ -0,0 +1,94 @@
+package tfcycle
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+var cycleStartRegex = regexp.MustCompile(`Error:\s*Cycle:|form a circular dependency chain through their references|Found a dependency cycle between (?:modules|units)`)
+
+// Parse reads Terraform output or a JSON analysis report from r and parses
+// the first cycle error. Only the cycle diagnostic is retained, as collected
+// by CycleSection, so plan logs of any size can be streamed through; the
+// returned RawError is that diagnostic, and Context holds the
+// DefaultContextLines lines before it. Parse returns once the diagnostic
+// ends, leaving the rest of r unread. Parse stops waiting on r and
+// returns ctx.Err() when ctx is done before the cycle has been read. Input
+// is decoded by InputReader. Errors are those of ParseError.
+func Parse(ctx context.Context, r io.Reader) (*TfCycle, error) {
+	type result struct {
+		cycle *TfCycle
+		err   error
+	}
+	
+	done := make(chan result, 1)
+	go func() {
+		cycle, err := parseReader(ctx, r)
+		done <- result{cycle, err}
+	}()
+	
+	select {
+	case res := <-done:
+		return res.cycle, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func parseReader(ctx context.Context, r io.Reader) (*TfCycle, error) {
+	reader := bufio.NewReader(InputReader(r))
+	section := CycleSection{ContextLines: DefaultContextLines}
+	
+	for !section.Done() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			section.AddLine(line)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+	}
+	
+	switch {
+	case section.JSON(), section.Graph():
+		return ParseInput(section.String())
+	case section.Found():
+		cycle, err := NewParser().ParseError(section.String())
+		if err == nil && cycle.Versions == nil {
+			cycle.Versions = section.Versions()
+		}
+		if err == nil && cycle.Context == nil {
+			if cycle.Context = parseContext(section.Context(), DefaultContextLines); cycle.Context != nil {
+				applyContext(cycle, cycle.Context)
+			}
+		}
+		return cycle, err
+	case section.Empty():
+		return nil, ErrEmptyInput
+	default:
+		return nil, ErrNoCycleFound
+	}
+}
+
+// Analyze finds the minimal cycles of cycle. If ctx is done first, the
+// returned analyzer is marked Partial and reports the cycle as listed by
+// Terraform, and the error is ctx.Err().
+func Analyze(ctx context.Context, cycle *TfCycle, opts ...Option) (*CycleAnalyzer, error) {
+	if cycle == nil || len(cycle.Nodes) == 0 {
+		return nil, ErrNoCycleFound
+	}
+	
+	analyzer := NewCycleAnalyzer(cycle, opts...)
+	return analyzer, analyzer.AnalyzeContext(ctx)
+}
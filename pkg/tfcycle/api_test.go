@@ -0,0 +1,93 @@
+package tfcycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse_StreamsPastPlanOutput(t *testing.T) {
+	planOutput := strings.Repeat("aws_instance.web: Refreshing state... [id=i-0123456789]\n", 50000)
+	input := planOutput + "\n│ Error: Cycle: aws_security_group.sg_ping, aws_security_group.sg_8080\n│\n"
+	
+	cycle, err := Parse(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cycle.Nodes) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(cycle.Nodes))
+	}
+	if strings.Contains(cycle.RawError, "Refreshing state") {
+		t.Errorf("Expected raw error to start at the cycle line, got %d bytes", len(cycle.RawError))
+	}
+}
+
+func TestParse_AnalysisJSON(t *testing.T) {
+	input := "\n" + `{"cycle": {"nodes": [{"resource_type": "aws_instance", "resource_name": "a"}]}}`
+	
+	cycle, err := Parse(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cycle.Nodes) != 1 || cycle.Nodes[0].FullName() != "aws_instance.a" {
+		t.Errorf("Expected aws_instance.a, got %+v", cycle.Nodes)
+	}
+}
+
+func TestParse_NoCycle(t *testing.T) {
+	if _, err := Parse(context.Background(), strings.NewReader("Plan: 1 to add, 0 to change, 0 to destroy.\n")); err == nil {
+		t.Error("Expected an error for input without a cycle")
+	}
+}
+
+func TestParse_Canceled(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	
+	_, err := Parse(ctx, reader)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestAnalyze_Options(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_instance.a, aws_instance.b, aws_instance.c")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	graph := map[string][]string{
+		"aws_instance.a": {"aws_instance.b"},
+		"aws_instance.b": {"aws_instance.a"},
+	}
+	analyzer, err := Analyze(context.Background(), cycle, WithGraph(graph))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	minimal := analyzer.FindMinimalCycles()
+	if len(minimal) != 1 || len(minimal[0]) != 2 {
+		t.Errorf("Expected one 2-node cycle from the supplied graph, got %v", minimal)
+	}
+}
+
+func TestAnalyze_Canceled(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_instance.a, aws_instance.b")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	analyzer, err := Analyze(ctx, cycle)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if analyzer == nil || !analyzer.Partial {
+		t.Error("Expected a partial analyzer when the context is canceled")
+	}
+}
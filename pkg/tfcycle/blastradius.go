@@ -0,0 +1,197 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Sources of an ImpactedResource.
+const (
+	ImpactSourceCycle  = "cycle"
+	ImpactSourceConfig = "config"
+)
+
+// ImpactedResource is a resource outside the minimal cycles that depends on a
+// member of one, directly or through other resources.
+type ImpactedResource struct {
+	Address string `json:"address"`
+	// Path leads from the resource to the cycle member it depends on.
+	Path []string `json:"path"`
+	// Source is "cycle" if Terraform listed the resource in the cycle error
+	// and "config" if it was only found in the configuration.
+	Source   string `json:"source"`
+	Location string `json:"location,omitempty"`
+}
+
+// BlastRadius lists the resources affected by changes to the members of the
+// minimal cycles, nearest first.
+type BlastRadius struct {
+	Members  []string           `json:"members"`
+	Impacted []ImpactedResource `json:"impacted"`
+}
+
+// BlastRadius follows the dependency graph backwards from the members of the
+// minimal cycles. The other nodes of the cycle error are connected by the
+// hypothesized graph, or the graph given with SetGraph; with an index, every
+// resource and data block whose configuration references a member, or a
+// resource that does, is included too.
+func (ca *CycleAnalyzer) BlastRadius(index *ConfigIndex) BlastRadius {
+	var members []string
+	for _, cycle := range ca.FindMinimalCycles() {
+		for _, name := range cycle {
+			if !slices.Contains(members, name) {
+				members = append(members, name)
+			}
+		}
+	}
+	sort.Strings(members)
+	
+	dependents := make(map[string][]string)
+	addEdge := func(from, to string) {
+		if from != to && !slices.Contains(dependents[to], from) {
+			dependents[to] = append(dependents[to], from)
+		}
+	}
+	for from, targets := range ca.HypothesizedGraph() {
+		for _, to := range targets {
+			addEdge(from, to)
+		}
+	}
+	if index != nil {
+		ca.addConfigDependents(index, addEdge)
+	}
+	
+	parent := make(map[string]string)
+	visited := make(map[string]bool)
+	queue := append([]string{}, members...)
+	for _, member := range members {
+		visited[member] = true
+	}
+	radius := BlastRadius{Members: []string{}, Impacted: []ImpactedResource{}}
+	for _, member := range members {
+		radius.Members = append(radius.Members, ca.address(member))
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		
+		next := append([]string{}, dependents[current]...)
+		sort.Strings(next)
+		for _, dependent := range next {
+			if visited[dependent] {
+				continue
+			}
+			visited[dependent] = true
+			parent[dependent] = current
+			queue = append(queue, dependent)
+			radius.Impacted = append(radius.Impacted, ca.impactedResource(dependent, parent, index))
+		}
+	}
+	return radius
+}
+
+// addConfigDependents adds an edge for every reference between resource and
+// data blocks of the same module. Blocks declaring cycle nodes are mapped to
+// those nodes, so the edges join the hypothesized graph.
+func (ca *CycleAnalyzer) addConfigDependents(index *ConfigIndex, addEdge func(from, to string)) {
+	nodesOf := make(map[string][]string)
+	for _, node := range ca.cycle.Nodes {
+		nodesOf[node.baseAddress()] = append(nodesOf[node.baseAddress()], node.FullName())
+	}
+	names := func(block *ConfigBlock) []string {
+		if nodes, ok := nodesOf[block.Address()]; ok {
+			return nodes
+		}
+		return []string{block.Address()}
+	}
+	
+	var blocks []*ConfigBlock
+	for _, block := range index.Blocks {
+		if block.Kind == "resource" || block.Kind == "data" {
+			blocks = append(blocks, block)
+		}
+	}
+	for _, from := range blocks {
+		text := strings.Join(from.Lines, "\n")
+		for _, to := range blocks {
+			if from == to || strings.Join(from.ModulePath, ".") != strings.Join(to.ModulePath, ".") {
+				continue
+			}
+			reference := to.Type + "." + to.Name
+			if to.Kind == "data" {
+				reference = "data." + reference
+			}
+			if !strings.Contains(text, reference) || !referencesAddress(text, reference) {
+				continue
+			}
+			for _, fromName := range names(from) {
+				for _, toName := range names(to) {
+					addEdge(fromName, toName)
+				}
+			}
+		}
+	}
+}
+
+func (ca *CycleAnalyzer) impactedResource(name string, parent map[string]string, index *ConfigIndex) ImpactedResource {
+	impacted := ImpactedResource{Address: ca.address(name), Source: ImpactSourceConfig}
+	for current := name; current != ""; current = parent[current] {
+		impacted.Path = append(impacted.Path, ca.address(current))
+	}
+	
+	if node := ca.cycle.GetNodeByName(name); node != nil {
+		impacted.Source = ImpactSourceCycle
+		if node.Location != nil {
+			impacted.Location = fmt.Sprintf("%s:%d", node.Location.File, node.Location.Line)
+		}
+	} else if index != nil {
+		if block := index.LookupAddress(name); block != nil {
+			impacted.Location = block.Location()
+		}
+	}
+	return impacted
+}
+
+// address returns the Terraform address of the node named name, or name if
+// it is not a node of the cycle.
+func (ca *CycleAnalyzer) address(name string) string {
+	if node := ca.cycle.GetNodeByName(name); node != nil {
+		return node.Address()
+	}
+	return name
+}
+
+// FormatBlastRadius returns the blast radius as text.
+func (of *OutputFormatter) FormatBlastRadius(radius BlastRadius) string {
+	var output strings.Builder
+	
+	output.WriteString("💥 BLAST RADIUS\n\n")
+	output.WriteString(fmt.Sprintf("Cycle members: %s\n\n", strings.Join(radius.Members, ", ")))
+	
+	if len(radius.Impacted) == 0 {
+		output.WriteString("No other resources depend on the cycle members.\n")
+		return output.String()
+	}
+	
+	output.WriteString(fmt.Sprintf("%d resource(s) outside the minimal cycles depend on its members:\n\n", len(radius.Impacted)))
+	for _, impacted := range radius.Impacted {
+		output.WriteString(fmt.Sprintf("  • %s (%s)\n", impacted.Address, impacted.Source))
+		if impacted.Location != "" {
+			output.WriteString(fmt.Sprintf("    📍 %s\n", impacted.Location))
+		}
+		output.WriteString(fmt.Sprintf("    %s\n", strings.Join(impacted.Path, " → ")))
+	}
+	return output.String()
+}
+
+// FormatBlastRadiusAsJSON returns the blast radius as indented JSON.
+func (of *OutputFormatter) FormatBlastRadiusAsJSON(radius BlastRadius) (string, error) {
+	jsonData, err := json.MarshalIndent(map[string]interface{}{"blast_radius": radius}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(jsonData), nil
+}
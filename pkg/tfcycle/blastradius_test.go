@@ -0,0 +1,91 @@
+package tfcycle
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBlastRadius_HypothesizedGraph(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_instance.a, aws_instance.b, aws_instance.c")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle, WithGraph(map[string][]string{
+		"aws_instance.a": {"aws_instance.b"},
+		"aws_instance.b": {"aws_instance.a"},
+		"aws_instance.c": {"aws_instance.a"},
+	}))
+	
+	radius := analyzer.BlastRadius(nil)
+	if !slices.Equal(radius.Members, []string{"aws_instance.a", "aws_instance.b"}) {
+		t.Errorf("Expected the minimal cycle's members, got %v", radius.Members)
+	}
+	if len(radius.Impacted) != 1 || radius.Impacted[0].Source != ImpactSourceCycle ||
+		!slices.Equal(radius.Impacted[0].Path, []string{"aws_instance.c", "aws_instance.a"}) {
+		t.Errorf("Expected aws_instance.c from the cycle error, got %+v", radius.Impacted)
+	}
+}
+
+func TestBlastRadius_Config(t *testing.T) {
+	dir := t.TempDir()
+	config := `resource "aws_security_group" "web" {
+  ingress {
+    security_groups = [aws_security_group.db.id]
+  }
+}
+
+resource "aws_security_group" "db" {
+  ingress {
+    security_groups = [aws_security_group.web.id]
+  }
+}
+
+resource "aws_instance" "app" {
+  vpc_security_group_ids = [aws_security_group.web.id]
+}
+
+resource "aws_route53_record" "app" {
+  records = [aws_instance.app.private_ip]
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_security_group.web, aws_security_group.db")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	radius := analyzer.BlastRadius(index)
+	
+	var addresses []string
+	for _, impacted := range radius.Impacted {
+		addresses = append(addresses, impacted.Address)
+		if impacted.Source != ImpactSourceConfig || !strings.HasSuffix(impacted.Location, "main.tf:13") && !strings.HasSuffix(impacted.Location, "main.tf:17") {
+			t.Errorf("Expected a configuration block with its location, got %+v", impacted)
+		}
+	}
+	if !slices.Equal(addresses, []string{"aws_instance.app", "aws_route53_record.app"}) {
+		t.Errorf("Expected the instance and the record that depends on it, got %v", addresses)
+	}
+	if path := radius.Impacted[1].Path; len(path) != 3 || path[2] != "aws_security_group.web" {
+		t.Errorf("Expected a path through the instance to the group, got %v", path)
+	}
+	
+	text := NewOutputFormatter(analyzer, false).FormatBlastRadius(radius)
+	if !strings.Contains(text, "aws_route53_record.app → aws_instance.app → aws_security_group.web") {
+		t.Errorf("Expected the dependency path in the text report, got:\n%s", text)
+	}
+}
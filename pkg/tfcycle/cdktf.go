@@ -0,0 +1,145 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ConstructPaths maps the addresses of resources and data sources
+// synthesized by CDKTF to the paths of the constructs that declared them,
+// e.g. aws_s3_bucket.mystack_bucket_F00D to mystack/bucket.
+type ConstructPaths map[string]string
+
+type cdktfManifest struct {
+	Stacks map[string]struct {
+		SynthesizedStackPath string `json:"synthesizedStackPath"`
+	} `json:"stacks"`
+}
+
+type cdktfBlock struct {
+	Comment struct {
+		Metadata struct {
+			Path string `json:"path"`
+		} `json:"metadata"`
+	} `json:"//"`
+}
+
+type cdktfStack struct {
+	Resource map[string]map[string]cdktfBlock `json:"resource"`
+	Data     map[string]map[string]cdktfBlock `json:"data"`
+}
+
+// LoadConstructPaths reads the construct paths from CDKTF's synthesized
+// output. path is the output directory (cdktf.out), its manifest.json, a
+// stack directory or a cdk.tf.json file. Addresses declared by more than
+// one stack of the manifest are ambiguous and left out.
+func LoadConstructPaths(path string) (ConstructPaths, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	
+	var stackFiles []string
+	switch {
+	case info.IsDir() && fileExists(filepath.Join(path, "manifest.json")):
+		stackFiles, err = manifestStackFiles(filepath.Join(path, "manifest.json"))
+	case info.IsDir():
+		stackFiles = []string{filepath.Join(path, "cdk.tf.json")}
+	case filepath.Base(path) == "manifest.json":
+		stackFiles, err = manifestStackFiles(path)
+	default:
+		stackFiles = []string{path}
+	}
+	if err != nil {
+		return nil, err
+	}
+	
+	paths := make(ConstructPaths)
+	ambiguous := make(map[string]bool)
+	for _, file := range stackFiles {
+		stackPaths, err := readStackConstructPaths(file)
+		if err != nil {
+			return nil, err
+		}
+		for address, constructPath := range stackPaths {
+			if existing, ok := paths[address]; ok && existing != constructPath {
+				ambiguous[address] = true
+			}
+			paths[address] = constructPath
+		}
+	}
+	for address := range ambiguous {
+		logger.Debug("address declared by more than one CDKTF stack", "address", address)
+		delete(paths, address)
+	}
+	return paths, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func manifestStackFiles(manifestPath string) ([]string, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	var manifest cdktfManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse CDKTF manifest %s: %w", manifestPath, err)
+	}
+	if len(manifest.Stacks) == 0 {
+		return nil, fmt.Errorf("CDKTF manifest %s lists no stacks", manifestPath)
+	}
+	
+	var files []string
+	for _, stack := range manifest.Stacks {
+		files = append(files, filepath.Join(filepath.Dir(manifestPath), filepath.FromSlash(stack.SynthesizedStackPath)))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func readStackConstructPaths(file string) (ConstructPaths, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var stack cdktfStack
+	if err := json.Unmarshal(data, &stack); err != nil {
+		return nil, fmt.Errorf("failed to parse CDKTF stack %s: %w", file, err)
+	}
+	
+	paths := make(ConstructPaths)
+	for prefix, blocks := range map[string]map[string]map[string]cdktfBlock{"": stack.Resource, "data.": stack.Data} {
+		for resourceType, byName := range blocks {
+			for name, block := range byName {
+				if block.Comment.Metadata.Path != "" {
+					paths[prefix+resourceType+"."+name] = block.Comment.Metadata.Path
+				}
+			}
+		}
+	}
+	return paths, nil
+}
+
+// Resolve sets ConstructPath on the nodes of cycle declared by a construct
+// and returns how many were found. Resources inside Terraform modules are
+// not synthesized by CDKTF and keep no construct path.
+func (cp ConstructPaths) Resolve(cycle *TfCycle) int {
+	resolved := 0
+	for _, node := range cycle.Nodes {
+		if len(node.ModulePath) > 0 {
+			continue
+		}
+		if constructPath, ok := cp[node.baseAddress()]; ok {
+			node.ConstructPath = constructPath
+			resolved++
+		}
+	}
+	return resolved
+}
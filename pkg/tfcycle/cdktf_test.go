@@ -0,0 +1,88 @@
+package tfcycle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const cdktfStackJSON = `{
+  "resource": {
+    "aws_security_group": {
+      "web_sg_1A2B3C4D": {
+        "//": {"metadata": {"path": "mystack/web/sg", "uniqueId": "web_sg_1A2B3C4D"}},
+        "name": "web"
+      },
+      "db_sg_5E6F7A8B": {
+        "//": {"metadata": {"path": "mystack/db/sg", "uniqueId": "db_sg_5E6F7A8B"}},
+        "name": "db"
+      }
+    }
+  },
+  "data": {
+    "aws_vpc": {
+      "main": {
+        "//": {"metadata": {"path": "mystack/main", "uniqueId": "main"}}
+      }
+    }
+  }
+}`
+
+func writeCDKTFOut(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	stackDir := filepath.Join(dir, "stacks", "mystack")
+	if err := os.MkdirAll(stackDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{"version": "0.20.0", "stacks": {"mystack": {"name": "mystack", "synthesizedStackPath": "stacks/mystack/cdk.tf.json"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(stackDir, "cdk.tf.json"), []byte(cdktfStackJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoadConstructPaths(t *testing.T) {
+	dir := writeCDKTFOut(t)
+	
+	for _, path := range []string{dir, filepath.Join(dir, "manifest.json"), filepath.Join(dir, "stacks", "mystack"), filepath.Join(dir, "stacks", "mystack", "cdk.tf.json")} {
+		paths, err := LoadConstructPaths(path)
+		if err != nil {
+			t.Fatalf("Expected no error for %s, got: %v", path, err)
+		}
+		if paths["aws_security_group.web_sg_1A2B3C4D"] != "mystack/web/sg" || paths["data.aws_vpc.main"] != "mystack/main" {
+			t.Errorf("Expected the construct paths from %s, got %v", path, paths)
+		}
+	}
+	
+	if _, err := LoadConstructPaths(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected an error for a missing path")
+	}
+}
+
+func TestConstructPaths_Resolve(t *testing.T) {
+	paths, err := LoadConstructPaths(writeCDKTFOut(t))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_security_group.web_sg_1A2B3C4D, aws_security_group.db_sg_5E6F7A8B, module.net.aws_security_group.web_sg_1A2B3C4D")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	if resolved := paths.Resolve(cycle); resolved != 2 {
+		t.Errorf("Expected 2 resolved nodes, got %d", resolved)
+	}
+	if cycle.Nodes[1].ConstructPath != "mystack/db/sg" || cycle.Nodes[2].ConstructPath != "" {
+		t.Errorf("Expected construct paths only outside modules, got %+v", cycle.Nodes)
+	}
+	
+	report := NewOutputFormatter(NewCycleAnalyzer(cycle), false).FormatAnalysis()
+	if !strings.Contains(report, "🧩 mystack/web/sg") {
+		t.Errorf("Expected the construct path in the report, got:\n%s", report)
+	}
+}
@@ -0,0 +1,104 @@
+package tfcycle
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultContextLines is how many lines before a cycle error Parse and
+// NewParser keep as its context.
+const DefaultContextLines = 10
+
+// Values of the "context_action" annotation of a CycleNode.
+const (
+	ContextDestroy = "destroy"
+	ContextReplace = "replace"
+)
+
+// CycleContext holds the output that preceded a cycle error, such as the
+// resources terraform apply was replacing and earlier errors. Destroyed and
+// Replaced list addresses in Terraform's syntax.
+type CycleContext struct {
+	Lines     []string `json:"lines"`
+	Destroyed []string `json:"destroyed,omitempty"`
+	Replaced  []string `json:"replaced,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+var (
+	ansiEscapeRegex     = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+	contextDestroyRegex = regexp.MustCompile(`^(?:(\S+): Destroying\.\.\.|# (\S+) will be destroyed)`)
+	contextReplaceRegex = regexp.MustCompile(`^# (\S+) (?:is tainted, so )?must be replaced`)
+	contextErrorRegex   = regexp.MustCompile(`^Error: (.+)`)
+)
+
+// parseContext returns the context of the last limit non-blank lines of
+// text, or nil if there are none.
+func parseContext(text string, limit int) *CycleContext {
+	if limit <= 0 {
+		return nil
+	}
+	
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(ansiEscapeRegex.ReplaceAllString(line, ""), " \t\r")
+		if strings.TrimSpace(line) == "" || strings.TrimSpace(line) == "│" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	if len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+	
+	context := &CycleContext{Lines: lines}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "│"))
+		if matches := contextDestroyRegex.FindStringSubmatch(trimmed); matches != nil {
+			if address := matches[1] + matches[2]; !slices.Contains(context.Destroyed, address) {
+				context.Destroyed = append(context.Destroyed, address)
+			}
+		} else if matches := contextReplaceRegex.FindStringSubmatch(trimmed); matches != nil {
+			if !slices.Contains(context.Replaced, matches[1]) {
+				context.Replaced = append(context.Replaced, matches[1])
+			}
+		} else if matches := contextErrorRegex.FindStringSubmatch(trimmed); matches != nil {
+			context.Errors = append(context.Errors, matches[1])
+		}
+	}
+	return context
+}
+
+// applyContext annotates the nodes that context says were being destroyed
+// or replaced with "context_action", unless Terraform already listed them
+// as destroy nodes.
+func applyContext(cycle *TfCycle, context *CycleContext) {
+	actions := make(map[string]string)
+	for _, address := range context.Destroyed {
+		actions[address] = ContextDestroy
+	}
+	for _, address := range context.Replaced {
+		actions[address] = ContextReplace
+	}
+	
+	for _, node := range cycle.Nodes {
+		if node.Action == ActionDestroy || node.Action == ActionDestroyDeposed {
+			continue
+		}
+		if action, ok := actions[node.Address()]; ok {
+			logger.Debug("cycle node is being destroyed according to the context", "address", node.Address(), "action", action)
+			node.Annotations["context_action"] = action
+		}
+	}
+}
+
+// Destroying reports whether the node is destroyed by the operation that hit
+// the cycle: it is a destroy node, or the output before the cycle error
+// shows it being destroyed or replaced.
+func (n *CycleNode) Destroying() bool {
+	return n.Action == ActionDestroy || n.Action == ActionDestroyDeposed || n.Annotations["context_action"] != ""
+}
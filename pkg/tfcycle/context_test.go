@@ -0,0 +1,79 @@
+package tfcycle
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const applyOutput = `aws_security_group.sg: Modifying... [id=sg-123]
+  # aws_subnet.private is tainted, so must be replaced
+aws_instance.web: Destroying... [id=i-123]
+
+Error: creating Security Group Rule: InvalidPermission.Duplicate
+
+Error: Cycle: aws_instance.web, aws_security_group.sg
+`
+
+func TestParseError_Context(t *testing.T) {
+	cycle, err := NewParser().ParseError(applyOutput)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cycle.Context == nil {
+		t.Fatalf("Expected the preceding output as context")
+	}
+	if len(cycle.Context.Lines) != 4 {
+		t.Errorf("Expected 4 context lines, got %q", cycle.Context.Lines)
+	}
+	if strings.Join(cycle.Context.Destroyed, ",") != "aws_instance.web" || strings.Join(cycle.Context.Replaced, ",") != "aws_subnet.private" {
+		t.Errorf("Expected destroy and replace hints, got %+v", cycle.Context)
+	}
+	if len(cycle.Context.Errors) != 1 || !strings.HasPrefix(cycle.Context.Errors[0], "creating Security Group Rule") {
+		t.Errorf("Expected the earlier error, got %q", cycle.Context.Errors)
+	}
+	
+	web := cycle.GetNodeByName("aws_instance.web")
+	if web.Annotations["context_action"] != ContextDestroy || !web.Destroying() {
+		t.Errorf("Expected aws_instance.web to be destroying, got %v", web.Annotations)
+	}
+	if cycle.GetNodeByName("aws_security_group.sg").Destroying() {
+		t.Errorf("Expected aws_security_group.sg not to be destroying")
+	}
+	if severity := NewCycleAnalyzer(cycle).Severity(); severity != SeverityHigh {
+		t.Errorf("Expected the context to raise severity to high, got %s", severity)
+	}
+}
+
+func TestParseError_ContextLines(t *testing.T) {
+	parser := NewParser()
+	parser.SetContextLines(1)
+	cycle, err := parser.ParseError(applyOutput)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cycle.Context.Lines) != 1 || len(cycle.Context.Destroyed) != 0 {
+		t.Errorf("Expected only the last line as context, got %+v", cycle.Context)
+	}
+	
+	parser.SetContextLines(0)
+	if cycle, _ := parser.ParseError(applyOutput); cycle.Context != nil || cycle.GetNodeByName("aws_instance.web").Destroying() {
+		t.Errorf("Expected no context with 0 lines, got %+v", cycle.Context)
+	}
+}
+
+func TestParse_Context(t *testing.T) {
+	input := strings.Repeat("aws_instance.db: Refreshing state...\n", 100) +
+		"aws_instance.web: Destroying... [id=i-123]\n" +
+		"╷\n│ Error: Cycle: aws_instance.web, aws_security_group.sg\n│ \n╵\n"
+	cycle, err := Parse(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cycle.Context == nil || len(cycle.Context.Lines) != DefaultContextLines || strings.Join(cycle.Context.Destroyed, ",") != "aws_instance.web" {
+		t.Errorf("Expected the last %d lines before the diagnostic as context, got %+v", DefaultContextLines, cycle.Context)
+	}
+	if !strings.HasPrefix(cycle.RawError, "│ Error: Cycle:") {
+		t.Errorf("Expected the raw error to exclude the context, got %q", cycle.RawError)
+	}
+}
@@ -0,0 +1,174 @@
+package tfcycle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	colorReset = "\033[0m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorBold  = "\033[1m"
+)
+
+// CycleDiff describes how a cycle error changed between two Terraform runs.
+type CycleDiff struct {
+	OldNodes         int        `json:"old_nodes"`
+	NewNodes         int        `json:"new_nodes"`
+	AddedNodes       []string   `json:"added_nodes"`
+	RemovedNodes     []string   `json:"removed_nodes"`
+	BrokenCycles     [][]string `json:"broken_cycles"`
+	IntroducedCycles [][]string `json:"introduced_cycles"`
+	RemainingCycles  [][]string `json:"remaining_cycles"`
+	Resolved         bool       `json:"resolved"`
+}
+
+// HistoryComparison relates an analysis to an earlier one of the same
+// configuration. Trend is progress, regression, unchanged or changed.
+type HistoryComparison struct {
+	PreviousID        string
+	PreviousTimestamp time.Time
+	Trend             string
+	Diff              CycleDiff
+}
+
+// Changed reports whether any node or minimal cycle differs.
+func (d CycleDiff) Changed() bool {
+	return len(d.AddedNodes) > 0 || len(d.RemovedNodes) > 0 ||
+		len(d.BrokenCycles) > 0 || len(d.IntroducedCycles) > 0
+}
+
+// DiffCycles compares the cycles reported before and after a change.
+func DiffCycles(oldCycle, newCycle *TfCycle) CycleDiff {
+	return DiffCycleSets(cycleNodeNames(oldCycle), cycleNodeNames(newCycle), minimalCycles(oldCycle), minimalCycles(newCycle))
+}
+
+// DiffCycleSets compares node addresses and minimal cycles directly, for
+// analyses that were stored rather than parsed.
+func DiffCycleSets(oldNodes, newNodes []string, oldMinimal, newMinimal [][]string) CycleDiff {
+	diff := CycleDiff{
+		OldNodes:         len(oldNodes),
+		NewNodes:         len(newNodes),
+		AddedNodes:       []string{},
+		RemovedNodes:     []string{},
+		BrokenCycles:     [][]string{},
+		IntroducedCycles: [][]string{},
+		RemainingCycles:  [][]string{},
+		Resolved:         len(oldNodes) > 0 && len(newNodes) == 0,
+	}
+	
+	oldNames := nameSet(oldNodes)
+	newNames := nameSet(newNodes)
+	for name := range newNames {
+		if !oldNames[name] {
+			diff.AddedNodes = append(diff.AddedNodes, name)
+		}
+	}
+	for name := range oldNames {
+		if !newNames[name] {
+			diff.RemovedNodes = append(diff.RemovedNodes, name)
+		}
+	}
+	sort.Strings(diff.AddedNodes)
+	sort.Strings(diff.RemovedNodes)
+	
+	oldCycles := cycleSet(oldMinimal)
+	newCycles := cycleSet(newMinimal)
+	for _, key := range sortedKeys(oldCycles) {
+		if _, ok := newCycles[key]; ok {
+			diff.RemainingCycles = append(diff.RemainingCycles, oldCycles[key])
+		} else {
+			diff.BrokenCycles = append(diff.BrokenCycles, oldCycles[key])
+		}
+	}
+	for _, key := range sortedKeys(newCycles) {
+		if _, ok := oldCycles[key]; !ok {
+			diff.IntroducedCycles = append(diff.IntroducedCycles, newCycles[key])
+		}
+	}
+	
+	return diff
+}
+
+func cycleNodeNames(cycle *TfCycle) []string {
+	return NewCycleAnalyzer(cycle).NodeNames()
+}
+
+func minimalCycles(cycle *TfCycle) [][]string {
+	if len(cycle.Nodes) == 0 {
+		return nil
+	}
+	return NewCycleAnalyzer(cycle).FindMinimalCycles()
+}
+
+func nameSet(names []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+func cycleSet(cycles [][]string) map[string][]string {
+	set := make(map[string][]string)
+	analyzer := &CycleAnalyzer{}
+	for _, c := range cycles {
+		normalized := analyzer.normalizeCycle(c)
+		set[strings.Join(normalized, ",")] = normalized
+	}
+	return set
+}
+
+// FormatCycleDiff renders diff as text, with ANSI colors if color is set.
+func FormatCycleDiff(diff CycleDiff, color bool) string {
+	paint := func(code, text string) string {
+		if !color {
+			return text
+		}
+		return code + text + colorReset
+	}
+	
+	var output strings.Builder
+	output.WriteString(paint(colorBold, "🔀 CYCLE DIFF") + "\n")
+	output.WriteString("=============\n\n")
+	
+	switch {
+	case diff.Resolved:
+		output.WriteString(paint(colorGreen, "✅ Cycle resolved: the new output contains no cycle error") + "\n\n")
+	case !diff.Changed():
+		output.WriteString(paint(colorRed, "⚠️  No change: the same cycle is still reported") + "\n\n")
+	}
+	
+	output.WriteString(fmt.Sprintf("Resources in cycle: %d → %d\n\n", diff.OldNodes, diff.NewNodes))
+	
+	writeList := func(title, code, marker string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		output.WriteString(fmt.Sprintf("%s (%d):\n", title, len(items)))
+		for _, item := range items {
+			output.WriteString(paint(code, fmt.Sprintf("  %s %s", marker, item)) + "\n")
+		}
+		output.WriteString("\n")
+	}
+	
+	writeList("Removed from cycle", colorGreen, "-", diff.RemovedNodes)
+	writeList("Added to cycle", colorRed, "+", diff.AddedNodes)
+	writeList("Cycles broken", colorGreen, "✓", CycleStrings(diff.BrokenCycles))
+	writeList("Cycles introduced", colorRed, "✗", CycleStrings(diff.IntroducedCycles))
+	writeList("Cycles remaining", "", "•", CycleStrings(diff.RemainingCycles))
+	
+	return output.String()
+}
+
+// CycleStrings renders each cycle as "a → b → a".
+func CycleStrings(cycles [][]string) []string {
+	result := make([]string, len(cycles))
+	for i, cycle := range cycles {
+		result[i] = strings.Join(cycle, " → ") + " → " + cycle[0]
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffCycles_BrokenAndIntroduced(t *testing.T) {
+	oldCycle, _ := NewParser().ParseError(`Error: Cycle: aws_security_group.sg_ping, aws_security_group.sg_8080, aws_instance.web (destroy)`)
+	newCycle, _ := NewParser().ParseError(`Error: Cycle: aws_security_group.sg_ping, aws_instance.web (destroy), aws_s3_bucket.b`)
+	
+	diff := DiffCycles(oldCycle, newCycle)
+	
+	if len(diff.RemovedNodes) != 1 || diff.RemovedNodes[0] != "aws_security_group.sg_8080" {
+		t.Errorf("Expected sg_8080 removed, got %v", diff.RemovedNodes)
+	}
+	if len(diff.AddedNodes) != 1 || diff.AddedNodes[0] != "aws_s3_bucket.b" {
+		t.Errorf("Expected aws_s3_bucket.b added, got %v", diff.AddedNodes)
+	}
+	if len(diff.BrokenCycles) == 0 {
+		t.Errorf("Expected at least one broken cycle")
+	}
+	if len(diff.IntroducedCycles) == 0 {
+		t.Errorf("Expected at least one introduced cycle")
+	}
+	if diff.Resolved {
+		t.Errorf("Expected diff not to be resolved")
+	}
+}
+
+func TestDiffCycles_Unchanged(t *testing.T) {
+	input := `Error: Cycle: aws_security_group.a, aws_security_group.b`
+	oldCycle, _ := NewParser().ParseError(input)
+	newCycle, _ := NewParser().ParseError(input)
+	
+	diff := DiffCycles(oldCycle, newCycle)
+	if diff.Changed() {
+		t.Errorf("Expected no change, got %+v", diff)
+	}
+	if len(diff.RemainingCycles) != 1 {
+		t.Errorf("Expected 1 remaining cycle, got %d", len(diff.RemainingCycles))
+	}
+	
+	output := FormatCycleDiff(diff, false)
+	if !strings.Contains(output, "No change") {
+		t.Errorf("Expected no-change notice in output, got:\n%s", output)
+	}
+	if strings.Contains(output, "\033[") {
+		t.Errorf("Expected no color codes when color is disabled")
+	}
+}
+
+func TestDiffCycles_Resolved(t *testing.T) {
+	oldCycle, _ := NewParser().ParseError(`Error: Cycle: aws_security_group.a, aws_security_group.b`)
+	newCycle := &TfCycle{Nodes: []*CycleNode{}}
+	
+	diff := DiffCycles(oldCycle, newCycle)
+	if !diff.Resolved {
+		t.Errorf("Expected diff to be resolved")
+	}
+	if len(diff.BrokenCycles) != 1 {
+		t.Errorf("Expected 1 broken cycle, got %d", len(diff.BrokenCycles))
+	}
+}
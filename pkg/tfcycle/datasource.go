@@ -0,0 +1,121 @@
+package tfcycle
+
+import (
+	"fmt"
+	"strings"
+)
+
+type dataSourceLookup struct {
+	argument  string
+	attribute string
+	tagFilter bool
+	prefix    string
+}
+
+var dataSourceLookups = map[string]dataSourceLookup{
+	"aws_security_group":                    {argument: "name", attribute: "name"},
+	"aws_iam_role":                          {argument: "name", attribute: "name"},
+	"aws_iam_policy":                        {argument: "name", attribute: "name"},
+	"aws_iam_instance_profile":              {argument: "name", attribute: "name"},
+	"aws_vpc":                               {tagFilter: true},
+	"aws_subnet":                            {tagFilter: true},
+	"aws_instance":                          {tagFilter: true},
+	"aws_s3_bucket":                         {argument: "bucket", attribute: "bucket"},
+	"aws_lambda_function":                   {argument: "function_name", attribute: "function_name"},
+	"aws_kms_key":                           {argument: "key_id", prefix: "alias/"},
+	"aws_route53_zone":                      {argument: "name", attribute: "name"},
+	"aws_sns_topic":                         {argument: "name", attribute: "name"},
+	"aws_sqs_queue":                         {argument: "name", attribute: "name"},
+	"aws_db_instance":                       {argument: "db_instance_identifier", attribute: "identifier"},
+	"aws_ecr_repository":                    {argument: "name", attribute: "name"},
+	"aws_organizations_organizational_unit": {argument: "name", attribute: "name"},
+	"google_compute_network":                {argument: "name", attribute: "name"},
+	"google_service_account":                {argument: "account_id", attribute: "account_id"},
+	"azurerm_resource_group":                {argument: "name", attribute: "name"},
+	"azurerm_virtual_network":               {argument: "name", attribute: "name"},
+}
+
+// DataSourceSnippet is a data block that looks up an existing resource, with
+// the reference rewrites that replace the managed resource by it.
+type DataSourceSnippet struct {
+	Block       string
+	Address     string
+	Replacement map[string]string
+}
+
+// HasDataSource reports whether GenerateDataSource supports resourceType.
+func HasDataSource(resourceType string) bool {
+	_, ok := dataSourceLookups[resourceType]
+	return ok
+}
+
+// GenerateDataSource builds a data source equivalent of node from its
+// configuration block.
+func GenerateDataSource(node *CycleNode, block *ConfigBlock) (DataSourceSnippet, bool) {
+	lookup, ok := dataSourceLookups[node.ResourceType]
+	if !ok {
+		return DataSourceSnippet{}, false
+	}
+	
+	value := fmt.Sprintf("%q", lookup.prefix+node.ResourceName)
+	if block != nil && lookup.attribute != "" {
+		if configured := block.Attribute(lookup.attribute); configured != "" {
+			value = configured
+		}
+	}
+	
+	var body string
+	if lookup.tagFilter {
+		if block != nil {
+			for _, tags := range block.NestedBlocksOrMaps("tags") {
+				if name, ok := tags["Name"]; ok {
+					value = name
+				}
+			}
+		}
+		body = fmt.Sprintf("  filter {\n    name   = \"tag:Name\"\n    values = [%s]\n  }\n", value)
+	} else {
+		body = fmt.Sprintf("  %s = %s\n", lookup.argument, value)
+	}
+	
+	dataBlock := fmt.Sprintf("data %q %q {\n%s}", node.ResourceType, node.ResourceName, body)
+	
+	resourceAddr := node.ResourceType + "." + node.ResourceName
+	dataAddr := "data." + resourceAddr
+	
+	return DataSourceSnippet{
+		Block:   dataBlock,
+		Address: dataAddr,
+		Replacement: map[string]string{
+			resourceAddr: dataAddr,
+		},
+	}, true
+}
+
+// NestedBlocksOrMaps returns the attributes of each nested block called name.
+func (b *ConfigBlock) NestedBlocksOrMaps(name string) []map[string]string {
+	var result []map[string]string
+	
+	for _, nested := range b.NestedBlocks(name) {
+		result = append(result, nested.Attributes())
+	}
+	
+	for i := 1; i < len(b.Lines)-1; i++ {
+		matches := attributeRegex.FindStringSubmatch(b.Lines[i])
+		if matches == nil || matches[1] != name || !strings.HasPrefix(strings.TrimSpace(matches[2]), "{") {
+			continue
+		}
+		
+		end := findBlockEnd(b.Lines, i)
+		nested := NestedBlock{Name: name, Lines: b.Lines[i : end+1]}
+		if end == i {
+			inner := strings.TrimSpace(matches[2])
+			inner = strings.TrimSuffix(strings.TrimPrefix(inner, "{"), "}")
+			nested.Lines = []string{"", inner, ""}
+		}
+		result = append(result, nested.Attributes())
+		i = end
+	}
+	
+	return result
+}
@@ -0,0 +1,78 @@
+package tfcycle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const diffContext = 3
+
+type lineEdit struct {
+	start       int
+	end         int
+	replacement []string
+}
+
+func unifiedDiff(path string, lines []string, edits []lineEdit) string {
+	if len(edits) == 0 {
+		return ""
+	}
+	
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	
+	var groups [][]lineEdit
+	for _, edit := range edits {
+		if len(groups) > 0 {
+			last := groups[len(groups)-1]
+			if edit.start-last[len(last)-1].end <= 2*diffContext {
+				groups[len(groups)-1] = append(last, edit)
+				continue
+			}
+		}
+		groups = append(groups, []lineEdit{edit})
+	}
+	
+	var diff strings.Builder
+	diff.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", path, path))
+	
+	offset := 0
+	for _, group := range groups {
+		ctxStart := group[0].start - diffContext
+		if ctxStart < 0 {
+			ctxStart = 0
+		}
+		ctxEnd := group[len(group)-1].end + diffContext
+		if ctxEnd > len(lines) {
+			ctxEnd = len(lines)
+		}
+		
+		var body strings.Builder
+		delta := 0
+		i := ctxStart
+		for _, edit := range group {
+			for ; i < edit.start; i++ {
+				body.WriteString(" " + lines[i] + "\n")
+			}
+			for ; i < edit.end; i++ {
+				body.WriteString("-" + lines[i] + "\n")
+			}
+			for _, line := range edit.replacement {
+				body.WriteString("+" + line + "\n")
+			}
+			delta += len(edit.replacement) - (edit.end - edit.start)
+		}
+		for ; i < ctxEnd; i++ {
+			body.WriteString(" " + lines[i] + "\n")
+		}
+		
+		oldCount := ctxEnd - ctxStart
+		diff.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", ctxStart+1, oldCount, ctxStart+1+offset, oldCount+delta))
+		diff.WriteString(body.String())
+		offset += delta
+	}
+	
+	return diff.String()
+}
@@ -0,0 +1,17 @@
+// Package tfcycle analyzes Terraform "Error: Cycle:" output. It parses the
+// resources Terraform reports, hypothesizes the dependencies between them,
+// finds the minimal cycles and formats reports, Graphviz visualizations, HCL
+// fixes and staged apply plans.
+//
+//	cycle, err := tfcycle.Parse(ctx, os.Stdin)
+//	if err != nil {
+//		return err
+//	}
+//	analyzer, _ := tfcycle.Analyze(ctx, cycle)
+//	fmt.Print(tfcycle.NewOutputFormatter(analyzer, false).FormatAnalysis())
+//
+// The tfcycle command in cmd/tfcycle is a thin CLI around this package.
+package tfcycle
+
+// Version is the tfcycle release this package belongs to.
+const Version = "1.0.0"
@@ -0,0 +1,36 @@
+package tfcycle
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrEmptyInput is returned when the input is empty or only whitespace.
+	ErrEmptyInput = errors.New("empty input")
+
+	// ErrNoCycleFound is returned when the input contains no cycle error or
+	// a JSON report without cycle nodes.
+	ErrNoCycleFound = errors.New("no Terraform cycle error found in input")
+
+	// ErrUnparsableResource matches every UnparsableResourceError with
+	// errors.Is.
+	ErrUnparsableResource = errors.New("unparsable resource")
+)
+
+// UnparsableResourceError reports a cycle member whose resource type and name
+// could not be parsed. ParseError skips such members and only returns the
+// errors, joined, when no member could be parsed.
+type UnparsableResourceError struct {
+	// Resource is the offending member as Terraform printed it.
+	Resource string
+}
+
+func (e *UnparsableResourceError) Error() string {
+	return fmt.Sprintf("could not parse resource type and name from '%s'", e.Resource)
+}
+
+// Is reports whether target is ErrUnparsableResource.
+func (e *UnparsableResourceError) Is(target error) bool {
+	return target == ErrUnparsableResource
+}
@@ -0,0 +1,57 @@
+package tfcycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseError_EmptyInput(t *testing.T) {
+	for _, input := range []string{"", "  \n\t\n"} {
+		if _, err := NewParser().ParseError(input); !errors.Is(err, ErrEmptyInput) {
+			t.Errorf("Expected ErrEmptyInput for %q, got %v", input, err)
+		}
+		if _, err := Parse(context.Background(), strings.NewReader(input)); !errors.Is(err, ErrEmptyInput) {
+			t.Errorf("Expected ErrEmptyInput from Parse for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestParseError_NoCycleFound(t *testing.T) {
+	input := "Plan: 1 to add, 0 to change, 0 to destroy."
+	if _, err := NewParser().ParseError(input); !errors.Is(err, ErrNoCycleFound) {
+		t.Errorf("Expected ErrNoCycleFound, got %v", err)
+	}
+	if _, err := Parse(context.Background(), strings.NewReader(input)); !errors.Is(err, ErrNoCycleFound) {
+		t.Errorf("Expected ErrNoCycleFound from Parse, got %v", err)
+	}
+	if _, err := ParseInput(`{"cycle": {"nodes": []}}`); !errors.Is(err, ErrNoCycleFound) {
+		t.Errorf("Expected ErrNoCycleFound for an empty JSON report, got %v", err)
+	}
+}
+
+func TestParseError_UnparsableResource(t *testing.T) {
+	_, err := NewParser().ParseError("Error: Cycle: ???, !!!")
+	if !errors.Is(err, ErrUnparsableResource) {
+		t.Fatalf("Expected ErrUnparsableResource, got %v", err)
+	}
+	
+	var resourceErr *UnparsableResourceError
+	if !errors.As(err, &resourceErr) {
+		t.Fatalf("Expected an UnparsableResourceError, got %T", err)
+	}
+	if resourceErr.Resource != "???" {
+		t.Errorf("Expected offending resource %q, got %q", "???", resourceErr.Resource)
+	}
+}
+
+func TestParseError_SkipsUnparsableResource(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_instance.a, ???")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cycle.Nodes) != 1 {
+		t.Errorf("Expected 1 node, got %d", len(cycle.Nodes))
+	}
+}
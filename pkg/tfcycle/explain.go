@@ -0,0 +1,167 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EdgeReason is a hypothesized dependency with the heuristic that produced it.
+type EdgeReason struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Reason string `json:"reason"`
+}
+
+// NodeExplanation summarizes one node: the minimal cycles it belongs to, its
+// inferred edges and suggestions.
+type NodeExplanation struct {
+	Node          *CycleNode   `json:"node"`
+	Cycles        [][]string   `json:"cycles"`
+	Outgoing      []EdgeReason `json:"depends_on"`
+	Incoming      []EdgeReason `json:"depended_on_by"`
+	Suggestions   []Suggestion `json:"suggestions"`
+	Documentation string       `json:"documentation,omitempty"`
+}
+
+const fallbackEdgeReason = "adjacent in Terraform's cycle listing (no heuristic matched)"
+
+// MatchNodes returns the nodes matching address exactly or, failing that, the
+// nodes whose address ends with it or matches it without an instance key.
+func (ca *CycleAnalyzer) MatchNodes(address string) []*CycleNode {
+	query := strings.ReplaceAll(strings.TrimSpace(address), `"`, "")
+	
+	var exact, partial []*CycleNode
+	for _, node := range ca.cycle.Nodes {
+		name := node.FullName()
+		withoutKey := name
+		if i := strings.Index(name, "["); i >= 0 {
+			withoutKey = name[:i]
+		}
+		
+		switch {
+		case name == query:
+			exact = append(exact, node)
+		case withoutKey == query, strings.HasSuffix(name, "."+query), strings.HasSuffix(withoutKey, "."+query):
+			partial = append(partial, node)
+		}
+	}
+	
+	if len(exact) > 0 {
+		return exact
+	}
+	return partial
+}
+
+// ExplainNode explains node's part in the cycle.
+func (ca *CycleAnalyzer) ExplainNode(node *CycleNode) NodeExplanation {
+	name := node.FullName()
+	explanation := NodeExplanation{
+		Node:          node,
+		Cycles:        [][]string{},
+		Outgoing:      []EdgeReason{},
+		Incoming:      []EdgeReason{},
+		Documentation: NodeDocURL(node),
+	}
+	
+	for _, cycle := range ca.FindMinimalCycles() {
+		for _, member := range cycle {
+			if member == name {
+				explanation.Cycles = append(explanation.Cycles, cycle)
+				break
+			}
+		}
+	}
+	
+	graph := ca.HypothesizedGraph()
+	for _, to := range graph[name] {
+		explanation.Outgoing = append(explanation.Outgoing, ca.edgeReason(name, to))
+	}
+	for _, from := range ca.NodeNames() {
+		for _, to := range graph[from] {
+			if to == name {
+				explanation.Incoming = append(explanation.Incoming, ca.edgeReason(from, to))
+			}
+		}
+	}
+	
+	seen := make(map[string]bool)
+	if node.Destroying() && ca.showsSuggestion("TFC-DESTROY-001") {
+		seen["create-before-destroy"] = true
+		explanation.Suggestions = append(explanation.Suggestions, Suggestion{
+			ID:       "create-before-destroy",
+			RuleID:   "TFC-DESTROY-001",
+			Title:    fmt.Sprintf("Add lifecycle { create_before_destroy = true } to %s.%s", node.ResourceType, node.ResourceName),
+			Severity: SeverityHigh,
+			Effort:   EffortLow,
+			Docs:     []string{lifecycleDocURL},
+			Snippet:  createBeforeDestroySnippet(node),
+		})
+	}
+	for _, cycle := range explanation.Cycles {
+		for _, suggestion := range ca.GenerateSuggestions(cycle) {
+			if !seen[suggestion.ID] {
+				seen[suggestion.ID] = true
+				explanation.Suggestions = append(explanation.Suggestions, suggestion)
+			}
+		}
+	}
+	if explanation.Suggestions == nil {
+		explanation.Suggestions = []Suggestion{}
+	}
+	
+	return explanation
+}
+
+func (ca *CycleAnalyzer) edgeReason(from, to string) EdgeReason {
+	if ca.resolvedVerdicts()[Edge{From: from, To: to}] == EdgeConfirmed {
+		return EdgeReason{From: from, To: to, Reason: confirmedEdgeReason}
+	}
+	
+	if ca.cycle.Terragrunt() {
+		return EdgeReason{From: from, To: to, Reason: terragruntEdgeReason}
+	}
+	
+	reason := fallbackEdgeReason
+	fromNode := ca.cycle.GetNodeByName(from)
+	toNode := ca.cycle.GetNodeByName(to)
+	if fromNode != nil && toNode != nil && fromNode.stackComponent() && toNode.stackComponent() {
+		return EdgeReason{From: from, To: to, Reason: stackEdgeReason(fromNode, toNode)}
+	}
+	if fromNode != nil && toNode != nil {
+		if r := ca.dependencyReason(fromNode, toNode); r != "" {
+			reason = r
+		}
+	}
+	return EdgeReason{From: from, To: to, Reason: reason}
+}
+
+// ParseInput parses input of any format detected by DetectInputFormat:
+// Terraform output, its JSON output, a graph, plan or state, or a JSON report
+// previously produced by FormatAsJSON.
+func ParseInput(text string) (*TfCycle, error) {
+	return NewParser().ParseFormat(text, InputAuto)
+}
+
+// IsCycleInput reports whether text contains a cycle error, a graph, plan or
+// state with a cycle, or looks like a JSON report that ParseInput accepts.
+func IsCycleInput(text string) bool {
+	return NewParser().ContainsCycleFormat(text, InputAuto)
+}
+
+func parseAnalysisJSON(text string) (*TfCycle, error) {
+	var analysis struct {
+		Cycle *TfCycle `json:"cycle"`
+	}
+	if err := json.Unmarshal([]byte(text), &analysis); err != nil {
+		return nil, fmt.Errorf("failed to parse analysis JSON: %w", err)
+	}
+	if analysis.Cycle == nil || len(analysis.Cycle.Nodes) == 0 {
+		return nil, fmt.Errorf("%w: analysis JSON contains no cycle nodes", ErrNoCycleFound)
+	}
+	return analysis.Cycle, nil
+}
+
+func looksLikeJSON(text string) bool {
+	return strings.HasPrefix(strings.TrimSpace(text), "{")
+}
@@ -0,0 +1,86 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+const explainTestError = `Error: Cycle: aws_security_group.sg_ping, aws_security_group.sg_8080, aws_instance.web (destroy)`
+
+func TestExplainNode_SecurityGroup(t *testing.T) {
+	cycle, err := NewParser().ParseError(explainTestError)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	nodes := analyzer.MatchNodes("sg_8080")
+	if len(nodes) != 1 {
+		t.Fatalf("Expected 1 matching node, got %d", len(nodes))
+	}
+	
+	explanation := analyzer.ExplainNode(nodes[0])
+	if len(explanation.Cycles) == 0 {
+		t.Errorf("Expected sg_8080 to be part of a minimal cycle")
+	}
+	
+	found := false
+	for _, edge := range explanation.Incoming {
+		if edge.From == "aws_instance.web" && strings.Contains(edge.Reason, "vpc_security_group_ids") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected incoming edge from aws_instance.web with a reason, got %+v", explanation.Incoming)
+	}
+}
+
+func TestExplainNode_DestroySuggestion(t *testing.T) {
+	cycle, _ := NewParser().ParseError(explainTestError)
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	nodes := analyzer.MatchNodes("aws_instance.web")
+	if len(nodes) != 1 {
+		t.Fatalf("Expected 1 matching node, got %d", len(nodes))
+	}
+	
+	explanation := analyzer.ExplainNode(nodes[0])
+	if len(explanation.Suggestions) == 0 || !strings.Contains(explanation.Suggestions[0].Title, "create_before_destroy") {
+		t.Errorf("Expected create_before_destroy suggestion first, got %v", explanation.Suggestions)
+	}
+}
+
+func TestMatchNodes_InstanceKeys(t *testing.T) {
+	cycle, _ := NewParser().ParseError(`Error: Cycle: aws_subnet.private[0], aws_subnet.private[1], aws_route_table.main`)
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	if nodes := analyzer.MatchNodes("aws_subnet.private"); len(nodes) != 2 {
+		t.Errorf("Expected 2 instances, got %d", len(nodes))
+	}
+	if nodes := analyzer.MatchNodes("aws_subnet.private[1]"); len(nodes) != 1 {
+		t.Errorf("Expected 1 exact match, got %d", len(nodes))
+	}
+	if nodes := analyzer.MatchNodes("aws_vpc.main"); len(nodes) != 0 {
+		t.Errorf("Expected no matches, got %d", len(nodes))
+	}
+}
+
+func TestParseAnalysisJSON(t *testing.T) {
+	cycle, _ := NewParser().ParseError(explainTestError)
+	formatter := NewOutputFormatter(NewCycleAnalyzer(cycle), false)
+	report, err := formatter.FormatAsJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	if !looksLikeJSON(report) {
+		t.Fatalf("Expected JSON report to be detected")
+	}
+	parsed, err := parseAnalysisJSON(report)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(parsed.Nodes) != 3 {
+		t.Errorf("Expected 3 nodes, got %d", len(parsed.Nodes))
+	}
+}
@@ -0,0 +1,104 @@
+package tfcycle
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const confirmedEdgeReason = "confirmed with tfcycle feedback"
+
+// EdgeVerdicts holds recorded answers about hypothesized edges, for example
+// from earlier runs of tfcycle feedback. Addresses may be given in Terraform
+// syntax or as node names.
+type EdgeVerdicts map[Edge]EdgeAnswer
+
+var edgeAnswerNames = map[EdgeAnswer]string{
+	EdgeUnsure:    "unsure",
+	EdgeConfirmed: "confirmed",
+	EdgeRejected:  "rejected",
+}
+
+func (a EdgeAnswer) String() string {
+	return edgeAnswerNames[a]
+}
+
+// MarshalText encodes the answer as its name.
+func (a EdgeAnswer) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an answer name.
+func (a *EdgeAnswer) UnmarshalText(text []byte) error {
+	for answer, name := range edgeAnswerNames {
+		if name == string(text) {
+			*a = answer
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown edge answer %q (use confirmed, rejected or unsure)", text)
+}
+
+// ParseEdge parses an edge written as "FROM -> TO".
+func ParseEdge(s string) (Edge, error) {
+	from, to, ok := strings.Cut(s, "->")
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if !ok || from == "" || to == "" || strings.Contains(to, "->") {
+		return Edge{}, fmt.Errorf("invalid edge %q, expected \"FROM -> TO\"", s)
+	}
+	return Edge{From: from, To: to}, nil
+}
+
+func (e Edge) String() string {
+	return e.From + " -> " + e.To
+}
+
+// applyVerdicts adds the confirmed edges between nodes of the cycle to graph
+// and removes the rejected ones.
+func (ca *CycleAnalyzer) applyVerdicts(graph map[string][]string) {
+	verdicts := ca.resolvedVerdicts()
+	edges := make([]Edge, 0, len(verdicts))
+	for edge := range verdicts {
+		edges = append(edges, edge)
+	}
+	slices.SortFunc(edges, func(a, b Edge) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	
+	for _, edge := range edges {
+		switch verdicts[edge] {
+		case EdgeConfirmed:
+			if !slices.Contains(graph[edge.From], edge.To) {
+				graph[edge.From] = append(graph[edge.From], edge.To)
+			}
+		case EdgeRejected:
+			graph[edge.From] = slices.DeleteFunc(graph[edge.From], func(to string) bool {
+				return to == edge.To
+			})
+		}
+	}
+}
+
+// resolvedVerdicts returns the verdicts about edges between nodes of the
+// cycle, keyed by node names.
+func (ca *CycleAnalyzer) resolvedVerdicts() EdgeVerdicts {
+	if len(ca.verdicts) == 0 {
+		return nil
+	}
+	
+	names := make(map[string]string, 2*len(ca.cycle.Nodes))
+	for _, node := range ca.cycle.Nodes {
+		names[node.FullName()] = node.FullName()
+		names[node.Address()] = node.FullName()
+	}
+	
+	resolved := make(EdgeVerdicts)
+	for edge, answer := range ca.verdicts {
+		from, to := names[edge.From], names[edge.To]
+		if from == "" || to == "" || from == to {
+			continue
+		}
+		resolved[Edge{From: from, To: to}] = answer
+	}
+	return resolved
+}
@@ -0,0 +1,74 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestParseEdge(t *testing.T) {
+	edge, err := ParseEdge(` aws_instance.web["a"] ->aws_security_group.web `)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if edge.From != `aws_instance.web["a"]` || edge.To != "aws_security_group.web" {
+		t.Errorf("Expected the trimmed addresses, got %+v", edge)
+	}
+	
+	for _, input := range []string{"aws_instance.web", "-> aws_instance.web", "a -> b -> c"} {
+		if _, err := ParseEdge(input); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+func TestEdgeAnswer_JSON(t *testing.T) {
+	data, err := json.Marshal(map[string]EdgeAnswer{"verdict": EdgeRejected})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if string(data) != `{"verdict":"rejected"}` {
+		t.Errorf("Expected the answer's name, got %s", data)
+	}
+	
+	var answer EdgeAnswer
+	if err := json.Unmarshal([]byte(`"confirmed"`), &answer); err != nil || answer != EdgeConfirmed {
+		t.Errorf("Expected confirmed, got %v, %v", answer, err)
+	}
+	if err := json.Unmarshal([]byte(`"maybe"`), &answer); err == nil {
+		t.Errorf("Expected an error for an unknown answer")
+	}
+}
+
+func TestWithEdgeVerdicts(t *testing.T) {
+	cycle, err := NewParser().ParseError(`Error: Cycle: aws_security_group.web, aws_security_group.db, aws_instance.app["blue"]`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	baseline := NewCycleAnalyzer(cycle).HypothesizedGraph()
+	if !slices.Contains(baseline["aws_security_group.web"], "aws_security_group.db") {
+		t.Fatalf("Expected the security group heuristic edge, got %v", baseline)
+	}
+	
+	verdicts := EdgeVerdicts{
+		{From: "aws_security_group.web", To: "aws_security_group.db"}:    EdgeRejected,
+		{From: `aws_instance.app["blue"]`, To: "aws_security_group.web"}: EdgeConfirmed,
+		{From: "aws_instance.other", To: "aws_security_group.web"}:       EdgeConfirmed,
+	}
+	analyzer := NewCycleAnalyzer(cycle, WithEdgeVerdicts(verdicts))
+	graph := analyzer.HypothesizedGraph()
+	
+	if slices.Contains(graph["aws_security_group.web"], "aws_security_group.db") {
+		t.Errorf("Expected the rejected edge to be removed, got %v", graph)
+	}
+	app := cycle.Nodes[2].FullName()
+	if !slices.Contains(graph[app], "aws_security_group.web") {
+		t.Errorf("Expected the confirmed edge from %s, got %v", app, graph)
+	}
+	if _, ok := graph["aws_instance.other"]; ok {
+		t.Errorf("Expected verdicts outside the cycle to be ignored, got %v", graph)
+	}
+	if reason := analyzer.edgeReason(app, "aws_security_group.web"); reason.Reason != confirmedEdgeReason {
+		t.Errorf("Expected the confirmed edge's reason, got %q", reason.Reason)
+	}
+}
@@ -0,0 +1,119 @@
+package tfcycle
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+var filterKeys = []string{"module", "type"}
+
+// NodeFilter restricts a cycle to nodes in matching modules or of matching
+// types and drops excluded addresses. Patterns are path.Match globs.
+type NodeFilter struct {
+	Modules []string
+	Types   []string
+	Exclude []string
+
+	OnlyDestroy bool
+}
+
+// ParseNodeFilter parses module=PATTERN and type=PATTERN filters and address
+// exclusion globs.
+func ParseNodeFilter(filters, excludes []string) (NodeFilter, error) {
+	nf := NodeFilter{Exclude: excludes}
+	for _, pattern := range excludes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nf, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok || value == "" {
+			return nf, fmt.Errorf("invalid filter %q (use KEY=VALUE with key %s)", filter, strings.Join(filterKeys, " or "))
+		}
+		if _, err := path.Match(value, ""); err != nil {
+			return nf, fmt.Errorf("invalid filter pattern %q: %w", value, err)
+		}
+		
+		switch key {
+		case "module":
+			nf.Modules = append(nf.Modules, strings.TrimPrefix(value, "module."))
+		case "type":
+			nf.Types = append(nf.Types, value)
+		default:
+			return nf, fmt.Errorf("unknown filter key %q (use %s)", key, strings.Join(filterKeys, " or "))
+		}
+	}
+	return nf, nil
+}
+
+// Empty reports whether the filter keeps every node.
+func (nf NodeFilter) Empty() bool {
+	return len(nf.Modules) == 0 && len(nf.Types) == 0 && len(nf.Exclude) == 0 && !nf.OnlyDestroy
+}
+
+// Matches reports whether node passes the module, type and destroy filters.
+func (nf NodeFilter) Matches(node *CycleNode) bool {
+	if nf.Excluded(node) {
+		return false
+	}
+	if nf.OnlyDestroy && node.Action != ActionDestroy && node.Action != ActionDestroyDeposed {
+		return false
+	}
+	if len(nf.Types) > 0 && !matchesAny(nf.Types, node.ResourceType) {
+		return false
+	}
+	if len(nf.Modules) > 0 && !nf.matchesModule(node) {
+		return false
+	}
+	return true
+}
+
+// Excluded reports whether node's address matches an exclusion glob.
+func (nf NodeFilter) Excluded(node *CycleNode) bool {
+	fullName := node.FullName()
+	localName := strings.TrimPrefix(fullName, strings.Join(node.ModulePath, ".")+".")
+	return matchesAny(nf.Exclude, fullName) || matchesAny(nf.Exclude, localName)
+}
+
+func (nf NodeFilter) matchesModule(node *CycleNode) bool {
+	var names []string
+	for i := 0; i+1 < len(node.ModulePath); i += 2 {
+		names = append(names, node.ModulePath[i+1])
+	}
+	
+	for _, pattern := range nf.Modules {
+		for i, name := range names {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+			if strings.Join(names[:i+1], ".module.") == pattern {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns a copy of cycle with only the kept nodes.
+func (nf NodeFilter) Apply(cycle *TfCycle) *TfCycle {
+	filtered := &TfCycle{RawError: cycle.RawError}
+	for _, node := range cycle.Nodes {
+		if nf.Matches(node) {
+			filtered.Nodes = append(filtered.Nodes, node)
+		}
+	}
+	logger.Debug("applied node filters", "before", len(cycle.Nodes), "after", len(filtered.Nodes))
+	return filtered
+}
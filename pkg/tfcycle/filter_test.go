@@ -0,0 +1,101 @@
+package tfcycle
+
+import "testing"
+
+func filterTestCycle(t *testing.T) *TfCycle {
+	cycle, err := NewParser().ParseError(`Error: Cycle: module.vpc.aws_security_group.a, module.vpc.module.subnets.aws_subnet.b, module.app.aws_instance.c, aws_iam_role.r`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	return cycle
+}
+
+func TestNodeFilter_Module(t *testing.T) {
+	cycle := filterTestCycle(t)
+	
+	tests := []struct {
+		filter   string
+		expected int
+	}{
+		{"module=vpc", 2},
+		{"module=module.vpc", 2},
+		{"module=subnets", 1},
+		{"module=vpc.module.subnets", 1},
+		{"module=a*", 1},
+	}
+	
+	for _, test := range tests {
+		filter, err := ParseNodeFilter([]string{test.filter}, nil)
+		if err != nil {
+			t.Fatalf("Expected no error for %s, got: %v", test.filter, err)
+		}
+		if got := len(filter.Apply(cycle).Nodes); got != test.expected {
+			t.Errorf("Expected %d nodes for %s, got %d", test.expected, test.filter, got)
+		}
+	}
+}
+
+func TestNodeFilter_TypeAndModuleCombined(t *testing.T) {
+	cycle := filterTestCycle(t)
+	
+	filter, err := ParseNodeFilter([]string{"module=vpc", "type=aws_security_group", "type=aws_iam_*"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	filtered := filter.Apply(cycle)
+	if len(filtered.Nodes) != 1 || filtered.Nodes[0].ResourceName != "a" {
+		t.Errorf("Expected only module.vpc.aws_security_group.a, got %v", filtered.Nodes)
+	}
+	if filtered.RawError != cycle.RawError {
+		t.Errorf("Expected raw error to be preserved")
+	}
+}
+
+func TestParseNodeFilter_Invalid(t *testing.T) {
+	for _, filter := range []string{"vpc", "region=us-east-1", "type=", "type=[a"} {
+		if _, err := ParseNodeFilter([]string{filter}, nil); err == nil {
+			t.Errorf("Expected error for filter %q", filter)
+		}
+	}
+}
+
+func TestNodeFilter_Exclude(t *testing.T) {
+	cycle, _ := NewParser().ParseError(`Error: Cycle: aws_security_group.a, null_resource.wait, module.app.time_sleep.delay, module.app.aws_instance.c`)
+	
+	filter, err := ParseNodeFilter(nil, []string{"null_resource.*", "time_sleep.*"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	filtered := filter.Apply(cycle)
+	if len(filtered.Nodes) != 2 {
+		t.Fatalf("Expected 2 nodes after exclusion, got %d", len(filtered.Nodes))
+	}
+	for _, node := range filtered.Nodes {
+		if node.ResourceType == "null_resource" || node.ResourceType == "time_sleep" {
+			t.Errorf("Expected %s to be excluded", node.FullName())
+		}
+	}
+	
+	if _, err := ParseNodeFilter(nil, []string{"[bad"}); err == nil {
+		t.Errorf("Expected error for invalid exclude pattern")
+	}
+}
+
+func TestNodeFilter_OnlyDestroy(t *testing.T) {
+	cycle, _ := NewParser().ParseError(`Error: Cycle: aws_instance.a (destroy), aws_security_group.b, aws_lb.c (destroy deposed 1a2b3c4d), aws_subnet.d (expand)`)
+	
+	filter := NodeFilter{OnlyDestroy: true}
+	if filter.Empty() {
+		t.Errorf("Expected only-destroy filter not to be empty")
+	}
+	
+	filtered := filter.Apply(cycle)
+	if len(filtered.Nodes) != 2 {
+		t.Fatalf("Expected 2 destroy nodes, got %d", len(filtered.Nodes))
+	}
+	if filtered.Nodes[0].ResourceName != "a" || filtered.Nodes[1].ResourceName != "c" {
+		t.Errorf("Expected aws_instance.a and aws_lb.c, got %v", filtered.Nodes)
+	}
+}
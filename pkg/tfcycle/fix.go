@@ -0,0 +1,717 @@
+package tfcycle
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Fix is a proposed configuration change that breaks a cycle, as an HCL
+// snippet, a unified diff or a module move. Edits apply the whole change
+// where it can be applied automatically: the changes of Diff plus, for fixes
+// that add blocks, the Snippet.
+type Fix struct {
+	Kind        string    `json:"kind"`
+	Title       string    `json:"title"`
+	Address     string    `json:"address"`
+	File        string    `json:"file,omitempty"`
+	Line        int       `json:"line,omitempty"`
+	Description string    `json:"description"`
+	Snippet     string    `json:"snippet,omitempty"`
+	Diff        string    `json:"diff,omitempty"`
+	Edits       []FixEdit `json:"edits,omitempty"`
+	Moves       *MovePlan `json:"moves,omitempty"`
+}
+
+// FixEdit replaces Count lines of File, starting at Line (counted from 1),
+// with Lines. A Count of 0 inserts Lines before Line. File is relative to the
+// configuration directory, as Fix.File is.
+type FixEdit struct {
+	File  string   `json:"file"`
+	Line  int      `json:"line"`
+	Count int      `json:"count"`
+	Lines []string `json:"lines"`
+}
+
+// Fixer derives fixes for recognized cycle patterns from the configuration.
+type Fixer struct {
+	analyzer *CycleAnalyzer
+	index    *ConfigIndex
+}
+
+// NewFixer returns a Fixer for the cycles found by analyzer in the
+// configuration scanned into index.
+func NewFixer(analyzer *CycleAnalyzer, index *ConfigIndex) *Fixer {
+	return &Fixer{
+		analyzer: analyzer,
+		index:    index,
+	}
+}
+
+var fixKinds = []string{"security_group_rule", "depends_on", "create_before_destroy", "data_source", "split", "module_refactor"}
+
+// FixKinds returns the names of the fix kinds, which disabled_rules accepts.
+func FixKinds() []string {
+	return append([]string{}, fixKinds...)
+}
+
+// ValidateFixKinds returns an error for names that are not fix kinds.
+func ValidateFixKinds(kinds []string) error {
+	for _, kind := range kinds {
+		known := false
+		for _, candidate := range fixKinds {
+			if kind == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown rule %q (known rules: %s)", kind, strings.Join(fixKinds, ", "))
+		}
+	}
+	return nil
+}
+
+// FilterFixes drops fixes whose Kind is in disabled.
+func FilterFixes(fixes []Fix, disabled []string) []Fix {
+	if len(disabled) == 0 {
+		return fixes
+	}
+	
+	skip := make(map[string]bool)
+	for _, kind := range disabled {
+		skip[kind] = true
+	}
+	
+	var kept []Fix
+	for _, fix := range fixes {
+		if !skip[fix.Kind] {
+			kept = append(kept, fix)
+		}
+	}
+	return kept
+}
+
+// GenerateFixes returns the fixes for every minimal cycle.
+func (f *Fixer) GenerateFixes() []Fix {
+	if f.analyzer.cycle.Terragrunt() || f.analyzer.cycle.Stack() {
+		return nil
+	}
+	
+	var fixes []Fix
+	seen := make(map[string]bool)
+	
+	for _, cycle := range f.analyzer.FindMinimalCycles() {
+		for _, fix := range f.fixesForCycle(cycle) {
+			key := fix.Kind + ":" + fix.Address
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			fixes = append(fixes, fix)
+		}
+	}
+	
+	return fixes
+}
+
+func (f *Fixer) fixesForCycle(cycle []string) []Fix {
+	var fixes []Fix
+	
+	var nodes []*CycleNode
+	for _, nodeName := range cycle {
+		if node := f.analyzer.cycle.GetNodeByName(nodeName); node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	
+	for _, node := range nodes {
+		if node.ResourceType != "aws_security_group" {
+			continue
+		}
+		if fix, ok := f.securityGroupRuleFix(node, nodes); ok {
+			fixes = append(fixes, fix)
+		}
+	}
+	for _, node := range nodes {
+		if fix, ok := f.dependsOnFix(node, nodes); ok {
+			fixes = append(fixes, fix)
+		}
+	}
+	structural := len(fixes) > 0
+	
+	for _, node := range nodes {
+		if !node.Destroying() {
+			continue
+		}
+		if fix, ok := f.lifecycleFix(node); ok {
+			fixes = append(fixes, fix)
+		}
+	}
+	
+	if !structural {
+		if fix, ok := f.dataSourceFix(nodes); ok {
+			fixes = append(fixes, fix)
+		}
+		if fix, ok := f.splitFix(cycle, nodes); ok {
+			fixes = append(fixes, fix)
+		}
+		if fix, ok := f.moduleRefactorFix(cycle); ok {
+			fixes = append(fixes, fix)
+		}
+	}
+	
+	return fixes
+}
+
+func (f *Fixer) securityGroupRuleFix(node *CycleNode, cycleNodes []*CycleNode) (Fix, bool) {
+	block := f.index.Lookup(node)
+	if block == nil {
+		return Fix{}, false
+	}
+	
+	var peers []string
+	for _, other := range cycleNodes {
+		if other.ResourceType == "aws_security_group" && other.ResourceName != node.ResourceName {
+			peers = append(peers, other.ResourceType+"."+other.ResourceName)
+		}
+	}
+	
+	var inline []NestedBlock
+	referencesPeer := false
+	for _, direction := range []string{"ingress", "egress"} {
+		for _, nested := range block.NestedBlocks(direction) {
+			inline = append(inline, nested)
+			text := strings.Join(nested.Lines, "\n")
+			for _, peer := range peers {
+				if referencesAddress(text, peer) {
+					referencesPeer = true
+				}
+			}
+		}
+	}
+	
+	if len(inline) == 0 || !referencesPeer {
+		return Fix{}, false
+	}
+	
+	var snippet strings.Builder
+	counts := make(map[string]int)
+	for _, nested := range inline {
+		for _, rule := range convertInlineRule(block.Name, nested, counts) {
+			snippet.WriteString(rule)
+			snippet.WriteString("\n")
+		}
+	}
+	
+	lines := f.index.FileLines(block.File)
+	edits := removeLinesEdits(lines, inline)
+	diff := unifiedDiff(f.relPath(block.File), lines, edits)
+	added := lineEdit{start: block.EndLine, end: block.EndLine, replacement: append([]string{""}, strings.Split(strings.TrimRight(snippet.String(), "\n"), "\n")...)}
+	
+	return Fix{
+		Kind:    "security_group_rule",
+		Title:   fmt.Sprintf("Move inline rules of %s into aws_security_group_rule resources", block.Address()),
+		Address: block.Address(),
+		File:    f.relPath(block.File),
+		Line:    block.StartLine,
+		Description: "Inline ingress/egress blocks make each security group depend on the other. " +
+			"Standalone rule resources depend on both groups, so the groups themselves no longer reference each other. " +
+			"All inline rules of the group are moved because AWS does not allow mixing inline rules with rule resources.",
+		Snippet: strings.TrimRight(snippet.String(), "\n"),
+		Diff:    diff,
+		Edits:   fixEdits(f.relPath(block.File), append(edits, added)),
+	}, true
+}
+
+func (f *Fixer) lifecycleFix(node *CycleNode) (Fix, bool) {
+	block := f.index.Lookup(node)
+	if block == nil {
+		return Fix{}, false
+	}
+	
+	lines := f.index.FileLines(block.File)
+	indent := blockIndent(block)
+	edit := lineEdit{}
+	
+	lifecycles := block.NestedBlocks("lifecycle")
+	if len(lifecycles) > 0 {
+		lifecycle := lifecycles[0]
+		value, ok := lifecycle.Attributes()["create_before_destroy"]
+		if value == "true" {
+			return Fix{}, false
+		}
+		
+		innerIndent := indent + indent
+		if lifecycle.StartLine == lifecycle.EndLine {
+			// Expand a one-line block so that the attribute is merged into
+			// it rather than added after its closing brace.
+			header := lifecycle.Lines[0]
+			outer := leadingWhitespace(header)
+			opening := outer + "lifecycle {"
+			if comment := strings.TrimSpace(header[len(stripComment(header)):]); comment != "" {
+				opening += " " + comment
+			}
+			replacement := []string{opening}
+			if body := lifecycle.InlineBody(); body != "" && !ok {
+				replacement = append(replacement, outer+indent+body)
+			}
+			replacement = append(replacement, outer+indent+"create_before_destroy = true", outer+"}")
+			edit = lineEdit{start: lifecycle.StartLine - 1, end: lifecycle.StartLine, replacement: replacement}
+		} else if ok {
+			for i, line := range lifecycle.Lines {
+				if matches := attributeRegex.FindStringSubmatch(line); matches != nil && matches[1] == "create_before_destroy" {
+					lineIndex := lifecycle.StartLine - 1 + i
+					innerIndent = leadingWhitespace(line)
+					edit = lineEdit{start: lineIndex, end: lineIndex + 1}
+					break
+				}
+			}
+		} else {
+			if len(lifecycle.Lines) > 2 {
+				innerIndent = leadingWhitespace(lifecycle.Lines[1])
+			}
+			edit = lineEdit{start: lifecycle.StartLine, end: lifecycle.StartLine}
+		}
+		if edit.replacement == nil {
+			edit.replacement = []string{innerIndent + "create_before_destroy = true"}
+		}
+	} else {
+		closing := block.EndLine - 1
+		replacement := []string{
+			indent + "lifecycle {",
+			indent + indent + "create_before_destroy = true",
+			indent + "}",
+		}
+		if closing > block.StartLine-1 && strings.TrimSpace(lines[closing-1]) != "" {
+			replacement = append([]string{""}, replacement...)
+		}
+		edit = lineEdit{start: closing, end: closing, replacement: replacement}
+	}
+	
+	return Fix{
+		Kind:    "create_before_destroy",
+		Title:   fmt.Sprintf("Add create_before_destroy to %s", block.Address()),
+		Address: block.Address(),
+		File:    f.relPath(block.File),
+		Line:    block.StartLine,
+		Description: "The resource is destroyed as part of the cycle. Creating the replacement before destroying " +
+			"the old object reverses the destroy edge that closes the loop.",
+		Snippet: strings.Join(edit.replacement, "\n"),
+		Diff:    unifiedDiff(f.relPath(block.File), lines, []lineEdit{edit}),
+		Edits:   fixEdits(f.relPath(block.File), []lineEdit{edit}),
+	}, true
+}
+
+func (f *Fixer) dependsOnFix(node *CycleNode, cycleNodes []*CycleNode) (Fix, bool) {
+	block := f.index.Lookup(node)
+	if block == nil {
+		return Fix{}, false
+	}
+	
+	attr, ok := block.DependsOn()
+	if !ok {
+		return Fix{}, false
+	}
+	
+	peers := make(map[string]bool)
+	for _, other := range cycleNodes {
+		if other != node && strings.Join(other.ModulePath, ".") == strings.Join(node.ModulePath, ".") {
+			peers[other.ResourceType+"."+other.ResourceName] = true
+		}
+	}
+	
+	var closing, kept []string
+	for _, entry := range attr.Entries {
+		if peers[entry] {
+			closing = append(closing, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	if len(closing) == 0 {
+		return Fix{}, false
+	}
+	
+	implicit := block.WithoutLines(attr.StartLine, attr.EndLine)
+	var redundant, explicitOnly []string
+	for _, entry := range closing {
+		if referencesAddress(implicit, entry) {
+			redundant = append(redundant, entry)
+		} else {
+			explicitOnly = append(explicitOnly, entry)
+		}
+	}
+	
+	var description strings.Builder
+	description.WriteString(fmt.Sprintf("%s declares depends_on on %s, which is part of the same cycle.", block.Address(), strings.Join(closing, ", ")))
+	if len(redundant) > 0 {
+		description.WriteString(fmt.Sprintf(" The entry for %s is redundant: the block already references it in an expression, so Terraform infers the dependency on its own. Removing it tidies the configuration, but the expression reference must also be broken to resolve the cycle.", strings.Join(redundant, ", ")))
+	}
+	if len(explicitOnly) > 0 {
+		description.WriteString(fmt.Sprintf(" The entry for %s is the only link between these resources, so it is the edge that closes the loop; remove it if the ordering is not actually required.", strings.Join(explicitOnly, ", ")))
+	}
+	
+	lines := f.index.FileLines(block.File)
+	edit := lineEdit{start: attr.StartLine - 1, end: attr.EndLine}
+	if len(kept) > 0 {
+		edit.replacement = []string{fmt.Sprintf("%sdepends_on = [%s]", leadingWhitespace(lines[attr.StartLine-1]), strings.Join(kept, ", "))}
+	}
+	
+	return Fix{
+		Kind:        "depends_on",
+		Title:       fmt.Sprintf("Remove depends_on %s from %s", strings.Join(closing, ", "), block.Address()),
+		Address:     block.Address(),
+		File:        f.relPath(block.File),
+		Line:        attr.StartLine,
+		Description: description.String(),
+		Diff:        unifiedDiff(f.relPath(block.File), lines, []lineEdit{edit}),
+		Edits:       fixEdits(f.relPath(block.File), []lineEdit{edit}),
+	}, true
+}
+
+func (f *Fixer) dataSourceFix(nodes []*CycleNode) (Fix, bool) {
+	var fallback *Fix
+	
+	for i, node := range nodes {
+		if node.DataSource || !HasDataSource(node.ResourceType) || len(nodes) < 2 {
+			continue
+		}
+		
+		referrer := nodes[(i-1+len(nodes))%len(nodes)]
+		snippet, ok := GenerateDataSource(node, f.index.Lookup(node))
+		if !ok {
+			continue
+		}
+		
+		resourceAddr := node.ResourceType + "." + node.ResourceName
+		fix := Fix{
+			Kind:    "data_source",
+			Title:   fmt.Sprintf("Look up %s with a data source", node.FullName()),
+			Address: node.FullName(),
+			Description: fmt.Sprintf("If %s already exists or is managed elsewhere, reading it through a data source "+
+				"removes the dependency of %s on the managed resource. Replace %s with %s in the referencing expressions.",
+				resourceAddr, referrer.FullName(), resourceAddr, snippet.Address),
+			Snippet: snippet.Block,
+		}
+		
+		if block := f.index.Lookup(node); block != nil {
+			fix.File = f.relPath(block.File)
+			fix.Line = block.StartLine
+		}
+		
+		referrerBlock := f.index.Lookup(referrer)
+		if referrerBlock != nil && strings.Join(referrer.ModulePath, ".") == strings.Join(node.ModulePath, ".") {
+			path, lines := f.relPath(referrerBlock.File), f.index.FileLines(referrerBlock.File)
+			if edits := replaceReferencesEdits(lines, referrerBlock, snippet.Replacement); len(edits) > 0 {
+				fix.Diff = unifiedDiff(path, lines, edits)
+				added := lineEdit{start: referrerBlock.EndLine, end: referrerBlock.EndLine, replacement: append([]string{""}, strings.Split(strings.TrimRight(snippet.Block, "\n"), "\n")...)}
+				fix.Edits = fixEdits(path, append(edits, added))
+				return fix, true
+			}
+		}
+		
+		if fallback == nil {
+			fallback = &fix
+		}
+	}
+	
+	if fallback != nil {
+		return *fallback, true
+	}
+	return Fix{}, false
+}
+
+func (f *Fixer) splitFix(cycle []string, nodes []*CycleNode) (Fix, bool) {
+	if len(nodes) < 2 {
+		return Fix{}, false
+	}
+	
+	inCycle := make(map[string]bool)
+	for _, nodeName := range cycle {
+		inCycle[nodeName] = true
+	}
+	
+	inDegree := make(map[string]int)
+	graph := f.analyzer.HypothesizedGraph()
+	for _, from := range cycle {
+		for _, to := range graph[from] {
+			if inCycle[to] {
+				inDegree[to]++
+			}
+		}
+	}
+	
+	shared := nodes[0]
+	for _, node := range nodes[1:] {
+		if inDegree[node.FullName()] > inDegree[shared.FullName()] {
+			shared = node
+		}
+	}
+	
+	var moving []*CycleNode
+	for _, node := range nodes {
+		if node.ResourceType == shared.ResourceType && node.ResourceName == shared.ResourceName &&
+			strings.Join(node.ModulePath, ".") == strings.Join(shared.ModulePath, ".") {
+			moving = append(moving, node)
+		}
+	}
+	
+	plan := NewMovePlan(moving, "split_"+shared.ResourceName)
+	
+	// moved blocks need Terraform 1.1; when the version is known only the
+	// variant that works with it is shown.
+	var snippet strings.Builder
+	switch core := f.analyzer.cycle.Versions.Version(ToolTerraform); {
+	case core != "" && compareVersions(core, "1.1") >= 0:
+		snippet.WriteString(fmt.Sprintf("# Move the resource block into the new module %q, then add:\n", plan.Module))
+		snippet.WriteString(plan.MovedBlocks())
+	case core != "":
+		snippet.WriteString(fmt.Sprintf("# Move the resource block into the new module %q, then run:\n", plan.Module))
+		for _, command := range plan.StateMvCommands(shared.ModulePath) {
+			snippet.WriteString(command + "\n")
+		}
+	default:
+		snippet.WriteString(fmt.Sprintf("# Move the resource block into the new module %q, then add:\n", plan.Module))
+		snippet.WriteString(plan.MovedBlocks())
+		snippet.WriteString("\n# Terraform < 1.1 (no moved blocks): run instead\n")
+		for _, command := range plan.StateMvCommands(shared.ModulePath) {
+			snippet.WriteString("# " + command + "\n")
+		}
+	}
+	
+	fix := Fix{
+		Kind:    "split",
+		Title:   fmt.Sprintf("Move %s into a separate module", shared.ResourceType+"."+shared.ResourceName),
+		Address: shared.FullName(),
+		Description: fmt.Sprintf("%s is the resource the rest of the cycle depends on most. Moving it into its own module "+
+			"(or a separate configuration read through data sources) separates it from the loop. The moved blocks record "+
+			"the new address so Terraform keeps the existing object instead of destroying and recreating it.", shared.FullName()),
+		Snippet: strings.TrimRight(snippet.String(), "\n"),
+		Moves:   &plan,
+	}
+	
+	if block := f.index.Lookup(shared); block != nil {
+		fix.File = f.relPath(block.File)
+		fix.Line = block.StartLine
+	}
+	
+	return fix, true
+}
+
+func (f *Fixer) moduleRefactorFix(cycle []string) (Fix, bool) {
+	refactor := f.analyzer.ModuleRefactor(cycle)
+	if refactor == nil {
+		return Fix{}, false
+	}
+	
+	plan := MovePlan{}
+	var addresses, moved []string
+	for i, move := range refactor.Blocks() {
+		prefix := ""
+		if move.Module != "" {
+			prefix = move.Module + "."
+		}
+		addresses = append(addresses, prefix+move.From)
+		moved = append(moved, fmt.Sprintf("%s to %s", prefix+move.From, move.ToModule))
+		if i < len(refactor.Moves) {
+			plan.Moves = append(plan.Moves, ResourceMove{From: prefix + move.From, To: prefix + move.To})
+		}
+	}
+	
+	var snippet strings.Builder
+	if len(refactor.Moves) == 0 {
+		snippet.WriteString(refactor.RelocationNotes())
+	} else if core := f.analyzer.cycle.Versions.Version(ToolTerraform); core != "" && compareVersions(core, "1.1") < 0 {
+		snippet.WriteString("# Move the resource blocks between the modules, then run:\n")
+		for _, command := range refactor.StateMvCommands() {
+			snippet.WriteString(command + "\n")
+		}
+		snippet.WriteString(refactor.RelocationNotes())
+	} else {
+		snippet.WriteString("# Move the resource blocks between the modules, then add:\n")
+		snippet.WriteString(refactor.MovedBlocks())
+	}
+	
+	fix := Fix{
+		Kind:    "module_refactor",
+		Title:   "Move " + strings.Join(moved, ", "),
+		Address: addresses[0],
+		Description: fmt.Sprintf("The cycle crosses module boundaries. With these resources moved, the modules depend on each other "+
+			"in one direction only and can be applied in the order %s.", strings.Join(refactor.Order, ", ")),
+		Snippet: strings.TrimRight(snippet.String(), "\n"),
+	}
+	if len(plan.Moves) > 0 {
+		fix.Moves = &plan
+	}
+	
+	if node := f.analyzer.cycle.GetNodeByName(fix.Address); node != nil {
+		if block := f.index.Lookup(node); block != nil {
+			fix.File = f.relPath(block.File)
+			fix.Line = block.StartLine
+		}
+	}
+	
+	return fix, true
+}
+
+func replaceReferencesEdits(lines []string, block *ConfigBlock, replacements map[string]string) []lineEdit {
+	froms := sortedKeys(replacements)
+	patterns := make([]*regexp.Regexp, len(froms))
+	for i, from := range froms {
+		patterns[i] = regexp.MustCompile(`(^|[^a-zA-Z0-9_.])` + regexp.QuoteMeta(from) + `([^a-zA-Z0-9_-]|$)`)
+	}
+	
+	var edits []lineEdit
+	for i := block.StartLine; i < block.EndLine-1; i++ {
+		line := lines[i]
+		updated := line
+		for j, pattern := range patterns {
+			updated = pattern.ReplaceAllString(updated, "${1}"+replacements[froms[j]]+"${2}")
+		}
+		if updated != line {
+			edits = append(edits, lineEdit{start: i, end: i + 1, replacement: []string{updated}})
+		}
+	}
+	return edits
+}
+
+func blockIndent(block *ConfigBlock) string {
+	for _, line := range block.Lines[1:] {
+		if strings.TrimSpace(line) != "" {
+			if indent := leadingWhitespace(line); indent != "" {
+				return indent
+			}
+		}
+	}
+	return "  "
+}
+
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+func (f *Fixer) relPath(file string) string {
+	rel, err := filepath.Rel(f.index.Root, file)
+	if err != nil {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+func referencesAddress(text, address string) bool {
+	pattern := `(^|[^a-zA-Z0-9_.])` + regexp.QuoteMeta(address) + `([^a-zA-Z0-9_-]|$)`
+	return regexp.MustCompile(pattern).MatchString(text)
+}
+
+func convertInlineRule(groupName string, nested NestedBlock, counts map[string]int) []string {
+	attrs := nested.Attributes()
+	
+	var sources []string
+	for _, group := range splitHCLList(attrs["security_groups"]) {
+		sources = append(sources, "source_security_group_id = "+group)
+	}
+	if attrs["self"] == "true" {
+		sources = append(sources, "self = true")
+	}
+	
+	var cidrs []string
+	for _, key := range []string{"cidr_blocks", "ipv6_cidr_blocks", "prefix_list_ids"} {
+		if value, ok := attrs[key]; ok {
+			cidrs = append(cidrs, key+" = "+value)
+		}
+	}
+	if len(cidrs) > 0 {
+		sources = append(sources, strings.Join(cidrs, "\n"))
+	}
+	
+	var rules []string
+	for _, source := range sources {
+		counts[nested.Name]++
+		name := fmt.Sprintf("%s_%s_%d", groupName, nested.Name, counts[nested.Name])
+		
+		attributes := [][2]string{
+			{"type", fmt.Sprintf("%q", nested.Name)},
+			{"security_group_id", fmt.Sprintf("aws_security_group.%s.id", groupName)},
+		}
+		for _, key := range []string{"from_port", "to_port", "protocol", "description"} {
+			if value, ok := attrs[key]; ok {
+				attributes = append(attributes, [2]string{key, value})
+			}
+		}
+		for _, line := range strings.Split(source, "\n") {
+			key, value, _ := strings.Cut(line, " = ")
+			attributes = append(attributes, [2]string{key, value})
+		}
+		
+		rule := fmt.Sprintf("resource \"aws_security_group_rule\" %q {\n", name)
+		rule += formatHCLAttributes(attributes, "  ")
+		rule += "}\n"
+		rules = append(rules, rule)
+	}
+	
+	return rules
+}
+
+func formatHCLAttributes(attributes [][2]string, indent string) string {
+	width := 0
+	for _, attr := range attributes {
+		if len(attr[0]) > width {
+			width = len(attr[0])
+		}
+	}
+	
+	var output strings.Builder
+	for _, attr := range attributes {
+		output.WriteString(fmt.Sprintf("%s%-*s = %s\n", indent, width, attr[0], attr[1]))
+	}
+	return output.String()
+}
+
+func splitHCLList(value string) []string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "[")
+	value = strings.TrimSuffix(value, "]")
+	
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func removeLinesEdits(lines []string, removed []NestedBlock) []lineEdit {
+	var edits []lineEdit
+	for _, nested := range removed {
+		start := nested.StartLine - 1
+		end := nested.EndLine
+		if start > 0 && strings.TrimSpace(lines[start-1]) == "" {
+			start--
+		}
+		edits = append(edits, lineEdit{start: start, end: end})
+	}
+	return edits
+}
+
+// fixEdits converts edits of the file at path into FixEdits, in line order.
+func fixEdits(path string, edits []lineEdit) []FixEdit {
+	sorted := slices.Clone(edits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].start < sorted[j].start
+	})
+	fixEdits := make([]FixEdit, 0, len(sorted))
+	for _, edit := range sorted {
+		lines := edit.replacement
+		if lines == nil {
+			lines = []string{}
+		}
+		fixEdits = append(fixEdits, FixEdit{File: path, Line: edit.start + 1, Count: edit.end - edit.start, Lines: lines})
+	}
+	return fixEdits
+}
@@ -0,0 +1,307 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFixer_SecurityGroupRules(t *testing.T) {
+	dir := writeTestConfig(t, map[string]string{
+		"main.tf": `resource "aws_security_group" "sg_ping" {
+  name = "sg_ping"
+
+  ingress {
+    from_port       = 8
+    to_port         = 0
+    protocol        = "icmp"
+    security_groups = [aws_security_group.sg_8080.id]
+  }
+}
+
+resource "aws_security_group" "sg_8080" {
+  name = "sg_8080"
+
+  ingress {
+    from_port       = 8080
+    to_port         = 8080
+    protocol        = "tcp"
+    security_groups = [aws_security_group.sg_ping.id]
+  }
+}
+`,
+	})
+	
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_security_group.sg_ping, aws_security_group.sg_8080")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	fixes := NewFixer(NewCycleAnalyzer(cycle), index).GenerateFixes()
+	if len(fixes) != 2 {
+		t.Fatalf("Expected 2 fixes, got %d", len(fixes))
+	}
+	
+	fix := fixes[0]
+	if fix.Kind != "security_group_rule" || fix.File != "main.tf" || fix.Line != 1 {
+		t.Errorf("Unexpected fix metadata: %+v", fix)
+	}
+	
+	if !strings.Contains(fix.Snippet, "source_security_group_id = aws_security_group.sg_8080.id") {
+		t.Errorf("Expected converted rule to reference the peer group, got:\n%s", fix.Snippet)
+	}
+	
+	if !strings.Contains(fix.Diff, "-  ingress {") || !strings.Contains(fix.Diff, "@@ -1,12 +1,5 @@") {
+		t.Errorf("Expected diff removing the inline rule, got:\n%s", fix.Diff)
+	}
+}
+
+func TestUnifiedDiff_MergesNearbyEdits(t *testing.T) {
+	lines := []string{"a", "b", "c", "d", "e", "f"}
+	diff := unifiedDiff("x.tf", lines, []lineEdit{
+		{start: 1, end: 2},
+		{start: 4, end: 4, replacement: []string{"new"}},
+	})
+	
+	expected := "--- a/x.tf\n+++ b/x.tf\n@@ -1,6 +1,6 @@\n a\n-b\n c\n d\n+new\n e\n f\n"
+	if diff != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, diff)
+	}
+}
+
+func TestFixer_CreateBeforeDestroy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name: "no lifecycle block",
+			config: `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+			expected: "@@ -1,3 +1,7 @@\n resource \"aws_instance\" \"web\" {\n   ami = \"ami-123\"\n+\n+  lifecycle {\n+    create_before_destroy = true\n+  }\n }\n",
+		},
+		{
+			name: "lifecycle without create_before_destroy",
+			config: `resource "aws_instance" "web" {
+  lifecycle {
+    prevent_destroy = true
+  }
+}
+`,
+			expected: "@@ -1,5 +1,6 @@\n resource \"aws_instance\" \"web\" {\n   lifecycle {\n+    create_before_destroy = true\n     prevent_destroy = true\n   }\n }\n",
+		},
+		{
+			name: "create_before_destroy disabled",
+			config: `resource "aws_instance" "web" {
+  lifecycle {
+    create_before_destroy = false
+  }
+}
+`,
+			expected: "@@ -1,5 +1,5 @@\n resource \"aws_instance\" \"web\" {\n   lifecycle {\n-    create_before_destroy = false\n+    create_before_destroy = true\n   }\n }\n",
+		},
+		{
+			name: "one-line lifecycle block",
+			config: `resource "aws_instance" "web" {
+  lifecycle { prevent_destroy = false }
+}
+`,
+			expected: "@@ -1,3 +1,6 @@\n resource \"aws_instance\" \"web\" {\n-  lifecycle { prevent_destroy = false }\n+  lifecycle {\n+    prevent_destroy = false\n+    create_before_destroy = true\n+  }\n }\n",
+		},
+		{
+			name: "commented lifecycle block",
+			config: `resource "aws_instance" "web" {
+  lifecycle { # keep the old instance
+    prevent_destroy = true
+  }
+}
+`,
+			expected: "@@ -1,5 +1,6 @@\n resource \"aws_instance\" \"web\" {\n   lifecycle { # keep the old instance\n+    create_before_destroy = true\n     prevent_destroy = true\n   }\n }\n",
+		},
+	}
+	
+	for _, tc := range testCases {
+		dir := writeTestConfig(t, map[string]string{"main.tf": tc.config})
+		index, err := ScanConfig(dir)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got: %v", tc.name, err)
+		}
+		
+		fixer := NewFixer(NewCycleAnalyzer(&TfCycle{}), index)
+		fix, ok := fixer.lifecycleFix(&CycleNode{ResourceType: "aws_instance", ResourceName: "web", Action: ActionDestroy})
+		if !ok {
+			t.Fatalf("%s: expected a fix", tc.name)
+		}
+		
+		expected := "--- a/main.tf\n+++ b/main.tf\n" + tc.expected
+		if fix.Diff != expected {
+			t.Errorf("%s: expected diff:\n%s\ngot:\n%s", tc.name, expected, fix.Diff)
+		}
+	}
+}
+
+func TestFixer_CreateBeforeDestroy_AlreadyEnabled(t *testing.T) {
+	configs := []string{`resource "aws_instance" "web" {
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+`, `resource "aws_instance" "web" {
+  lifecycle { create_before_destroy = true }
+}
+`}
+	
+	for _, config := range configs {
+		dir := writeTestConfig(t, map[string]string{"main.tf": config})
+		index, err := ScanConfig(dir)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		
+		fixer := NewFixer(NewCycleAnalyzer(&TfCycle{}), index)
+		if _, ok := fixer.lifecycleFix(&CycleNode{ResourceType: "aws_instance", ResourceName: "web", Action: ActionDestroy}); ok {
+			t.Errorf("Expected no fix when create_before_destroy is already enabled in:\n%s", config)
+		}
+	}
+}
+
+func TestFixer_DataSource(t *testing.T) {
+	dir := writeTestConfig(t, map[string]string{"main.tf": `resource "aws_iam_role" "app" {
+  name                = "app-role"
+  managed_policy_arns = [aws_iam_policy.app.arn]
+}
+
+resource "aws_iam_policy" "app" {
+  name   = "app-policy"
+  policy = jsonencode({ Resource = aws_iam_role.app.arn })
+}
+`})
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_iam_role.app, aws_iam_policy.app")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	var fix *Fix
+	for _, candidate := range NewFixer(NewCycleAnalyzer(cycle), index).GenerateFixes() {
+		if candidate.Kind == "data_source" {
+			fix = &candidate
+			break
+		}
+	}
+	if fix == nil {
+		t.Fatalf("Expected a data source fix")
+	}
+	
+	if !strings.Contains(fix.Snippet, `data "aws_iam_role" "app"`) || !strings.Contains(fix.Snippet, `name = "app-role"`) {
+		t.Errorf("Expected data block using the configured name, got:\n%s", fix.Snippet)
+	}
+	
+	if !strings.Contains(fix.Diff, "+  policy = jsonencode({ Resource = data.aws_iam_role.app.arn })") {
+		t.Errorf("Expected reference replacement diff, got:\n%s", fix.Diff)
+	}
+	
+	if len(fix.Edits) != 2 || fix.Edits[0].Line != 8 || fix.Edits[1].Line != 10 || fix.Edits[1].Count != 0 ||
+		!strings.Contains(strings.Join(fix.Edits[1].Lines, "\n"), `data "aws_iam_role" "app"`) {
+		t.Errorf("Expected the reference edit followed by the inserted data block, got %+v", fix.Edits)
+	}
+}
+
+func TestGenerateDataSource_TagFilter(t *testing.T) {
+	lines := []string{
+		`resource "aws_vpc" "main" {`,
+		`  cidr_block = "10.0.0.0/16"`,
+		`  tags = {`,
+		`    Name = "main-vpc"`,
+		`  }`,
+		`}`,
+	}
+	block := scanBlocks("main.tf", lines, nil)[0]
+	
+	snippet, ok := GenerateDataSource(&CycleNode{ResourceType: "aws_vpc", ResourceName: "main"}, block)
+	if !ok {
+		t.Fatalf("Expected aws_vpc to have a data source")
+	}
+	
+	if !strings.Contains(snippet.Block, `values = ["main-vpc"]`) {
+		t.Errorf("Expected tag filter from the Name tag, got:\n%s", snippet.Block)
+	}
+	
+	if _, ok := GenerateDataSource(&CycleNode{ResourceType: "null_resource", ResourceName: "x"}, nil); ok {
+		t.Errorf("Expected no data source for null_resource")
+	}
+}
+
+func TestFixer_DependsOn(t *testing.T) {
+	dir := writeTestConfig(t, map[string]string{"main.tf": `resource "aws_lambda_function" "fn" {
+  function_name = "fn"
+  depends_on    = [aws_iam_role.fn] # role must exist first
+}
+
+resource "aws_iam_role" "fn" {
+  name = "fn"
+  tags = { fn = aws_lambda_function.fn.function_name }
+}
+`})
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_lambda_function.fn, aws_iam_role.fn")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	fixes := NewFixer(NewCycleAnalyzer(cycle), index).GenerateFixes()
+	if len(fixes) == 0 || fixes[0].Kind != "depends_on" {
+		t.Fatalf("Expected a depends_on fix first, got %+v", fixes)
+	}
+	
+	fix := fixes[0]
+	if fix.Line != 3 {
+		t.Errorf("Expected fix at line 3, got %d", fix.Line)
+	}
+	
+	if !strings.Contains(fix.Description, "closes the loop") {
+		t.Errorf("Expected explanation that the entry closes the loop, got: %s", fix.Description)
+	}
+	
+	if !strings.Contains(fix.Diff, "-  depends_on    = [aws_iam_role.fn] # role must exist first\n }") {
+		t.Errorf("Expected diff removing the depends_on line, got:\n%s", fix.Diff)
+	}
+}
+
+func TestMovePlan(t *testing.T) {
+	nodes := []*CycleNode{
+		{ResourceType: "aws_iam_role", ResourceName: "app", InstanceKey: "a"},
+		{ResourceType: "aws_iam_role", ResourceName: "app", InstanceKey: "b"},
+	}
+	plan := NewMovePlan(nodes, "split_app")
+	
+	if len(plan.Moves) != 1 {
+		t.Fatalf("Expected instances to collapse into 1 move, got %d", len(plan.Moves))
+	}
+	
+	expectedBlock := "moved {\n  from = aws_iam_role.app\n  to   = module.split_app.aws_iam_role.app\n}\n"
+	if plan.MovedBlocks() != expectedBlock {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expectedBlock, plan.MovedBlocks())
+	}
+	
+	commands := plan.StateMvCommands([]string{"module", "iam"})
+	expectedCommand := "terraform state mv 'module.iam.aws_iam_role.app' 'module.iam.module.split_app.aws_iam_role.app'"
+	if len(commands) != 1 || commands[0] != expectedCommand {
+		t.Errorf("Expected %q, got %v", expectedCommand, commands)
+	}
+}
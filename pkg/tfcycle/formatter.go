@@ -0,0 +1,770 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Default limits of the text report; see SetDisplayLimits.
+const (
+	DefaultMaxCyclesShown = 3
+	DefaultMaxNodesShown  = 10
+)
+
+// OutputFormatter renders an analysis as text, JSON, Markdown, Graphviz DOT or
+// a staged apply plan.
+type OutputFormatter struct {
+	analyzer   *CycleAnalyzer
+	verbose    bool
+	messages   catalog
+	comparison *HistoryComparison
+	templates  *ReportTemplates
+	maxCycles  int
+	maxNodes   int
+}
+
+// NewOutputFormatter returns an English formatter for analyzer. verbose adds
+// the cycle details and all resources to the text report.
+func NewOutputFormatter(analyzer *CycleAnalyzer, verbose bool) *OutputFormatter {
+	return &OutputFormatter{
+		analyzer:  analyzer,
+		verbose:   verbose,
+		messages:  catalogs[defaultLanguage],
+		maxCycles: DefaultMaxCyclesShown,
+		maxNodes:  DefaultMaxNodesShown,
+	}
+}
+
+// Analyzer returns the analyzer being formatted.
+func (of *OutputFormatter) Analyzer() *CycleAnalyzer {
+	return of.analyzer
+}
+
+// SetLanguage switches the report language; see SupportedLanguages.
+func (of *OutputFormatter) SetLanguage(lang string) error {
+	messages, ok := catalogs[lang]
+	if !ok {
+		return fmt.Errorf("unsupported language %q", lang)
+	}
+	of.messages = messages
+	return nil
+}
+
+// SetDisplayLimits sets how many minimal cycles, and how many resources of
+// each, the text report shows; 0 shows all. The other formats always include
+// everything.
+func (of *OutputFormatter) SetDisplayLimits(maxCycles, maxNodes int) {
+	of.maxCycles = maxCycles
+	of.maxNodes = maxNodes
+}
+
+// SetTemplates replaces report sections with templates, see
+// LoadReportTemplates.
+func (of *OutputFormatter) SetTemplates(templates *ReportTemplates) {
+	of.templates = templates
+}
+
+// section returns a report section, rendered by the template for it if one
+// was set.
+func (of *OutputFormatter) section(name, format, defaultText string) string {
+	if of.templates == nil {
+		return defaultText
+	}
+	data := ReportSection{
+		Default:     defaultText,
+		Severity:    of.analyzer.Severity().String(),
+		Partial:     of.analyzer.Partial,
+		Terragrunt:  of.analyzer.cycle.Terragrunt(),
+		Resources:   len(of.analyzer.cycle.Nodes),
+		Cycles:      of.analyzer.FindMinimalCycles(),
+		Suggestions: []Suggestion{},
+	}
+	if len(data.Cycles) > 0 {
+		data.Suggestions = of.analyzer.GenerateSuggestions(data.Cycles[0])
+	}
+	return of.templates.render(name, format, data)
+}
+
+// SetComparison adds a comparison with an earlier analysis to the reports.
+func (of *OutputFormatter) SetComparison(comparison *HistoryComparison) {
+	of.comparison = comparison
+}
+
+// FormatAnalysis returns the human-readable report.
+func (of *OutputFormatter) FormatAnalysis() string {
+	var output strings.Builder
+	
+	output.WriteString(of.section("header", "text", of.messages.text(of.titleKey("header", "header_terragrunt"))+"\n\n"))
+	
+	if of.verbose {
+		of.writeVerboseInfo(&output)
+	}
+	
+	cycles := of.analyzer.FindMinimalCycles()
+	
+	if len(cycles) == 0 {
+		output.WriteString(of.messages.text("no_cycles") + "\n")
+		output.WriteString(of.section("footer", "text", ""))
+		return output.String()
+	}
+	
+	if of.analyzer.Partial {
+		output.WriteString(of.messages.text("partial") + "\n\n")
+	}
+	output.WriteString(of.messages.text("severity", of.analyzer.Severity()) + "\n\n")
+	if stateful := of.statefulResources(cycles); len(stateful) > 0 {
+		output.WriteString(of.messages.text("stateful", strings.Join(stateful, ", ")) + "\n\n")
+	}
+	if of.comparison != nil {
+		output.WriteString(of.comparisonSummary() + "\n\n")
+	}
+	
+	of.writeMinimalCycles(&output, cycles)
+	var suggestions strings.Builder
+	of.writeSuggestions(&suggestions, cycles)
+	output.WriteString(of.section("suggestions", "text", suggestions.String()))
+	if of.analyzer.SuggestionsShown() {
+		of.writeApplyPlan(&output, of.analyzer.StagedApplyPlan())
+	}
+	
+	if of.verbose {
+		of.writeAllResources(&output)
+	}
+	
+	output.WriteString(of.section("footer", "text", ""))
+	return output.String()
+}
+
+func (of *OutputFormatter) comparisonSummary() string {
+	since := of.comparison.PreviousTimestamp.Local().Format("2006-01-02 15:04")
+	diff := of.comparison.Diff
+	
+	switch of.comparison.Trend {
+	case "progress":
+		return of.messages.text("compare_progress", since, diff.OldNodes, diff.NewNodes)
+	case "regression":
+		return of.messages.text("compare_regression", since, diff.OldNodes, diff.NewNodes)
+	case "unchanged":
+		return of.messages.text("compare_unchanged", since, diff.NewNodes)
+	default:
+		return of.messages.text("compare_changed", since, len(diff.AddedNodes), len(diff.RemovedNodes))
+	}
+}
+
+// statefulResources returns the stateful resources of all minimal cycles,
+// each once.
+func (of *OutputFormatter) statefulResources(cycles [][]string) []string {
+	var stateful []string
+	for _, cycle := range cycles {
+		for _, name := range of.analyzer.StatefulResources(cycle) {
+			if !slices.Contains(stateful, name) {
+				stateful = append(stateful, name)
+			}
+		}
+	}
+	return stateful
+}
+
+// FormatCyclePaths returns just the minimal cycles, one per line.
+func (of *OutputFormatter) FormatCyclePaths() string {
+	var output strings.Builder
+	
+	for _, cycle := range of.analyzer.FindMinimalCycles() {
+		output.WriteString(strings.Join(cycle, " -> ") + " -> " + cycle[0] + "\n")
+	}
+	
+	return output.String()
+}
+
+// AnalysisSchemaVersion is the schema_version of FormatAsJSON reports.
+// Version 1 reports had no schema_version and encoded node actions as
+// integers; version 2 encodes them as names such as "destroy". Version 3
+// turns suggestions from strings into Suggestion objects.
+const AnalysisSchemaVersion = 3
+
+// FormatAsJSON returns the analysis as indented JSON.
+func (of *OutputFormatter) FormatAsJSON() (string, error) {
+	cycles := of.analyzer.FindMinimalCycles()
+	
+	result := map[string]interface{}{
+		"schema_version":  AnalysisSchemaVersion,
+		"cycle":           of.analyzer.cycle,
+		"minimal_cycles":  cycles,
+		"resource_types":  of.analyzer.cycle.GetResourceTypes(),
+		"total_resources": len(of.analyzer.cycle.Nodes),
+		"apply_plan":      of.analyzer.StagedApplyPlan(),
+		"severity":        of.analyzer.Severity().String(),
+	}
+	
+	if of.analyzer.Partial {
+		result["partial"] = true
+	}
+	
+	if stateful := of.statefulResources(cycles); len(stateful) > 0 {
+		result["stateful_resources"] = stateful
+	}
+	
+	if of.comparison != nil {
+		result["comparison"] = map[string]interface{}{
+			"previous_id":        of.comparison.PreviousID,
+			"previous_timestamp": of.comparison.PreviousTimestamp,
+			"trend":              of.comparison.Trend,
+			"summary":            of.comparisonSummary(),
+			"diff":               of.comparison.Diff,
+		}
+	}
+	
+	if len(cycles) > 0 {
+		result["suggestions"] = of.analyzer.GenerateSuggestions(cycles[0])
+		result["documentation"] = of.analyzer.DocumentationLinks(cycles[0])
+	}
+	
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	
+	return string(jsonData), nil
+}
+
+// titleKey returns the message key of a report title, which names Terragrunt
+// for cycles between Terragrunt units.
+func (of *OutputFormatter) titleKey(terraform, terragrunt string) string {
+	if of.analyzer.cycle.Terragrunt() {
+		return terragrunt
+	}
+	return terraform
+}
+
+// FormatAsMarkdown returns the report as Markdown, e.g. for pull request
+// comments.
+func (of *OutputFormatter) FormatAsMarkdown() string {
+	var output strings.Builder
+	
+	output.WriteString(of.section("header", "markdown", fmt.Sprintf("# %s\n\n", of.messages.text(of.titleKey("md_title", "md_terragrunt")))))
+	
+	cycles := of.analyzer.FindMinimalCycles()
+	output.WriteString(fmt.Sprintf("**%s:** %d\n\n", of.messages.text("md_total_resources"), len(of.analyzer.cycle.Nodes)))
+	
+	if len(cycles) == 0 {
+		output.WriteString(of.messages.text("md_no_cycles") + "\n")
+		output.WriteString(of.section("footer", "markdown", ""))
+		return output.String()
+	}
+	
+	for i, cycle := range cycles {
+		output.WriteString(fmt.Sprintf("## %s\n\n", of.messages.text("md_minimal_cycle", i+1, len(cycle))))
+		for j, nodeName := range cycle {
+			next := cycle[(j+1)%len(cycle)]
+			line := fmt.Sprintf("`%s`", nodeName)
+			node := of.analyzer.cycle.GetNodeByName(nodeName)
+			if node != nil && node.Action != ActionNormal {
+				line += fmt.Sprintf(" _(%s)_", node.Action.String())
+			}
+			if node != nil && node.Location != nil {
+				line += fmt.Sprintf(" [%s]", node.Location.String())
+			}
+			if node != nil && node.ConstructPath != "" {
+				line += fmt.Sprintf(" (construct `%s`)", node.ConstructPath)
+			}
+			output.WriteString(fmt.Sprintf("%d. %s → %s `%s`\n", j+1, line, of.messages.text("depends_on"), next))
+		}
+		output.WriteString("\n")
+	}
+	
+	if of.analyzer.SuggestionsShown() {
+		var suggestions strings.Builder
+		suggestions.WriteString(fmt.Sprintf("## %s\n\n", of.messages.text("md_suggestions")))
+		for _, suggestion := range of.analyzer.GenerateSuggestions(cycles[0]) {
+			suggestions.WriteString(fmt.Sprintf("- %s\n", suggestion.Title))
+			if suggestion.Detail != "" {
+				suggestions.WriteString(fmt.Sprintf("  %s\n", suggestion.Detail))
+			}
+			if suggestion.Snippet != "" {
+				suggestions.WriteString("\n  ```hcl\n")
+				for _, line := range strings.Split(strings.TrimSuffix(suggestion.Snippet, "\n"), "\n") {
+					suggestions.WriteString(strings.TrimRight("  "+line, " ") + "\n")
+				}
+				suggestions.WriteString("  ```\n")
+			}
+		}
+		suggestions.WriteString("\n")
+		output.WriteString(of.section("suggestions", "markdown", suggestions.String()))
+	}
+	
+	links := of.analyzer.DocumentationLinks(cycles[0])
+	if len(links) > 0 {
+		output.WriteString(fmt.Sprintf("## %s\n\n", of.messages.text("md_documentation")))
+		for _, resType := range sortedKeys(links) {
+			output.WriteString(fmt.Sprintf("- [`%s`](%s)\n", resType, links[resType]))
+		}
+		output.WriteString("\n")
+	}
+	
+	if of.verbose {
+		output.WriteString(fmt.Sprintf("## %s\n\n", of.messages.text("md_all_resources")))
+		output.WriteString(fmt.Sprintf("| # | %s | %s |\n", of.messages.text("md_address"), of.messages.text("md_action")))
+		output.WriteString("|---|---------|--------|\n")
+		for i, node := range of.analyzer.cycle.Nodes {
+			output.WriteString(fmt.Sprintf("| %d | `%s` | %s |\n", i+1, node.FullName(), node.Action.String()))
+		}
+		output.WriteString("\n")
+	}
+	
+	output.WriteString(of.section("footer", "markdown", ""))
+	return output.String()
+}
+
+// FormatExplanation renders a node explanation as text.
+func (of *OutputFormatter) FormatExplanation(explanation NodeExplanation) string {
+	var output strings.Builder
+	node := explanation.Node
+	
+	output.WriteString(fmt.Sprintf("🔍 %s\n", node.String()))
+	output.WriteString(fmt.Sprintf("  Type: %s\n", node.ResourceType))
+	if len(node.ModulePath) > 0 {
+		output.WriteString(fmt.Sprintf("  %s: %s\n", of.messages.text("module"), strings.Join(node.ModulePath, ".")))
+	}
+	if node.Location != nil {
+		output.WriteString(fmt.Sprintf("  📍 %s\n", node.Location.String()))
+	}
+	if node.ConstructPath != "" {
+		output.WriteString(fmt.Sprintf("  🧩 %s\n", node.ConstructPath))
+	}
+	if explanation.Documentation != "" {
+		output.WriteString(fmt.Sprintf("  📚 %s\n", explanation.Documentation))
+	}
+	output.WriteString("\n")
+	
+	output.WriteString(fmt.Sprintf("Member of %d minimal cycle(s):\n", len(explanation.Cycles)))
+	for i, cycle := range explanation.Cycles {
+		output.WriteString(fmt.Sprintf("  %d. %s → %s\n", i+1, strings.Join(cycle, " → "), cycle[0]))
+	}
+	output.WriteString("\n")
+	
+	output.WriteString("Depends on (inferred):\n")
+	of.writeEdgeReasons(&output, explanation.Outgoing, false)
+	output.WriteString("Depended on by (inferred):\n")
+	of.writeEdgeReasons(&output, explanation.Incoming, true)
+	
+	output.WriteString(of.messages.text("suggestions") + "\n")
+	for _, suggestion := range explanation.Suggestions {
+		output.WriteString(fmt.Sprintf("  • %s\n", suggestion.Title))
+	}
+	output.WriteString("\n")
+	
+	return output.String()
+}
+
+func (of *OutputFormatter) writeEdgeReasons(output *strings.Builder, edges []EdgeReason, incoming bool) {
+	if len(edges) == 0 {
+		output.WriteString("  (none)\n\n")
+		return
+	}
+	for _, edge := range edges {
+		other := edge.To
+		if incoming {
+			other = edge.From
+		}
+		output.WriteString(fmt.Sprintf("  • %s\n      %s\n", other, edge.Reason))
+	}
+	output.WriteString("\n")
+}
+
+// FormatFixes renders fixes as text.
+func (of *OutputFormatter) FormatFixes(fixes []Fix) string {
+	var output strings.Builder
+	
+	output.WriteString("🛠  PROPOSED FIXES\n\n")
+	
+	if len(fixes) == 0 {
+		output.WriteString("No automatic fixes are available for this cycle pattern.\n")
+		output.WriteString("Run 'tfcycle analyze' for general suggestions.\n")
+		return output.String()
+	}
+	
+	for i, fix := range fixes {
+		output.WriteString(fmt.Sprintf("%d. %s\n", i+1, fix.Title))
+		if fix.File != "" {
+			output.WriteString(fmt.Sprintf("   📍 %s:%d\n", fix.File, fix.Line))
+		}
+		output.WriteString(fmt.Sprintf("   %s\n\n", fix.Description))
+		
+		if fix.Snippet != "" {
+			output.WriteString(indentLines(fix.Snippet, "     "))
+			output.WriteString("\n\n")
+		}
+		
+		if fix.Diff != "" {
+			output.WriteString(indentLines(strings.TrimRight(fix.Diff, "\n"), "     "))
+			output.WriteString("\n\n")
+		}
+	}
+	
+	return output.String()
+}
+
+// FormatFixesAsJSON returns fixes as indented JSON.
+func (of *OutputFormatter) FormatFixesAsJSON(fixes []Fix) (string, error) {
+	if fixes == nil {
+		fixes = []Fix{}
+	}
+	
+	jsonData, err := json.MarshalIndent(map[string]interface{}{"fixes": fixes}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	
+	return string(jsonData), nil
+}
+
+func indentLines(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatApplyPlan returns the staged apply plan as a shell script.
+func (of *OutputFormatter) FormatApplyPlan() string {
+	plan := of.analyzer.StagedApplyPlan()
+	
+	var output strings.Builder
+	output.WriteString("#!/bin/sh\n")
+	output.WriteString("# Staged targeted applies to break the dependency cycle.\n")
+	output.WriteString("# Review each plan before confirming; -target is meant for one-off remediation.\n")
+	if len(plan.FeedbackArcs) > 0 {
+		output.WriteString("#\n# Dependencies deferred to break the cycle:\n")
+		for _, arc := range plan.FeedbackArcs {
+			output.WriteString(fmt.Sprintf("#   %s -> %s\n", arc.From, arc.To))
+		}
+	}
+	output.WriteString("\n")
+	
+	for i, stage := range plan.Stages {
+		output.WriteString(fmt.Sprintf("# Stage %d\n%s\n\n", i+1, stage.Command))
+	}
+	
+	return output.String()
+}
+
+func (of *OutputFormatter) writeApplyPlan(output *strings.Builder, plan ApplyPlan) {
+	if len(plan.Stages) < 2 {
+		return
+	}
+	
+	output.WriteString(of.messages.text("apply_plan") + "\n")
+	if len(plan.FeedbackArcs) > 0 {
+		output.WriteString("  " + of.messages.text("deferred_edges") + "\n")
+		for _, arc := range plan.FeedbackArcs {
+			output.WriteString(fmt.Sprintf("    • %s → %s\n", arc.From, arc.To))
+		}
+	}
+	for i, stage := range plan.Stages {
+		output.WriteString(fmt.Sprintf("  %d. %s\n", i+1, stage.Command))
+	}
+	output.WriteString("\n")
+}
+
+func (of *OutputFormatter) writeVerboseInfo(output *strings.Builder) {
+	output.WriteString(of.messages.text("summary") + "\n")
+	output.WriteString(of.messages.text("total_resources", len(of.analyzer.cycle.Nodes)) + "\n")
+	
+	resourceTypes := of.analyzer.cycle.GetResourceTypes()
+	output.WriteString(of.messages.text("resource_types") + "\n")
+	for resType, count := range resourceTypes {
+		output.WriteString(fmt.Sprintf("  • %s: %d\n", resType, count))
+	}
+	output.WriteString("\n")
+}
+
+func (of *OutputFormatter) writeMinimalCycles(output *strings.Builder, cycles [][]string) {
+	if len(cycles) == 1 && len(cycles[0]) == len(of.analyzer.cycle.Nodes) {
+		output.WriteString(of.messages.text("full_cycle", len(cycles[0])) + "\n")
+		of.writeCycleDetails(output, cycles[0], true)
+	} else {
+		for i, cycle := range cycles {
+			if of.maxCycles > 0 && i >= of.maxCycles {
+				output.WriteString(of.messages.text("more_cycles", len(cycles)-i) + "\n\n")
+				break
+			}
+			
+			output.WriteString(of.messages.text("minimal_cycle", i+1, len(cycle)) + "\n")
+			of.writeCycleDetails(output, cycle, false)
+		}
+	}
+}
+
+func (of *OutputFormatter) writeCycleDetails(output *strings.Builder, cycle []string, showAll bool) {
+	maxDisplay := len(cycle)
+	if !showAll && of.maxNodes > 0 && len(cycle) > of.maxNodes {
+		maxDisplay = of.maxNodes
+	}
+	
+	for i := 0; i < maxDisplay; i++ {
+		nodeName := cycle[i]
+		node := of.analyzer.cycle.GetNodeByName(nodeName)
+		
+		output.WriteString(fmt.Sprintf("  %d. %s", i+1, nodeName))
+		
+		if node != nil && node.Action != ActionNormal {
+			output.WriteString(fmt.Sprintf(" (%s)", node.Action.String()))
+		}
+		
+		if node != nil && node.Location != nil {
+			output.WriteString(fmt.Sprintf("\n     📍 %s", node.Location.String()))
+		}
+		
+		if node != nil && node.ConstructPath != "" {
+			output.WriteString(fmt.Sprintf("\n     🧩 %s", node.ConstructPath))
+		}
+		
+		if i < len(cycle)-1 {
+			nextNodeName := cycle[i+1]
+			output.WriteString(fmt.Sprintf("\n     ↳ %s %s", of.messages.text("depends_on"), nextNodeName))
+		} else {
+			output.WriteString(fmt.Sprintf("\n     ↳ %s %s", of.messages.text("depends_on"), cycle[0]))
+		}
+		output.WriteString("\n")
+	}
+	
+	if len(cycle) > maxDisplay {
+		output.WriteString("     " + of.messages.text("more_resources", len(cycle)-maxDisplay) + "\n")
+	}
+	
+	output.WriteString("\n")
+}
+
+func (of *OutputFormatter) writeSuggestions(output *strings.Builder, cycles [][]string) {
+	if len(cycles) == 0 {
+		return
+	}
+	
+	if of.analyzer.SuggestionsShown() {
+		output.WriteString(of.messages.text("suggestions") + "\n")
+		for _, suggestion := range of.analyzer.GenerateSuggestions(cycles[0]) {
+			output.WriteString(fmt.Sprintf("  • %s\n", suggestion.Title))
+		}
+		output.WriteString("\n")
+	}
+	
+	links := of.analyzer.DocumentationLinks(cycles[0])
+	if len(links) > 0 {
+		output.WriteString(of.messages.text("documentation") + "\n")
+		for _, resType := range sortedKeys(links) {
+			output.WriteString(fmt.Sprintf("  • %s: %s\n", resType, links[resType]))
+		}
+		output.WriteString("\n")
+	}
+	
+	if of.analyzer.cycle.Terragrunt() || of.analyzer.cycle.Stack() || !of.analyzer.SuggestionsShown() {
+		return
+	}
+	output.WriteString(of.messages.text("common_solutions") + "\n")
+	for _, key := range []string{"solution_cbd", "solution_data", "solution_split", "solution_depends"} {
+		output.WriteString(fmt.Sprintf("  • %s\n", of.messages.text(key)))
+	}
+	output.WriteString("\n")
+}
+
+func (of *OutputFormatter) writeAllResources(output *strings.Builder) {
+	output.WriteString(of.messages.text("all_resources") + "\n")
+	
+	for i, node := range of.analyzer.cycle.Nodes {
+		output.WriteString(fmt.Sprintf("  %d. %s", i+1, node.String()))
+		
+		if len(node.ModulePath) > 0 {
+			output.WriteString(fmt.Sprintf(" (%s: %s)", of.messages.text("module"), strings.Join(node.ModulePath, ".")))
+		}
+		
+		if node.InstanceKey != "" {
+			output.WriteString(fmt.Sprintf(" [%s]", node.InstanceKey))
+		}
+		
+		if node.Location != nil {
+			output.WriteString(fmt.Sprintf("\n     📍 %s", node.Location.String()))
+		}
+		
+		if node.ConstructPath != "" {
+			output.WriteString(fmt.Sprintf("\n     🧩 %s", node.ConstructPath))
+		}
+		
+		if url := NodeDocURL(node); url != "" {
+			output.WriteString(fmt.Sprintf("\n     📚 %s", url))
+		}
+		
+		output.WriteString("\n")
+	}
+	output.WriteString("\n")
+}
+
+// VisualOptions controls the Graphviz output of GenerateVisualization.
+type VisualOptions struct {
+	Scope          string
+	RankDir        string
+	NodeShape      string
+	FontName       string
+	FullLabels     bool
+	MaxLabelLength int
+}
+
+// DefaultVisualOptions returns the minimal scope, left-to-right layout with
+// box nodes and short labels.
+func DefaultVisualOptions() VisualOptions {
+	return VisualOptions{
+		Scope:     "minimal",
+		RankDir:   "LR",
+		NodeShape: "box",
+	}
+}
+
+// graphvizShapes are the node shapes Graphviz knows.
+var graphvizShapes = []string{
+	"box", "polygon", "ellipse", "oval", "circle", "point", "egg", "triangle", "plaintext", "plain",
+	"diamond", "trapezium", "parallelogram", "house", "pentagon", "hexagon", "septagon", "octagon",
+	"doublecircle", "doubleoctagon", "tripleoctagon", "invtriangle", "invtrapezium", "invhouse",
+	"Mdiamond", "Msquare", "Mcircle", "rect", "rectangle", "square", "star", "none", "underline",
+	"cylinder", "note", "tab", "folder", "box3d", "component", "promoter", "cds", "terminator", "utr",
+	"primersite", "restrictionsite", "fivepoverhang", "threepoverhang", "noverhang", "assembly",
+	"signature", "insulator", "ribosite", "rnastab", "proteasesite", "proteinstab", "rpromoter",
+	"rarrow", "larrow", "lpromoter", "record", "Mrecord",
+}
+
+// Validate returns an error for unknown scopes, directions or label modes.
+func (vo VisualOptions) Validate() error {
+	switch vo.Scope {
+	case "minimal", "full":
+	default:
+		return fmt.Errorf("invalid scope %q (expected minimal or full)", vo.Scope)
+	}
+	
+	switch strings.ToUpper(vo.RankDir) {
+	case "LR", "RL", "TB", "BT":
+	default:
+		return fmt.Errorf("invalid rankdir %q (expected LR, RL, TB or BT)", vo.RankDir)
+	}
+	
+	if !slices.Contains(graphvizShapes, vo.NodeShape) {
+		return fmt.Errorf("invalid node shape %q (see https://graphviz.org/doc/info/shapes.html)", vo.NodeShape)
+	}
+	
+	if vo.MaxLabelLength < 0 {
+		return fmt.Errorf("max label length must not be negative")
+	}
+	
+	return nil
+}
+
+// GenerateVisualization returns the cycle as a Graphviz DOT digraph.
+func (of *OutputFormatter) GenerateVisualization(opts VisualOptions) string {
+	var output strings.Builder
+	
+	output.WriteString("digraph terraform_cycle {\n")
+	output.WriteString(fmt.Sprintf("  rankdir=%s;\n", strings.ToUpper(opts.RankDir)))
+	
+	nodeAttrs := fmt.Sprintf("shape=%s, style=rounded", opts.NodeShape)
+	if opts.FontName != "" {
+		nodeAttrs += fmt.Sprintf(", fontname=\"%s\"", dotString(opts.FontName))
+		output.WriteString(fmt.Sprintf("  edge [fontname=\"%s\"];\n", dotString(opts.FontName)))
+	}
+	output.WriteString(fmt.Sprintf("  node [%s];\n\n", nodeAttrs))
+	
+	cycles := of.analyzer.FindMinimalCycles()
+	if len(cycles) == 0 {
+		return ""
+	}
+	
+	cycle := cycles[0]
+	
+	nodeNames := cycle
+	if opts.Scope == "full" {
+		nodeNames = of.analyzer.NodeNames()
+	}
+	
+	for _, nodeName := range nodeNames {
+		label := of.visualLabel(nodeName, opts)
+		node := of.analyzer.cycle.GetNodeByName(nodeName)
+		color := "lightblue"
+		if node != nil {
+			switch node.Action {
+			case ActionDestroy, ActionDestroyDeposed:
+				color = "lightcoral"
+			case ActionExpand:
+				color = "lightyellow"
+			case ActionClose:
+				color = "lightgreen"
+			}
+		}
+		
+		output.WriteString(fmt.Sprintf("  %s [label=\"%s\", fillcolor=%s, style=filled];\n", 
+			dotID(nodeName), label, color))
+	}
+	
+	output.WriteString("\n")
+	
+	cycleEdges := make(map[string]bool)
+	for i, nodeName := range cycle {
+		nextIndex := (i + 1) % len(cycle)
+		nextNodeName := cycle[nextIndex]
+		cycleEdges[nodeName+"->"+nextNodeName] = true
+		
+		if opts.Scope == "minimal" {
+			output.WriteString(fmt.Sprintf("  %s -> %s;\n", dotID(nodeName), dotID(nextNodeName)))
+		}
+	}
+	
+	if opts.Scope == "full" {
+		graph := of.analyzer.HypothesizedGraph()
+		for _, from := range nodeNames {
+			for _, to := range graph[from] {
+				if cycleEdges[from+"->"+to] {
+					output.WriteString(fmt.Sprintf("  %s -> %s [color=red, penwidth=2];\n", dotID(from), dotID(to)))
+				} else {
+					output.WriteString(fmt.Sprintf("  %s -> %s [style=dashed, color=gray50];\n", dotID(from), dotID(to)))
+				}
+			}
+		}
+	}
+	
+	output.WriteString("}\n")
+	
+	return output.String()
+}
+
+func (of *OutputFormatter) visualLabel(nodeName string, opts VisualOptions) string {
+	label := nodeName
+	node := of.analyzer.cycle.GetNodeByName(nodeName)
+	if node != nil && !opts.FullLabels {
+		label = fmt.Sprintf("%s.%s", node.ResourceType, node.ResourceName)
+		if node.InstanceKey != "" {
+			label += fmt.Sprintf("[%s]", node.InstanceKey)
+		}
+	}
+	
+	// Truncate by runes, so that a multi-byte character is not split into
+	// invalid UTF-8.
+	if runes := []rune(label); opts.MaxLabelLength > 0 && len(runes) > opts.MaxLabelLength {
+		if opts.MaxLabelLength <= 3 {
+			label = string(runes[:opts.MaxLabelLength])
+		} else {
+			label = string(runes[:opts.MaxLabelLength-3]) + "..."
+		}
+	}
+	
+	return dotString(label)
+}
+
+// dotString escapes value for a quoted DOT attribute.
+func dotString(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "\\\"").Replace(value)
+}
+
+// dotID returns nodeName as a quoted DOT ID, which may hold any character.
+func dotID(nodeName string) string {
+	return "\"" + dotString(nodeName) + "\""
+}
@@ -0,0 +1,206 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func newTestFormatter() *OutputFormatter {
+	cycle := &TfCycle{
+		Nodes: []*CycleNode{
+			{ResourceType: "aws_security_group", ResourceName: "sg1", ModulePath: []string{"module", "vpc"}},
+			{ResourceType: "aws_security_group", ResourceName: "sg2", ModulePath: []string{"module", "vpc"}},
+		},
+	}
+	
+	return NewOutputFormatter(NewCycleAnalyzer(cycle), false)
+}
+
+func TestOutputFormatter_GenerateVisualization_Defaults(t *testing.T) {
+	formatter := newTestFormatter()
+	dot := formatter.GenerateVisualization(DefaultVisualOptions())
+	
+	if !strings.Contains(dot, "rankdir=LR;") {
+		t.Errorf("Expected default rankdir LR, got:\n%s", dot)
+	}
+	
+	if !strings.Contains(dot, "shape=box") {
+		t.Errorf("Expected default box shape, got:\n%s", dot)
+	}
+	
+	if !strings.Contains(dot, `label="aws_security_group.sg1"`) {
+		t.Errorf("Expected short label by default, got:\n%s", dot)
+	}
+}
+
+func TestOutputFormatter_GenerateVisualization_Options(t *testing.T) {
+	formatter := newTestFormatter()
+	opts := VisualOptions{
+		RankDir:        "tb",
+		NodeShape:      "ellipse",
+		FontName:       "Helvetica",
+		FullLabels:     true,
+		MaxLabelLength: 20,
+	}
+	
+	dot := formatter.GenerateVisualization(opts)
+	
+	if !strings.Contains(dot, "rankdir=TB;") {
+		t.Errorf("Expected rankdir TB, got:\n%s", dot)
+	}
+	
+	if !strings.Contains(dot, `shape=ellipse, style=rounded, fontname="Helvetica"`) {
+		t.Errorf("Expected custom node attributes, got:\n%s", dot)
+	}
+	
+	if !strings.Contains(dot, `label="module.vpc.aws_se..."`) {
+		t.Errorf("Expected truncated full label, got:\n%s", dot)
+	}
+}
+
+func TestOutputFormatter_GenerateVisualization_Escaping(t *testing.T) {
+	cycle := &TfCycle{
+		Nodes: []*CycleNode{
+			{ResourceType: "aws_iam_role", ResourceName: "r", InstanceKey: "ünïcödé"},
+			{ResourceType: "aws_iam_policy", ResourceName: "p", InstanceKey: `a"; x -> y; "b`},
+		},
+	}
+	formatter := NewOutputFormatter(NewCycleAnalyzer(cycle), false)
+	opts := DefaultVisualOptions()
+	opts.FontName = `Fira "Sans"`
+	opts.MaxLabelLength = 19
+	
+	dot := formatter.GenerateVisualization(opts)
+	
+	if !utf8.ValidString(dot) {
+		t.Errorf("Expected truncated labels to stay valid UTF-8, got:\n%s", dot)
+	}
+	if !strings.Contains(dot, `label="aws_iam_role.r[ü..."`) {
+		t.Errorf("Expected the label truncated to 19 characters, got:\n%s", dot)
+	}
+	if !strings.Contains(dot, `fontname="Fira \"Sans\""`) {
+		t.Errorf("Expected the font name escaped, got:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"aws_iam_policy.p[a\"; x -> y; \"b]" -> "aws_iam_role.r[ünïcödé]";`) {
+		t.Errorf("Expected node IDs quoted and escaped, got:\n%s", dot)
+	}
+}
+
+func TestVisualOptions_Validate(t *testing.T) {
+	opts := DefaultVisualOptions()
+	if err := opts.Validate(); err != nil {
+		t.Errorf("Expected default options to be valid, got: %v", err)
+	}
+	
+	opts.RankDir = "diagonal"
+	if err := opts.Validate(); err == nil {
+		t.Errorf("Expected error for invalid rankdir")
+	}
+	
+	opts = DefaultVisualOptions()
+	opts.NodeShape = "box, color=red"
+	if err := opts.Validate(); err == nil {
+		t.Errorf("Expected error for an unknown node shape")
+	}
+}
+
+func TestOutputFormatter_GenerateVisualization_FullScope(t *testing.T) {
+	cycle := &TfCycle{
+		Nodes: []*CycleNode{
+			{ResourceType: "aws_security_group", ResourceName: "sg1"},
+			{ResourceType: "aws_security_group", ResourceName: "sg2"},
+			{ResourceType: "aws_instance", ResourceName: "web"},
+		},
+	}
+	formatter := NewOutputFormatter(NewCycleAnalyzer(cycle), false)
+	
+	opts := DefaultVisualOptions()
+	opts.Scope = "full"
+	dot := formatter.GenerateVisualization(opts)
+	
+	for _, node := range cycle.Nodes {
+		if !strings.Contains(dot, dotID(node.FullName())+" [label=") {
+			t.Errorf("Expected node %s in full scope graph, got:\n%s", node.FullName(), dot)
+		}
+	}
+	
+	if !strings.Contains(dot, "color=red") {
+		t.Errorf("Expected minimal cycle edges to be highlighted, got:\n%s", dot)
+	}
+	
+	if !strings.Contains(dot, "style=dashed") {
+		t.Errorf("Expected non-cycle hypothesized edges to be dashed, got:\n%s", dot)
+	}
+}
+
+func TestOutputFormatter_FormatAsMarkdown(t *testing.T) {
+	formatter := newTestFormatter()
+	md := formatter.FormatAsMarkdown()
+	
+	if !strings.HasPrefix(md, "# Terraform Cycle Detected") {
+		t.Errorf("Expected markdown heading, got:\n%s", md)
+	}
+	
+	if !strings.Contains(md, "`module.vpc.aws_security_group.sg1`") {
+		t.Errorf("Expected cycle members as inline code, got:\n%s", md)
+	}
+	
+	if !strings.Contains(md, "## Suggestions") {
+		t.Errorf("Expected suggestions section, got:\n%s", md)
+	}
+}
+
+func TestFormatCyclePaths(t *testing.T) {
+	output := newTestFormatter().FormatCyclePaths()
+	
+	expected := "module.vpc.aws_security_group.sg1 -> module.vpc.aws_security_group.sg2 -> module.vpc.aws_security_group.sg1\n"
+	if output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+}
+
+func newDisplayLimitsFormatter() *OutputFormatter {
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	nodes := make([]*CycleNode, len(names))
+	for i, name := range names {
+		nodes[i] = &CycleNode{ResourceType: "null_resource", ResourceName: name}
+	}
+	analyzer := NewCycleAnalyzer(&TfCycle{Nodes: nodes})
+	analyzer.SetGraph(map[string][]string{
+		"null_resource.a": {"null_resource.b"},
+		"null_resource.b": {"null_resource.a"},
+		"null_resource.c": {"null_resource.d"},
+		"null_resource.d": {"null_resource.c"},
+		"null_resource.e": {"null_resource.f"},
+		"null_resource.f": {"null_resource.e"},
+		"null_resource.g": {"null_resource.h"},
+		"null_resource.h": {"null_resource.g"},
+	})
+	
+	return NewOutputFormatter(analyzer, false)
+}
+
+func TestOutputFormatter_DisplayLimits(t *testing.T) {
+	output := newDisplayLimitsFormatter().FormatAnalysis()
+	if !strings.Contains(output, "... and 1 more cycles") {
+		t.Errorf("Expected the default limit to hide one cycle, got:\n%s", output)
+	}
+	
+	formatter := newDisplayLimitsFormatter()
+	formatter.SetDisplayLimits(1, 1)
+	output = formatter.FormatAnalysis()
+	if !strings.Contains(output, "... and 3 more cycles") {
+		t.Errorf("Expected three hidden cycles, got:\n%s", output)
+	}
+	if !strings.Contains(output, "... and 1 more resources") {
+		t.Errorf("Expected one hidden resource, got:\n%s", output)
+	}
+	
+	formatter = newDisplayLimitsFormatter()
+	formatter.SetDisplayLimits(0, 0)
+	output = formatter.FormatAnalysis()
+	if strings.Contains(output, "more cycles") || strings.Contains(output, "more resources") {
+		t.Errorf("Expected every cycle and resource with no limits, got:\n%s", output)
+	}
+}
@@ -0,0 +1,52 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+// The seed corpora in testdata/fuzz are real Terraform, Terragrunt and
+// Terraform Stacks output. Run the fuzzers with, for example,
+// go test ./pkg/tfcycle -run '^$' -fuzz FuzzParseError -fuzztime 1m
+
+func FuzzParseError(f *testing.F) {
+	f.Add("Error: Cycle: aws_security_group.sg_ping, aws_security_group.sg_8080")
+	f.Add(explainTestError)
+	
+	f.Fuzz(func(t *testing.T, errorText string) {
+		cycle, err := NewParser().ParseError(errorText)
+		if err != nil {
+			if cycle != nil {
+				t.Fatalf("Expected no cycle with error %v, got %+v", err, cycle)
+			}
+			return
+		}
+		if cycle == nil || len(cycle.Nodes) == 0 {
+			t.Fatalf("Expected nodes for a parsed cycle, got %+v", cycle)
+		}
+		
+		// Everything downstream of the parser must cope with what it accepts.
+		for _, node := range cycle.Nodes {
+			_ = node.Address()
+			_ = node.String()
+		}
+		NewRedactor().RedactCycle(cycle)
+		NewCycleAnalyzer(cycle).FindMinimalCycles()
+	})
+}
+
+func FuzzSplitResources(f *testing.F) {
+	f.Add("aws_security_group.sg_ping, aws_security_group.sg_8080")
+	f.Add(`module.a["x,y"].aws_instance.b[0], aws_instance.c (destroy)`)
+	
+	f.Fuzz(func(t *testing.T, cycleText string) {
+		for _, resource := range NewParser().splitResources(cycleText) {
+			if resource == "" || resource != strings.TrimSpace(resource) {
+				t.Fatalf("Expected trimmed, non-empty resources, got %q", resource)
+			}
+			if strings.ContainsAny(resource, "\n\t") {
+				t.Fatalf("Expected newlines and tabs replaced, got %q", resource)
+			}
+		}
+	})
+}
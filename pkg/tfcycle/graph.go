@@ -0,0 +1,119 @@
+package tfcycle
+
+import "slices"
+
+// GraphNode is a resource in GraphData.
+type GraphNode struct {
+	ID      string `json:"id"`
+	Label   string `json:"label"`
+	Action  string `json:"action"`
+	InCycle bool   `json:"in_cycle"`
+}
+
+// GraphEdge is a hypothesized dependency in GraphData.
+type GraphEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Reason  string `json:"reason"`
+	InCycle bool   `json:"in_cycle"`
+}
+
+// GraphData is the hypothesized graph in a form suited to interactive
+// rendering.
+type GraphData struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphData returns all nodes and hypothesized edges, marking those that are
+// part of a minimal cycle.
+func (ca *CycleAnalyzer) GraphData() GraphData {
+	cycleNodes := make(map[string]bool)
+	cycleEdges := make(map[string]bool)
+	for _, cycle := range ca.FindMinimalCycles() {
+		for i, name := range cycle {
+			cycleNodes[name] = true
+			cycleEdges[name+"->"+cycle[(i+1)%len(cycle)]] = true
+		}
+	}
+	
+	data := GraphData{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	for _, node := range ca.cycle.Nodes {
+		name := node.FullName()
+		data.Nodes = append(data.Nodes, GraphNode{
+			ID:      name,
+			Label:   node.ResourceType + "." + node.ResourceName,
+			Action:  node.Action.String(),
+			InCycle: cycleNodes[name],
+		})
+	}
+	
+	graph := ca.HypothesizedGraph()
+	for _, from := range ca.NodeNames() {
+		for _, to := range graph[from] {
+			edge := ca.edgeReason(from, to)
+			data.Edges = append(data.Edges, GraphEdge{
+				From:    from,
+				To:      to,
+				Reason:  edge.Reason,
+				InCycle: cycleEdges[from+"->"+to],
+			})
+		}
+	}
+	return data
+}
+
+// Edges returns the dependencies that make up the minimal cycles in Cycles,
+// each once, in the order they first appear. It is empty until the cycle has
+// been analyzed.
+func (tc *TfCycle) Edges() []Edge {
+	edges := []Edge{}
+	seen := make(map[Edge]bool)
+	for _, cycle := range tc.Cycles {
+		for i, from := range cycle {
+			edge := Edge{From: from, To: cycle[(i+1)%len(cycle)]}
+			if !seen[edge] {
+				seen[edge] = true
+				edges = append(edges, edge)
+			}
+		}
+	}
+	return edges
+}
+
+// AdjacencyMap returns Edges as adjacency lists keyed by node address. Every
+// node has an entry, empty if it is in no minimal cycle.
+func (tc *TfCycle) AdjacencyMap() map[string][]string {
+	adjacency := make(map[string][]string, len(tc.Nodes))
+	for _, node := range tc.Nodes {
+		adjacency[node.FullName()] = []string{}
+	}
+	for _, edge := range tc.Edges() {
+		adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+	}
+	return adjacency
+}
+
+// Subgraph returns a cycle restricted to the nodes with the given addresses
+// and the minimal cycles that lie entirely among them. The nodes are shared
+// with tc.
+func (tc *TfCycle) Subgraph(names []string) *TfCycle {
+	keep := make(map[string]bool, len(names))
+	for _, name := range names {
+		keep[name] = true
+	}
+	
+	sub := &TfCycle{RawError: tc.RawError, Nodes: []*CycleNode{}}
+	for _, node := range tc.Nodes {
+		if keep[node.FullName()] {
+			sub.Nodes = append(sub.Nodes, node)
+		}
+	}
+	
+	for _, cycle := range tc.Cycles {
+		if !slices.ContainsFunc(cycle, func(name string) bool { return !keep[name] }) {
+			sub.Cycles = append(sub.Cycles, cycle)
+		}
+	}
+	return sub
+}
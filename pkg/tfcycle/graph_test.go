@@ -0,0 +1,49 @@
+package tfcycle
+
+import (
+	"reflect"
+	"testing"
+)
+
+func analyzedCycle(t *testing.T, errorText string, cycles [][]string) *TfCycle {
+	t.Helper()
+	cycle, err := NewParser().ParseError(errorText)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	cycle.Cycles = cycles
+	return cycle
+}
+
+func TestTfCycle_EdgesAndAdjacencyMap(t *testing.T) {
+	cycle := analyzedCycle(t, "Error: Cycle: a.a, b.b, c.c, d.d", [][]string{{"a.a", "b.b"}, {"b.b", "c.c"}, {"a.a", "b.b"}})
+	
+	edges := cycle.Edges()
+	if len(edges) != 4 {
+		t.Fatalf("Expected 4 distinct edges, got %v", edges)
+	}
+	
+	adjacency := cycle.AdjacencyMap()
+	if len(adjacency) != 4 || len(adjacency["d.d"]) != 0 {
+		t.Errorf("Expected an entry for every node, got %v", adjacency)
+	}
+	if got := adjacency["b.b"]; len(got) != 2 {
+		t.Errorf("Expected b.b to depend on a.a and c.c, got %v", got)
+	}
+	
+	if edges := (&TfCycle{}).Edges(); edges == nil || len(edges) != 0 {
+		t.Errorf("Expected no edges before analysis, got %v", edges)
+	}
+}
+
+func TestTfCycle_Subgraph(t *testing.T) {
+	cycle := analyzedCycle(t, "Error: Cycle: a.a, b.b, c.c", [][]string{{"a.a", "b.b"}, {"b.b", "c.c"}})
+	
+	sub := cycle.Subgraph([]string{"a.a", "b.b", "unknown.node"})
+	if len(sub.Nodes) != 2 || sub.Nodes[0] != cycle.Nodes[0] {
+		t.Errorf("Expected the a.a and b.b nodes, got %v", sub.Nodes)
+	}
+	if len(sub.Cycles) != 1 || !reflect.DeepEqual(sub.AdjacencyMap()["a.a"], []string{"b.b"}) {
+		t.Errorf("Expected only the a.a <-> b.b cycle, got %v", sub.Cycles)
+	}
+}
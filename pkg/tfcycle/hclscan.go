@@ -0,0 +1,451 @@
+package tfcycle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	blockHeaderRegex  = regexp.MustCompile(`^\s*(resource|data)\s+"([^"]+)"\s+"([^"]+)"\s*\{`)
+	moduleHeaderRegex = regexp.MustCompile(`^\s*module\s+"([^"]+)"\s*\{`)
+	nestedBlockRegex  = regexp.MustCompile(`^\s*([a-zA-Z0-9_-]+)\s*\{`)
+	attributeRegex    = regexp.MustCompile(`^\s*([a-zA-Z0-9_-]+)\s*=\s*(.*)$`)
+	sourceRegex       = regexp.MustCompile(`^\s*source\s*=\s*"([^"]+)"`)
+	heredocRegex      = regexp.MustCompile(`<<-?([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+	ignoreRegex       = regexp.MustCompile(`^(?:#|//)\s*tfcycle:ignore\b(.*)$`)
+)
+
+// ConfigBlock is a resource, data or module block found in the configuration.
+type ConfigBlock struct {
+	Kind       string
+	Type       string
+	Name       string
+	ModulePath []string
+	File       string
+	StartLine  int
+	EndLine    int
+	Lines      []string
+	// Ignore holds the rule IDs and lint finding kinds, or globs of them, of
+	// the "# tfcycle:ignore" comments above or in the block; "*" for a
+	// comment that names none.
+	Ignore []string
+}
+
+// Address returns the block's Terraform address without instance key.
+func (b *ConfigBlock) Address() string {
+	parts := make([]string, 0, len(b.ModulePath)+3)
+	parts = append(parts, b.ModulePath...)
+	if b.Kind == "data" {
+		parts = append(parts, "data")
+	}
+	parts = append(parts, b.Type, b.Name)
+	return strings.Join(parts, ".")
+}
+
+// Location returns "file:line" of the block's first line.
+func (b *ConfigBlock) Location() string {
+	return fmt.Sprintf("%s:%d", b.File, b.StartLine)
+}
+
+// ConfigIndex is a lightweight index of the blocks in a Terraform configuration
+// and the local modules it calls.
+type ConfigIndex struct {
+	Root   string
+	Blocks []*ConfigBlock
+	byAddr map[string]*ConfigBlock
+	files  map[string][]string
+}
+
+// ScanConfig indexes the .tf files in dir and in local modules called from it.
+func ScanConfig(dir string) (*ConfigIndex, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("config path %s is not a directory", dir)
+	}
+	
+	index := &ConfigIndex{
+		Root:   dir,
+		byAddr: make(map[string]*ConfigBlock),
+		files:  make(map[string][]string),
+	}
+	
+	if err := index.scanModule(dir, nil, make(map[string]bool)); err != nil {
+		return nil, err
+	}
+	logger.Info("scanned configuration", "dir", dir, "files", len(index.files), "blocks", len(index.Blocks))
+	
+	return index, nil
+}
+
+func (ci *ConfigIndex) scanModule(dir string, modulePath []string, visiting map[string]bool) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if visiting[absDir] {
+		return nil
+	}
+	visiting[absDir] = true
+	defer delete(visiting, absDir)
+	
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		
+		text := strings.ReplaceAll(string(content), "\r\n", "\n")
+		lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+		ci.files[file] = lines
+		
+		for _, block := range scanBlocks(file, lines, modulePath) {
+			if block.Kind == "module" {
+				source := block.Attribute("source")
+				if isLocalModuleSource(source) {
+					childPath := append(append([]string{}, modulePath...), "module", block.Name)
+					if err := ci.scanModule(filepath.Join(dir, source), childPath, visiting); err != nil {
+						return err
+					}
+				}
+			}
+			ci.Blocks = append(ci.Blocks, block)
+			ci.byAddr[block.Address()] = block
+		}
+	}
+	
+	return nil
+}
+
+func isLocalModuleSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+// Lookup returns the block declaring node, or nil.
+func (ci *ConfigIndex) Lookup(node *CycleNode) *ConfigBlock {
+	return ci.byAddr[node.baseAddress()]
+}
+
+// ResolveLocations sets Location on the nodes of cycle found in the index and
+// returns how many were found.
+func (ci *ConfigIndex) ResolveLocations(cycle *TfCycle) int {
+	resolved := 0
+	for _, node := range cycle.Nodes {
+		block := ci.Lookup(node)
+		if block == nil {
+			logger.Debug("no configuration block for cycle node", "node", node.FullName())
+			continue
+		}
+		node.Location = &SourceLocation{
+			File: filepath.ToSlash(filepath.Clean(block.File)),
+			Line: block.StartLine,
+		}
+		resolved++
+	}
+	return resolved
+}
+
+// LookupAddress returns the block with the given address, or nil.
+func (ci *ConfigIndex) LookupAddress(address string) *ConfigBlock {
+	return ci.byAddr[address]
+}
+
+// FileLines returns the lines of a scanned file.
+func (ci *ConfigIndex) FileLines(file string) []string {
+	return ci.files[file]
+}
+
+func scanBlocks(file string, lines []string, modulePath []string) []*ConfigBlock {
+	var blocks []*ConfigBlock
+	
+	for i := 0; i < len(lines); i++ {
+		var block *ConfigBlock
+		
+		if matches := blockHeaderRegex.FindStringSubmatch(lines[i]); matches != nil {
+			block = &ConfigBlock{Kind: matches[1], Type: matches[2], Name: matches[3]}
+		} else if matches := moduleHeaderRegex.FindStringSubmatch(lines[i]); matches != nil {
+			block = &ConfigBlock{Kind: "module", Type: "module", Name: matches[1]}
+		} else {
+			continue
+		}
+		
+		end := findBlockEnd(lines, i)
+		block.File = file
+		block.ModulePath = modulePath
+		block.StartLine = i + 1
+		block.EndLine = end + 1
+		block.Lines = lines[i : end+1]
+		for j := i - 1; j >= 0 && isCommentLine(lines[j]); j-- {
+			block.Ignore = append(block.Ignore, ignoreComment(lines[j])...)
+		}
+		for _, line := range block.Lines {
+			block.Ignore = append(block.Ignore, ignoreComment(line)...)
+		}
+		blocks = append(blocks, block)
+		
+		i = end
+	}
+	
+	return blocks
+}
+
+func findBlockEnd(lines []string, start int) int {
+	depth := 0
+	inBlockComment := false
+	heredoc := ""
+	
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		
+		if heredoc != "" {
+			if strings.TrimSpace(line) == heredoc {
+				heredoc = ""
+			}
+			continue
+		}
+		
+		inString := false
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			
+			if inBlockComment {
+				if c == '*' && j+1 < len(line) && line[j+1] == '/' {
+					inBlockComment = false
+					j++
+				}
+				continue
+			}
+			
+			if inString {
+				if c == '\\' {
+					j++
+				} else if c == '"' {
+					inString = false
+				}
+				continue
+			}
+			
+			switch {
+			case c == '"':
+				inString = true
+			case c == '#', c == '/' && j+1 < len(line) && line[j+1] == '/':
+				j = len(line)
+			case c == '/' && j+1 < len(line) && line[j+1] == '*':
+				inBlockComment = true
+				j++
+			case c == '{':
+				depth++
+			case c == '}':
+				depth--
+				if depth == 0 {
+					return i
+				}
+			}
+		}
+		
+		if matches := heredocRegex.FindStringSubmatch(line); matches != nil {
+			heredoc = matches[1]
+		}
+	}
+	
+	return len(lines) - 1
+}
+
+// Attribute returns the raw value of a top-level attribute, or "".
+func (b *ConfigBlock) Attribute(name string) string {
+	depth := 0
+	for i, line := range b.Lines {
+		if i == 0 {
+			continue
+		}
+		
+		if depth == 0 {
+			if name == "source" {
+				if matches := sourceRegex.FindStringSubmatch(line); matches != nil {
+					return matches[1]
+				}
+			} else if matches := attributeRegex.FindStringSubmatch(line); matches != nil && matches[1] == name {
+				return strings.TrimSpace(matches[2])
+			}
+		}
+		
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+	}
+	return ""
+}
+
+// DependsOnAttribute is a depends_on list with its position in the file.
+type DependsOnAttribute struct {
+	Entries   []string
+	StartLine int
+	EndLine   int
+}
+
+// DependsOn returns the block's depends_on attribute, if any.
+func (b *ConfigBlock) DependsOn() (DependsOnAttribute, bool) {
+	depth := 0
+	for i := 1; i < len(b.Lines)-1; i++ {
+		line := b.Lines[i]
+		matches := attributeRegex.FindStringSubmatch(line)
+		if depth == 0 && matches != nil && matches[1] == "depends_on" {
+			value := strings.TrimSpace(stripComment(matches[2]))
+			end := i
+			balance := strings.Count(value, "[") - strings.Count(value, "]")
+			for balance > 0 && end+1 < len(b.Lines)-1 {
+				end++
+				next := strings.TrimSpace(stripComment(b.Lines[end]))
+				value += " " + next
+				balance += strings.Count(next, "[") - strings.Count(next, "]")
+			}
+			
+			return DependsOnAttribute{
+				Entries:   splitHCLList(value),
+				StartLine: b.StartLine + i,
+				EndLine:   b.StartLine + end,
+			}, true
+		}
+		
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+	}
+	return DependsOnAttribute{}, false
+}
+
+// WithoutLines returns the block's text without the given file line range.
+func (b *ConfigBlock) WithoutLines(startLine, endLine int) string {
+	var kept []string
+	for i, line := range b.Lines {
+		lineNumber := b.StartLine + i
+		if lineNumber >= startLine && lineNumber <= endLine {
+			continue
+		}
+		kept = append(kept, stripComment(line))
+	}
+	return strings.Join(kept, "\n")
+}
+
+func isCommentLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//")
+}
+
+// ignoreComment returns the patterns of a "# tfcycle:ignore TFC001, TFC004"
+// or "// tfcycle:ignore" comment on line, "*" if it names none, or nil if
+// the line has no such comment.
+func ignoreComment(line string) []string {
+	comment := strings.TrimSpace(line[len(stripComment(line)):])
+	matches := ignoreRegex.FindStringSubmatch(comment)
+	if matches == nil {
+		return nil
+	}
+	patterns := strings.FieldsFunc(matches[1], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	if len(patterns) == 0 {
+		return []string{"*"}
+	}
+	return patterns
+}
+
+func stripComment(line string) string {
+	inString := false
+	for j := 0; j < len(line); j++ {
+		switch c := line[j]; {
+		case inString && c == '\\':
+			j++
+		case c == '"':
+			inString = !inString
+		case !inString && (c == '#' || c == '/' && j+1 < len(line) && line[j+1] == '/'):
+			return strings.TrimRight(line[:j], " \t")
+		}
+	}
+	return line
+}
+
+// NestedBlock is a block nested in a ConfigBlock, such as ingress or lifecycle.
+type NestedBlock struct {
+	Name      string
+	StartLine int
+	EndLine   int
+	Lines     []string
+}
+
+// NestedBlocks returns the directly nested blocks called name.
+func (b *ConfigBlock) NestedBlocks(name string) []NestedBlock {
+	var nested []NestedBlock
+	
+	for i := 1; i < len(b.Lines)-1; i++ {
+		matches := nestedBlockRegex.FindStringSubmatch(b.Lines[i])
+		if matches == nil || matches[1] != name {
+			continue
+		}
+		
+		end := findBlockEnd(b.Lines, i)
+		nested = append(nested, NestedBlock{
+			Name:      name,
+			StartLine: b.StartLine + i,
+			EndLine:   b.StartLine + end,
+			Lines:     b.Lines[i : end+1],
+		})
+		i = end
+	}
+	
+	return nested
+}
+
+// Attributes returns the nested block's attributes with their raw values.
+func (nb NestedBlock) Attributes() map[string]string {
+	attrs := make(map[string]string)
+	
+	if len(nb.Lines) == 1 {
+		// A one-line block such as "lifecycle { prevent_destroy = true }"
+		// holds at most one attribute.
+		if matches := attributeRegex.FindStringSubmatch(nb.InlineBody()); matches != nil {
+			attrs[matches[1]] = strings.TrimSpace(matches[2])
+		}
+		return attrs
+	}
+	
+	for i := 1; i < len(nb.Lines)-1; i++ {
+		matches := attributeRegex.FindStringSubmatch(nb.Lines[i])
+		if matches == nil {
+			continue
+		}
+		
+		value := strings.TrimSpace(matches[2])
+		balance := strings.Count(value, "[") - strings.Count(value, "]")
+		for balance > 0 && i+1 < len(nb.Lines)-1 {
+			i++
+			next := strings.TrimSpace(nb.Lines[i])
+			value += " " + next
+			balance += strings.Count(next, "[") - strings.Count(next, "]")
+		}
+		
+		attrs[matches[1]] = value
+	}
+	
+	return attrs
+}
+
+// InlineBody returns what is between the braces of a one-line block, or ""
+// if the block spans several lines.
+func (nb NestedBlock) InlineBody() string {
+	if len(nb.Lines) != 1 {
+		return ""
+	}
+	line := stripComment(nb.Lines[0])
+	start, end := strings.Index(line, "{"), strings.LastIndex(line, "}")
+	if start < 0 || end <= start {
+		return ""
+	}
+	return strings.TrimSpace(line[start+1 : end])
+}
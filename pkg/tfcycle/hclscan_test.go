@@ -0,0 +1,136 @@
+package tfcycle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestConfig(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestScanConfig_ResourceBlocks(t *testing.T) {
+	dir := writeTestConfig(t, map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  ami       = "ami-123" # not a brace {
+  user_data = <<EOT
+echo }
+EOT
+}
+
+data "aws_ami" "ubuntu" {
+  most_recent = true
+}
+
+module "vpc" {
+  source = "./modules/vpc"
+}
+`,
+		"modules/vpc/main.tf": `resource "aws_security_group" "sg" {
+  name = "sg"
+}
+`,
+	})
+	
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	web := index.LookupAddress("aws_instance.web")
+	if web == nil {
+		t.Fatalf("Expected to find aws_instance.web")
+	}
+	if web.StartLine != 1 || web.EndLine != 6 {
+		t.Errorf("Expected aws_instance.web at lines 1-6, got %d-%d", web.StartLine, web.EndLine)
+	}
+	
+	if index.LookupAddress("data.aws_ami.ubuntu") == nil {
+		t.Errorf("Expected to find data.aws_ami.ubuntu")
+	}
+	
+	node := &CycleNode{ResourceType: "aws_security_group", ResourceName: "sg", ModulePath: []string{"module", "vpc"}}
+	sg := index.Lookup(node)
+	if sg == nil {
+		t.Fatalf("Expected to resolve module.vpc.aws_security_group.sg")
+	}
+	if filepath.Base(sg.File) != "main.tf" || sg.StartLine != 1 {
+		t.Errorf("Expected module resource at main.tf:1, got %s", sg.Location())
+	}
+}
+
+func TestConfigBlock_NestedBlocks(t *testing.T) {
+	lines := []string{
+		`resource "aws_security_group" "sg" {`,
+		`  ingress {`,
+		`    from_port       = 80`,
+		`    security_groups = [`,
+		`      aws_security_group.other.id,`,
+		`    ]`,
+		`  }`,
+		`}`,
+	}
+	block := scanBlocks("main.tf", lines, nil)[0]
+	
+	nested := block.NestedBlocks("ingress")
+	if len(nested) != 1 {
+		t.Fatalf("Expected 1 ingress block, got %d", len(nested))
+	}
+	
+	attrs := nested[0].Attributes()
+	if attrs["from_port"] != "80" {
+		t.Errorf("Expected from_port 80, got %q", attrs["from_port"])
+	}
+	
+	groups := splitHCLList(attrs["security_groups"])
+	if len(groups) != 1 || groups[0] != "aws_security_group.other.id" {
+		t.Errorf("Expected one security group reference, got %v", groups)
+	}
+}
+
+func TestConfigIndex_ResolveLocations(t *testing.T) {
+	dir := writeTestConfig(t, map[string]string{
+		"network.tf": `
+resource "aws_security_group" "sg1" {
+  name = "sg1"
+}
+`,
+	})
+	
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	cycle := &TfCycle{
+		Nodes: []*CycleNode{
+			{ResourceType: "aws_security_group", ResourceName: "sg1", InstanceKey: "0"},
+			{ResourceType: "aws_security_group", ResourceName: "missing"},
+		},
+	}
+	
+	if resolved := index.ResolveLocations(cycle); resolved != 1 {
+		t.Errorf("Expected 1 resolved node, got %d", resolved)
+	}
+	
+	location := cycle.Nodes[0].Location
+	if location == nil || filepath.Base(location.File) != "network.tf" || location.Line != 2 {
+		t.Errorf("Expected location network.tf:2, got %v", location)
+	}
+	
+	if cycle.Nodes[1].Location != nil {
+		t.Errorf("Expected no location for unresolved node")
+	}
+}
@@ -0,0 +1,141 @@
+package tfcycle
+
+import (
+	"strings"
+)
+
+// Heuristic infers a likely dependency between two cycle nodes. Confidence
+// ranges from 0 to 1 and is compared against WithMinConfidence. RuleID is the
+// stable ID WithRuleSelection turns the heuristic off and on by.
+type Heuristic struct {
+	RuleID     string
+	Name       string
+	Confidence float64
+	// Reason returns why from likely depends on to, or "" if it does not.
+	Reason func(from, to *CycleNode) string
+}
+
+var builtinHeuristics = DefaultHeuristics()
+
+// DefaultHeuristics returns the built-in heuristics in the order they are
+// tried. The first one that matches a pair of nodes supplies the edge reason.
+func DefaultHeuristics() []Heuristic {
+	return []Heuristic{
+		{RuleID: "TFC001", Name: "security-group-peers", Confidence: 0.9, Reason: securityGroupPeersReason},
+		{RuleID: "TFC002", Name: "instance-security-group", Confidence: 0.9, Reason: instanceSecurityGroupReason},
+		{RuleID: "TFC003", Name: "security-group-instance", Confidence: 0.6, Reason: securityGroupInstanceReason},
+		{RuleID: "TFC004", Name: "iam", Confidence: 0.8, Reason: iamReason},
+		{RuleID: "TFC005", Name: "shared-module", Confidence: 0.5, Reason: sharedModuleReason},
+		{RuleID: "TFC006", Name: "destroy-ordering", Confidence: 0.4, Reason: destroyOrderingReason},
+	}
+}
+
+// HeuristicsByName returns the built-in heuristics with the given names, in
+// the order given, and the names that match none.
+func HeuristicsByName(names ...string) ([]Heuristic, []string) {
+	builtin := make(map[string]Heuristic)
+	for _, h := range DefaultHeuristics() {
+		builtin[h.Name] = h
+	}
+	
+	var selected []Heuristic
+	var unknown []string
+	for _, name := range names {
+		if h, ok := builtin[name]; ok {
+			selected = append(selected, h)
+		} else {
+			unknown = append(unknown, name)
+		}
+	}
+	return selected, unknown
+}
+
+func securityGroupPeersReason(from, to *CycleNode) string {
+	if from.ResourceType == "aws_security_group" && to.ResourceType == "aws_security_group" {
+		return "security groups commonly reference each other in inline ingress/egress rules"
+	}
+	return ""
+}
+
+func instanceSecurityGroupReason(from, to *CycleNode) string {
+	if from.ResourceType == "aws_instance" && to.ResourceType == "aws_security_group" {
+		return "instances reference security groups through vpc_security_group_ids"
+	}
+	return ""
+}
+
+func securityGroupInstanceReason(from, to *CycleNode) string {
+	if from.ResourceType == "aws_security_group" && to.ResourceType == "aws_instance" {
+		return "security group rules often reference instance attributes such as private IPs"
+	}
+	return ""
+}
+
+func iamReason(from, to *CycleNode) string {
+	if hasCategory(from, CategoryIAM) && hasCategory(to, CategoryIAM) {
+		return "IAM roles, policies and attachments reference each other's names and ARNs"
+	}
+	return ""
+}
+
+func sharedModuleReason(from, to *CycleNode) string {
+	if shareModulePath(from.ModulePath, to.ModulePath) {
+		return "both resources live under " + strings.Join(commonModulePrefix(from.ModulePath, to.ModulePath), ".")
+	}
+	return ""
+}
+
+// destroyOrderingReason only applies outside modules; resources in modules
+// are related through their module paths instead.
+func destroyOrderingReason(from, to *CycleNode) string {
+	if len(from.ModulePath) > 0 && len(to.ModulePath) > 0 {
+		return ""
+	}
+	if from.Action == ActionDestroy && to.Action != ActionDestroy {
+		return "destroying " + from.FullName() + " must be ordered against changes to " + to.FullName()
+	}
+	return ""
+}
+
+// EdgeSource supplies known dependency edges between the nodes of a cycle as
+// adjacency lists keyed by node address. They are added to the edges the
+// heuristics infer.
+type EdgeSource interface {
+	Edges(cycle *TfCycle) map[string][]string
+}
+
+// EdgeSourceFunc adapts a function to EdgeSource.
+type EdgeSourceFunc func(cycle *TfCycle) map[string][]string
+
+// Edges calls f.
+func (f EdgeSourceFunc) Edges(cycle *TfCycle) map[string][]string {
+	return f(cycle)
+}
+
+// Edges returns the depends_on references between the nodes of cycle, so the
+// index can be used as an EdgeSource.
+func (ci *ConfigIndex) Edges(cycle *TfCycle) map[string][]string {
+	edges := make(map[string][]string)
+	for _, from := range cycle.Nodes {
+		block := ci.Lookup(from)
+		if block == nil {
+			continue
+		}
+		dependsOn, ok := block.DependsOn()
+		if !ok {
+			continue
+		}
+		for _, entry := range dependsOn.Entries {
+			for _, to := range cycle.Nodes {
+				if to != from && sameModule(from, to) && to.ResourceType+"."+to.ResourceName == entry {
+					edges[from.FullName()] = append(edges[from.FullName()], to.FullName())
+				}
+			}
+		}
+	}
+	return edges
+}
+
+func sameModule(a, b *CycleNode) bool {
+	return strings.Join(a.ModulePath, ".") == strings.Join(b.ModulePath, ".")
+}
@@ -0,0 +1,244 @@
+package tfcycle
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const defaultLanguage = "en"
+
+type catalog map[string]string
+
+var catalogs = map[string]catalog{
+	"en": {
+		"header":             "🔄 TERRAFORM CYCLE DETECTED",
+		"header_terragrunt":  "🔄 TERRAGRUNT DEPENDENCY CYCLE DETECTED",
+		"severity":           "⚠️  Severity: %s",
+		"partial":            "⏱  Analysis timed out: showing the cycle as listed by Terraform, without minimal-cycle detection",
+		"stateful":           "🗄️  Stateful resources in the cycle: %s. Make sure the fix does not replace them, or their data is lost",
+		"compare_progress":   "📉 Progress since %s: cycle shrank from %d to %d nodes",
+		"compare_regression": "📈 Regression since %s: cycle grew from %d to %d nodes",
+		"compare_unchanged":  "⏸  Unchanged since %s: the same %d-node cycle",
+		"compare_changed":    "🔀 Changed since %s: %d resources added, %d removed",
+		"no_cycles":          "❌ No cycles found in the provided resources",
+		"summary":            "📊 ANALYSIS SUMMARY",
+		"total_resources":    "Total resources in cycle: %d",
+		"resource_types":     "Resource types:",
+		"full_cycle":         "Full Cycle (%d resources):",
+		"minimal_cycle":      "Minimal Cycle #%d (%d resources):",
+		"more_cycles":        "... and %d more cycles",
+		"more_resources":     "... and %d more resources",
+		"depends_on":         "depends on",
+		"suggestions":        "💡 SUGGESTIONS:",
+		"documentation":      "📚 DOCUMENTATION:",
+		"common_solutions":   "🔧 COMMON SOLUTIONS:",
+		"solution_cbd":       "Use lifecycle { create_before_destroy = true } for replacement scenarios",
+		"solution_data":      "Replace direct references with data source lookups",
+		"solution_split":     "Split complex resources into multiple Terraform configurations",
+		"solution_depends":   "Use depends_on explicitly to control dependency order",
+		"all_resources":      "📋 ALL RESOURCES IN CYCLE:",
+		"module":             "module",
+		"md_title":           "Terraform Cycle Detected",
+		"md_terragrunt":      "Terragrunt Dependency Cycle Detected",
+		"md_total_resources": "Total resources in cycle",
+		"md_no_cycles":       "No cycles found in the provided resources.",
+		"md_minimal_cycle":   "Minimal Cycle #%d (%d resources)",
+		"md_suggestions":     "Suggestions",
+		"md_documentation":   "Documentation",
+		"md_all_resources":   "All Resources",
+		"md_address":         "Address",
+		"md_action":          "Action",
+		"apply_plan":         "🎯 STAGED APPLY PLAN:",
+		"deferred_edges":     "Dependencies deferred to break the cycle:",
+	},
+	"de": {
+		"header":             "🔄 TERRAFORM-ZYKLUS ERKANNT",
+		"header_terragrunt":  "🔄 TERRAGRUNT-ABHÄNGIGKEITSZYKLUS ERKANNT",
+		"severity":           "⚠️  Schweregrad: %s",
+		"partial":            "⏱  Zeitlimit der Analyse erreicht: Zyklus wie von Terraform gemeldet, ohne Suche nach minimalen Zyklen",
+		"stateful":           "🗄️  Zustandsbehaftete Ressourcen im Zyklus: %s. Stellen Sie sicher, dass die Korrektur sie nicht ersetzt, sonst gehen ihre Daten verloren",
+		"compare_progress":   "📉 Fortschritt seit %s: Zyklus von %d auf %d Knoten geschrumpft",
+		"compare_regression": "📈 Verschlechterung seit %s: Zyklus von %d auf %d Knoten gewachsen",
+		"compare_unchanged":  "⏸  Unverändert seit %s: derselbe Zyklus mit %d Knoten",
+		"compare_changed":    "🔀 Geändert seit %s: %d Ressourcen hinzugekommen, %d entfernt",
+		"no_cycles":          "❌ In den angegebenen Ressourcen wurden keine Zyklen gefunden",
+		"summary":            "📊 ANALYSEÜBERSICHT",
+		"total_resources":    "Ressourcen im Zyklus insgesamt: %d",
+		"resource_types":     "Ressourcentypen:",
+		"full_cycle":         "Vollständiger Zyklus (%d Ressourcen):",
+		"minimal_cycle":      "Minimaler Zyklus #%d (%d Ressourcen):",
+		"more_cycles":        "... und %d weitere Zyklen",
+		"more_resources":     "... und %d weitere Ressourcen",
+		"depends_on":         "hängt ab von",
+		"suggestions":        "💡 VORSCHLÄGE:",
+		"documentation":      "📚 DOKUMENTATION:",
+		"common_solutions":   "🔧 GÄNGIGE LÖSUNGEN:",
+		"solution_cbd":       "lifecycle { create_before_destroy = true } für Ersetzungsszenarien verwenden",
+		"solution_data":      "Direkte Referenzen durch Data-Source-Abfragen ersetzen",
+		"solution_split":     "Komplexe Ressourcen auf mehrere Terraform-Konfigurationen aufteilen",
+		"solution_depends":   "depends_on explizit verwenden, um die Abhängigkeitsreihenfolge zu steuern",
+		"all_resources":      "📋 ALLE RESSOURCEN IM ZYKLUS:",
+		"module":             "Modul",
+		"md_title":           "Terraform-Zyklus erkannt",
+		"md_terragrunt":      "Terragrunt-Abhängigkeitszyklus erkannt",
+		"md_total_resources": "Ressourcen im Zyklus insgesamt",
+		"md_no_cycles":       "In den angegebenen Ressourcen wurden keine Zyklen gefunden.",
+		"md_minimal_cycle":   "Minimaler Zyklus #%d (%d Ressourcen)",
+		"md_suggestions":     "Vorschläge",
+		"md_documentation":   "Dokumentation",
+		"md_all_resources":   "Alle Ressourcen",
+		"md_address":         "Adresse",
+		"md_action":          "Aktion",
+		"apply_plan":         "🎯 GESTAFFELTER APPLY-PLAN:",
+		"deferred_edges":     "Zum Aufbrechen des Zyklus zurückgestellte Abhängigkeiten:",
+	},
+	"ja": {
+		"header":             "🔄 TERRAFORM の循環依存を検出しました",
+		"header_terragrunt":  "🔄 TERRAGRUNT の依存関係の循環を検出しました",
+		"severity":           "⚠️  重大度: %s",
+		"partial":            "⏱  解析がタイムアウトしました: 最小サイクルを検出せず、Terraform が報告したサイクルを表示しています",
+		"stateful":           "🗄️  サイクル内のステートフルなリソース: %s。修正でこれらが置き換えられるとデータが失われるため注意してください",
+		"compare_progress":   "📉 %s から改善: 循環が %d ノードから %d ノードに縮小しました",
+		"compare_regression": "📈 %s から悪化: 循環が %d ノードから %d ノードに拡大しました",
+		"compare_unchanged":  "⏸  %s から変化なし: 同じ %d ノードの循環です",
+		"compare_changed":    "🔀 %s から変化あり: %d 件のリソースが追加、%d 件が削除されました",
+		"no_cycles":          "❌ 指定されたリソースに循環依存は見つかりませんでした",
+		"summary":            "📊 分析の概要",
+		"total_resources":    "循環内のリソース総数: %d",
+		"resource_types":     "リソースタイプ:",
+		"full_cycle":         "完全な循環 (%d リソース):",
+		"minimal_cycle":      "最小の循環 #%d (%d リソース):",
+		"more_cycles":        "... 他 %d 件の循環",
+		"more_resources":     "... 他 %d 件のリソース",
+		"depends_on":         "依存先",
+		"suggestions":        "💡 提案:",
+		"documentation":      "📚 ドキュメント:",
+		"common_solutions":   "🔧 一般的な解決策:",
+		"solution_cbd":       "置き換えが発生する場合は lifecycle { create_before_destroy = true } を使用する",
+		"solution_data":      "直接参照をデータソースによる参照に置き換える",
+		"solution_split":     "複雑なリソースを複数の Terraform 構成に分割する",
+		"solution_depends":   "depends_on を明示的に使用して依存関係の順序を制御する",
+		"all_resources":      "📋 循環内のすべてのリソース:",
+		"module":             "モジュール",
+		"md_title":           "Terraform の循環依存を検出しました",
+		"md_terragrunt":      "Terragrunt の依存関係の循環を検出しました",
+		"md_total_resources": "循環内のリソース総数",
+		"md_no_cycles":       "指定されたリソースに循環依存は見つかりませんでした。",
+		"md_minimal_cycle":   "最小の循環 #%d (%d リソース)",
+		"md_suggestions":     "提案",
+		"md_documentation":   "ドキュメント",
+		"md_all_resources":   "すべてのリソース",
+		"md_address":         "アドレス",
+		"md_action":          "アクション",
+		"apply_plan":         "🎯 段階的な apply 計画:",
+		"deferred_edges":     "循環を断ち切るために後回しにする依存関係:",
+	},
+	"pt-BR": {
+		"header":             "🔄 CICLO DO TERRAFORM DETECTADO",
+		"header_terragrunt":  "🔄 CICLO DE DEPENDÊNCIAS DO TERRAGRUNT DETECTADO",
+		"severity":           "⚠️  Severidade: %s",
+		"partial":            "⏱  A análise excedeu o tempo limite: exibindo o ciclo como listado pelo Terraform, sem detecção de ciclos mínimos",
+		"stateful":           "🗄️  Recursos com estado no ciclo: %s. Garanta que a correção não os substitua, ou seus dados serão perdidos",
+		"compare_progress":   "📉 Progresso desde %s: o ciclo diminuiu de %d para %d nós",
+		"compare_regression": "📈 Regressão desde %s: o ciclo cresceu de %d para %d nós",
+		"compare_unchanged":  "⏸  Sem mudanças desde %s: o mesmo ciclo de %d nós",
+		"compare_changed":    "🔀 Mudou desde %s: %d recursos adicionados, %d removidos",
+		"no_cycles":          "❌ Nenhum ciclo encontrado nos recursos informados",
+		"summary":            "📊 RESUMO DA ANÁLISE",
+		"total_resources":    "Total de recursos no ciclo: %d",
+		"resource_types":     "Tipos de recurso:",
+		"full_cycle":         "Ciclo completo (%d recursos):",
+		"minimal_cycle":      "Ciclo mínimo #%d (%d recursos):",
+		"more_cycles":        "... e mais %d ciclos",
+		"more_resources":     "... e mais %d recursos",
+		"depends_on":         "depende de",
+		"suggestions":        "💡 SUGESTÕES:",
+		"documentation":      "📚 DOCUMENTAÇÃO:",
+		"common_solutions":   "🔧 SOLUÇÕES COMUNS:",
+		"solution_cbd":       "Use lifecycle { create_before_destroy = true } em cenários de substituição",
+		"solution_data":      "Substitua referências diretas por consultas a data sources",
+		"solution_split":     "Divida recursos complexos em várias configurações do Terraform",
+		"solution_depends":   "Use depends_on explicitamente para controlar a ordem das dependências",
+		"all_resources":      "📋 TODOS OS RECURSOS NO CICLO:",
+		"module":             "módulo",
+		"md_title":           "Ciclo do Terraform detectado",
+		"md_terragrunt":      "Ciclo de dependências do Terragrunt detectado",
+		"md_total_resources": "Total de recursos no ciclo",
+		"md_no_cycles":       "Nenhum ciclo encontrado nos recursos informados.",
+		"md_minimal_cycle":   "Ciclo mínimo #%d (%d recursos)",
+		"md_suggestions":     "Sugestões",
+		"md_documentation":   "Documentação",
+		"md_all_resources":   "Todos os recursos",
+		"md_address":         "Endereço",
+		"md_action":          "Ação",
+		"apply_plan":         "🎯 PLANO DE APPLY EM ETAPAS:",
+		"deferred_edges":     "Dependências adiadas para quebrar o ciclo:",
+	},
+}
+
+// SupportedLanguages returns the report languages, sorted.
+func SupportedLanguages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// ResolveLanguage normalizes requested, or picks a supported language from
+// LC_ALL, LC_MESSAGES or LANG when it is empty.
+func ResolveLanguage(requested string) (string, error) {
+	if requested != "" {
+		lang := normalizeLanguage(requested)
+		if _, ok := catalogs[lang]; !ok {
+			return "", fmt.Errorf("unsupported language %q (supported: %s)", requested, strings.Join(SupportedLanguages(), ", "))
+		}
+		return lang, nil
+	}
+	
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		lang := normalizeLanguage(os.Getenv(env))
+		if _, ok := catalogs[lang]; ok {
+			return lang, nil
+		}
+	}
+	
+	return defaultLanguage, nil
+}
+
+func normalizeLanguage(value string) string {
+	value, _, _ = strings.Cut(value, ".")
+	value, _, _ = strings.Cut(value, "@")
+	value = strings.ReplaceAll(value, "_", "-")
+	
+	lang, region, _ := strings.Cut(value, "-")
+	lang = strings.ToLower(lang)
+	
+	if region != "" {
+		tagged := lang + "-" + strings.ToUpper(region)
+		if _, ok := catalogs[tagged]; ok {
+			return tagged
+		}
+	}
+	
+	if lang == "pt" {
+		return "pt-BR"
+	}
+	
+	return lang
+}
+
+func (c catalog) text(key string, args ...interface{}) string {
+	format, ok := c[key]
+	if !ok {
+		format = catalogs[defaultLanguage][key]
+	}
+	
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,36 @@
+package tfcycle
+
+import "testing"
+
+func TestCatalogs_Complete(t *testing.T) {
+	for lang, messages := range catalogs {
+		for key := range catalogs[defaultLanguage] {
+			if _, ok := messages[key]; !ok {
+				t.Errorf("Language %s is missing message %q", lang, key)
+			}
+		}
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	testCases := map[string]string{
+		"de_DE.UTF-8": "de",
+		"ja_JP":       "ja",
+		"pt_BR.UTF-8": "pt-BR",
+		"pt":          "pt-BR",
+		"EN":          "en",
+		"C":           "c",
+	}
+	
+	for input, expected := range testCases {
+		if got := normalizeLanguage(input); got != expected {
+			t.Errorf("normalizeLanguage(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestResolveLanguage_Unsupported(t *testing.T) {
+	if _, err := ResolveLanguage("xx"); err == nil {
+		t.Errorf("Expected error for unsupported language")
+	}
+}
@@ -0,0 +1,28 @@
+package tfcycle
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+const byteOrderMark = "\ufeff"
+
+// InputReader returns r decoded to UTF-8. A UTF-8 byte order mark is dropped,
+// and UTF-16 with a byte order mark, which Windows PowerShell writes for
+// "terraform plan 2> error.txt", is converted. Other input is passed through.
+func InputReader(r io.Reader) io.Reader {
+	return transform.NewReader(r, unicode.BOMOverride(transform.Nop))
+}
+
+// NormalizeInput drops a leading byte order mark and turns Windows ("\r\n")
+// and lone "\r" line endings into "\n".
+func NormalizeInput(text string) string {
+	text = strings.TrimPrefix(text, byteOrderMark)
+	if !strings.Contains(text, "\r") {
+		return text
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+}
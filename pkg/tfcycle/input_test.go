@@ -0,0 +1,61 @@
+package tfcycle
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func utf16LE(text string) string {
+	var data []byte
+	data = append(data, 0xff, 0xfe)
+	for _, unit := range utf16.Encode([]rune(text)) {
+		data = append(data, byte(unit), byte(unit>>8))
+	}
+	return string(data)
+}
+
+func TestNormalizeInput(t *testing.T) {
+	tests := map[string]string{
+		"a\r\nb\r\n":       "a\nb\n",
+		"a\rb\r\r\n":       "a\nb\n\n",
+		"\ufeffError: a\n": "Error: a\n",
+		"plain\n":          "plain\n",
+	}
+	for input, expected := range tests {
+		if normalized := NormalizeInput(input); normalized != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, input, normalized)
+		}
+	}
+}
+
+func TestInputReader(t *testing.T) {
+	input := "Error: Cycle: aws_instance.a, aws_instance.b\r\n"
+	for name, encoded := range map[string]string{"utf-8": "\ufeff" + input, "utf-16": utf16LE(input), "plain": input} {
+		data, err := io.ReadAll(InputReader(strings.NewReader(encoded)))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if string(data) != input {
+			t.Errorf("Expected %s input decoded to %q, got %q", name, input, data)
+		}
+	}
+}
+
+func TestParse_WindowsInput(t *testing.T) {
+	input := utf16LE("Plan: 1 to add\r\n\r\nError: Cycle: aws_instance.a,\r\naws_instance.b\r\n\r\n")
+	cycle, err := Parse(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cycle.Nodes) != 2 || strings.Contains(cycle.RawError, "\r") {
+		t.Errorf("Expected two nodes and no carriage returns, got %d nodes and %q", len(cycle.Nodes), cycle.RawError)
+	}
+	
+	cycle, err = ParseInput("\ufeff{\r\n  \"cycle\": {\"nodes\": [{\"type\": \"aws_instance\", \"name\": \"a\"}]}\r\n}\r\n")
+	if err != nil || len(cycle.Nodes) != 1 {
+		t.Errorf("Expected a JSON report with a byte order mark to parse, got %v", err)
+	}
+}
@@ -0,0 +1,336 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Input formats accepted by Parser.ParseFormat.
+const (
+	// InputAuto detects the format from the content (see DetectInputFormat).
+	InputAuto = "auto"
+	// InputText is Terraform, Terraform Stacks or Terragrunt output with a
+	// cycle error.
+	InputText = "text"
+	// InputTFJSON is Terraform's machine-readable output: the JSON lines of
+	// terraform plan -json or apply -json, or terraform validate -json.
+	InputTFJSON = "tfjson"
+	// InputDOT is the output of terraform graph.
+	InputDOT = "dot"
+	// InputPlan is terraform show -json of a saved plan.
+	InputPlan = "plan"
+	// InputState is a state file (terraform state pull) or terraform show
+	// -json of the state.
+	InputState = "state"
+	// InputReport is a tfcycle JSON analysis report.
+	InputReport = "report"
+)
+
+// InputFormats lists the input formats, InputAuto first.
+var InputFormats = []string{InputAuto, InputText, InputTFJSON, InputDOT, InputPlan, InputState, InputReport}
+
+// DetectInputFormat sniffs the format of text: a DOT digraph, JSON lines
+// with Terraform's "@level" key, validate output, a plan, a state or an
+// analysis report. Anything else is InputText.
+func DetectInputFormat(text string) string {
+	text = strings.TrimSpace(NormalizeInput(text))
+	switch {
+	case looksLikeDOT(text):
+		return InputDOT
+	case !looksLikeJSON(text):
+		return InputText
+	}
+	
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(text), &document); err != nil {
+		if isJSONLog(text) {
+			return InputTFJSON
+		}
+		return InputText
+	}
+	switch {
+	case document["cycle"] != nil:
+		return InputReport
+	case document["@level"] != nil, document["valid"] != nil && document["diagnostics"] != nil:
+		return InputTFJSON
+	case document["planned_values"] != nil, document["resource_changes"] != nil, document["configuration"] != nil:
+		return InputPlan
+	case document["resources"] != nil, document["values"] != nil:
+		return InputState
+	default:
+		return InputReport
+	}
+}
+
+// ParseFormat parses the first cycle in text, read as format ("" is
+// InputAuto). Text and tfjson input is parsed as by ParseError. For a graph,
+// plan or state, which record dependencies rather than a cycle error, the
+// first cycle among the dependencies is parsed as if Terraform had reported
+// it; ErrNoCycleFound means there is none.
+func (p *Parser) ParseFormat(text, format string) (*TfCycle, error) {
+	text = NormalizeInput(text)
+	if format == "" || format == InputAuto {
+		format = DetectInputFormat(text)
+	}
+	
+	switch format {
+	case InputText:
+		return p.ParseError(text)
+	case InputTFJSON:
+		return p.parseJSONLog(text)
+	case InputReport:
+		return parseAnalysisJSON(text)
+	case InputDOT, InputPlan, InputState:
+		graph, err := inputGraph(text, format)
+		if err != nil {
+			return nil, err
+		}
+		return p.parseGraph(graph)
+	default:
+		return nil, fmt.Errorf("unknown input format %q (use %s)", format, strings.Join(InputFormats, ", "))
+	}
+}
+
+// ContainsCycleFormat reports whether text, read as format, contains a cycle
+// error or, for a graph, plan or state, a cycle among its dependencies.
+func (p *Parser) ContainsCycleFormat(text, format string) bool {
+	text = NormalizeInput(text)
+	if format == "" || format == InputAuto {
+		format = DetectInputFormat(text)
+	}
+	
+	switch format {
+	case InputDOT, InputPlan, InputState:
+		graph, err := inputGraph(text, format)
+		return err == nil && len(stronglyConnected(graph)) > 0
+	case InputReport:
+		return looksLikeJSON(text)
+	default:
+		return p.ContainsCycle(text)
+	}
+}
+
+// parseJSONLog parses the first cycle diagnostic of terraform validate -json
+// output or of the JSON lines of terraform plan -json. Lines that are not
+// JSON messages are skipped.
+func (p *Parser) parseJSONLog(text string) (*TfCycle, error) {
+	if output, ok := decodeValidateJSON(text); ok {
+		return p.parseValidateJSON(output)
+	}
+	
+	var output validateOutput
+	for _, line := range strings.Split(text, "\n") {
+		var message struct {
+			Type       string              `json:"type"`
+			Diagnostic *validateDiagnostic `json:"diagnostic"`
+		}
+		if json.Unmarshal([]byte(line), &message) != nil || message.Type != "diagnostic" || message.Diagnostic == nil {
+			continue
+		}
+		output.Diagnostics = append(output.Diagnostics, *message.Diagnostic)
+	}
+	return p.parseValidateJSON(output)
+}
+
+// parseGraph parses the first cycle of graph as if Terraform had listed its
+// members in an "Error: Cycle:" line, starting with a shortest loop through
+// its first address.
+func (p *Parser) parseGraph(graph map[string][]string) (*TfCycle, error) {
+	components := stronglyConnected(graph)
+	if len(components) == 0 {
+		return nil, ErrNoCycleFound
+	}
+	if len(components) > 1 {
+		logger.Info("input has more than one cycle, parsing the first", "cycles", len(components))
+	}
+	
+	component := components[0]
+	first := component[0]
+	var order []string
+	for _, next := range graph[first] {
+		if slices.Contains(component, next) {
+			if path := shortestPath(graph, next, first, ""); path != nil {
+				order = append([]string{first}, path[:len(path)-1]...)
+				break
+			}
+		}
+	}
+	for _, name := range component {
+		if !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+	return p.ParseError("Error: Cycle: " + strings.Join(order, ", "))
+}
+
+// inputGraph returns the dependencies recorded in a DOT graph, plan or
+// state, keyed by the dependent address.
+func inputGraph(text, format string) (map[string][]string, error) {
+	switch format {
+	case InputDOT:
+		return parseDOTGraph(text), nil
+	case InputPlan:
+		return parsePlanGraph([]byte(text))
+	default:
+		return ParseStateDependencies([]byte(text))
+	}
+}
+
+var dotEdgeRegex = regexp.MustCompile(`^\s*"((?:[^"\\]|\\.)*)"\s*->\s*"((?:[^"\\]|\\.)*)"`)
+
+func looksLikeDOT(text string) bool {
+	return strings.HasPrefix(text, "digraph") || strings.HasPrefix(text, "strict digraph")
+}
+
+// parseDOTGraph returns the edges of terraform graph output. Node names
+// lose the "[root] " prefix of older Terraform versions and keep their
+// "(expand)" or "(close)" suffix, as in cycle errors. The root node,
+// providers and meta nodes are left out.
+func parseDOTGraph(text string) map[string][]string {
+	graph := make(map[string][]string)
+	for _, line := range strings.Split(text, "\n") {
+		matches := dotEdgeRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		from, to := dotNodeName(matches[1]), dotNodeName(matches[2])
+		if from == "" || to == "" || from == to || slices.Contains(graph[from], to) {
+			continue
+		}
+		graph[from] = append(graph[from], to)
+	}
+	return graph
+}
+
+func dotNodeName(id string) string {
+	name := strings.TrimPrefix(strings.ReplaceAll(id, `\"`, `"`), "[root] ")
+	if name == "root" || strings.HasPrefix(name, "provider[") || strings.HasPrefix(name, "meta.") {
+		return ""
+	}
+	return name
+}
+
+type planModule struct {
+	Resources []struct {
+		Address     string          `json:"address"`
+		Expressions json.RawMessage `json:"expressions"`
+		DependsOn   []string        `json:"depends_on"`
+	} `json:"resources"`
+	ModuleCalls map[string]struct {
+		Expressions json.RawMessage `json:"expressions"`
+		DependsOn   []string        `json:"depends_on"`
+		Module      planModule      `json:"module"`
+	} `json:"module_calls"`
+}
+
+// parsePlanGraph returns the dependencies of a plan: the references and
+// depends_on lists of its configuration, between the resources and module
+// calls of each module, and those recorded in its prior state.
+func parsePlanGraph(data []byte) (map[string][]string, error) {
+	var plan struct {
+		Configuration *struct {
+			RootModule planModule `json:"root_module"`
+		} `json:"configuration"`
+		PriorState json.RawMessage `json:"prior_state"`
+	}
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if plan.Configuration == nil {
+		return nil, fmt.Errorf("unsupported plan: expected terraform show -json output of a plan")
+	}
+	
+	graph := make(map[string][]string)
+	if plan.PriorState != nil {
+		if state, err := ParseStateDependencies(plan.PriorState); err == nil {
+			graph = state
+		}
+	}
+	add := func(prefix, from string, expressions json.RawMessage, dependsOn []string) {
+		from = prefix + from
+		for _, reference := range append(expressionReferences(expressions), dependsOn...) {
+			if to := referenceAddress(reference); to != "" && prefix+to != from && !slices.Contains(graph[from], prefix+to) {
+				graph[from] = append(graph[from], prefix+to)
+			}
+		}
+	}
+	
+	var walk func(prefix string, module planModule)
+	walk = func(prefix string, module planModule) {
+		for _, resource := range module.Resources {
+			add(prefix, resource.Address, resource.Expressions, resource.DependsOn)
+		}
+		for name, call := range module.ModuleCalls {
+			add(prefix, "module."+name, call.Expressions, call.DependsOn)
+			walk(prefix+"module."+name+".", call.Module)
+		}
+	}
+	walk("", plan.Configuration.RootModule)
+	return graph, nil
+}
+
+// expressionReferences returns every "references" list in the expressions
+// of a plan's configuration, however deeply nested.
+func expressionReferences(expressions json.RawMessage) []string {
+	var value any
+	if len(expressions) == 0 || json.Unmarshal(expressions, &value) != nil {
+		return nil
+	}
+	
+	var references []string
+	var walk func(value any)
+	walk = func(value any) {
+		switch value := value.(type) {
+		case map[string]any:
+			for key, child := range value {
+				if list, ok := child.([]any); ok && key == "references" {
+					for _, reference := range list {
+						if reference, ok := reference.(string); ok {
+							references = append(references, reference)
+						}
+					}
+					continue
+				}
+				walk(child)
+			}
+		case []any:
+			for _, child := range value {
+				walk(child)
+			}
+		}
+	}
+	walk(value)
+	return references
+}
+
+// referenceAddress returns the resource, data source or module call that a
+// reference such as aws_vpc.main.id or module.network.vpc_id points to, or
+// "" for variables, locals and other values of the module itself.
+func referenceAddress(reference string) string {
+	parts := strings.Split(stripInstanceKeys(reference), ".")
+	switch {
+	case len(parts) < 2:
+		return ""
+	case parts[0] == "var", parts[0] == "local", parts[0] == "each", parts[0] == "count",
+		parts[0] == "path", parts[0] == "self", parts[0] == "terraform":
+		return ""
+	case parts[0] == "data":
+		if len(parts) < 3 {
+			return ""
+		}
+		return strings.Join(parts[:3], ".")
+	default:
+		return parts[0] + "." + parts[1]
+	}
+}
+
+// isJSONLog reports whether the first line of text is a message of
+// Terraform's machine-readable UI output.
+func isJSONLog(text string) bool {
+	line, _, _ := strings.Cut(text, "\n")
+	var message map[string]json.RawMessage
+	return json.Unmarshal([]byte(line), &message) == nil && message["@level"] != nil
+}
@@ -0,0 +1,111 @@
+package tfcycle
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const jsonLogInput = `{"@level":"info","@message":"Terraform 1.9.5","type":"version","terraform":"1.9.5"}
+{"@level":"error","@message":"Error: Cycle: aws_instance.web, aws_security_group.sg","type":"diagnostic","diagnostic":{"severity":"error","summary":"Cycle: aws_instance.web, aws_security_group.sg","detail":""}}
+`
+
+const dotInput = `digraph {
+	compound = "true"
+	subgraph "root" {
+		"[root] aws_instance.web (expand)" [label = "aws_instance.web", shape = "box"]
+		"[root] aws_instance.web (expand)" -> "[root] aws_security_group.sg (expand)"
+		"[root] aws_security_group.sg (expand)" -> "[root] aws_instance.web (expand)"
+		"[root] aws_security_group.sg (expand)" -> "[root] provider[\"registry.terraform.io/hashicorp/aws\"]"
+		"[root] root" -> "[root] aws_instance.web (expand)"
+	}
+}
+`
+
+const planInput = `{
+  "format_version": "1.2",
+  "configuration": {
+    "root_module": {
+      "resources": [
+        {"address": "aws_instance.web", "expressions": {"vpc_security_group_ids": {"references": ["module.net.sg_id", "module.net"]}}},
+        {"address": "aws_eip.web", "expressions": {"instance": {"references": ["aws_instance.web.id", "aws_instance.web"]}}}
+      ],
+      "module_calls": {
+        "net": {
+          "expressions": {"instance_ip": {"references": ["aws_eip.web.public_ip", "aws_eip.web"]}},
+          "module": {"resources": [{"address": "aws_security_group.sg", "expressions": {"name": {"references": ["var.name"]}}}]}
+        }
+      }
+    }
+  }
+}`
+
+const stateInput = `{
+  "version": 4,
+  "resources": [
+    {"mode": "managed", "type": "aws_instance", "name": "web", "instances": [{"dependencies": ["aws_security_group.sg"]}]},
+    {"mode": "managed", "type": "aws_security_group", "name": "sg", "instances": [{"dependencies": ["aws_instance.web"]}]}
+  ]
+}`
+
+func TestDetectInputFormat(t *testing.T) {
+	tests := map[string]string{
+		"Error: Cycle: aws_instance.a, aws_instance.b": InputText,
+		jsonLogInput:                             InputTFJSON,
+		`{"valid": false, "diagnostics": []}`:    InputTFJSON,
+		dotInput:                                 InputDOT,
+		planInput:                                InputPlan,
+		stateInput:                               InputState,
+		`{"cycle": {"nodes": []}, "cycles": []}`: InputReport,
+	}
+	for input, expected := range tests {
+		if format := DetectInputFormat(input); format != expected {
+			t.Errorf("Expected %s for %.40q, got %s", expected, input, format)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		format   string
+		expected []string
+	}{
+		{jsonLogInput, InputTFJSON, []string{"aws_instance.web", "aws_security_group.sg"}},
+		{dotInput, InputDOT, []string{"aws_instance.web", "aws_security_group.sg"}},
+		{planInput, InputPlan, []string{"aws_eip.web", "aws_instance.web", "module.net"}},
+		{stateInput, InputState, []string{"aws_instance.web", "aws_security_group.sg"}},
+		{stateInput, InputAuto, []string{"aws_instance.web", "aws_security_group.sg"}},
+	}
+	for _, test := range tests {
+		cycle, err := NewParser().ParseFormat(test.input, test.format)
+		if err != nil {
+			t.Errorf("Expected no error for %s, got: %v", test.format, err)
+			continue
+		}
+		var names []string
+		for _, node := range cycle.Nodes {
+			names = append(names, node.FullName())
+		}
+		if strings.Join(names, ", ") != strings.Join(test.expected, ", ") {
+			t.Errorf("Expected %v for %s, got %v", test.expected, test.format, names)
+		}
+	}
+}
+
+func TestParseFormat_NoCycle(t *testing.T) {
+	acyclic := strings.Replace(stateInput, `"dependencies": ["aws_instance.web"]`, `"dependencies": []`, 1)
+	parser := NewParser()
+	if parser.ContainsCycleFormat(acyclic, InputAuto) {
+		t.Errorf("Expected no cycle in an acyclic state")
+	}
+	if _, err := parser.ParseFormat(acyclic, InputState); !errors.Is(err, ErrNoCycleFound) {
+		t.Errorf("Expected ErrNoCycleFound, got: %v", err)
+	}
+	if _, err := parser.ParseFormat(stateInput, "yaml"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+	if !parser.ContainsCycleFormat(dotInput, InputDOT) {
+		t.Errorf("Expected a cycle in the graph")
+	}
+}
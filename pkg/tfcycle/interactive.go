@@ -0,0 +1,106 @@
+package tfcycle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EdgeAnswer is a user's verdict on a hypothesized dependency.
+type EdgeAnswer int
+
+// Answers to ConfirmEdges prompts.
+const (
+	EdgeUnsure EdgeAnswer = iota
+	EdgeConfirmed
+	EdgeRejected
+)
+
+func parseEdgeAnswer(answer string) (EdgeAnswer, bool) {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return EdgeConfirmed, true
+	case "n", "no":
+		return EdgeRejected, true
+	case "", "u", "unsure", "?":
+		return EdgeUnsure, true
+	default:
+		return EdgeUnsure, false
+	}
+}
+
+// ConfirmEdges asks on out whether each hypothesized edge is real, reading
+// answers from in, and returns the graph without the rejected edges. Edges
+// left unanswered at EOF are kept.
+func (ca *CycleAnalyzer) ConfirmEdges(in io.Reader, out io.Writer) map[string][]string {
+	graph := ca.HypothesizedGraph()
+	confirmed := make(map[string][]string)
+	scanner := bufio.NewScanner(in)
+	exhausted := false
+	
+	edges := 0
+	for _, targets := range graph {
+		edges += len(targets)
+	}
+	fmt.Fprintf(out, "Confirm %d hypothesized dependencies (y = yes, n = no, Enter = unsure)\n\n", edges)
+	
+	for _, from := range ca.NodeNames() {
+		confirmed[from] = []string{}
+		for _, to := range graph[from] {
+			answer := EdgeUnsure
+			if !exhausted {
+				answer, exhausted = askEdge(scanner, out, ca.edgeReason(from, to))
+			}
+			if answer != EdgeRejected {
+				confirmed[from] = append(confirmed[from], to)
+			}
+		}
+	}
+	
+	fmt.Fprintln(out)
+	return confirmed
+}
+
+func askEdge(scanner *bufio.Scanner, out io.Writer, edge EdgeReason) (EdgeAnswer, bool) {
+	for {
+		fmt.Fprintf(out, "Does %s actually reference %s?\n  (%s)\n  [y/n/unsure]: ", edge.From, edge.To, edge.Reason)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return EdgeUnsure, true
+		}
+		if answer, ok := parseEdgeAnswer(scanner.Text()); ok {
+			return answer, false
+		}
+		fmt.Fprintln(out, "  Please answer y, n or unsure.")
+	}
+}
+
+// GraphHasCycle reports whether graph contains a cycle.
+func GraphHasCycle(graph map[string][]string) bool {
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := make(map[string]int)
+	
+	var visit func(node string) bool
+	visit = func(node string) bool {
+		state[node] = active
+		for _, next := range graph[node] {
+			if state[next] == active || (state[next] == unvisited && visit(next)) {
+				return true
+			}
+		}
+		state[node] = done
+		return false
+	}
+	
+	for _, node := range sortedKeys(graph) {
+		if state[node] == unvisited && visit(node) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package tfcycle
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newInteractiveAnalyzer() *CycleAnalyzer {
+	return NewCycleAnalyzer(&TfCycle{
+		Nodes: []*CycleNode{
+			{ResourceType: "aws_security_group", ResourceName: "sg1"},
+			{ResourceType: "aws_security_group", ResourceName: "sg2"},
+			{ResourceType: "aws_security_group", ResourceName: "sg3"},
+		},
+	})
+}
+
+func TestConfirmEdges_RecomputesCycles(t *testing.T) {
+	analyzer := newInteractiveAnalyzer()
+	ring := map[string]string{
+		"aws_security_group.sg1": "aws_security_group.sg2",
+		"aws_security_group.sg2": "aws_security_group.sg3",
+		"aws_security_group.sg3": "aws_security_group.sg1",
+	}
+	
+	graph := analyzer.HypothesizedGraph()
+	var answers strings.Builder
+	for _, from := range analyzer.NodeNames() {
+		for _, to := range graph[from] {
+			if ring[from] == to {
+				answers.WriteString("y\n")
+			} else {
+				answers.WriteString("n\n")
+			}
+		}
+	}
+	
+	var prompts bytes.Buffer
+	analyzer.SetGraph(analyzer.ConfirmEdges(strings.NewReader(answers.String()), &prompts))
+	
+	if !strings.Contains(prompts.String(), "Does aws_security_group.sg1 actually reference aws_security_group.sg2?") {
+		t.Errorf("Expected a prompt per edge, got:\n%s", prompts.String())
+	}
+	
+	cycles := analyzer.FindMinimalCycles()
+	expected := [][]string{{"aws_security_group.sg1", "aws_security_group.sg2", "aws_security_group.sg3"}}
+	if !reflect.DeepEqual(cycles, expected) {
+		t.Errorf("Expected %v, got %v", expected, cycles)
+	}
+}
+
+func TestConfirmEdges_UnsureKeepsEdges(t *testing.T) {
+	analyzer := newInteractiveAnalyzer()
+	hypothesized := analyzer.HypothesizedGraph()
+	
+	var prompts bytes.Buffer
+	confirmed := analyzer.ConfirmEdges(strings.NewReader("maybe\nunsure\n\n"), &prompts)
+	
+	if !strings.Contains(prompts.String(), "Please answer y, n or unsure.") {
+		t.Errorf("Expected invalid answer to be re-asked, got:\n%s", prompts.String())
+	}
+	
+	for from, targets := range hypothesized {
+		if !reflect.DeepEqual(confirmed[from], targets) {
+			t.Errorf("Expected edges %v from %s to be kept, got %v", targets, from, confirmed[from])
+		}
+	}
+}
+
+func TestGraphHasCycle(t *testing.T) {
+	if !GraphHasCycle(map[string][]string{"a": {"b"}, "b": {"a"}}) {
+		t.Errorf("Expected a -> b -> a to be a cycle")
+	}
+	if GraphHasCycle(map[string][]string{"a": {"b"}, "b": {}}) {
+		t.Errorf("Expected a -> b to have no cycle")
+	}
+}
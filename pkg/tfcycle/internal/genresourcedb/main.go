@@ -0,0 +1,112 @@
+// Command genresourcedb compiles resources.yaml into the resource knowledge
+// database of package tfcycle. It runs from go generate:
+//
+//	go run ./internal/genresourcedb resources.yaml resourcedb_gen.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var categories = map[string]string{
+	"network":  "CategoryNetwork",
+	"iam":      "CategoryIAM",
+	"compute":  "CategoryCompute",
+	"stateful": "CategoryStateful",
+}
+
+type entry struct {
+	Category    string            `yaml:"category"`
+	Split       []string          `yaml:"split"`
+	Docs        string            `yaml:"docs"`
+	MinVersions map[string]string `yaml:"min_versions"`
+}
+
+type database struct {
+	Types map[string]entry `yaml:"types"`
+}
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: genresourcedb INPUT.yaml OUTPUT.go")
+		os.Exit(2)
+	}
+	if err := generate(os.Args[1], os.Args[2]); err != nil {
+		fmt.Fprintln(os.Stderr, "genresourcedb:", err)
+		os.Exit(1)
+	}
+}
+
+func generate(input, output string) error {
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	var db database
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&db); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", input, err)
+	}
+	
+	types := make([]string, 0, len(db.Types))
+	for resourceType := range db.Types {
+		types = append(types, resourceType)
+	}
+	sort.Strings(types)
+	
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by genresourcedb from %s. DO NOT EDIT.\n\n", input)
+	source.WriteString("package tfcycle\n\nvar resourceDB = map[string]ResourceInfo{\n")
+	for _, resourceType := range types {
+		e := db.Types[resourceType]
+		category, ok := categories[e.Category]
+		if !ok {
+			return fmt.Errorf("%s: unknown category %q", resourceType, e.Category)
+		}
+		for _, split := range e.Split {
+			if _, ok := db.Types[split]; !ok {
+				return fmt.Errorf("%s: split resource %s is not in the database", resourceType, split)
+			}
+		}
+		
+		fmt.Fprintf(&source, "\t%q: {Type: %q, Category: %s", resourceType, resourceType, category)
+		if e.Docs != "" {
+			fmt.Fprintf(&source, ", DocURL: %q", e.Docs)
+		}
+		if len(e.Split) > 0 {
+			quoted := make([]string, len(e.Split))
+			for i, split := range e.Split {
+				quoted[i] = fmt.Sprintf("%q", split)
+			}
+			fmt.Fprintf(&source, ", SplitResources: []string{%s}", strings.Join(quoted, ", "))
+		}
+		if len(e.MinVersions) > 0 {
+			names := make([]string, 0, len(e.MinVersions))
+			for name := range e.MinVersions {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			pairs := make([]string, len(names))
+			for i, name := range names {
+				pairs[i] = fmt.Sprintf("%q: %q", name, e.MinVersions[name])
+			}
+			fmt.Fprintf(&source, ", MinVersions: map[string]string{%s}", strings.Join(pairs, ", "))
+		}
+		source.WriteString("},\n")
+	}
+	source.WriteString("}\n")
+	
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return os.WriteFile(output, formatted, 0644)
+}
@@ -0,0 +1,117 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JGFDocument is the hypothesized graph in JSON Graph Format version 2
+// (https://jsongraphformat.info), for generic graph tooling.
+type JGFDocument struct {
+	Graph JGFGraph `json:"graph"`
+}
+
+// JGFGraph is the single graph of a JGFDocument. Nodes are keyed by address.
+type JGFGraph struct {
+	ID       string             `json:"id"`
+	Type     string             `json:"type"`
+	Label    string             `json:"label"`
+	Directed bool               `json:"directed"`
+	Metadata JGFGraphMetadata   `json:"metadata"`
+	Nodes    map[string]JGFNode `json:"nodes"`
+	Edges    []JGFEdge          `json:"edges"`
+}
+
+// JGFGraphMetadata describes the analysis the graph comes from.
+type JGFGraphMetadata struct {
+	Tool        string     `json:"tool"`
+	ToolVersion string     `json:"tool_version"`
+	Severity    string     `json:"severity"`
+	Partial     bool       `json:"partial"`
+	Cycles      [][]string `json:"cycles"`
+}
+
+// JGFNode is a resource in a JGFGraph.
+type JGFNode struct {
+	Label    string          `json:"label"`
+	Metadata JGFNodeMetadata `json:"metadata"`
+}
+
+// JGFNodeMetadata holds the resource details of a JGFNode.
+type JGFNodeMetadata struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Module  string `json:"module,omitempty"`
+	Action  string `json:"action"`
+	InCycle bool   `json:"in_cycle"`
+}
+
+// JGFEdge is a hypothesized dependency in a JGFGraph: source depends on
+// target.
+type JGFEdge struct {
+	Source   string          `json:"source"`
+	Target   string          `json:"target"`
+	Relation string          `json:"relation"`
+	Directed bool            `json:"directed"`
+	Metadata JGFEdgeMetadata `json:"metadata"`
+}
+
+// JGFEdgeMetadata explains a JGFEdge.
+type JGFEdgeMetadata struct {
+	Reason  string `json:"reason"`
+	InCycle bool   `json:"in_cycle"`
+}
+
+// NewJGFDocument returns every node and hypothesized edge of analyzer's
+// cycle as a JSON Graph Format document.
+func NewJGFDocument(analyzer *CycleAnalyzer) JGFDocument {
+	data := analyzer.GraphData()
+	graph := JGFGraph{
+		ID:       "tfcycle",
+		Type:     "terraform-dependency-cycle",
+		Label:    "Terraform dependency cycle",
+		Directed: true,
+		Metadata: JGFGraphMetadata{
+			Tool:        "tfcycle",
+			ToolVersion: Version,
+			Severity:    analyzer.Severity().String(),
+			Partial:     analyzer.Partial,
+			Cycles:      analyzer.FindMinimalCycles(),
+		},
+		Nodes: make(map[string]JGFNode),
+		Edges: []JGFEdge{},
+	}
+	if graph.Metadata.Cycles == nil {
+		graph.Metadata.Cycles = [][]string{}
+	}
+	
+	for _, node := range data.Nodes {
+		metadata := JGFNodeMetadata{Address: node.ID, Action: node.Action, InCycle: node.InCycle}
+		if cycleNode := analyzer.cycle.GetNodeByName(node.ID); cycleNode != nil {
+			metadata.Address = cycleNode.Address()
+			metadata.Type = cycleNode.ResourceType
+			metadata.Module = strings.Join(cycleNode.ModulePath, ".")
+		}
+		graph.Nodes[node.ID] = JGFNode{Label: node.Label, Metadata: metadata}
+	}
+	for _, edge := range data.Edges {
+		graph.Edges = append(graph.Edges, JGFEdge{
+			Source:   edge.From,
+			Target:   edge.To,
+			Relation: "depends_on",
+			Directed: true,
+			Metadata: JGFEdgeMetadata{Reason: edge.Reason, InCycle: edge.InCycle},
+		})
+	}
+	return JGFDocument{Graph: graph}
+}
+
+// FormatJGF returns document as indented JSON.
+func FormatJGF(document JGFDocument) (string, error) {
+	jsonData, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON graph: %w", err)
+	}
+	return string(jsonData) + "\n", nil
+}
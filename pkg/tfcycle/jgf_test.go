@@ -0,0 +1,47 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewJGFDocument(t *testing.T) {
+	cycle, err := NewParser().ParseError(explainTestError)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	output, err := FormatJGF(NewJGFDocument(analyzer))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	var document JGFDocument
+	if err := json.Unmarshal([]byte(output), &document); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	graph := document.Graph
+	if !graph.Directed || graph.Metadata.Severity != "low" || len(graph.Metadata.Cycles) != 1 {
+		t.Errorf("Expected a directed graph with one low-severity cycle, got %+v", graph)
+	}
+	if len(graph.Nodes) != 3 || !graph.Nodes["aws_security_group.sg_ping"].Metadata.InCycle {
+		t.Errorf("Expected 3 nodes keyed by address, got %+v", graph.Nodes)
+	}
+	if node := graph.Nodes["aws_instance.web"]; node.Metadata.Action != "destroy" || node.Metadata.Type != "aws_instance" {
+		t.Errorf("Expected the instance's type and action, got %+v", node)
+	}
+	
+	inCycle := 0
+	for _, edge := range graph.Edges {
+		if _, ok := graph.Nodes[edge.Source]; !ok {
+			t.Errorf("Expected edge sources to be nodes, got %q", edge.Source)
+		}
+		if edge.Metadata.InCycle {
+			inCycle++
+		}
+	}
+	if inCycle != 2 {
+		t.Errorf("Expected the two security group edges in the cycle, got %+v", graph.Edges)
+	}
+}
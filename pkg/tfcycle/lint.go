@@ -0,0 +1,494 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Kinds of LintFinding.
+const (
+	// LintCycle is a cycle between resources of one module: terraform plan
+	// fails on it now.
+	LintCycle = "cycle"
+	// LintModuleCycle runs through module calls. Terraform fails only if the
+	// outputs and inputs involved depend on each other, so it is imminent
+	// rather than certain.
+	LintModuleCycle = "module_cycle"
+	// LintStateCycle needs a dependency recorded in state: it appears when
+	// the resources are destroyed or replaced, which orders them by the state
+	// dependencies as well as the configuration.
+	LintStateCycle = "state_cycle"
+	// LintNearCycle is not a cycle yet: a standalone resource such as an
+	// aws_security_group_rule depends on two resources that already depend
+	// on each other in one direction, so moving it inline into its parent
+	// would close the loop.
+	LintNearCycle = "near_cycle"
+)
+
+// Sources of LintEdge.
+const (
+	EdgeReference = "reference"
+	EdgeDependsOn = "depends_on"
+	EdgeState     = "state"
+)
+
+// LintEdge is a dependency of From on To found in the configuration or state.
+type LintEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Source   string `json:"source"`
+	Location string `json:"location,omitempty"`
+}
+
+// LintFinding is a cycle in the dependency graph of a configuration. Cycle
+// lists addresses in dependency order as FindMinimalCycles does, and Edges
+// the dependency behind each step. Fixes are those tfcycle fix proposes for
+// the cycle. For a near cycle, Cycle is the loop that inlining Via would
+// create. Rules lists the IDs of the heuristics that explain its edges and
+// of its suggestions.
+type LintFinding struct {
+	Kind        string       `json:"kind"`
+	Severity    Severity     `json:"severity"`
+	Cycle       []string     `json:"cycle"`
+	Via         string       `json:"via,omitempty"`
+	Edges       []LintEdge   `json:"edges"`
+	Rules       []string     `json:"rules,omitempty"`
+	Suggestions []Suggestion `json:"suggestions,omitempty"`
+	Fixes       []Fix        `json:"fixes,omitempty"`
+}
+
+// LintReport is the result of Lint.
+type LintReport struct {
+	Root     string        `json:"root"`
+	Blocks   int           `json:"blocks"`
+	Edges    int           `json:"edges"`
+	Findings []LintFinding `json:"findings"`
+	// NearCycles are warnings; they do not count as findings.
+	NearCycles []LintFinding `json:"near_cycles"`
+	// Ignored counts the findings and near cycles left out because of a
+	// tfcycle:ignore comment (see LintFinding.Ignored).
+	Ignored int `json:"ignored,omitempty"`
+}
+
+// referenceRegex matches the start of a reference such as aws_vpc.main.id,
+// data.aws_ami.ubuntu.id or module.network.vpc_id.
+var referenceRegex = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_.])((?:data\.)?[a-zA-Z_][a-zA-Z0-9_-]*\.[a-zA-Z_][a-zA-Z0-9_-]*)`)
+
+// Lint builds the dependency graph of the configuration in index from its
+// references and depends_on lists, adds the dependencies recorded in state
+// (see ParseStateDependencies; nil for none), and reports the cycles in it
+// before Terraform does. opts configure the analyzer that suggests fixes for
+// each cycle. Findings that a block in them ignores with a tfcycle:ignore
+// comment are left out.
+func Lint(index *ConfigIndex, state map[string][]string, opts ...Option) LintReport {
+	edges := configEdges(index)
+	for from, targets := range state {
+		if index.LookupAddress(from) == nil {
+			continue
+		}
+		for _, to := range targets {
+			if to != from && index.LookupAddress(to) != nil && !hasLintEdge(edges[from], to) {
+				edges[from] = append(edges[from], LintEdge{From: from, To: to, Source: EdgeState})
+			}
+		}
+	}
+	
+	report := LintReport{Root: index.Root, Findings: []LintFinding{}}
+	graph := make(map[string][]string)
+	for _, block := range index.Blocks {
+		report.Blocks++
+		for _, edge := range edges[block.Address()] {
+			graph[edge.From] = append(graph[edge.From], edge.To)
+			report.Edges++
+		}
+	}
+	
+	for _, component := range stronglyConnected(graph) {
+		for _, finding := range lintComponent(index, component, graph, edges, opts) {
+			if finding.Ignored(index) {
+				report.Ignored++
+				continue
+			}
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Severity > report.Findings[j].Severity
+	})
+	report.NearCycles = []LintFinding{}
+	for _, finding := range nearCycles(index, edges, graph) {
+		if finding.Ignored(index) {
+			report.Ignored++
+			continue
+		}
+		report.NearCycles = append(report.NearCycles, finding)
+	}
+	return report
+}
+
+// Ignored reports whether a block of the finding, a member of its cycle or
+// Via, has a tfcycle:ignore comment that matches its kind or one of its
+// Rules.
+func (f LintFinding) Ignored(index *ConfigIndex) bool {
+	for _, address := range append(slices.Clone(f.Cycle), f.Via) {
+		block := index.LookupAddress(address)
+		if block == nil || len(block.Ignore) == 0 {
+			continue
+		}
+		if matchesAny(block.Ignore, f.Kind) || slices.ContainsFunc(f.Rules, func(id string) bool { return matchesAny(block.Ignore, id) }) {
+			logger.Debug("lint finding ignored", "address", address, "kind", f.Kind, "cycle", f.Cycle)
+			return true
+		}
+	}
+	return false
+}
+
+// nearCycles returns the standalone resources that keep a loop open: a
+// resource of a split type of its parent (see ResourceInfo.SplitResources)
+// that also depends on a resource which already depends on the parent.
+func nearCycles(index *ConfigIndex, edges map[string][]LintEdge, graph map[string][]string) []LintFinding {
+	findings := []LintFinding{}
+	seen := make(map[string]bool)
+	for _, block := range index.Blocks {
+		via := block.Address()
+		for _, toParent := range edges[via] {
+			parent := index.LookupAddress(toParent.To)
+			if parent == nil || !slices.Contains(splitResources(parent.Type), block.Type) {
+				continue
+			}
+			for _, toOther := range edges[via] {
+				key := toParent.To + " " + toOther.To
+				if toOther.To == toParent.To || seen[key] {
+					continue
+				}
+				path := shortestPath(graph, toOther.To, toParent.To, via)
+				if path == nil || shortestPath(graph, toParent.To, toOther.To, "") != nil {
+					// No loop to close, or one that is already reported.
+					continue
+				}
+				seen[key] = true
+				
+				finding := LintFinding{
+					Kind:  LintNearCycle,
+					Cycle: append([]string{toParent.To}, path[:len(path)-1]...),
+					Via:   via,
+					Edges: []LintEdge{toParent, toOther},
+					Suggestions: []Suggestion{{
+						ID:     "keep-standalone",
+						Title:  fmt.Sprintf("Keep %s a standalone resource: moving it inline into %s would create a cycle", via, toParent.To),
+						Effort: EffortLow,
+					}},
+				}
+				for i := 0; i+1 < len(path); i++ {
+					for _, edge := range edges[path[i]] {
+						if edge.To == path[i+1] {
+							finding.Edges = append(finding.Edges, edge)
+						}
+					}
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings
+}
+
+// shortestPath returns the shortest path of graph from one address to
+// another that avoids skip, or nil if there is none.
+func shortestPath(graph map[string][]string, from, to, skip string) []string {
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if node == to {
+			var path []string
+			for ; node != ""; node = previous[node] {
+				path = append([]string{node}, path...)
+			}
+			return path
+		}
+		for _, next := range graph[node] {
+			if _, visited := previous[next]; !visited && next != skip {
+				previous[next] = node
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// configEdges returns the references and depends_on entries of every block
+// to the other blocks of its module, keyed by the referring address.
+func configEdges(index *ConfigIndex) map[string][]LintEdge {
+	edges := make(map[string][]LintEdge)
+	for _, block := range index.Blocks {
+		from := block.Address()
+		dependsOn, hasDependsOn := block.DependsOn()
+		for i, line := range block.Lines[1:] {
+			lineNumber := block.StartLine + 1 + i
+			for _, matches := range referenceRegex.FindAllStringSubmatch(stripComment(line), -1) {
+				to := localAddress(index, block.ModulePath, matches[1])
+				if to == "" || to == from || hasLintEdge(edges[from], to) {
+					continue
+				}
+				source := EdgeReference
+				if hasDependsOn && lineNumber >= dependsOn.StartLine && lineNumber <= dependsOn.EndLine {
+					source = EdgeDependsOn
+				}
+				edges[from] = append(edges[from], LintEdge{
+					From:     from,
+					To:       to,
+					Source:   source,
+					Location: fmt.Sprintf("%s:%d", filepath.ToSlash(filepath.Clean(block.File)), lineNumber),
+				})
+			}
+		}
+	}
+	return edges
+}
+
+// localAddress returns the address of the block in modulePath that reference
+// names, or "" if there is none.
+func localAddress(index *ConfigIndex, modulePath []string, reference string) string {
+	parts := strings.Split(reference, ".")
+	if parts[0] == "data" && len(parts) < 3 {
+		return ""
+	}
+	address := strings.Join(append(append([]string{}, modulePath...), parts...), ".")
+	if index.LookupAddress(address) == nil {
+		return ""
+	}
+	return address
+}
+
+func hasLintEdge(edges []LintEdge, to string) bool {
+	for _, edge := range edges {
+		if edge.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// lintComponent returns the minimal cycles of one strongly connected
+// component of the dependency graph.
+func lintComponent(index *ConfigIndex, component []string, graph map[string][]string, edges map[string][]LintEdge, opts []Option) []LintFinding {
+	inComponent := make(map[string]bool)
+	for _, address := range component {
+		inComponent[address] = true
+	}
+	
+	// Configuration addresses have no instance keys, so they are also the
+	// nodes' FullNames.
+	cycle := &TfCycle{RawError: "Error: Cycle: " + strings.Join(component, ", ")}
+	parser := NewParser()
+	subgraph := make(map[string][]string)
+	for _, address := range component {
+		node, err := parser.parseResource(address)
+		if err != nil {
+			logger.Debug("skipping unparsable lint node", "address", address, "error", err)
+			continue
+		}
+		cycle.Nodes = append(cycle.Nodes, node)
+		for _, to := range graph[address] {
+			if inComponent[to] {
+				subgraph[address] = append(subgraph[address], to)
+			}
+		}
+	}
+	
+	analyzer := NewCycleAnalyzer(cycle, append(opts, WithGraph(subgraph))...)
+	fixer := NewFixer(analyzer, index)
+	var findings []LintFinding
+	for _, minimal := range analyzer.FindMinimalCycles() {
+		finding := LintFinding{
+			Kind:        LintCycle,
+			Severity:    analyzer.CycleSeverity(minimal),
+			Cycle:       minimal,
+			Suggestions: analyzer.GenerateSuggestions(minimal),
+			Fixes:       fixer.fixesForCycle(minimal),
+		}
+		for i, from := range minimal {
+			to := minimal[(i+1)%len(minimal)]
+			for _, edge := range edges[from] {
+				if edge.To == to {
+					finding.Edges = append(finding.Edges, edge)
+				}
+			}
+			fromNode, toNode := cycle.GetNodeByName(from), cycle.GetNodeByName(to)
+			if fromNode == nil || toNode == nil {
+				continue
+			}
+			if id, _ := analyzer.dependencyHeuristic(fromNode, toNode); id != "" && !slices.Contains(finding.Rules, id) {
+				finding.Rules = append(finding.Rules, id)
+			}
+		}
+		for _, suggestion := range finding.Suggestions {
+			if suggestion.RuleID != "" && !slices.Contains(finding.Rules, suggestion.RuleID) {
+				finding.Rules = append(finding.Rules, suggestion.RuleID)
+			}
+		}
+		for _, edge := range finding.Edges {
+			switch {
+			case edge.Source == EdgeState:
+				finding.Kind = LintStateCycle
+			case finding.Kind == LintCycle && (isModuleCall(edge.From) || isModuleCall(edge.To)):
+				finding.Kind = LintModuleCycle
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+func isModuleCall(address string) bool {
+	parts := strings.Split(address, ".")
+	return len(parts) >= 2 && parts[len(parts)-2] == "module"
+}
+
+// stronglyConnected returns the strongly connected components of graph that
+// contain a cycle, each sorted, in order of their first address.
+func stronglyConnected(graph map[string][]string) [][]string {
+	var nodes []string
+	for from := range graph {
+		nodes = append(nodes, from)
+	}
+	sort.Strings(nodes)
+	
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+	
+	var visit func(node string)
+	visit = func(node string) {
+		index[node] = len(index)
+		lowLink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+		
+		for _, next := range graph[node] {
+			if _, seen := index[next]; !seen {
+				visit(next)
+				lowLink[node] = min(lowLink[node], lowLink[next])
+			} else if onStack[next] {
+				lowLink[node] = min(lowLink[node], index[next])
+			}
+		}
+		
+		if lowLink[node] != index[node] {
+			return
+		}
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == node {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+	
+	for _, node := range nodes {
+		if _, seen := index[node]; !seen {
+			visit(node)
+		}
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+	return components
+}
+
+// ParseStateDependencies returns the dependencies recorded in a state file
+// (terraform state pull) or in terraform show -json output, keyed by resource
+// address without instance keys.
+func ParseStateDependencies(data []byte) (map[string][]string, error) {
+	var document struct {
+		Version   int `json:"version"`
+		Resources []struct {
+			Module    string `json:"module"`
+			Mode      string `json:"mode"`
+			Type      string `json:"type"`
+			Name      string `json:"name"`
+			Instances []struct {
+				Dependencies []string `json:"dependencies"`
+			} `json:"instances"`
+		} `json:"resources"`
+		Values *struct {
+			RootModule showModule `json:"root_module"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	
+	dependencies := make(map[string][]string)
+	add := func(from string, targets []string) {
+		from = stripInstanceKeys(from)
+		for _, to := range targets {
+			to = stripInstanceKeys(to)
+			if !slices.Contains(dependencies[from], to) {
+				dependencies[from] = append(dependencies[from], to)
+			}
+		}
+	}
+	
+	switch {
+	case document.Values != nil:
+		var walk func(module showModule)
+		walk = func(module showModule) {
+			for _, resource := range module.Resources {
+				add(resource.Address, resource.DependsOn)
+			}
+			for _, child := range module.ChildModules {
+				walk(child)
+			}
+		}
+		walk(document.Values.RootModule)
+	case document.Version == 4:
+		for _, resource := range document.Resources {
+			parts := []string{}
+			if resource.Module != "" {
+				parts = append(parts, resource.Module)
+			}
+			if resource.Mode == "data" {
+				parts = append(parts, "data")
+			}
+			address := strings.Join(append(parts, resource.Type, resource.Name), ".")
+			for _, instance := range resource.Instances {
+				add(address, instance.Dependencies)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported state: expected a version 4 state file or terraform show -json output")
+	}
+	return dependencies, nil
+}
+
+type showModule struct {
+	Resources []struct {
+		Address   string   `json:"address"`
+		DependsOn []string `json:"depends_on"`
+	} `json:"resources"`
+	ChildModules []showModule `json:"child_modules"`
+}
+
+var instanceKeyRegex = regexp.MustCompile(`\[[^\]]*\]`)
+
+func stripInstanceKeys(address string) string {
+	return instanceKeyRegex.ReplaceAllString(address, "")
+}
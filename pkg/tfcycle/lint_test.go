@@ -0,0 +1,229 @@
+package tfcycle
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	dir := writeTestConfig(t, map[string]string{
+		"main.tf": `resource "aws_security_group" "web" {
+  ingress {
+    security_groups = [aws_security_group.db.id] # web <- db
+  }
+}
+
+resource "aws_security_group" "db" {
+  ingress {
+    security_groups = [aws_security_group.web.id]
+  }
+}
+
+resource "aws_instance" "app" {
+  ami = data.aws_ami.ubuntu.id
+}
+
+data "aws_ami" "ubuntu" {
+  depends_on = [aws_instance.app]
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket_policy" "logs" {
+  bucket = aws_s3_bucket.logs.id
+}
+`,
+	})
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	state := map[string][]string{"aws_s3_bucket.logs": {"aws_s3_bucket_policy.logs"}}
+	report := Lint(index, state)
+	if report.Blocks != 6 {
+		t.Errorf("Expected 6 blocks, got %d", report.Blocks)
+	}
+	
+	byCycle := make(map[string]LintFinding)
+	for _, finding := range report.Findings {
+		members := slices.Sorted(slices.Values(finding.Cycle))
+		byCycle[strings.Join(members, ",")] = finding
+	}
+	if len(byCycle) != 3 {
+		t.Fatalf("Expected 3 findings, got %+v", report.Findings)
+	}
+	
+	groups := byCycle["aws_security_group.db,aws_security_group.web"]
+	locations := []string{}
+	for _, edge := range groups.Edges {
+		locations = append(locations, edge.Location)
+	}
+	if groups.Kind != LintCycle || len(groups.Edges) != 2 || !strings.Contains(strings.Join(locations, " "), "main.tf:3") {
+		t.Errorf("Expected a configuration cycle between the security groups, got %+v", groups)
+	}
+	if len(groups.Suggestions) == 0 || groups.Suggestions[0].ID != "security-group-mutual-references" {
+		t.Errorf("Expected the security group suggestions, got %+v", groups.Suggestions)
+	}
+	if len(groups.Fixes) == 0 || groups.Fixes[0].Kind != "security_group_rule" || len(groups.Fixes[0].Edits) != 2 {
+		t.Errorf("Expected a security group rule fix with its removal and insertion, got %+v", groups.Fixes)
+	}
+	
+	ami := byCycle["aws_instance.app,data.aws_ami.ubuntu"]
+	if len(ami.Edges) != 2 || ami.Edges[1].Source != EdgeDependsOn {
+		t.Errorf("Expected a depends_on edge from the data source, got %+v", ami.Edges)
+	}
+	
+	bucket := byCycle["aws_s3_bucket.logs,aws_s3_bucket_policy.logs"]
+	if bucket.Kind != LintStateCycle || bucket.Severity != SeverityMedium {
+		t.Errorf("Expected a medium state cycle through the stateful bucket, got %+v", bucket)
+	}
+}
+
+func TestLint_ModuleCycle(t *testing.T) {
+	dir := writeTestConfig(t, map[string]string{
+		"main.tf": `module "network" {
+  source = "./network"
+  dns_id = module.dns.zone_id
+}
+
+module "dns" {
+  source = "./dns"
+  vpc_id = module.network.vpc_id
+}
+`,
+		"network/main.tf": `resource "aws_vpc" "main" {}`,
+		"dns/main.tf":     `resource "aws_route53_zone" "main" {}`,
+	})
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	report := Lint(index, nil)
+	if len(report.Findings) != 1 || report.Findings[0].Kind != LintModuleCycle {
+		t.Errorf("Expected one module cycle, got %+v", report.Findings)
+	}
+}
+
+func TestParseStateDependencies(t *testing.T) {
+	state := `{
+  "version": 4,
+  "resources": [
+    {"module": "module.app[0]", "mode": "managed", "type": "aws_instance", "name": "web",
+     "instances": [{"dependencies": ["module.app[0].aws_security_group.web", "module.app[0].data.aws_ami.ubuntu"]}]}
+  ]
+}`
+	dependencies, err := ParseStateDependencies([]byte(state))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	got := dependencies["module.app.aws_instance.web"]
+	if len(got) != 2 || got[0] != "module.app.aws_security_group.web" || got[1] != "module.app.data.aws_ami.ubuntu" {
+		t.Errorf("Expected the instance's dependencies without keys, got %v", dependencies)
+	}
+	
+	show := `{"values": {"root_module": {"child_modules": [{"resources": [
+  {"address": "module.db.aws_db_instance.main", "depends_on": ["module.db.aws_subnet.a"]}
+]}]}}}`
+	dependencies, err = ParseStateDependencies([]byte(show))
+	if err != nil || len(dependencies["module.db.aws_db_instance.main"]) != 1 {
+		t.Errorf("Expected show -json dependencies, got %v, %v", dependencies, err)
+	}
+	
+	if _, err := ParseStateDependencies([]byte(`{"version": 3}`)); err == nil {
+		t.Errorf("Expected an error for an unsupported state")
+	}
+}
+
+func TestLint_NearCycles(t *testing.T) {
+	dir := writeTestConfig(t, map[string]string{
+		"main.tf": `resource "aws_security_group" "web" {}
+
+resource "aws_instance" "web" {
+  vpc_security_group_ids = [aws_security_group.web.id]
+}
+
+resource "aws_security_group_rule" "web_from_instance" {
+  security_group_id = aws_security_group.web.id
+  cidr_blocks       = ["${aws_instance.web.private_ip}/32"]
+}
+`,
+	})
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	report := Lint(index, nil)
+	if len(report.Findings) != 0 {
+		t.Errorf("Expected no cycles, got %+v", report.Findings)
+	}
+	if len(report.NearCycles) != 1 {
+		t.Fatalf("Expected one near cycle, got %+v", report.NearCycles)
+	}
+	near := report.NearCycles[0]
+	if near.Via != "aws_security_group_rule.web_from_instance" || strings.Join(near.Cycle, ",") != "aws_security_group.web,aws_instance.web" {
+		t.Errorf("Expected the rule to keep the group and instance apart, got %+v", near)
+	}
+	if len(near.Edges) != 3 || len(near.Suggestions) != 1 {
+		t.Errorf("Expected the rule's two references and the instance's, got %+v", near)
+	}
+}
+
+func TestLint_IgnoreComments(t *testing.T) {
+	dir := writeTestConfig(t, map[string]string{
+		"main.tf": `# Peered on purpose, see ADR-12
+# tfcycle:ignore TFC001
+resource "aws_security_group" "web" {
+  ingress {
+    security_groups = [aws_security_group.db.id]
+  }
+}
+
+resource "aws_security_group" "db" {
+  ingress {
+    security_groups = [aws_security_group.web.id]
+  }
+}
+
+resource "aws_iam_role" "app" {
+  name = aws_iam_policy.app.name // tfcycle:ignore TFC-NOPE-*, near_cycle
+}
+
+resource "aws_iam_policy" "app" {
+  name = aws_iam_role.app.name
+}
+
+resource "aws_instance" "web" {
+  vpc_security_group_ids = [aws_security_group.web.id]
+}
+
+resource "aws_security_group_rule" "web_from_instance" { # tfcycle:ignore
+  security_group_id = aws_security_group.web.id
+  cidr_blocks       = ["${aws_instance.web.private_ip}/32"]
+}
+`,
+	})
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if ignore := index.LookupAddress("aws_iam_role.app").Ignore; strings.Join(ignore, " ") != "TFC-NOPE-* near_cycle" {
+		t.Errorf("Expected the role's patterns, got %v", ignore)
+	}
+	
+	report := Lint(index, nil)
+	if len(report.Findings) != 1 || !slices.Contains(report.Findings[0].Cycle, "aws_iam_role.app") {
+		t.Fatalf("Expected only the IAM cycle, got %+v", report.Findings)
+	}
+	if !slices.Contains(report.Findings[0].Rules, "TFC004") {
+		t.Errorf("Expected the IAM heuristic in the rules, got %v", report.Findings[0].Rules)
+	}
+	if len(report.NearCycles) != 0 || report.Ignored != 2 {
+		t.Errorf("Expected the group cycle and near cycle ignored, got %d and %+v", report.Ignored, report.NearCycles)
+	}
+}
@@ -0,0 +1,11 @@
+package tfcycle
+
+import "log/slog"
+
+var logger = slog.New(slog.DiscardHandler)
+
+// SetLogger directs the package's diagnostics to l. They are discarded by
+// default.
+func SetLogger(l *slog.Logger) {
+	logger = l
+}
@@ -0,0 +1,74 @@
+package tfcycle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResourceMove moves one resource address to another.
+type ResourceMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// MovePlan moves resources into a new child module.
+type MovePlan struct {
+	Module string         `json:"module"`
+	Moves  []ResourceMove `json:"moves"`
+}
+
+// NewMovePlan plans moving nodes into module targetModule.
+func NewMovePlan(nodes []*CycleNode, targetModule string) MovePlan {
+	plan := MovePlan{Module: targetModule}
+	seen := make(map[string]bool)
+	
+	for _, node := range nodes {
+		from := node.ResourceType + "." + node.ResourceName
+		if seen[from] {
+			continue
+		}
+		seen[from] = true
+		
+		plan.Moves = append(plan.Moves, ResourceMove{
+			From: from,
+			To:   "module." + targetModule + "." + from,
+		})
+	}
+	
+	sort.Slice(plan.Moves, func(i, j int) bool {
+		return plan.Moves[i].From < plan.Moves[j].From
+	})
+	
+	return plan
+}
+
+// MovedBlocks returns the plan as Terraform moved blocks.
+func (mp MovePlan) MovedBlocks() string {
+	var output strings.Builder
+	for i, move := range mp.Moves {
+		if i > 0 {
+			output.WriteString("\n")
+		}
+		output.WriteString("moved {\n")
+		output.WriteString(fmt.Sprintf("  from = %s\n", move.From))
+		output.WriteString(fmt.Sprintf("  to   = %s\n", move.To))
+		output.WriteString("}\n")
+	}
+	return output.String()
+}
+
+// StateMvCommands returns the equivalent terraform state mv commands, with
+// both addresses prefixed by modulePath.
+func (mp MovePlan) StateMvCommands(modulePath []string) []string {
+	prefix := ""
+	if len(modulePath) > 0 {
+		prefix = strings.Join(modulePath, ".") + "."
+	}
+	
+	var commands []string
+	for _, move := range mp.Moves {
+		commands = append(commands, fmt.Sprintf("terraform state mv '%s%s' '%s%s'", prefix, move.From, prefix, move.To))
+	}
+	return commands
+}
@@ -0,0 +1,116 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// OPASchemaVersion is the SchemaVersion of the documents NewOPAInput returns.
+// Version 2 switched addresses to CycleNode.Address, quoting string keys.
+const OPASchemaVersion = 2
+
+// OPAInput is the input document for policy engines such as Open Policy
+// Agent. SchemaVersion changes on incompatible changes.
+type OPAInput struct {
+	SchemaVersion int           `json:"schema_version"`
+	Tool          string        `json:"tool"`
+	ToolVersion   string        `json:"tool_version"`
+	CycleFound    bool          `json:"cycle_found"`
+	Partial       bool          `json:"partial"`
+	Severity      string        `json:"severity"`
+	ResourceTypes []string      `json:"resource_types"`
+	Resources     []OPAResource `json:"resources"`
+	Cycles        []OPACycle    `json:"cycles"`
+}
+
+// OPAResource is a cycle member in OPAInput.
+type OPAResource struct {
+	Address     string `json:"address"`
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Module      string `json:"module"`
+	InstanceKey string `json:"instance_key"`
+	Action      string `json:"action"`
+}
+
+// OPACycle is a minimal cycle in OPAInput.
+type OPACycle struct {
+	Resources     []string `json:"resources"`
+	ResourceTypes []string `json:"resource_types"`
+	Modules       []string `json:"modules"`
+	Severity      string   `json:"severity"`
+	HasDestroy    bool     `json:"has_destroy"`
+}
+
+// NewOPAInput builds the policy input for analyzer. A nil analyzer yields the
+// document for input without a cycle.
+func NewOPAInput(analyzer *CycleAnalyzer) OPAInput {
+	input := OPAInput{
+		SchemaVersion: OPASchemaVersion,
+		Tool:          "tfcycle",
+		ToolVersion:   Version,
+		Severity:      SeverityNone.String(),
+		ResourceTypes: []string{},
+		Resources:     []OPAResource{},
+		Cycles:        []OPACycle{},
+	}
+	if analyzer == nil {
+		return input
+	}
+	
+	input.CycleFound = true
+	input.Partial = analyzer.Partial
+	input.Severity = analyzer.Severity().String()
+	input.ResourceTypes = sortedKeys(analyzer.cycle.GetResourceTypes())
+	
+	for _, node := range analyzer.cycle.Nodes {
+		input.Resources = append(input.Resources, OPAResource{
+			Address:     node.Address(),
+			Type:        node.ResourceType,
+			Name:        node.ResourceName,
+			Module:      strings.Join(node.ModulePath, "."),
+			InstanceKey: node.InstanceKey,
+			Action:      node.Action.String(),
+		})
+	}
+	
+	for _, cycle := range analyzer.FindMinimalCycles() {
+		types := make(map[string]bool)
+		modules := make(map[string]bool)
+		hasDestroy := false
+		addresses := make([]string, 0, len(cycle))
+		for _, name := range cycle {
+			node := analyzer.cycle.GetNodeByName(name)
+			if node == nil {
+				addresses = append(addresses, name)
+				continue
+			}
+			addresses = append(addresses, node.Address())
+			types[node.ResourceType] = true
+			modules[strings.Join(node.ModulePath, ".")] = true
+			if node.Action == ActionDestroy || node.Action == ActionDestroyDeposed {
+				hasDestroy = true
+			}
+		}
+		
+		input.Cycles = append(input.Cycles, OPACycle{
+			Resources:     addresses,
+			ResourceTypes: sortedKeys(types),
+			Modules:       sortedKeys(modules),
+			Severity:      analyzer.CycleSeverity(cycle).String(),
+			HasDestroy:    hasDestroy,
+		})
+	}
+	
+	return input
+}
+
+// FormatOPAInput returns input as indented JSON.
+func FormatOPAInput(input OPAInput) (string, error) {
+	jsonData, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal OPA input: %w", err)
+	}
+	return string(jsonData) + "\n", nil
+}
@@ -0,0 +1,49 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewOPAInput(t *testing.T) {
+	cycle, err := NewParser().ParseError(explainTestError)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	output, err := FormatOPAInput(NewOPAInput(analyzer))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	var input OPAInput
+	if err := json.Unmarshal([]byte(output), &input); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if !input.CycleFound || input.Severity != "low" || input.SchemaVersion != OPASchemaVersion {
+		t.Errorf("Expected a low-severity cycle document, got %+v", input)
+	}
+	if len(input.Resources) != 3 || input.Resources[2].Action != "destroy" {
+		t.Errorf("Expected 3 resources with the destroy action kept, got %+v", input.Resources)
+	}
+	if len(input.Cycles) != 1 || input.Cycles[0].HasDestroy || len(input.Cycles[0].ResourceTypes) != 1 {
+		t.Errorf("Expected one security group cycle without destroys, got %+v", input.Cycles)
+	}
+}
+
+func TestNewOPAInput_NoCycle(t *testing.T) {
+	output, err := FormatOPAInput(NewOPAInput(nil))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	var input map[string]interface{}
+	json.Unmarshal([]byte(output), &input)
+	if input["cycle_found"] != false || input["severity"] != "none" {
+		t.Errorf("Expected an empty document, got %v", input)
+	}
+	if cycles, ok := input["cycles"].([]interface{}); !ok || len(cycles) != 0 {
+		t.Errorf("Expected cycles to be an empty array, got %v", input["cycles"])
+	}
+}
@@ -0,0 +1,105 @@
+package tfcycle
+
+import "strings"
+
+// Option configures a CycleAnalyzer. Pass options to NewCycleAnalyzer or
+// Analyze.
+type Option func(*CycleAnalyzer)
+
+// WithGraph replaces the hypothesized dependency graph, as SetGraph does.
+func WithGraph(graph map[string][]string) Option {
+	return func(ca *CycleAnalyzer) {
+		ca.SetGraph(graph)
+	}
+}
+
+// WithHeuristics replaces DefaultHeuristics. With no heuristics the analyzer
+// relies on edge sources and the sequential fallback alone.
+func WithHeuristics(heuristics ...Heuristic) Option {
+	return func(ca *CycleAnalyzer) {
+		ca.heuristics = append([]Heuristic{}, heuristics...)
+	}
+}
+
+// WithEdgeSources adds known edges, such as the depends_on references of a
+// ConfigIndex, to the edges the heuristics infer.
+func WithEdgeSources(sources ...EdgeSource) Option {
+	return func(ca *CycleAnalyzer) {
+		ca.edgeSources = append(ca.edgeSources, sources...)
+	}
+}
+
+// WithMaxCycles limits FindMinimalCycles to the n shortest cycles. Zero, the
+// default, keeps them all.
+func WithMaxCycles(n int) Option {
+	return func(ca *CycleAnalyzer) {
+		ca.maxCycles = n
+	}
+}
+
+// WithMinConfidence ignores heuristics whose Confidence is below threshold.
+func WithMinConfidence(threshold float64) Option {
+	return func(ca *CycleAnalyzer) {
+		ca.minConfidence = threshold
+	}
+}
+
+// WithSuggestionRules replaces DefaultSuggestionRules, for example with the
+// result of MergeSuggestionRules.
+func WithSuggestionRules(rules ...SuggestionRule) Option {
+	return func(ca *CycleAnalyzer) {
+		ca.suggestionRules = append([]SuggestionRule{}, rules...)
+	}
+}
+
+// WithEdgeVerdicts adds the confirmed edges to the hypothesized graph and
+// removes the rejected ones. Verdicts about addresses outside the cycle are
+// ignored.
+func WithEdgeVerdicts(verdicts EdgeVerdicts) Option {
+	return func(ca *CycleAnalyzer) {
+		ca.verdicts = verdicts
+	}
+}
+
+// WithRuleSelection turns heuristics and suggestions off and on by rule ID.
+// Both lists hold globs such as TFC-AWS-*; enabled wins over disabled, so
+// "disable TFC-AWS-*, enable TFC-AWS-SG-002" keeps one AWS suggestion.
+func WithRuleSelection(enabled, disabled []string) Option {
+	return func(ca *CycleAnalyzer) {
+		ca.rules = ruleSelection{enabled: enabled, disabled: disabled}
+	}
+}
+
+// WithSuppressedSuggestions hides the suggestions whose rule ID matches one
+// of patterns, globs such as TFC-AWS-SG-*. Unlike WithRuleSelection it leaves
+// the heuristics on, so the cycles found stay the same; only the advice is
+// trimmed.
+func WithSuppressedSuggestions(patterns ...string) Option {
+	return func(ca *CycleAnalyzer) {
+		ca.suppressed = append(ca.suppressed, patterns...)
+	}
+}
+
+// WithoutSuggestions hides every suggestion, the common solutions and the
+// staged apply plan, so reports hold only the facts about the cycle.
+func WithoutSuggestions() Option {
+	return func(ca *CycleAnalyzer) {
+		ca.noSuggestions = true
+	}
+}
+
+// WithTerraformVersion overrides the Terraform or OpenTofu version detected
+// in the input, which decides the version-specific suggestions and fixes.
+func WithTerraformVersion(version string) Option {
+	return func(ca *CycleAnalyzer) {
+		var versions Versions
+		if ca.cycle.Versions != nil {
+			versions = *ca.cycle.Versions
+		}
+		versions.Core = strings.TrimPrefix(version, "v")
+		if versions.Tool == "" {
+			versions.Tool = ToolTerraform
+		}
+		ca.cycle.Versions = &versions
+	}
+}
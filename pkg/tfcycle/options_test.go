@@ -0,0 +1,145 @@
+package tfcycle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithHeuristics_Selected(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_iam_role.a, aws_iam_policy.b, aws_security_group.c, aws_security_group.d")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	heuristics, unknown := HeuristicsByName("security-group-peers", "no-such-heuristic")
+	if len(heuristics) != 1 || len(unknown) != 1 || unknown[0] != "no-such-heuristic" {
+		t.Fatalf("Expected one heuristic and one unknown name, got %d and %v", len(heuristics), unknown)
+	}
+	
+	analyzer := NewCycleAnalyzer(cycle, WithHeuristics(heuristics...))
+	graph := analyzer.HypothesizedGraph()
+	if len(graph["aws_iam_role.a"]) != 1 || graph["aws_iam_role.a"][0] != "aws_iam_policy.b" {
+		t.Errorf("Expected the sequential fallback without the iam heuristic, got %v", graph)
+	}
+}
+
+func TestWithMinConfidence(t *testing.T) {
+	node := func(resourceType string, action NodeAction) *CycleNode {
+		return &CycleNode{ResourceType: resourceType, ResourceName: "x", Action: action}
+	}
+	from, to := node("aws_instance", ActionDestroy), node("aws_s3_bucket", ActionNormal)
+	
+	if NewCycleAnalyzer(&TfCycle{}).dependencyReason(from, to) == "" {
+		t.Fatal("Expected the destroy-ordering heuristic to match by default")
+	}
+	if reason := NewCycleAnalyzer(&TfCycle{}, WithMinConfidence(0.5)).dependencyReason(from, to); reason != "" {
+		t.Errorf("Expected no reason below the confidence threshold, got %q", reason)
+	}
+}
+
+func TestWithMaxCycles(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_instance.a, aws_instance.b, aws_instance.c, aws_instance.d")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	graph := map[string][]string{
+		"aws_instance.a": {"aws_instance.b"},
+		"aws_instance.b": {"aws_instance.a"},
+		"aws_instance.c": {"aws_instance.d"},
+		"aws_instance.d": {"aws_instance.c"},
+	}
+	
+	if got := len(NewCycleAnalyzer(cycle, WithGraph(graph)).FindMinimalCycles()); got != 2 {
+		t.Fatalf("Expected 2 cycles without a limit, got %d", got)
+	}
+	if got := len(NewCycleAnalyzer(cycle, WithGraph(graph), WithMaxCycles(1)).FindMinimalCycles()); got != 1 {
+		t.Errorf("Expected 1 cycle with WithMaxCycles(1), got %d", got)
+	}
+}
+
+func TestWithEdgeSources_ConfigIndex(t *testing.T) {
+	dir := t.TempDir()
+	config := `resource "aws_s3_bucket" "logs" {
+  bucket     = "logs"
+  depends_on = [aws_lambda_function.audit]
+}
+
+resource "aws_lambda_function" "audit" {
+  function_name = "audit"
+  depends_on    = [aws_s3_bucket.logs]
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_s3_bucket.logs, aws_lambda_function.audit")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	edges := index.Edges(cycle)
+	if len(edges["aws_s3_bucket.logs"]) != 1 || edges["aws_s3_bucket.logs"][0] != "aws_lambda_function.audit" {
+		t.Errorf("Expected depends_on edge from the bucket to the function, got %v", edges)
+	}
+	
+	analyzer := NewCycleAnalyzer(cycle, WithHeuristics(), WithEdgeSources(index))
+	graph := analyzer.HypothesizedGraph()
+	if len(graph["aws_lambda_function.audit"]) != 1 || graph["aws_lambda_function.audit"][0] != "aws_s3_bucket.logs" {
+		t.Errorf("Expected depends_on edge from the function to the bucket, got %v", graph)
+	}
+}
+
+func TestWithRuleSelection(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_security_group.a, aws_security_group.b")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	analyzer := NewCycleAnalyzer(cycle, WithRuleSelection([]string{"TFC-AWS-SG-002"}, []string{"TFC-AWS-*", "TFC001"}))
+	suggestions := analyzer.GenerateSuggestions(analyzer.NodeNames())
+	if len(suggestions) != 1 || suggestions[0].RuleID != "TFC-AWS-SG-002" {
+		t.Errorf("Expected only the re-enabled TFC-AWS-SG-002, got %+v", suggestions)
+	}
+	if reason := analyzer.dependencyReason(cycle.Nodes[0], cycle.Nodes[1]); reason != "" {
+		t.Errorf("Expected no reason with TFC001 disabled, got %q", reason)
+	}
+}
+
+func TestWithSuppressedSuggestions(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_security_group.a, aws_security_group.b")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	analyzer := NewCycleAnalyzer(cycle, WithSuppressedSuggestions("TFC-AWS-SG-002"))
+	for _, suggestion := range analyzer.GenerateSuggestions(analyzer.NodeNames()) {
+		if suggestion.RuleID == "TFC-AWS-SG-002" {
+			t.Errorf("Expected TFC-AWS-SG-002 to be suppressed, got %+v", suggestion)
+		}
+	}
+	if reason := analyzer.dependencyReason(cycle.Nodes[0], cycle.Nodes[1]); reason == "" {
+		t.Error("Expected the heuristics to stay on")
+	}
+}
+
+func TestWithoutSuggestions(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_security_group.a, aws_security_group.b")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	analyzer := NewCycleAnalyzer(cycle, WithoutSuggestions())
+	if suggestions := analyzer.GenerateSuggestions(analyzer.NodeNames()); len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions, got %+v", suggestions)
+	}
+	output := NewOutputFormatter(analyzer, false).FormatAnalysis()
+	if !strings.Contains(output, "aws_security_group.a") || strings.Contains(output, "SUGGESTIONS") || strings.Contains(output, "COMMON SOLUTIONS") {
+		t.Errorf("Expected only the cycle, got:\n%s", output)
+	}
+}
@@ -0,0 +1,289 @@
+package tfcycle
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Parser extracts the resources from Terraform's "Error: Cycle:" output.
+type Parser struct {
+	cycleRegex     *regexp.Regexp
+	resourceRegex  *regexp.Regexp
+	moduleRegex    *regexp.Regexp
+	instanceRegex  *regexp.Regexp
+	actionRegex    *regexp.Regexp
+	deposedRegex   *regexp.Regexp
+	contextLines   int
+}
+
+// NewParser returns a Parser.
+func NewParser() *Parser {
+	return &Parser{
+		cycleRegex:     regexp.MustCompile(`(?s)Error:\s*Cycle:\s*(.+)`),
+		resourceRegex:  regexp.MustCompile(`([a-zA-Z0-9_-]+)\.([a-zA-Z0-9_-]+)`),
+		moduleRegex:    regexp.MustCompile(`^((?:(?:module|stack)\.[a-zA-Z0-9_-]+\.)*)`),
+		instanceRegex:  regexp.MustCompile(`\[([^\]]+)\]`),
+		actionRegex:    regexp.MustCompile(`\s*\((expand|destroy|close|destroy\s+deposed\s+[a-f0-9]+)\)`),
+		deposedRegex:   regexp.MustCompile(`destroy\s+deposed\s+([a-f0-9]+)`),
+		contextLines:   DefaultContextLines,
+	}
+}
+
+// SetContextLines sets how many non-blank lines before a cycle error are
+// kept as its Context; 0 keeps none.
+func (p *Parser) SetContextLines(n int) {
+	p.contextLines = n
+}
+
+// ContainsCycle reports whether text contains a Terraform, Terraform Stacks
+// or Terragrunt cycle error.
+func (p *Parser) ContainsCycle(text string) bool {
+	text = NormalizeInput(text)
+	return p.cycleRegex.MatchString(text) || stackCycleRegex.MatchString(text) || terragruntCycleRegex.MatchString(text) ||
+		isValidateCycle(text)
+}
+
+// ParseError parses the first cycle error in errorText, a Terraform Stacks
+// cycle error (see parseStackCycle), a Terragrunt dependency cycle error
+// (see parseTerragruntCycle) or "terraform validate -json" output (see
+// parseValidateJSON). An address listed
+// more than once becomes a single node (see mergeDuplicateNodes). Resources
+// that cannot be parsed are logged and skipped; if none can be parsed the returned error
+// wraps an UnparsableResourceError for each. It returns ErrEmptyInput or
+// ErrNoCycleFound when there is nothing to parse. Terraform, OpenTofu and
+// provider versions mentioned in errorText are recorded in Versions. Line
+// endings are normalized first (see NormalizeInput).
+func (p *Parser) ParseError(errorText string) (*TfCycle, error) {
+	errorText = NormalizeInput(errorText)
+	cycle, err := p.parseError(errorText)
+	if err != nil {
+		return nil, err
+	}
+	cycle.Versions = DetectVersions(errorText)
+	return cycle, nil
+}
+
+func (p *Parser) parseError(errorText string) (*TfCycle, error) {
+	cycle := &TfCycle{
+		RawError: errorText,
+		Nodes:    make([]*CycleNode, 0),
+	}
+	
+	if strings.TrimSpace(errorText) == "" {
+		return nil, ErrEmptyInput
+	}
+	
+	if output, ok := decodeValidateJSON(errorText); ok {
+		return p.parseValidateJSON(output)
+	}
+	if matches := stackCycleRegex.FindStringSubmatch(errorText); matches != nil && !p.cycleRegex.MatchString(errorText) {
+		return p.parseStackCycle(errorText, matches[1])
+	}
+	if matches := terragruntCycleRegex.FindStringSubmatch(errorText); matches != nil && !p.cycleRegex.MatchString(errorText) {
+		return parseTerragruntCycle(errorText, matches[1])
+	}
+	
+	matches := p.cycleRegex.FindStringSubmatchIndex(errorText)
+	if matches == nil {
+		return nil, ErrNoCycleFound
+	}
+	
+	cycleText := errorText[matches[2]:matches[3]]
+	resourceStrings := p.splitResources(cycleText)
+	
+	var unparsable []error
+	for _, resourceStr := range resourceStrings {
+		node, err := p.parseResource(strings.TrimSpace(resourceStr))
+		if err != nil {
+			logger.Warn("failed to parse resource", "resource", resourceStr, "error", err)
+			unparsable = append(unparsable, err)
+			continue
+		}
+		cycle.Nodes = append(cycle.Nodes, node)
+	}
+	
+	cycle.Nodes = mergeDuplicateNodes(cycle.Nodes)
+	
+	if len(cycle.Nodes) == 0 {
+		if len(unparsable) == 0 {
+			return nil, ErrNoCycleFound
+		}
+		return nil, fmt.Errorf("no valid resources found in cycle: %w", errors.Join(unparsable...))
+	}
+	
+	if cycle.Context = parseContext(errorText[:matches[0]], p.contextLines); cycle.Context != nil {
+		applyContext(cycle, cycle.Context)
+	}
+	return cycle, nil
+}
+
+// mergeDuplicateNodes merges nodes that share an address, such as
+// "aws_instance.a" and "aws_instance.a (destroy)", keeping the position of
+// the first. The merged node takes the most significant action; if the
+// listings differed, the "actions" annotation lists each action seen.
+func mergeDuplicateNodes(nodes []*CycleNode) []*CycleNode {
+	merged := make([]*CycleNode, 0, len(nodes))
+	byName := make(map[string]*CycleNode)
+	actions := make(map[string][]string)
+	
+	for _, node := range nodes {
+		name := node.FullName()
+		existing, ok := byName[name]
+		if !ok {
+			byName[name] = node
+			actions[name] = []string{node.Action.String()}
+			merged = append(merged, node)
+			continue
+		}
+		
+		logger.Debug("merging duplicate cycle node", "address", name, "listed_as", node.RawString)
+		if !slices.Contains(actions[name], node.Action.String()) {
+			actions[name] = append(actions[name], node.Action.String())
+		}
+		if actionRank(node.Action) > actionRank(existing.Action) {
+			existing.Action = node.Action
+			existing.RawString = node.RawString
+		}
+		for key, value := range node.Annotations {
+			if _, set := existing.Annotations[key]; !set {
+				existing.Annotations[key] = value
+			}
+		}
+	}
+	
+	for _, node := range merged {
+		if seen := actions[node.FullName()]; len(seen) > 1 {
+			node.Annotations["actions"] = strings.Join(seen, ",")
+		}
+	}
+	return merged
+}
+
+// actionRank orders actions by how much they matter to the analysis: destroy
+// nodes drive the destroy-ordering heuristics and severity.
+func actionRank(action NodeAction) int {
+	switch action {
+	case ActionDestroy, ActionDestroyDeposed:
+		return 2
+	case ActionExpand, ActionClose:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (p *Parser) splitResources(cycleText string) []string {
+	cycleText = strings.ReplaceAll(cycleText, "\n", " ")
+	cycleText = strings.ReplaceAll(cycleText, "\t", " ")
+	
+	var resources []string
+	var current strings.Builder
+	inBrackets := 0
+	inParens := 0
+	
+	for _, char := range cycleText {
+		switch char {
+		case '[':
+			inBrackets++
+			current.WriteRune(char)
+		case ']':
+			inBrackets--
+			current.WriteRune(char)
+		case '(':
+			inParens++
+			current.WriteRune(char)
+		case ')':
+			inParens--
+			current.WriteRune(char)
+		case ',':
+			if inBrackets == 0 && inParens == 0 {
+				if current.Len() > 0 {
+					resources = append(resources, current.String())
+					current.Reset()
+				}
+			} else {
+				current.WriteRune(char)
+			}
+		default:
+			current.WriteRune(char)
+		}
+	}
+	
+	if current.Len() > 0 {
+		resources = append(resources, current.String())
+	}
+	
+	var filtered []string
+	for _, resource := range resources {
+		trimmed := strings.TrimSpace(resource)
+		if trimmed != "" {
+			filtered = append(filtered, trimmed)
+		}
+	}
+	
+	return filtered
+}
+
+func (p *Parser) parseResource(resourceStr string) (*CycleNode, error) {
+	node := &CycleNode{
+		RawString:   resourceStr,
+		Action:      ActionNormal,
+		Annotations: make(map[string]string),
+	}
+	
+	cleanStr := resourceStr
+	
+	actionMatches := p.actionRegex.FindStringSubmatch(resourceStr)
+	if len(actionMatches) >= 2 {
+		actionStr := strings.TrimSpace(actionMatches[1])
+		cleanStr = p.actionRegex.ReplaceAllString(cleanStr, "")
+		
+		switch {
+		case actionStr == "expand":
+			node.Action = ActionExpand
+		case actionStr == "close":
+			node.Action = ActionClose
+		case actionStr == "destroy":
+			node.Action = ActionDestroy
+		case strings.HasPrefix(actionStr, "destroy deposed"):
+			node.Action = ActionDestroyDeposed
+			deposedMatches := p.deposedRegex.FindStringSubmatch(actionStr)
+			if len(deposedMatches) >= 2 {
+				node.Annotations["deposed_id"] = deposedMatches[1]
+			}
+		}
+	}
+	
+	instanceMatches := p.instanceRegex.FindStringSubmatch(cleanStr)
+	if len(instanceMatches) >= 2 {
+		node.InstanceKey = strings.Trim(instanceMatches[1], `"`)
+		cleanStr = p.instanceRegex.ReplaceAllString(cleanStr, "")
+	}
+	
+	moduleMatches := p.moduleRegex.FindStringSubmatch(cleanStr)
+	if len(moduleMatches) >= 2 && moduleMatches[1] != "" {
+		modulePath := strings.TrimSuffix(moduleMatches[1], ".")
+		if modulePath != "" {
+			node.ModulePath = strings.Split(modulePath, ".")
+		}
+		cleanStr = strings.TrimPrefix(cleanStr, moduleMatches[1])
+	}
+	
+	if strings.HasPrefix(cleanStr, "data.") {
+		node.DataSource = true
+		cleanStr = strings.TrimPrefix(cleanStr, "data.")
+	}
+	
+	resourceMatches := p.resourceRegex.FindStringSubmatch(cleanStr)
+	if len(resourceMatches) < 3 {
+		return nil, &UnparsableResourceError{Resource: resourceStr}
+	}
+	
+	node.ResourceType = resourceMatches[1]
+	node.ResourceName = resourceMatches[2]
+	
+	return node, nil
+}
\ No newline at end of file
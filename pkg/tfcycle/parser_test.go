@@ -1,7 +1,9 @@
-package main
+package tfcycle
 
 import (
+	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -138,6 +140,34 @@ module.vpc.module.security.aws_security_group.sg_8080 (destroy deposed abc123ef)
 	}
 }
 
+func TestParser_ParseError_MergesDuplicates(t *testing.T) {
+	parser := NewParser()
+	errorText := `Error: Cycle: aws_instance.web, aws_security_group.sg, aws_instance.web (destroy), aws_security_group.sg`
+	
+	cycle, err := parser.ParseError(errorText)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	if len(cycle.Nodes) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(cycle.Nodes))
+	}
+	
+	web := cycle.Nodes[0]
+	if web.FullName() != "aws_instance.web" || web.Action != ActionDestroy {
+		t.Errorf("Expected aws_instance.web merged as a destroy node, got %s", web)
+	}
+	if web.Annotations["actions"] != "normal,destroy" {
+		t.Errorf("Expected actions annotation 'normal,destroy', got '%s'", web.Annotations["actions"])
+	}
+	if _, ok := cycle.Nodes[1].Annotations["actions"]; ok {
+		t.Errorf("Expected no actions annotation for an exact duplicate")
+	}
+	if count := cycle.GetResourceTypes()["aws_instance"]; count != 1 {
+		t.Errorf("Expected 1 aws_instance, got %d", count)
+	}
+}
+
 func TestParser_ParseError_InvalidInput(t *testing.T) {
 	parser := NewParser()
 	errorText := "This is not a cycle error"
@@ -191,6 +221,50 @@ func TestCycleNode_FullName(t *testing.T) {
 	}
 }
 
+func TestCycleNode_Address(t *testing.T) {
+	parser := NewParser()
+	errorText := `Error: Cycle: module.app.aws_instance.web["prod"], aws_instance.db[0], module.app.data.aws_ami.ubuntu, aws_ami.ubuntu, aws_subnet.private["10"] (destroy)`
+	
+	cycle, err := parser.ParseError(errorText)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	expected := []struct{ address, fullName string }{
+		{`module.app.aws_instance.web["prod"]`, "module.app.aws_instance.web[prod]"},
+		{"aws_instance.db[0]", "aws_instance.db[0]"},
+		{"module.app.data.aws_ami.ubuntu", "module.app.data.aws_ami.ubuntu"},
+		{"aws_ami.ubuntu", "aws_ami.ubuntu"},
+		{`aws_subnet.private["10"]`, "aws_subnet.private[10]"},
+	}
+	if len(cycle.Nodes) != len(expected) {
+		t.Fatalf("Expected %d nodes, got %d", len(expected), len(cycle.Nodes))
+	}
+	for i, want := range expected {
+		node := cycle.Nodes[i]
+		if node.Address() != want.address || node.FullName() != want.fullName {
+			t.Errorf("Node %d: expected %s (%s), got %s (%s)", i, want.address, want.fullName, node.Address(), node.FullName())
+		}
+	}
+	if !cycle.Nodes[2].DataSource || cycle.Nodes[2].ResourceType != "aws_ami" || cycle.Nodes[2].ResourceName != "ubuntu" {
+		t.Errorf("Expected a data source aws_ami.ubuntu, got %+v", cycle.Nodes[2])
+	}
+	
+	// Without the raw listing, only numeric keys are taken to be count indexes.
+	node := &CycleNode{ResourceType: "aws_instance", ResourceName: "web", InstanceKey: "blue"}
+	if node.Address() != `aws_instance.web["blue"]` {
+		t.Errorf("Expected a quoted string key, got %s", node.Address())
+	}
+	
+	data, err := json.Marshal(cycle.Nodes[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(string(data), `"address":"module.app.aws_instance.web[\"prod\"]"`) {
+		t.Errorf("Expected the address in JSON, got %s", data)
+	}
+}
+
 func TestCycleNode_String(t *testing.T) {
 	node := &CycleNode{
 		ResourceType: "aws_security_group",
@@ -202,4 +276,31 @@ func TestCycleNode_String(t *testing.T) {
 	if node.String() != expected {
 		t.Errorf("Expected '%s', got '%s'", expected, node.String())
 	}
-}
\ No newline at end of file
+}
+
+func TestNodeAction_JSON(t *testing.T) {
+	node := &CycleNode{ResourceType: "aws_instance", ResourceName: "web", Action: ActionDestroyDeposed}
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(string(data), `"action":"destroy_deposed"`) {
+		t.Errorf("Expected the action as a string, got %s", data)
+	}
+	
+	var decoded CycleNode
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Action != ActionDestroyDeposed {
+		t.Errorf("Expected destroy_deposed to round-trip, got %v (%v)", decoded.Action, err)
+	}
+	
+	// Schema version 1 reports encoded actions as integers.
+	if err := json.Unmarshal([]byte(`{"action": 2}`), &decoded); err != nil || decoded.Action != ActionDestroy {
+		t.Errorf("Expected integer action 2 to decode as destroy, got %v (%v)", decoded.Action, err)
+	}
+	
+	for _, invalid := range []string{`{"action": "explode"}`, `{"action": 9}`, `{"action": true}`} {
+		if err := json.Unmarshal([]byte(invalid), &decoded); err == nil {
+			t.Errorf("Expected an error for %s", invalid)
+		}
+	}
+}
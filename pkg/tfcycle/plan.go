@@ -0,0 +1,234 @@
+package tfcycle
+
+import (
+	"sort"
+	"strings"
+)
+
+// Edge is a dependency between two node addresses.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ApplyStage is one targeted terraform apply.
+type ApplyStage struct {
+	Targets []string `json:"targets"`
+	Command string   `json:"command"`
+}
+
+// ApplyPlan breaks a cycle by deferring the FeedbackArcs and applying the
+// Stages in order.
+type ApplyPlan struct {
+	FeedbackArcs []Edge       `json:"feedback_arcs"`
+	Stages       []ApplyStage `json:"stages"`
+}
+
+// FeedbackArcSet returns a small set of hypothesized edges whose removal makes
+// the graph acyclic.
+func (ca *CycleAnalyzer) FeedbackArcSet() []Edge {
+	nodeNames := ca.NodeNames()
+	graph := ca.buildHypotheticalGraph(nodeNames)
+	order := eadesOrdering(graph, nodeNames)
+	
+	position := make(map[string]int, len(order))
+	for i, name := range order {
+		position[name] = i
+	}
+	
+	var arcs []Edge
+	for _, from := range nodeNames {
+		for _, to := range graph[from] {
+			if position[to] < position[from] {
+				arcs = append(arcs, Edge{From: from, To: to})
+			}
+		}
+	}
+	
+	return arcs
+}
+
+func eadesOrdering(graph map[string][]string, nodeNames []string) []string {
+	remaining := make(map[string]bool, len(nodeNames))
+	for _, name := range nodeNames {
+		remaining[name] = true
+	}
+	
+	inDegree := func(node string) int {
+		count := 0
+		for _, from := range nodeNames {
+			if !remaining[from] {
+				continue
+			}
+			for _, to := range graph[from] {
+				if to == node {
+					count++
+				}
+			}
+		}
+		return count
+	}
+	outDegree := func(node string) int {
+		count := 0
+		for _, to := range graph[node] {
+			if remaining[to] {
+				count++
+			}
+		}
+		return count
+	}
+	
+	var head, tail []string
+	for len(remaining) > 0 {
+		changed := true
+		for changed {
+			changed = false
+			for _, name := range nodeNames {
+				if remaining[name] && outDegree(name) == 0 {
+					tail = append([]string{name}, tail...)
+					delete(remaining, name)
+					changed = true
+				}
+			}
+			for _, name := range nodeNames {
+				if remaining[name] && inDegree(name) == 0 {
+					head = append(head, name)
+					delete(remaining, name)
+					changed = true
+				}
+			}
+		}
+		
+		best := ""
+		bestDelta := 0
+		for _, name := range nodeNames {
+			if !remaining[name] {
+				continue
+			}
+			delta := outDegree(name) - inDegree(name)
+			if best == "" || delta > bestDelta {
+				best = name
+				bestDelta = delta
+			}
+		}
+		if best != "" {
+			head = append(head, best)
+			delete(remaining, best)
+		}
+	}
+	
+	return append(head, tail...)
+}
+
+// StagedApplyPlan returns targeted applies that create the resources in
+// dependency order once the feedback arcs are deferred.
+func (ca *CycleAnalyzer) StagedApplyPlan() ApplyPlan {
+	nodeNames := ca.NodeNames()
+	graph := ca.buildHypotheticalGraph(nodeNames)
+	arcs := ca.FeedbackArcSet()
+	
+	deferred := make(map[Edge]bool, len(arcs))
+	for _, arc := range arcs {
+		deferred[arc] = true
+	}
+	
+	levels := make(map[string]int)
+	var level func(node string, visiting map[string]bool) int
+	level = func(node string, visiting map[string]bool) int {
+		if l, ok := levels[node]; ok {
+			return l
+		}
+		if visiting[node] {
+			return 0
+		}
+		visiting[node] = true
+		
+		l := 0
+		for _, dep := range graph[node] {
+			if deferred[Edge{From: node, To: dep}] {
+				continue
+			}
+			if depLevel := level(dep, visiting) + 1; depLevel > l {
+				l = depLevel
+			}
+		}
+		
+		delete(visiting, node)
+		levels[node] = l
+		return l
+	}
+	
+	maxLevel := 0
+	for _, name := range nodeNames {
+		if l := level(name, make(map[string]bool)); l > maxLevel {
+			maxLevel = l
+		}
+	}
+	
+	plan := ApplyPlan{FeedbackArcs: arcs}
+	for l := 0; l <= maxLevel; l++ {
+		seen := make(map[string]bool)
+		var targets []string
+		for _, name := range nodeNames {
+			if levels[name] != l {
+				continue
+			}
+			target := targetAddress(ca.cycle.GetNodeByName(name))
+			if target != "" && !seen[target] {
+				seen[target] = true
+				targets = append(targets, target)
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		sort.Strings(targets)
+		
+		if ca.cycle.Terragrunt() {
+			commands := make([]string, len(targets))
+			for i, target := range targets {
+				commands[i] = "(cd " + shellQuote(target) + " && terragrunt apply)"
+			}
+			plan.Stages = append(plan.Stages, ApplyStage{
+				Targets: targets,
+				Command: strings.Join(commands, " && "),
+			})
+			continue
+		}
+		
+		args := make([]string, len(targets))
+		for i, target := range targets {
+			args[i] = "-target=" + shellQuote(target)
+		}
+		plan.Stages = append(plan.Stages, ApplyStage{
+			Targets: targets,
+			Command: "terraform apply " + strings.Join(args, " "),
+		})
+	}
+	
+	final := "terraform apply"
+	if ca.cycle.Terragrunt() {
+		final = "terragrunt run-all apply"
+	}
+	plan.Stages = append(plan.Stages, ApplyStage{
+		Targets: []string{},
+		Command: final,
+	})
+	
+	return plan
+}
+
+func targetAddress(node *CycleNode) string {
+	if node == nil {
+		return ""
+	}
+	if node.Unit != "" {
+		return node.Unit
+	}
+	switch node.ResourceType {
+	case "", "local", "var", "output", "component":
+		return ""
+	}
+	
+	return node.Address()
+}
@@ -0,0 +1,87 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCycleAnalyzer_FeedbackArcSet_BreaksCycles(t *testing.T) {
+	cycle := &TfCycle{
+		Nodes: []*CycleNode{
+			{ResourceType: "aws_security_group", ResourceName: "a"},
+			{ResourceType: "aws_security_group", ResourceName: "b"},
+			{ResourceType: "aws_instance", ResourceName: "web"},
+		},
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	arcs := analyzer.FeedbackArcSet()
+	if len(arcs) == 0 {
+		t.Fatalf("Expected a non-empty feedback arc set")
+	}
+	
+	removed := make(map[Edge]bool)
+	for _, arc := range arcs {
+		removed[arc] = true
+	}
+	
+	graph := analyzer.HypothesizedGraph()
+	dag := make(map[string][]string)
+	for from, targets := range graph {
+		for _, to := range targets {
+			if !removed[Edge{From: from, To: to}] {
+				dag[from] = append(dag[from], to)
+			}
+		}
+	}
+	
+	cycles := analyzer.findCyclesInGraph(dag, analyzer.NodeNames())
+	for _, c := range cycles {
+		if len(c) < len(cycle.Nodes) {
+			t.Errorf("Expected no cycles after removing the feedback arc set, found %v", c)
+		}
+	}
+}
+
+func TestCycleAnalyzer_StagedApplyPlan(t *testing.T) {
+	cycle := &TfCycle{
+		Nodes: []*CycleNode{
+			{ResourceType: "aws_security_group", ResourceName: "a"},
+			{ResourceType: "aws_security_group", ResourceName: "b", InstanceKey: "prod"},
+		},
+	}
+	
+	plan := NewCycleAnalyzer(cycle).StagedApplyPlan()
+	if len(plan.Stages) != 3 {
+		t.Fatalf("Expected 2 targeted stages and a final apply, got %+v", plan.Stages)
+	}
+	
+	if plan.Stages[2].Command != "terraform apply" {
+		t.Errorf("Expected final untargeted apply, got %q", plan.Stages[2].Command)
+	}
+	
+	var all []string
+	for _, stage := range plan.Stages[:2] {
+		all = append(all, stage.Command)
+	}
+	if !strings.Contains(strings.Join(all, "\n"), `-target='aws_security_group.b["prod"]'`) {
+		t.Errorf("Expected quoted instance key in target, got %v", all)
+	}
+}
+
+func TestCycleAnalyzer_StagedApplyPlanQuotesTargets(t *testing.T) {
+	cycle := &TfCycle{
+		Nodes: []*CycleNode{
+			{ResourceType: "aws_iam_role", ResourceName: "r", InstanceKey: "it's"},
+			{ResourceType: "aws_iam_policy", ResourceName: "p"},
+		},
+	}
+	
+	var all []string
+	for _, stage := range NewCycleAnalyzer(cycle).StagedApplyPlan().Stages {
+		all = append(all, stage.Command)
+	}
+	if !strings.Contains(strings.Join(all, "\n"), `-target='aws_iam_role.r["it'\''s"]'`) {
+		t.Errorf("Expected the quote in the instance key to be escaped, got %v", all)
+	}
+}
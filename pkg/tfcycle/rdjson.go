@@ -0,0 +1,139 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Severities of an RDDiagnostic.
+const (
+	RDError   = "ERROR"
+	RDWarning = "WARNING"
+	RDInfo    = "INFO"
+)
+
+// RDJSONResult is a Reviewdog Diagnostic Format result
+// (https://github.com/reviewdog/reviewdog/tree/master/proto/rdf), for
+// reviewdog -f=rdjson.
+type RDJSONResult struct {
+	Source      RDSource       `json:"source"`
+	Diagnostics []RDDiagnostic `json:"diagnostics"`
+}
+
+// RDSource names the tool behind an RDJSONResult or RDDiagnostic.
+type RDSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// RDDiagnostic is a finding at a location, with the fixes reviewdog can
+// propose as review suggestions.
+type RDDiagnostic struct {
+	Message     string         `json:"message"`
+	Location    RDLocation     `json:"location"`
+	Severity    string         `json:"severity"`
+	Source      *RDSource      `json:"source,omitempty"`
+	Code        *RDCode        `json:"code,omitempty"`
+	Suggestions []RDSuggestion `json:"suggestions,omitempty"`
+}
+
+// RDLocation is a file and an optional range in it.
+type RDLocation struct {
+	Path  string   `json:"path"`
+	Range *RDRange `json:"range,omitempty"`
+}
+
+// RDRange spans Start to the exclusive End. Lines and columns count from 1.
+type RDRange struct {
+	Start RDPosition  `json:"start"`
+	End   *RDPosition `json:"end,omitempty"`
+}
+
+// RDPosition is a line and column, both counted from 1.
+type RDPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+}
+
+// RDCode identifies the rule behind an RDDiagnostic.
+type RDCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+// RDSuggestion replaces Range with Text.
+type RDSuggestion struct {
+	Range RDRange `json:"range"`
+	Text  string  `json:"text"`
+}
+
+const rdSourceURL = "https://github.com/raimdev/tfcycle"
+
+// NewRDJSONResult reports each minimal cycle of analyzer on every member
+// whose configuration block is known, as resolved by
+// ConfigIndex.ResolveLocations. A cycle with no known member is reported
+// without a location, which reviewdog shows with -filter-mode=nofilter.
+func NewRDJSONResult(analyzer *CycleAnalyzer) RDJSONResult {
+	result := RDJSONResult{Source: RDSource{Name: "tfcycle", URL: rdSourceURL}, Diagnostics: []RDDiagnostic{}}
+	for _, cycle := range analyzer.FindMinimalCycles() {
+		message := fmt.Sprintf("Cycle (%s): %s", analyzer.CycleSeverity(cycle), CycleStrings([][]string{cycle})[0])
+		for _, suggestion := range analyzer.GenerateSuggestions(cycle) {
+			message += "\n💡 " + suggestion.Title
+		}
+		
+		var locations []RDLocation
+		for _, name := range cycle {
+			if node := analyzer.cycle.GetNodeByName(name); node != nil && node.Location != nil {
+				locations = append(locations, RDLocation{
+					Path:  node.Location.File,
+					Range: &RDRange{Start: RDPosition{Line: node.Location.Line}},
+				})
+			}
+		}
+		if len(locations) == 0 {
+			locations = []RDLocation{{}}
+		}
+		for _, location := range locations {
+			result.Diagnostics = append(result.Diagnostics, RDDiagnostic{
+				Message:  message,
+				Location: location,
+				Severity: RDError,
+				Code:     &RDCode{Value: "cycle"},
+			})
+		}
+	}
+	return result
+}
+
+// RDSuggestions returns the edits of fix to file as suggestions, replacing
+// whole lines. Fixes without Edits have none.
+func RDSuggestions(fix Fix, file string) []RDSuggestion {
+	var suggestions []RDSuggestion
+	for _, edit := range fix.Edits {
+		if edit.File != file {
+			continue
+		}
+		text := ""
+		if len(edit.Lines) > 0 {
+			text = strings.Join(edit.Lines, "\n") + "\n"
+		}
+		suggestions = append(suggestions, RDSuggestion{
+			Range: RDRange{
+				Start: RDPosition{Line: edit.Line, Column: 1},
+				End:   &RDPosition{Line: edit.Line + edit.Count, Column: 1},
+			},
+			Text: text,
+		})
+	}
+	return suggestions
+}
+
+// FormatRDJSON returns result as indented JSON.
+func FormatRDJSON(result RDJSONResult) (string, error) {
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal rdjson: %w", err)
+	}
+	return string(jsonData) + "\n", nil
+}
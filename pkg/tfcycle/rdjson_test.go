@@ -0,0 +1,48 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewRDJSONResult(t *testing.T) {
+	cycle, err := NewParser().ParseError(explainTestError)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	result := NewRDJSONResult(NewCycleAnalyzer(cycle))
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Location.Path != "" {
+		t.Fatalf("Expected one diagnostic without a location, got %+v", result.Diagnostics)
+	}
+	
+	cycle.GetNodeByName("aws_security_group.sg_ping").Location = &SourceLocation{File: "network/main.tf", Line: 12}
+	result = NewRDJSONResult(NewCycleAnalyzer(cycle))
+	if len(result.Diagnostics) != 1 {
+		t.Fatalf("Expected the located member only, got %+v", result.Diagnostics)
+	}
+	diagnostic := result.Diagnostics[0]
+	if diagnostic.Location.Path != "network/main.tf" || diagnostic.Location.Range.Start.Line != 12 || diagnostic.Severity != RDError {
+		t.Errorf("Expected an error at network/main.tf:12, got %+v", diagnostic)
+	}
+	if !strings.HasPrefix(diagnostic.Message, "Cycle (low): ") || !strings.Contains(diagnostic.Message, "💡 ") {
+		t.Errorf("Expected the cycle and suggestions in the message, got %q", diagnostic.Message)
+	}
+}
+
+func TestRDSuggestions(t *testing.T) {
+	fix := Fix{Edits: []FixEdit{
+		{File: "main.tf", Line: 3, Count: 2},
+		{File: "main.tf", Line: 9, Lines: []string{"", `resource "aws_security_group_rule" "x" {}`}},
+		{File: "other.tf", Line: 1, Count: 1},
+	}}
+	suggestions := RDSuggestions(fix, "main.tf")
+	if len(suggestions) != 2 {
+		t.Fatalf("Expected the two edits of main.tf, got %+v", suggestions)
+	}
+	if removal := suggestions[0]; removal.Range.Start.Line != 3 || removal.Range.End.Line != 5 || removal.Text != "" {
+		t.Errorf("Expected lines 3-4 removed, got %+v", removal)
+	}
+	if insertion := suggestions[1]; insertion.Range.End.Line != 9 || insertion.Text != "\nresource \"aws_security_group_rule\" \"x\" {}\n" {
+		t.Errorf("Expected an insertion before line 9, got %+v", insertion)
+	}
+}
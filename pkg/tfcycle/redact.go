@@ -0,0 +1,120 @@
+package tfcycle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Redactor replaces names, module names and instance keys with stable
+// pseudonyms, keeping resource types.
+type Redactor struct {
+	resources map[string]string
+	modules   map[string]string
+	keys      map[string]string
+	units     map[string]string
+}
+
+// NewRedactor returns a Redactor with no pseudonyms assigned yet.
+func NewRedactor() *Redactor {
+	return &Redactor{
+		resources: make(map[string]string),
+		modules:   make(map[string]string),
+		keys:      make(map[string]string),
+		units:     make(map[string]string),
+	}
+}
+
+// RedactCycle returns a redacted copy of cycle.
+func (r *Redactor) RedactCycle(cycle *TfCycle) *TfCycle {
+	redacted := &TfCycle{
+		Nodes:    make([]*CycleNode, 0, len(cycle.Nodes)),
+		Versions: cycle.Versions,
+	}
+	
+	rawStrings := make([]string, 0, len(cycle.Nodes))
+	for _, node := range cycle.Nodes {
+		redactedNode := r.redactNode(node)
+		redacted.Nodes = append(redacted.Nodes, redactedNode)
+		rawStrings = append(rawStrings, redactedNode.RawString)
+	}
+	
+	redacted.RawError = "Error: Cycle: " + strings.Join(rawStrings, ", ")
+	if cycle.Terragrunt() {
+		redacted.RawError = "Found a dependency cycle between modules: " + strings.Join(append(rawStrings, rawStrings[0]), " -> ")
+	}
+	
+	return redacted
+}
+
+func (r *Redactor) redactNode(node *CycleNode) *CycleNode {
+	if node.Unit != "" {
+		unit := r.pseudonym(r.units, node.Unit, unitPseudonym)
+		return &CycleNode{Unit: unit, RawString: unit}
+	}
+	
+	redacted := &CycleNode{
+		ResourceType: node.ResourceType,
+		ResourceName: r.pseudonym(r.resources, node.ResourceName, resourcePseudonym),
+		DataSource:   node.DataSource,
+		Action:       node.Action,
+		Annotations:  make(map[string]string),
+	}
+	
+	for key, value := range node.Annotations {
+		redacted.Annotations[key] = value
+	}
+	
+	for i, part := range node.ModulePath {
+		if i%2 == 0 {
+			redacted.ModulePath = append(redacted.ModulePath, part)
+			continue
+		}
+		redacted.ModulePath = append(redacted.ModulePath, r.pseudonym(r.modules, part, modulePseudonym))
+	}
+	
+	if node.InstanceKey != "" {
+		if _, err := strconv.Atoi(node.InstanceKey); err == nil {
+			redacted.InstanceKey = node.InstanceKey
+		} else {
+			redacted.InstanceKey = r.pseudonym(r.keys, node.InstanceKey, keyPseudonym)
+		}
+	}
+	
+	redacted.RawString = strings.Replace(redacted.String(), redacted.FullName(), redacted.Address(), 1)
+	
+	return redacted
+}
+
+func (r *Redactor) pseudonym(seen map[string]string, value string, generate func(int) string) string {
+	if alias, ok := seen[value]; ok {
+		return alias
+	}
+	
+	alias := generate(len(seen))
+	seen[value] = alias
+	return alias
+}
+
+func resourcePseudonym(index int) string {
+	return fmt.Sprintf("res_%d", index+1)
+}
+
+func unitPseudonym(index int) string {
+	return fmt.Sprintf("unit_%d", index+1)
+}
+
+func keyPseudonym(index int) string {
+	return fmt.Sprintf("key_%d", index+1)
+}
+
+func modulePseudonym(index int) string {
+	var suffix []byte
+	for n := index; ; n = n/26 - 1 {
+		suffix = append([]byte{byte('a' + n%26)}, suffix...)
+		if n < 26 {
+			break
+		}
+	}
+	return "mod_" + string(suffix)
+}
@@ -0,0 +1,65 @@
+package tfcycle
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRedactor_RedactCycle(t *testing.T) {
+	parser := NewParser()
+	cycle, err := parser.ParseError(`Error: Cycle: module.vpc.module.security.aws_security_group.sg_ping["prod"], module.vpc.aws_instance.web[0] (destroy), module.vpc.aws_security_group.sg_ping`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	cycle.Versions = &Versions{Tool: ToolTerraform, Core: "1.5.7", Providers: map[string]string{"aws": "5.31.0"}}
+	
+	redacted := NewRedactor().RedactCycle(cycle)
+	
+	if len(redacted.Nodes) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(redacted.Nodes))
+	}
+	
+	node1 := redacted.Nodes[0]
+	if node1.ResourceType != "aws_security_group" || node1.ResourceName != "res_1" {
+		t.Errorf("Expected aws_security_group.res_1, got %s.%s", node1.ResourceType, node1.ResourceName)
+	}
+	
+	expectedPath := []string{"module", "mod_a", "module", "mod_b"}
+	if !reflect.DeepEqual(node1.ModulePath, expectedPath) {
+		t.Errorf("Expected module path %v, got %v", expectedPath, node1.ModulePath)
+	}
+	
+	if node1.InstanceKey != "key_1" {
+		t.Errorf("Expected instance key 'key_1', got '%s'", node1.InstanceKey)
+	}
+	
+	node2 := redacted.Nodes[1]
+	if node2.InstanceKey != "0" || node2.Action != ActionDestroy {
+		t.Errorf("Expected numeric key and action to be preserved, got %s", node2.String())
+	}
+	
+	if redacted.Nodes[2].ResourceName != "res_1" {
+		t.Errorf("Expected stable pseudonym for repeated name, got %s", redacted.Nodes[2].ResourceName)
+	}
+	
+	for _, secret := range []string{"vpc", "module.security", "sg_ping", "prod", "web"} {
+		if strings.Contains(redacted.RawError, secret) {
+			t.Errorf("Redacted raw error still contains %q: %s", secret, redacted.RawError)
+		}
+	}
+	
+	if !reflect.DeepEqual(redacted.Versions, cycle.Versions) {
+		t.Errorf("Expected versions to be kept, got %+v", redacted.Versions)
+	}
+}
+
+func TestModulePseudonym(t *testing.T) {
+	testCases := map[int]string{0: "mod_a", 25: "mod_z", 26: "mod_aa", 27: "mod_ab"}
+	for index, expected := range testCases {
+		if got := modulePseudonym(index); got != expected {
+			t.Errorf("modulePseudonym(%d): expected %q, got %q", index, expected, got)
+		}
+	}
+}
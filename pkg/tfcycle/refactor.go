@@ -0,0 +1,277 @@
+package tfcycle
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ModuleMove moves a resource block from one module to another. The moved
+// block goes into Module, the closest module containing both, and From and
+// To are relative to it.
+type ModuleMove struct {
+	ResourceMove
+	Module     string `json:"module,omitempty"`
+	FromModule string `json:"from_module"`
+	ToModule   string `json:"to_module"`
+}
+
+// ModuleRefactor is a small set of resource moves between the modules of a
+// cycle after which the dependencies between those modules are acyclic.
+type ModuleRefactor struct {
+	Moves []ModuleMove `json:"moves"`
+	// Relocations are the data sources to move. They have no state, so
+	// only their configuration blocks move: there is no moved block or
+	// state mv command for them.
+	Relocations []ModuleMove `json:"relocations,omitempty"`
+	// Order lists the modules in apply order: once the moves are made, each
+	// depends only on modules before it.
+	Order []string `json:"order"`
+}
+
+// ModuleRefactor plans resource moves that make the module-level
+// dependencies of cycle acyclic: with them every module can be applied after
+// the modules it depends on. It returns nil if cycle stays within one module,
+// or if no plan moving fewer than half of its resources exists. Moves are
+// chosen greedily, each removing as many cross-module edges of module cycles
+// as possible.
+func (ca *CycleAnalyzer) ModuleRefactor(cycle []string) *ModuleRefactor {
+	modules := make(map[string]string)
+	var candidates []string
+	for _, name := range cycle {
+		node := ca.cycle.GetNodeByName(name)
+		if node == nil || node.Unit != "" {
+			return nil
+		}
+		module := strings.Join(node.ModulePath, ".")
+		if !slices.Contains(candidates, module) {
+			candidates = append(candidates, module)
+		}
+		modules[name] = module
+	}
+	if len(candidates) < 2 {
+		return nil
+	}
+	sort.Strings(candidates)
+	
+	graph := make(map[string][]string)
+	hypothesized := ca.HypothesizedGraph()
+	for _, from := range cycle {
+		for _, to := range hypothesized[from] {
+			if _, ok := modules[to]; ok {
+				graph[from] = append(graph[from], to)
+			}
+		}
+	}
+	
+	moved := make(map[string]string)
+	for len(moved) < (len(cycle)+1)/2 {
+		remaining := moduleCycleEdges(graph, modules)
+		if remaining == 0 {
+			break
+		}
+		best, bestTarget, bestRemaining := "", "", remaining
+		for _, name := range cycle {
+			if _, done := moved[name]; done {
+				continue
+			}
+			original := modules[name]
+			for _, target := range candidates {
+				if target == original {
+					continue
+				}
+				modules[name] = target
+				if count := moduleCycleEdges(graph, modules); count < bestRemaining {
+					best, bestTarget, bestRemaining = name, target, count
+				}
+			}
+			modules[name] = original
+		}
+		if best == "" {
+			return nil
+		}
+		moved[best] = modules[best]
+		modules[best] = bestTarget
+	}
+	if moduleCycleEdges(graph, modules) > 0 {
+		return nil
+	}
+	
+	refactor := &ModuleRefactor{Order: moduleOrder(graph, modules, candidates)}
+	seen := make(map[string]bool)
+	for _, name := range cycle {
+		from, ok := moved[name]
+		if !ok {
+			continue
+		}
+		node := ca.cycle.GetNodeByName(name)
+		resource := node.ResourceType + "." + node.ResourceName
+		if node.DataSource {
+			resource = "data." + resource
+		}
+		if seen[from+" "+resource] {
+			continue
+		}
+		seen[from+" "+resource] = true
+		
+		to := modules[name]
+		// Module paths alternate "module" and the call name.
+		ancestor := commonModulePrefix(splitModule(from), splitModule(to))
+		ancestor = ancestor[:len(ancestor)/2*2]
+		move := ModuleMove{
+			ResourceMove: ResourceMove{
+				From: joinAddress(splitModule(from)[len(ancestor):], resource),
+				To:   joinAddress(splitModule(to)[len(ancestor):], resource),
+			},
+			Module:     strings.Join(ancestor, "."),
+			FromModule: displayModule(from),
+			ToModule:   displayModule(to),
+		}
+		if node.DataSource {
+			refactor.Relocations = append(refactor.Relocations, move)
+		} else {
+			refactor.Moves = append(refactor.Moves, move)
+		}
+	}
+	for _, moves := range [][]ModuleMove{refactor.Moves, refactor.Relocations} {
+		sort.Slice(moves, func(i, j int) bool {
+			return moves[i].From < moves[j].From
+		})
+	}
+	return refactor
+}
+
+// Blocks returns the moves and relocations, that is every block to move.
+func (mr *ModuleRefactor) Blocks() []ModuleMove {
+	return append(append([]ModuleMove{}, mr.Moves...), mr.Relocations...)
+}
+
+// MovedBlocks returns the moves as Terraform moved blocks, each preceded by
+// a comment naming the module it belongs in unless that is the root module.
+func (mr *ModuleRefactor) MovedBlocks() string {
+	var output strings.Builder
+	for i, move := range mr.Moves {
+		if i > 0 {
+			output.WriteString("\n")
+		}
+		if move.Module != "" {
+			output.WriteString(fmt.Sprintf("# in %s\n", move.Module))
+		}
+		output.WriteString(MovePlan{Moves: []ResourceMove{move.ResourceMove}}.MovedBlocks())
+	}
+	if len(mr.Moves) > 0 && len(mr.Relocations) > 0 {
+		output.WriteString("\n")
+	}
+	output.WriteString(mr.RelocationNotes())
+	return output.String()
+}
+
+// RelocationNotes returns a comment asking to move the block of each
+// relocated data source.
+func (mr *ModuleRefactor) RelocationNotes() string {
+	var output strings.Builder
+	for _, move := range mr.Relocations {
+		address := move.From
+		if move.Module != "" {
+			address = move.Module + "." + address
+		}
+		output.WriteString(fmt.Sprintf("# Move the block of %s to %s; data sources have no state to move.\n", address, move.ToModule))
+	}
+	return output.String()
+}
+
+// StateMvCommands returns the equivalent terraform state mv commands.
+func (mr *ModuleRefactor) StateMvCommands() []string {
+	var commands []string
+	for _, move := range mr.Moves {
+		commands = append(commands, MovePlan{Moves: []ResourceMove{move.ResourceMove}}.StateMvCommands(splitModule(move.Module))...)
+	}
+	return commands
+}
+
+// moduleCycleEdges counts the resource edges between different modules that
+// are part of a cycle of the module graph.
+func moduleCycleEdges(graph map[string][]string, modules map[string]string) int {
+	moduleGraph := make(map[string][]string)
+	for from, targets := range graph {
+		for _, to := range targets {
+			if modules[from] != modules[to] {
+				moduleGraph[modules[from]] = append(moduleGraph[modules[from]], modules[to])
+			}
+		}
+	}
+	component := make(map[string]int)
+	for i, members := range stronglyConnected(moduleGraph) {
+		for _, module := range members {
+			component[module] = i + 1
+		}
+	}
+	
+	count := 0
+	for from, targets := range graph {
+		for _, to := range targets {
+			if modules[from] != modules[to] && component[modules[from]] != 0 && component[modules[from]] == component[modules[to]] {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// moduleOrder returns candidates in a topological order of the acyclic
+// module graph, dependencies first.
+func moduleOrder(graph map[string][]string, modules map[string]string, candidates []string) []string {
+	dependsOn := make(map[string]map[string]bool)
+	for from, targets := range graph {
+		for _, to := range targets {
+			if modules[from] != modules[to] {
+				if dependsOn[modules[from]] == nil {
+					dependsOn[modules[from]] = make(map[string]bool)
+				}
+				dependsOn[modules[from]][modules[to]] = true
+			}
+		}
+	}
+	
+	var order []string
+	placed := make(map[string]bool)
+	for progress := true; progress; {
+		progress = false
+		for _, module := range candidates {
+			if placed[module] {
+				continue
+			}
+			ready := true
+			for dependency := range dependsOn[module] {
+				if !placed[dependency] {
+					ready = false
+				}
+			}
+			if ready {
+				placed[module] = true
+				order = append(order, displayModule(module))
+				progress = true
+			}
+		}
+	}
+	return order
+}
+
+func splitModule(module string) []string {
+	if module == "" {
+		return nil
+	}
+	return strings.Split(module, ".")
+}
+
+func joinAddress(modulePath []string, resource string) string {
+	return strings.Join(append(append([]string{}, modulePath...), resource), ".")
+}
+
+func displayModule(module string) string {
+	if module == "" {
+		return "root"
+	}
+	return module
+}
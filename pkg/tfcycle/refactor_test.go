@@ -0,0 +1,115 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestModuleRefactor(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: module.app.aws_instance.web, module.network.aws_security_group.web, " +
+		"module.network.aws_security_group_rule.app, module.app.aws_eip.web")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	graph := map[string][]string{
+		"module.app.aws_instance.web":                {"module.network.aws_security_group.web"},
+		"module.network.aws_security_group_rule.app": {"module.app.aws_eip.web"},
+	}
+	analyzer := NewCycleAnalyzer(cycle, WithGraph(graph))
+	
+	refactor := analyzer.ModuleRefactor(analyzer.NodeNames())
+	if refactor == nil || len(refactor.Moves) != 1 {
+		t.Fatalf("Expected one move, got %+v", refactor)
+	}
+	move := refactor.Moves[0]
+	if move.Module != "" || move.From != "module.app.aws_instance.web" || move.To != "module.network.aws_instance.web" || move.ToModule != "module.network" {
+		t.Errorf("Expected the instance to move into the network module, got %+v", move)
+	}
+	if strings.Join(refactor.Order, ",") != "module.app,module.network" {
+		t.Errorf("Expected app before network, got %v", refactor.Order)
+	}
+	
+	expected := "terraform state mv 'module.app.aws_instance.web' 'module.network.aws_instance.web'"
+	if commands := refactor.StateMvCommands(); len(commands) != 1 || commands[0] != expected {
+		t.Errorf("Expected %q, got %v", expected, commands)
+	}
+	
+	suggestions := analyzer.GenerateSuggestions(analyzer.NodeNames())
+	found := false
+	for _, suggestion := range suggestions {
+		if suggestion.ID == "module-refactor" {
+			found = strings.Contains(suggestion.Snippet, "to   = module.network.aws_instance.web")
+		}
+	}
+	if !found {
+		t.Errorf("Expected a module refactor suggestion with moved blocks, got %+v", suggestions)
+	}
+	
+	index, err := ScanConfig(writeTestConfig(t, map[string]string{"main.tf": ""}))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer = NewCycleAnalyzer(cycle, WithGraph(graph), WithTerraformVersion("1.0.11"))
+	var fix *Fix
+	for _, candidate := range NewFixer(analyzer, index).GenerateFixes() {
+		if candidate.Kind == "module_refactor" {
+			fix = &candidate
+		}
+	}
+	if fix == nil || fix.Address != "module.app.aws_instance.web" || !strings.Contains(fix.Snippet, expected) || strings.Contains(fix.Snippet, "moved {") {
+		t.Errorf("Expected a state mv fix for Terraform 1.0, got %+v", fix)
+	}
+}
+
+func TestModuleRefactor_SingleModule(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_security_group.a, aws_security_group.b")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	if refactor := analyzer.ModuleRefactor(analyzer.NodeNames()); refactor != nil {
+		t.Errorf("Expected no refactor within one module, got %+v", refactor)
+	}
+}
+
+func TestModuleRefactor_DataSource(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: module.app.data.aws_ami.web, module.network.aws_security_group.web, " +
+		"module.network.aws_security_group_rule.app")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	graph := map[string][]string{
+		"module.app.data.aws_ami.web":                {"module.network.aws_security_group.web"},
+		"module.network.aws_security_group_rule.app": {"module.app.data.aws_ami.web"},
+	}
+	analyzer := NewCycleAnalyzer(cycle, WithGraph(graph))
+	
+	refactor := analyzer.ModuleRefactor(analyzer.NodeNames())
+	if refactor == nil || len(refactor.Moves) != 0 || len(refactor.Relocations) != 1 {
+		t.Fatalf("Expected the data source as a relocation only, got %+v", refactor)
+	}
+	if relocation := refactor.Relocations[0]; relocation.From != "module.app.data.aws_ami.web" || relocation.ToModule != "module.network" {
+		t.Errorf("Expected the data source to move into the network module, got %+v", relocation)
+	}
+	if commands := refactor.StateMvCommands(); len(commands) != 0 {
+		t.Errorf("Expected no state mv commands for a data source, got %v", commands)
+	}
+	blocks := refactor.MovedBlocks()
+	if strings.Contains(blocks, "moved {") || !strings.Contains(blocks, "# Move the block of module.app.data.aws_ami.web to module.network") {
+		t.Errorf("Expected only a note to move the data block, got:\n%s", blocks)
+	}
+	
+	index, err := ScanConfig(writeTestConfig(t, map[string]string{"main.tf": ""}))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var fix *Fix
+	for _, candidate := range NewFixer(analyzer, index).GenerateFixes() {
+		if candidate.Kind == "module_refactor" {
+			fix = &candidate
+		}
+	}
+	if fix == nil || fix.Address != "module.app.data.aws_ami.web" || fix.Moves != nil || strings.Contains(fix.Snippet, "moved {") {
+		t.Errorf("Expected a fix without moves for a data source, got %+v", fix)
+	}
+}
@@ -0,0 +1,103 @@
+package tfcycle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const registryBaseURL = "https://registry.terraform.io"
+
+var providerNamespaces = map[string]string{
+	"archive":    "hashicorp",
+	"aws":        "hashicorp",
+	"azuread":    "hashicorp",
+	"azurerm":    "hashicorp",
+	"cloudinit":  "hashicorp",
+	"consul":     "hashicorp",
+	"dns":        "hashicorp",
+	"external":   "hashicorp",
+	"google":     "hashicorp",
+	"helm":       "hashicorp",
+	"http":       "hashicorp",
+	"kubernetes": "hashicorp",
+	"local":      "hashicorp",
+	"nomad":      "hashicorp",
+	"null":       "hashicorp",
+	"random":     "hashicorp",
+	"tfe":        "hashicorp",
+	"time":       "hashicorp",
+	"tls":        "hashicorp",
+	"vault":      "hashicorp",
+	"cloudflare": "cloudflare",
+	"datadog":    "DataDog",
+	"github":     "integrations",
+	"newrelic":   "newrelic",
+	"pagerduty":  "PagerDuty",
+}
+
+// RegistryDocURL returns the Terraform Registry documentation page for
+// resourceType from the resource database or its name, or a registry search
+// when the provider is not known.
+func RegistryDocURL(resourceType string) string {
+	return registryDocURL(resourceType, false)
+}
+
+// NodeDocURL returns the documentation page for node: the data source page
+// for a data source and the resource page otherwise.
+func NodeDocURL(node *CycleNode) string {
+	return registryDocURL(node.ResourceType, node.DataSource)
+}
+
+func registryDocURL(resourceType string, dataSource bool) string {
+	kind := "resources"
+	if dataSource {
+		kind = "data-sources"
+	}
+	
+	if info, ok := LookupResource(resourceType); ok && info.DocURL != "" {
+		return strings.Replace(info.DocURL, "/docs/resources/", "/docs/"+kind+"/", 1)
+	}
+	
+	provider, resource, ok := strings.Cut(resourceType, "_")
+	if !ok || provider == "" || resource == "" {
+		return ""
+	}
+	
+	namespace, known := providerNamespaces[provider]
+	if !known {
+		return fmt.Sprintf("%s/search?q=%s", registryBaseURL, resourceType)
+	}
+	
+	return fmt.Sprintf("%s/providers/%s/%s/latest/docs/%s/%s", registryBaseURL, namespace, provider, kind, resource)
+}
+
+// DocumentationLinks returns the documentation pages for the resource types in
+// cycle, keyed by type, or by "data." and the type for data sources.
+func (ca *CycleAnalyzer) DocumentationLinks(cycle []string) map[string]string {
+	links := make(map[string]string)
+	for _, nodeName := range cycle {
+		node := ca.cycle.GetNodeByName(nodeName)
+		if node == nil {
+			continue
+		}
+		
+		key := node.ResourceType
+		if node.DataSource {
+			key = "data." + key
+		}
+		if url := NodeDocURL(node); url != "" {
+			links[key] = url
+		}
+	}
+	return links
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,48 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryDocURL(t *testing.T) {
+	testCases := []struct {
+		resourceType string
+		expected     string
+	}{
+		{"aws_security_group", "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/security_group"},
+		{"google_compute_instance", "https://registry.terraform.io/providers/hashicorp/google/latest/docs/resources/compute_instance"},
+		{"github_repository", "https://registry.terraform.io/providers/integrations/github/latest/docs/resources/repository"},
+		{"acme_widget", "https://registry.terraform.io/search?q=acme_widget"},
+		{"local", ""},
+		{"module", ""},
+	}
+	
+	for _, tc := range testCases {
+		if got := RegistryDocURL(tc.resourceType); got != tc.expected {
+			t.Errorf("RegistryDocURL(%q): expected %q, got %q", tc.resourceType, tc.expected, got)
+		}
+	}
+}
+
+func TestDocumentationLinks_DataSource(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: data.aws_iam_policy_document.app, aws_iam_policy.app, data.aws_alb.app")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	links := analyzer.DocumentationLinks(analyzer.NodeNames())
+	expected := map[string]string{
+		"data.aws_iam_policy_document": "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/data-sources/iam_policy_document",
+		"aws_iam_policy":               "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/iam_policy",
+	}
+	for key, url := range expected {
+		if links[key] != url {
+			t.Errorf("Expected %s for %s, got %q", url, key, links[key])
+		}
+	}
+	if !strings.HasSuffix(links["data.aws_alb"], "/docs/data-sources/lb") {
+		t.Errorf("Expected the data source page of the database override, got %q", links["data.aws_alb"])
+	}
+}
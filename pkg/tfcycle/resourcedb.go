@@ -0,0 +1,62 @@
+package tfcycle
+
+//go:generate go run ./internal/genresourcedb resources.yaml resourcedb_gen.go
+
+// ResourceCategory groups resource types by what they manage.
+type ResourceCategory string
+
+// Resource categories of the resource knowledge database.
+const (
+	CategoryNetwork  ResourceCategory = "network"
+	CategoryIAM      ResourceCategory = "iam"
+	CategoryCompute  ResourceCategory = "compute"
+	CategoryStateful ResourceCategory = "stateful"
+)
+
+// ResourceInfo is what tfcycle knows about a resource type offline.
+// SplitResources are standalone resource types that replace the type's
+// inline blocks; DocURL is set only where it differs from RegistryDocURL.
+// MinVersions holds the provider version that introduced the type, keyed as
+// for Versions.AtLeast.
+type ResourceInfo struct {
+	Type           string            `json:"type"`
+	Category       ResourceCategory  `json:"category"`
+	DocURL         string            `json:"doc_url,omitempty"`
+	SplitResources []string          `json:"split_resources,omitempty"`
+	MinVersions    map[string]string `json:"min_versions,omitempty"`
+}
+
+// LookupResource returns the database entry for resourceType. The database is
+// compiled from resources.yaml by go generate.
+func LookupResource(resourceType string) (ResourceInfo, bool) {
+	info, ok := resourceDB[resourceType]
+	return info, ok
+}
+
+// IsStateful reports whether node is a resource whose data is lost when
+// Terraform replaces it, such as a database or a bucket.
+func (node *CycleNode) IsStateful() bool {
+	info, _ := LookupResource(node.ResourceType)
+	return info.Category == CategoryStateful
+}
+
+// StatefulResources returns the addresses of the stateful resources in cycle.
+func (ca *CycleAnalyzer) StatefulResources(cycle []string) []string {
+	var stateful []string
+	for _, name := range cycle {
+		if node := ca.cycle.GetNodeByName(name); node != nil && node.IsStateful() {
+			stateful = append(stateful, name)
+		}
+	}
+	return stateful
+}
+
+func splitResources(resourceType string) []string {
+	info, _ := LookupResource(resourceType)
+	return info.SplitResources
+}
+
+func hasCategory(node *CycleNode, category ResourceCategory) bool {
+	info, _ := LookupResource(node.ResourceType)
+	return info.Category == category
+}
@@ -0,0 +1,144 @@
+// Code generated by genresourcedb from resources.yaml. DO NOT EDIT.
+
+package tfcycle
+
+var resourceDB = map[string]ResourceInfo{
+	"aws_alb":                              {Type: "aws_alb", Category: CategoryNetwork, DocURL: "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/lb"},
+	"aws_alb_listener":                     {Type: "aws_alb_listener", Category: CategoryNetwork, DocURL: "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/lb_listener"},
+	"aws_alb_target_group":                 {Type: "aws_alb_target_group", Category: CategoryNetwork, DocURL: "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/lb_target_group"},
+	"aws_autoscaling_group":                {Type: "aws_autoscaling_group", Category: CategoryCompute},
+	"aws_backup_vault":                     {Type: "aws_backup_vault", Category: CategoryStateful},
+	"aws_cloudfront_distribution":          {Type: "aws_cloudfront_distribution", Category: CategoryNetwork},
+	"aws_db_instance":                      {Type: "aws_db_instance", Category: CategoryStateful},
+	"aws_default_route_table":              {Type: "aws_default_route_table", Category: CategoryNetwork, SplitResources: []string{"aws_route"}},
+	"aws_default_security_group":           {Type: "aws_default_security_group", Category: CategoryNetwork, SplitResources: []string{"aws_vpc_security_group_ingress_rule", "aws_vpc_security_group_egress_rule"}},
+	"aws_docdb_cluster":                    {Type: "aws_docdb_cluster", Category: CategoryStateful},
+	"aws_dynamodb_table":                   {Type: "aws_dynamodb_table", Category: CategoryStateful},
+	"aws_ebs_volume":                       {Type: "aws_ebs_volume", Category: CategoryStateful},
+	"aws_ecr_repository":                   {Type: "aws_ecr_repository", Category: CategoryStateful},
+	"aws_ecs_cluster":                      {Type: "aws_ecs_cluster", Category: CategoryCompute},
+	"aws_ecs_service":                      {Type: "aws_ecs_service", Category: CategoryCompute},
+	"aws_ecs_task_definition":              {Type: "aws_ecs_task_definition", Category: CategoryCompute},
+	"aws_efs_file_system":                  {Type: "aws_efs_file_system", Category: CategoryStateful},
+	"aws_eip":                              {Type: "aws_eip", Category: CategoryNetwork},
+	"aws_eks_cluster":                      {Type: "aws_eks_cluster", Category: CategoryCompute},
+	"aws_eks_node_group":                   {Type: "aws_eks_node_group", Category: CategoryCompute},
+	"aws_elasticache_cluster":              {Type: "aws_elasticache_cluster", Category: CategoryStateful},
+	"aws_elasticache_replication_group":    {Type: "aws_elasticache_replication_group", Category: CategoryStateful},
+	"aws_elasticsearch_domain":             {Type: "aws_elasticsearch_domain", Category: CategoryStateful},
+	"aws_iam_access_key":                   {Type: "aws_iam_access_key", Category: CategoryIAM},
+	"aws_iam_group":                        {Type: "aws_iam_group", Category: CategoryIAM},
+	"aws_iam_group_membership":             {Type: "aws_iam_group_membership", Category: CategoryIAM},
+	"aws_iam_group_policy":                 {Type: "aws_iam_group_policy", Category: CategoryIAM},
+	"aws_iam_group_policy_attachment":      {Type: "aws_iam_group_policy_attachment", Category: CategoryIAM},
+	"aws_iam_instance_profile":             {Type: "aws_iam_instance_profile", Category: CategoryIAM},
+	"aws_iam_openid_connect_provider":      {Type: "aws_iam_openid_connect_provider", Category: CategoryIAM},
+	"aws_iam_policy":                       {Type: "aws_iam_policy", Category: CategoryIAM},
+	"aws_iam_policy_attachment":            {Type: "aws_iam_policy_attachment", Category: CategoryIAM},
+	"aws_iam_role":                         {Type: "aws_iam_role", Category: CategoryIAM, SplitResources: []string{"aws_iam_role_policy", "aws_iam_role_policy_attachment"}},
+	"aws_iam_role_policy":                  {Type: "aws_iam_role_policy", Category: CategoryIAM},
+	"aws_iam_role_policy_attachment":       {Type: "aws_iam_role_policy_attachment", Category: CategoryIAM},
+	"aws_iam_saml_provider":                {Type: "aws_iam_saml_provider", Category: CategoryIAM},
+	"aws_iam_service_linked_role":          {Type: "aws_iam_service_linked_role", Category: CategoryIAM},
+	"aws_iam_user":                         {Type: "aws_iam_user", Category: CategoryIAM},
+	"aws_iam_user_policy":                  {Type: "aws_iam_user_policy", Category: CategoryIAM},
+	"aws_iam_user_policy_attachment":       {Type: "aws_iam_user_policy_attachment", Category: CategoryIAM},
+	"aws_instance":                         {Type: "aws_instance", Category: CategoryCompute},
+	"aws_internet_gateway":                 {Type: "aws_internet_gateway", Category: CategoryNetwork},
+	"aws_kinesis_stream":                   {Type: "aws_kinesis_stream", Category: CategoryStateful},
+	"aws_kms_key":                          {Type: "aws_kms_key", Category: CategoryStateful},
+	"aws_lambda_function":                  {Type: "aws_lambda_function", Category: CategoryCompute},
+	"aws_launch_template":                  {Type: "aws_launch_template", Category: CategoryCompute},
+	"aws_lb":                               {Type: "aws_lb", Category: CategoryNetwork},
+	"aws_lb_listener":                      {Type: "aws_lb_listener", Category: CategoryNetwork, SplitResources: []string{"aws_lb_listener_rule"}},
+	"aws_lb_listener_rule":                 {Type: "aws_lb_listener_rule", Category: CategoryNetwork},
+	"aws_lb_target_group":                  {Type: "aws_lb_target_group", Category: CategoryNetwork},
+	"aws_lb_target_group_attachment":       {Type: "aws_lb_target_group_attachment", Category: CategoryNetwork},
+	"aws_msk_cluster":                      {Type: "aws_msk_cluster", Category: CategoryStateful},
+	"aws_nat_gateway":                      {Type: "aws_nat_gateway", Category: CategoryNetwork},
+	"aws_neptune_cluster":                  {Type: "aws_neptune_cluster", Category: CategoryStateful},
+	"aws_network_acl":                      {Type: "aws_network_acl", Category: CategoryNetwork, SplitResources: []string{"aws_network_acl_rule"}},
+	"aws_network_acl_rule":                 {Type: "aws_network_acl_rule", Category: CategoryNetwork},
+	"aws_opensearch_domain":                {Type: "aws_opensearch_domain", Category: CategoryStateful},
+	"aws_rds_cluster":                      {Type: "aws_rds_cluster", Category: CategoryStateful},
+	"aws_redshift_cluster":                 {Type: "aws_redshift_cluster", Category: CategoryStateful},
+	"aws_route":                            {Type: "aws_route", Category: CategoryNetwork},
+	"aws_route53_record":                   {Type: "aws_route53_record", Category: CategoryNetwork},
+	"aws_route53_zone":                     {Type: "aws_route53_zone", Category: CategoryNetwork},
+	"aws_route_table":                      {Type: "aws_route_table", Category: CategoryNetwork, SplitResources: []string{"aws_route"}},
+	"aws_route_table_association":          {Type: "aws_route_table_association", Category: CategoryNetwork},
+	"aws_s3_bucket":                        {Type: "aws_s3_bucket", Category: CategoryStateful},
+	"aws_secretsmanager_secret":            {Type: "aws_secretsmanager_secret", Category: CategoryStateful},
+	"aws_security_group":                   {Type: "aws_security_group", Category: CategoryNetwork, SplitResources: []string{"aws_vpc_security_group_ingress_rule", "aws_vpc_security_group_egress_rule", "aws_security_group_rule"}},
+	"aws_security_group_rule":              {Type: "aws_security_group_rule", Category: CategoryNetwork},
+	"aws_sqs_queue":                        {Type: "aws_sqs_queue", Category: CategoryStateful},
+	"aws_ssm_parameter":                    {Type: "aws_ssm_parameter", Category: CategoryStateful},
+	"aws_subnet":                           {Type: "aws_subnet", Category: CategoryNetwork},
+	"aws_vpc":                              {Type: "aws_vpc", Category: CategoryNetwork},
+	"aws_vpc_endpoint":                     {Type: "aws_vpc_endpoint", Category: CategoryNetwork},
+	"aws_vpc_peering_connection":           {Type: "aws_vpc_peering_connection", Category: CategoryNetwork},
+	"aws_vpc_security_group_egress_rule":   {Type: "aws_vpc_security_group_egress_rule", Category: CategoryNetwork, MinVersions: map[string]string{"aws": "5.0"}},
+	"aws_vpc_security_group_ingress_rule":  {Type: "aws_vpc_security_group_ingress_rule", Category: CategoryNetwork, MinVersions: map[string]string{"aws": "5.0"}},
+	"azurerm_cosmosdb_account":             {Type: "azurerm_cosmosdb_account", Category: CategoryStateful},
+	"azurerm_key_vault":                    {Type: "azurerm_key_vault", Category: CategoryStateful, SplitResources: []string{"azurerm_key_vault_access_policy"}},
+	"azurerm_key_vault_access_policy":      {Type: "azurerm_key_vault_access_policy", Category: CategoryIAM},
+	"azurerm_kubernetes_cluster":           {Type: "azurerm_kubernetes_cluster", Category: CategoryCompute, SplitResources: []string{"azurerm_kubernetes_cluster_node_pool"}},
+	"azurerm_kubernetes_cluster_node_pool": {Type: "azurerm_kubernetes_cluster_node_pool", Category: CategoryCompute},
+	"azurerm_lb":                           {Type: "azurerm_lb", Category: CategoryNetwork},
+	"azurerm_linux_function_app":           {Type: "azurerm_linux_function_app", Category: CategoryCompute},
+	"azurerm_linux_virtual_machine":        {Type: "azurerm_linux_virtual_machine", Category: CategoryCompute},
+	"azurerm_managed_disk":                 {Type: "azurerm_managed_disk", Category: CategoryStateful},
+	"azurerm_mssql_database":               {Type: "azurerm_mssql_database", Category: CategoryStateful},
+	"azurerm_mysql_flexible_server":        {Type: "azurerm_mysql_flexible_server", Category: CategoryStateful},
+	"azurerm_network_interface":            {Type: "azurerm_network_interface", Category: CategoryNetwork},
+	"azurerm_network_security_group":       {Type: "azurerm_network_security_group", Category: CategoryNetwork, SplitResources: []string{"azurerm_network_security_rule"}},
+	"azurerm_network_security_rule":        {Type: "azurerm_network_security_rule", Category: CategoryNetwork},
+	"azurerm_postgresql_flexible_server":   {Type: "azurerm_postgresql_flexible_server", Category: CategoryStateful},
+	"azurerm_public_ip":                    {Type: "azurerm_public_ip", Category: CategoryNetwork},
+	"azurerm_role_assignment":              {Type: "azurerm_role_assignment", Category: CategoryIAM},
+	"azurerm_role_definition":              {Type: "azurerm_role_definition", Category: CategoryIAM},
+	"azurerm_route":                        {Type: "azurerm_route", Category: CategoryNetwork},
+	"azurerm_route_table":                  {Type: "azurerm_route_table", Category: CategoryNetwork, SplitResources: []string{"azurerm_route"}},
+	"azurerm_storage_account":              {Type: "azurerm_storage_account", Category: CategoryStateful},
+	"azurerm_subnet":                       {Type: "azurerm_subnet", Category: CategoryNetwork},
+	"azurerm_user_assigned_identity":       {Type: "azurerm_user_assigned_identity", Category: CategoryIAM},
+	"azurerm_virtual_network":              {Type: "azurerm_virtual_network", Category: CategoryNetwork, SplitResources: []string{"azurerm_subnet"}},
+	"azurerm_windows_virtual_machine":      {Type: "azurerm_windows_virtual_machine", Category: CategoryCompute},
+	"google_bigquery_dataset":              {Type: "google_bigquery_dataset", Category: CategoryStateful, SplitResources: []string{"google_bigquery_dataset_access"}},
+	"google_bigquery_dataset_access":       {Type: "google_bigquery_dataset_access", Category: CategoryIAM},
+	"google_bigquery_table":                {Type: "google_bigquery_table", Category: CategoryStateful},
+	"google_cloud_run_service":             {Type: "google_cloud_run_service", Category: CategoryCompute},
+	"google_cloudfunctions_function":       {Type: "google_cloudfunctions_function", Category: CategoryCompute},
+	"google_compute_address":               {Type: "google_compute_address", Category: CategoryNetwork},
+	"google_compute_disk":                  {Type: "google_compute_disk", Category: CategoryStateful},
+	"google_compute_firewall":              {Type: "google_compute_firewall", Category: CategoryNetwork},
+	"google_compute_instance":              {Type: "google_compute_instance", Category: CategoryCompute},
+	"google_compute_network":               {Type: "google_compute_network", Category: CategoryNetwork},
+	"google_compute_router":                {Type: "google_compute_router", Category: CategoryNetwork},
+	"google_compute_router_nat":            {Type: "google_compute_router_nat", Category: CategoryNetwork},
+	"google_compute_subnetwork":            {Type: "google_compute_subnetwork", Category: CategoryNetwork},
+	"google_container_cluster":             {Type: "google_container_cluster", Category: CategoryCompute, SplitResources: []string{"google_container_node_pool"}},
+	"google_container_node_pool":           {Type: "google_container_node_pool", Category: CategoryCompute},
+	"google_dns_record_set":                {Type: "google_dns_record_set", Category: CategoryNetwork},
+	"google_kms_crypto_key":                {Type: "google_kms_crypto_key", Category: CategoryStateful},
+	"google_project_iam_binding":           {Type: "google_project_iam_binding", Category: CategoryIAM},
+	"google_project_iam_member":            {Type: "google_project_iam_member", Category: CategoryIAM},
+	"google_redis_instance":                {Type: "google_redis_instance", Category: CategoryStateful},
+	"google_service_account":               {Type: "google_service_account", Category: CategoryIAM},
+	"google_service_account_iam_member":    {Type: "google_service_account_iam_member", Category: CategoryIAM},
+	"google_spanner_instance":              {Type: "google_spanner_instance", Category: CategoryStateful},
+	"google_sql_database_instance":         {Type: "google_sql_database_instance", Category: CategoryStateful},
+	"google_storage_bucket":                {Type: "google_storage_bucket", Category: CategoryStateful},
+	"google_storage_bucket_iam_member":     {Type: "google_storage_bucket_iam_member", Category: CategoryIAM},
+	"kubernetes_cluster_role":              {Type: "kubernetes_cluster_role", Category: CategoryIAM},
+	"kubernetes_cluster_role_binding":      {Type: "kubernetes_cluster_role_binding", Category: CategoryIAM},
+	"kubernetes_deployment":                {Type: "kubernetes_deployment", Category: CategoryCompute},
+	"kubernetes_ingress_v1":                {Type: "kubernetes_ingress_v1", Category: CategoryNetwork},
+	"kubernetes_persistent_volume":         {Type: "kubernetes_persistent_volume", Category: CategoryStateful},
+	"kubernetes_persistent_volume_claim":   {Type: "kubernetes_persistent_volume_claim", Category: CategoryStateful},
+	"kubernetes_role":                      {Type: "kubernetes_role", Category: CategoryIAM},
+	"kubernetes_role_binding":              {Type: "kubernetes_role_binding", Category: CategoryIAM},
+	"kubernetes_service":                   {Type: "kubernetes_service", Category: CategoryNetwork},
+	"kubernetes_service_account":           {Type: "kubernetes_service_account", Category: CategoryIAM},
+	"kubernetes_stateful_set":              {Type: "kubernetes_stateful_set", Category: CategoryCompute},
+}
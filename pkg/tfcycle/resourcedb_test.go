@@ -0,0 +1,76 @@
+package tfcycle
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestResourceDB_InSyncWithYAML(t *testing.T) {
+	data, err := os.ReadFile("resources.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var db struct {
+		Types map[string]struct {
+			Category string `yaml:"category"`
+		} `yaml:"types"`
+	}
+	if err := yaml.Unmarshal(data, &db); err != nil {
+		t.Fatalf("Expected valid YAML, got: %v", err)
+	}
+	
+	if len(db.Types) != len(resourceDB) {
+		t.Errorf("Expected %d types as in resources.yaml, got %d; run go generate", len(db.Types), len(resourceDB))
+	}
+	for resourceType, entry := range db.Types {
+		if info, ok := LookupResource(resourceType); !ok || string(info.Category) != entry.Category {
+			t.Errorf("Expected %s in category %s, got %+v; run go generate", resourceType, entry.Category, info)
+		}
+	}
+}
+
+func TestResourceDB_Severity(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_db_instance.main, aws_security_group.db")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	if severity := analyzer.CycleSeverity(analyzer.NodeNames()); severity != SeverityMedium {
+		t.Errorf("Expected medium severity for a cycle through a database, got %s", severity)
+	}
+	
+	output := NewOutputFormatter(analyzer, false).FormatAnalysis()
+	if !strings.Contains(output, "Stateful resources in the cycle: aws_db_instance.main") {
+		t.Errorf("Expected a stateful resource warning, got:\n%s", output)
+	}
+}
+
+func TestResourceDB_SplitSuggestion(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_route_table.private, aws_nat_gateway.main")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	var split *Suggestion
+	for _, suggestion := range analyzer.GenerateSuggestions(analyzer.NodeNames()) {
+		if suggestion.RuleID == "TFC-SPLIT-001" {
+			split = &suggestion
+		}
+	}
+	if split == nil || split.Title != "Move the inline blocks of aws_route_table.private into aws_route resources" {
+		t.Fatalf("Expected the split suggestion for the route table, got %+v", split)
+	}
+	if len(split.Docs) != 1 || split.Docs[0] != RegistryDocURL("aws_route") {
+		t.Errorf("Expected the aws_route docs, got %v", split.Docs)
+	}
+}
+
+func TestRegistryDocURL_DatabaseOverride(t *testing.T) {
+	if url := RegistryDocURL("aws_alb"); !strings.HasSuffix(url, "/docs/resources/lb") {
+		t.Errorf("Expected the aws_lb page for the aws_alb alias, got %s", url)
+	}
+}
@@ -0,0 +1,425 @@
+package tfcycle
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed suggestions.yaml
+var builtinRulesYAML []byte
+
+var builtinRules = mustParseBuiltinRules()
+
+// SuggestionRule yields suggestions for the minimal cycles that satisfy all
+// of its Match conditions. Fallback rules apply only when no other rule
+// matched the cycle.
+type SuggestionRule struct {
+	ID          string               `yaml:"id" json:"id"`
+	Description string               `yaml:"description" json:"description,omitempty"`
+	Fallback    bool                 `yaml:"fallback" json:"fallback,omitempty"`
+	Disabled    bool                 `yaml:"disabled" json:"disabled,omitempty"`
+	Match       RuleMatch            `yaml:"match" json:"match"`
+	Suggestions []SuggestionTemplate `yaml:"suggestions" json:"suggestions"`
+	Source      string               `yaml:"-" json:"source"`
+}
+
+// RuleMatch holds the conditions of a SuggestionRule. Empty conditions always
+// hold, except that Terragrunt must agree with the cycle.
+type RuleMatch struct {
+	// Types maps resource type globs to the minimum number of nodes of a
+	// matching type.
+	Types map[string]int `yaml:"types" json:"types,omitempty"`
+	// Actions holds if any node has one of these actions. Nodes that the
+	// output before the cycle error shows being destroyed or replaced have
+	// the destroy action.
+	Actions []string `yaml:"actions" json:"actions,omitempty"`
+	// Modules holds if any node is in a module matching one of these globs,
+	// as with --filter module=.
+	Modules []string `yaml:"modules" json:"modules,omitempty"`
+	// CrossModule holds if the cycle spans more than one module.
+	CrossModule bool `yaml:"cross_module" json:"cross_module,omitempty"`
+	// Terragrunt selects cycles between Terragrunt units. Rules without it
+	// only match cycles between resources.
+	Terragrunt bool `yaml:"terragrunt" json:"terragrunt,omitempty"`
+	// Splittable holds if any node's type has split resources in the
+	// resource database.
+	Splittable bool `yaml:"splittable" json:"splittable,omitempty"`
+	// Refactorable holds if moving resources between the cycle's modules
+	// makes the module dependencies acyclic (see CycleAnalyzer.ModuleRefactor).
+	Refactorable bool `yaml:"refactorable" json:"refactorable,omitempty"`
+}
+
+// SuggestionTemplate is a Suggestion whose Title, Detail, Docs and Snippet
+// are text/template templates executed against a RuleContext. MinVersions
+// maps "terraform" or a provider name to the oldest version the advice works
+// with; it is left out when the cycle's Versions are known to be older.
+type SuggestionTemplate struct {
+	ID          string            `yaml:"id" json:"id"`
+	RuleID      string            `yaml:"rule_id" json:"rule_id,omitempty"`
+	MinVersions map[string]string `yaml:"min_versions" json:"min_versions,omitempty"`
+	Title       string            `yaml:"title" json:"title"`
+	Detail      string            `yaml:"detail" json:"detail,omitempty"`
+	Effort      Effort            `yaml:"effort" json:"effort"`
+	Docs        []string          `yaml:"docs" json:"docs,omitempty"`
+	Snippet     string            `yaml:"snippet" json:"snippet,omitempty"`
+}
+
+// RuleContext is the data suggestion templates are executed against.
+type RuleContext struct {
+	// Nodes are the cycle's nodes in cycle order.
+	Nodes []*CycleNode
+	// Destroyed are the nodes with a destroy or destroy deposed action, or
+	// shown being destroyed or replaced before the cycle error.
+	Destroyed []*CycleNode
+	// Splittable are the nodes whose types have split resources in the
+	// resource database that the cycle's Versions support.
+	Splittable []*CycleNode
+	// Versions are the Terraform and provider versions, or nil if unknown.
+	Versions *Versions
+	// Refactor are the resource moves that make the module dependencies of
+	// the cycle acyclic, or nil if there are none. It is only computed when a
+	// rule matches refactorable cycles.
+	Refactor *ModuleRefactor
+}
+
+// Split returns the split resources of resourceType that Versions support.
+func (rc RuleContext) Split(resourceType string) []string {
+	var supported []string
+	for _, split := range splitResources(resourceType) {
+		if info, _ := LookupResource(split); rc.Versions.meets(info.MinVersions) {
+			supported = append(supported, split)
+		}
+	}
+	return supported
+}
+
+// Node returns the i-th node of resourceType in the cycle, or an empty node.
+func (rc RuleContext) Node(resourceType string, i int) *CycleNode {
+	for _, node := range rc.Nodes {
+		if node.ResourceType != resourceType {
+			continue
+		}
+		if i == 0 {
+			return node
+		}
+		i--
+	}
+	return &CycleNode{}
+}
+
+var ruleTemplateFuncs = template.FuncMap{
+	"registry": RegistryDocURL,
+	"split":    splitResources,
+	"join":     strings.Join,
+	"base":     func(name string) string { return path.Base(filepath.ToSlash(name)) },
+}
+
+type rulesFile struct {
+	Rules []SuggestionRule `yaml:"rules"`
+}
+
+// DefaultSuggestionRules returns the built-in rules.
+func DefaultSuggestionRules() []SuggestionRule {
+	return append([]SuggestionRule{}, builtinRules...)
+}
+
+func mustParseBuiltinRules() []SuggestionRule {
+	rules, err := ParseSuggestionRules(bytes.NewReader(builtinRulesYAML), "builtin")
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// ParseSuggestionRules reads a rules file and checks every rule, recording
+// source as the rules' Source.
+func ParseSuggestionRules(r io.Reader, source string) ([]SuggestionRule, error) {
+	var file rulesFile
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", source, err)
+	}
+	
+	seen := make(map[string]bool)
+	for i := range file.Rules {
+		rule := &file.Rules[i]
+		rule.Source = source
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		if seen[rule.ID] {
+			return nil, fmt.Errorf("%s: duplicate rule %q", source, rule.ID)
+		}
+		seen[rule.ID] = true
+	}
+	return file.Rules, nil
+}
+
+func (rule *SuggestionRule) validate() error {
+	if rule.ID == "" {
+		return errors.New("rule without an id")
+	}
+	if rule.Disabled {
+		return nil
+	}
+	if len(rule.Suggestions) == 0 {
+		return fmt.Errorf("rule %s has no suggestions", rule.ID)
+	}
+	
+	for pattern, count := range rule.Match.Types {
+		if _, err := path.Match(pattern, ""); err != nil || count < 1 {
+			return fmt.Errorf("rule %s: invalid type condition %s: %d", rule.ID, pattern, count)
+		}
+	}
+	for _, action := range rule.Match.Actions {
+		if _, err := ParseNodeAction(action); err != nil {
+			return fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+	}
+	for _, pattern := range rule.Match.Modules {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("rule %s: invalid module pattern %q: %w", rule.ID, pattern, err)
+		}
+	}
+	
+	for i := range rule.Suggestions {
+		suggestion := &rule.Suggestions[i]
+		if suggestion.ID == "" || suggestion.Title == "" {
+			return fmt.Errorf("rule %s: every suggestion needs an id and a title", rule.ID)
+		}
+		for name, version := range suggestion.MinVersions {
+			if err := ValidateVersion(version); err != nil {
+				return fmt.Errorf("rule %s: suggestion %s: min_versions %s: %w", rule.ID, suggestion.ID, name, err)
+			}
+		}
+		switch suggestion.Effort {
+		case "":
+			suggestion.Effort = EffortMedium
+		case EffortLow, EffortMedium, EffortHigh:
+		default:
+			return fmt.Errorf("rule %s: suggestion %s: invalid effort %q (use low, medium or high)", rule.ID, suggestion.ID, suggestion.Effort)
+		}
+		for _, text := range append([]string{suggestion.Title, suggestion.Detail, suggestion.Snippet}, suggestion.Docs...) {
+			if _, err := template.New(suggestion.ID).Funcs(ruleTemplateFuncs).Parse(text); err != nil {
+				return fmt.Errorf("rule %s: suggestion %s: %w", rule.ID, suggestion.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MergeSuggestionRules returns base with overrides applied: an override
+// replaces the rule with the same ID, or removes it if Disabled, and new
+// rules are added before the fallback rules.
+func MergeSuggestionRules(base, overrides []SuggestionRule) []SuggestionRule {
+	merged := append([]SuggestionRule{}, base...)
+	for _, override := range overrides {
+		index := -1
+		for i, rule := range merged {
+			if rule.ID == override.ID {
+				index = i
+				break
+			}
+		}
+		
+		switch {
+		case index >= 0 && override.Disabled:
+			merged = append(merged[:index], merged[index+1:]...)
+		case index >= 0:
+			merged[index] = override
+		case override.Disabled:
+		case override.Fallback:
+			merged = append(merged, override)
+		default:
+			insertAt := len(merged)
+			for i, rule := range merged {
+				if rule.Fallback {
+					insertAt = i
+					break
+				}
+			}
+			merged = append(merged[:insertAt], append([]SuggestionRule{override}, merged[insertAt:]...)...)
+		}
+	}
+	return merged
+}
+
+// Matches reports whether the nodes of a cycle satisfy the rule's conditions.
+// Refactorable is treated like CrossModule, since the nodes alone do not tell
+// whether a refactor exists.
+func (rule SuggestionRule) Matches(nodes []*CycleNode) bool {
+	match := rule.Match
+	if match.Terragrunt != anyNode(nodes, func(node *CycleNode) bool { return node.Unit != "" }) {
+		return false
+	}
+	
+	for pattern, minimum := range match.Types {
+		count := 0
+		for _, node := range nodes {
+			if ok, _ := path.Match(pattern, node.ResourceType); ok {
+				count++
+			}
+		}
+		if count < minimum {
+			return false
+		}
+	}
+	
+	if len(match.Actions) > 0 && !anyNode(nodes, func(node *CycleNode) bool {
+		if node.Annotations["context_action"] != "" && matchesAny(match.Actions, ActionDestroy.String()) {
+			return true
+		}
+		return matchesAny(match.Actions, node.Action.String())
+	}) {
+		return false
+	}
+	
+	if len(match.Modules) > 0 && !anyNode(nodes, NodeFilter{Modules: match.Modules}.matchesModule) {
+		return false
+	}
+	
+	if match.Splittable && !anyNode(nodes, func(node *CycleNode) bool { return len(splitResources(node.ResourceType)) > 0 }) {
+		return false
+	}
+	
+	if match.CrossModule || match.Refactorable {
+		modules := make(map[string]bool)
+		for _, node := range nodes {
+			modules[strings.Join(node.ModulePath, ".")] = true
+		}
+		if len(modules) < 2 {
+			return false
+		}
+	}
+	return true
+}
+
+func anyNode(nodes []*CycleNode, predicate func(*CycleNode) bool) bool {
+	for _, node := range nodes {
+		if predicate(node) {
+			return true
+		}
+	}
+	return false
+}
+
+// Render executes the rule's templates for a cycle. A suggestion whose
+// templates fail is logged and left out.
+func (rule SuggestionRule) Render(context RuleContext, severity Severity) []Suggestion {
+	var suggestions []Suggestion
+	for _, tmpl := range rule.Suggestions {
+		if !context.Versions.meets(tmpl.MinVersions) {
+			logger.Debug("skipping suggestion for older versions", "rule", rule.ID, "suggestion", tmpl.ID)
+			continue
+		}
+		suggestion, err := tmpl.render(context)
+		if err != nil {
+			logger.Warn("skipping suggestion", "rule", rule.ID, "suggestion", tmpl.ID, "error", err)
+			continue
+		}
+		suggestion.Severity = severity
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
+
+func (st SuggestionTemplate) render(context RuleContext) (Suggestion, error) {
+	suggestion := Suggestion{ID: st.ID, RuleID: st.RuleID, Effort: st.Effort}
+	
+	execute := func(text string) (string, error) {
+		tmpl, err := template.New(st.ID).Funcs(ruleTemplateFuncs).Parse(text)
+		if err != nil {
+			return "", err
+		}
+		var output strings.Builder
+		if err := tmpl.Execute(&output, context); err != nil {
+			return "", err
+		}
+		return output.String(), nil
+	}
+	
+	var err error
+	if suggestion.Title, err = execute(st.Title); err != nil {
+		return suggestion, err
+	}
+	if suggestion.Detail, err = execute(st.Detail); err != nil {
+		return suggestion, err
+	}
+	if suggestion.Snippet, err = execute(st.Snippet); err != nil {
+		return suggestion, err
+	}
+	for _, doc := range st.Docs {
+		url, err := execute(doc)
+		if err != nil {
+			return suggestion, err
+		}
+		if url != "" {
+			suggestion.Docs = append(suggestion.Docs, url)
+		}
+	}
+	return suggestion, nil
+}
+
+// RuleIDs returns the rule IDs of the built-in heuristics and of the
+// suggestions of rules, or of DefaultSuggestionRules if rules is nil.
+func RuleIDs(rules []SuggestionRule) []string {
+	if rules == nil {
+		rules = builtinRules
+	}
+	
+	var ids []string
+	for _, h := range DefaultHeuristics() {
+		ids = append(ids, h.RuleID)
+	}
+	for _, rule := range rules {
+		for _, suggestion := range rule.Suggestions {
+			if suggestion.RuleID != "" && !slices.Contains(ids, suggestion.RuleID) {
+				ids = append(ids, suggestion.RuleID)
+			}
+		}
+	}
+	return ids
+}
+
+// ValidateRuleIDs returns an error for patterns that are neither a fix kind
+// nor match one of RuleIDs(rules). Patterns are globs such as TFC-AWS-*.
+func ValidateRuleIDs(patterns []string, rules []SuggestionRule) error {
+	ids := RuleIDs(rules)
+	for _, pattern := range patterns {
+		if slices.Contains(fixKinds, pattern) {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid rule pattern %q: %w", pattern, err)
+		}
+		if !slices.ContainsFunc(ids, func(id string) bool { return matchesAny([]string{pattern}, id) }) {
+			return fmt.Errorf("unknown rule %q (see tfcycle rules list; fix kinds: %s)", pattern, strings.Join(fixKinds, ", "))
+		}
+	}
+	return nil
+}
+
+// ruleSelection holds the rule ID patterns of WithRuleSelection. A rule that
+// matches an enabled pattern stays on even if it matches a disabled one.
+type ruleSelection struct {
+	enabled  []string
+	disabled []string
+}
+
+func (rs ruleSelection) allows(ruleID string) bool {
+	if ruleID == "" || matchesAny(rs.enabled, ruleID) {
+		return true
+	}
+	return !matchesAny(rs.disabled, ruleID)
+}
@@ -0,0 +1,151 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+const testRulesYAML = `
+rules:
+  - id: iam-role-policy
+    disabled: true
+  - id: rds
+    description: Database and parameter group
+    match:
+      types:
+        aws_db_*: 2
+      actions: [destroy]
+    suggestions:
+      - id: rds-name-prefix
+        title: Use name_prefix on {{ (.Node "aws_db_parameter_group" 0).Address }}
+        docs:
+          - '{{ registry "aws_db_parameter_group" }}'
+`
+
+func parseTestRules(t *testing.T, text string) []SuggestionRule {
+	t.Helper()
+	rules, err := ParseSuggestionRules(strings.NewReader(text), "test.yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	return rules
+}
+
+func TestDefaultSuggestionRules(t *testing.T) {
+	rules := DefaultSuggestionRules()
+	if len(rules) == 0 || !rules[len(rules)-1].Fallback {
+		t.Fatalf("Expected built-in rules ending in a fallback, got %+v", rules)
+	}
+	for _, rule := range rules {
+		if rule.Source != "builtin" {
+			t.Errorf("Expected source builtin, got %q for %s", rule.Source, rule.ID)
+		}
+	}
+}
+
+func TestParseSuggestionRules_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing id":     "rules:\n  - suggestions: [{id: a, title: A}]\n",
+		"no suggestions": "rules:\n  - id: a\n",
+		"bad action":     "rules:\n  - id: a\n    match: {actions: [explode]}\n    suggestions: [{id: a, title: A}]\n",
+		"bad effort":     "rules:\n  - id: a\n    suggestions: [{id: a, title: A, effort: huge}]\n",
+		"bad template":   "rules:\n  - id: a\n    suggestions: [{id: a, title: '{{ .Nodes'}]\n",
+		"duplicate":      "rules:\n  - {id: a, disabled: true}\n  - {id: a, disabled: true}\n",
+		"unknown field":  "rules:\n  - {id: a, disabled: true, priority: 1}\n",
+	}
+	for name, text := range tests {
+		if _, err := ParseSuggestionRules(strings.NewReader(text), "test.yaml"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMergeSuggestionRules(t *testing.T) {
+	merged := MergeSuggestionRules(DefaultSuggestionRules(), parseTestRules(t, testRulesYAML))
+	
+	ids := make([]string, len(merged))
+	for i, rule := range merged {
+		ids[i] = rule.ID
+	}
+	if got := strings.Join(ids, ","); got != "security-groups,destroy,stacks,terragrunt,module-boundaries,rds,split-inline-blocks,general" {
+		t.Errorf("Expected iam-role-policy removed and rds before the fallbacks, got %s", got)
+	}
+	if merged[5].Source != "test.yaml" || merged[5].Suggestions[0].Effort != EffortMedium {
+		t.Errorf("Expected the override's source and default effort, got %+v", merged[5])
+	}
+}
+
+func TestSuggestionRule_Matches(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: module.db.aws_db_instance.main (destroy), aws_db_parameter_group.main")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	nodes := cycle.Nodes
+	
+	tests := []struct {
+		match RuleMatch
+		want  bool
+	}{
+		{RuleMatch{}, true},
+		{RuleMatch{Types: map[string]int{"aws_db_*": 2}}, true},
+		{RuleMatch{Types: map[string]int{"aws_db_instance": 2}}, false},
+		{RuleMatch{Actions: []string{"destroy"}}, true},
+		{RuleMatch{Actions: []string{"destroy_deposed"}}, false},
+		{RuleMatch{Modules: []string{"db"}}, true},
+		{RuleMatch{Modules: []string{"network"}}, false},
+		{RuleMatch{CrossModule: true}, true},
+	}
+	for _, test := range tests {
+		if got := (SuggestionRule{Match: test.match}).Matches(nodes); got != test.want {
+			t.Errorf("Expected %v for %+v, got %v", test.want, test.match, got)
+		}
+	}
+}
+
+func TestWithSuggestionRules(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_db_instance.main (destroy), aws_db_parameter_group.main")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	rules := MergeSuggestionRules(DefaultSuggestionRules(), parseTestRules(t, testRulesYAML))
+	analyzer := NewCycleAnalyzer(cycle, WithSuggestionRules(rules...))
+	
+	var found *Suggestion
+	suggestions := analyzer.GenerateSuggestions(analyzer.NodeNames())
+	for i := range suggestions {
+		if suggestions[i].ID == "rds-name-prefix" {
+			found = &suggestions[i]
+		}
+		if suggestions[i].ID == "remove-direct-references" {
+			t.Errorf("Expected no fallback suggestions once a rule matched")
+		}
+	}
+	if found == nil || found.Title != "Use name_prefix on aws_db_parameter_group.main" ||
+		len(found.Docs) != 1 || !strings.Contains(found.Docs[0], "db_parameter_group") {
+		t.Errorf("Expected the rendered custom suggestion, got %+v", found)
+	}
+}
+
+func TestValidateRuleIDs(t *testing.T) {
+	if err := ValidateRuleIDs([]string{"TFC001", "TFC-AWS-*", "split"}, nil); err != nil {
+		t.Errorf("Expected known rule IDs, globs and fix kinds to be valid, got: %v", err)
+	}
+	if err := ValidateRuleIDs([]string{"TFC999"}, nil); err == nil {
+		t.Error("Expected an error for an unknown rule ID")
+	}
+	if err := ValidateRuleIDs([]string{"TFC["}, nil); err == nil {
+		t.Error("Expected an error for a malformed pattern")
+	}
+}
+
+func TestDefaultSuggestionRules_RuleIDs(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, rule := range DefaultSuggestionRules() {
+		for _, suggestion := range rule.Suggestions {
+			if suggestion.RuleID == "" || seen[suggestion.RuleID] {
+				t.Errorf("Expected a unique rule ID for %s, got %q", suggestion.ID, suggestion.RuleID)
+			}
+			seen[suggestion.RuleID] = true
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package tfcycle
+
+import (
+	"fmt"
+	"strings"
+)
+
+const scriptPrelude = `#!/usr/bin/env bash
+# Remediation script generated by tfcycle %s.
+#
+# Each step asks for confirmation before it runs. Answer anything other than
+# "y" to stop the script; steps that already ran are not rolled back.
+set -euo pipefail
+
+confirm() {
+  local answer
+  read -r -p "$1 [y/N] " answer
+  case "$answer" in
+    [yY]|[yY][eE][sS]) return 0 ;;
+    *) echo "Aborted."; exit 1 ;;
+  esac
+}
+
+`
+
+// FormatRemediationScript returns a bash script that walks through the staged
+// apply plan and fixes, asking before every command.
+func (of *OutputFormatter) FormatRemediationScript(fixes []Fix) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf(scriptPrelude, Version))
+	
+	step := 1
+	for _, fix := range fixes {
+		if fix.Moves == nil {
+			continue
+		}
+		
+		output.WriteString(fmt.Sprintf("# Step %d: %s\n", step, fix.Title))
+		output.WriteString("# Only run this after moving the resource blocks into their new modules\n")
+		output.WriteString("# (or use the moved blocks from 'tfcycle fix' instead).\n")
+		
+		// Plans into a new module are relative to the moved resource's
+		// module; the others hold absolute addresses.
+		var modulePath []string
+		if node := of.analyzer.cycle.GetNodeByName(fix.Address); node != nil && fix.Moves.Module != "" {
+			modulePath = node.ModulePath
+		}
+		commands := fix.Moves.StateMvCommands(modulePath)
+		output.WriteString(fmt.Sprintf("confirm %s\n", shellQuote(fmt.Sprintf("Step %d: move %d resource(s) in state?", step, len(commands)))))
+		for _, command := range commands {
+			output.WriteString(command + "\n")
+		}
+		output.WriteString("\n")
+		step++
+	}
+	
+	plan := of.analyzer.StagedApplyPlan()
+	if len(plan.FeedbackArcs) > 0 {
+		output.WriteString("# The targeted applies below defer these dependencies:\n")
+		for _, arc := range plan.FeedbackArcs {
+			output.WriteString(fmt.Sprintf("#   %s -> %s\n", arc.From, arc.To))
+		}
+		output.WriteString("\n")
+	}
+	
+	for _, stage := range plan.Stages {
+		output.WriteString(fmt.Sprintf("# Step %d: %s\n", step, stage.Command))
+		output.WriteString(fmt.Sprintf("confirm %s\n", shellQuote(fmt.Sprintf("Step %d: %s?", step, stage.Command))))
+		output.WriteString(stage.Command + "\n\n")
+		step++
+	}
+	
+	output.WriteString("echo \"Remediation complete.\"\n")
+	
+	return output.String()
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
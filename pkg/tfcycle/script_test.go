@@ -0,0 +1,35 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutputFormatter_FormatRemediationScript(t *testing.T) {
+	formatter := newTestFormatter()
+	fixes := []Fix{{
+		Title:   "Move aws_security_group.sg1 into a separate module",
+		Address: "module.vpc.aws_security_group.sg1",
+		Moves:   &MovePlan{Module: "split_sg1", Moves: []ResourceMove{{From: "aws_security_group.sg1", To: "module.split_sg1.aws_security_group.sg1"}}},
+	}}
+	
+	script := formatter.FormatRemediationScript(fixes)
+	
+	if !strings.HasPrefix(script, "#!/usr/bin/env bash\n") {
+		t.Errorf("Expected bash shebang, got:\n%s", script)
+	}
+	
+	if !strings.Contains(script, "terraform state mv 'module.vpc.aws_security_group.sg1' 'module.vpc.module.split_sg1.aws_security_group.sg1'") {
+		t.Errorf("Expected module-qualified state mv command, got:\n%s", script)
+	}
+	
+	if strings.Count(script, "\nconfirm ") != strings.Count(script, "\nterraform apply")+1 {
+		t.Errorf("Expected a confirmation prompt before every step, got:\n%s", script)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("Unexpected quoting: %s", got)
+	}
+}
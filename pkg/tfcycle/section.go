@@ -0,0 +1,131 @@
+package tfcycle
+
+import "strings"
+
+// CycleSection picks the cycle diagnostic out of Terraform output that is fed
+// to it one line at a time. Only the "Error: Cycle:" line and the rest of its
+// diagnostic block are retained, so logs of any size can be scanned in
+// bounded memory. Input whose first non-blank line starts with "{" is taken
+// to be JSON (see DetectInputFormat), and input starting with "digraph" a
+// DOT graph; both are retained whole. Versions mentioned before the
+// diagnostic are collected, as are the last ContextLines non-blank lines
+// before it. The zero value is ready to use.
+type CycleSection struct {
+	// ContextLines is how many lines before the diagnostic to keep for its
+	// context (see TfCycle.Context).
+	ContextLines int
+
+	text     strings.Builder
+	context  []string
+	versions Versions
+	seenText bool
+	json     bool
+	graph    bool
+	started  bool
+	done     bool
+}
+
+// AddLine adds the next line of input. A trailing "\n" or "\r\n" is
+// optional, and a byte order mark before the first line is dropped.
+func (s *CycleSection) AddLine(line string) {
+	line = strings.TrimRight(strings.TrimSuffix(line, "\n"), "\r")
+	if !s.seenText {
+		line = strings.TrimPrefix(line, byteOrderMark)
+	}
+	
+	switch {
+	case s.json, s.graph:
+		s.retain(line)
+	case s.done:
+	case s.started:
+		if endsDiagnostic(line) {
+			s.done = true
+			return
+		}
+		s.retain(line)
+	case !s.seenText && strings.TrimSpace(line) == "":
+	case !s.seenText && looksLikeJSON(line):
+		s.seenText, s.json = true, true
+		s.retain(line)
+	case !s.seenText && looksLikeDOT(strings.TrimSpace(line)):
+		s.seenText, s.graph = true, true
+		s.retain(line)
+	case cycleStartRegex.MatchString(line):
+		s.seenText, s.started = true, true
+		s.retain(line)
+	default:
+		s.seenText = true
+		s.versions.addLine(line)
+		if s.ContextLines > 0 && strings.TrimSpace(line) != "" {
+			if len(s.context) == s.ContextLines {
+				s.context = s.context[1:]
+			}
+			s.context = append(s.context, line)
+		}
+	}
+}
+
+// Versions returns the versions seen before the cycle diagnostic, or nil if
+// there were none.
+func (s *CycleSection) Versions() *Versions {
+	if s.versions.Empty() {
+		return nil
+	}
+	versions := s.versions
+	return &versions
+}
+
+func (s *CycleSection) retain(line string) {
+	s.text.WriteString(line)
+	s.text.WriteByte('\n')
+}
+
+// endsDiagnostic reports whether line closes a diagnostic: a blank line, or
+// the empty "│" line or closing "╵" of Terraform's boxed output.
+func endsDiagnostic(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || trimmed == "│" || strings.HasPrefix(trimmed, "╵")
+}
+
+// Found reports whether a cycle error, JSON or a DOT graph has been seen.
+func (s *CycleSection) Found() bool {
+	return s.started || s.json || s.graph
+}
+
+// Done reports whether the cycle diagnostic is complete, so the remaining
+// input can be skipped.
+func (s *CycleSection) Done() bool {
+	return s.done
+}
+
+// Empty reports whether every line so far was blank.
+func (s *CycleSection) Empty() bool {
+	return !s.seenText
+}
+
+// JSON reports whether the input is a JSON analysis report or other JSON,
+// such as Terraform's JSON output, a plan or a state.
+func (s *CycleSection) JSON() bool {
+	return s.json
+}
+
+// Graph reports whether the input is a DOT graph, as printed by terraform
+// graph.
+func (s *CycleSection) Graph() bool {
+	return s.graph
+}
+
+// String returns the retained lines: the cycle diagnostic, the whole JSON
+// or DOT input, or "" if none has been seen.
+func (s *CycleSection) String() string {
+	return s.text.String()
+}
+
+// Context returns up to ContextLines non-blank lines that preceded the cycle
+// diagnostic, or "" if there were none or no diagnostic has been seen.
+func (s *CycleSection) Context() string {
+	if !s.started || len(s.context) == 0 {
+		return ""
+	}
+	return strings.Join(s.context, "\n") + "\n"
+}
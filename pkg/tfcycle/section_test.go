@@ -0,0 +1,55 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+func sectionOf(input string) *CycleSection {
+	var section CycleSection
+	for _, line := range strings.SplitAfter(input, "\n") {
+		if line != "" {
+			section.AddLine(line)
+		}
+	}
+	return &section
+}
+
+func TestCycleSection_BoxedDiagnostic(t *testing.T) {
+	input := strings.Repeat("aws_instance.web: Refreshing state...\n", 1000) +
+		"╷\n" +
+		"│ Error: Cycle: aws_instance.a, aws_instance.b\r\n" +
+		"│ \n" +
+		"╵\n" +
+		strings.Repeat("Releasing state lock. This may take a few moments...\n", 1000)
+	
+	section := sectionOf(input)
+	if !section.Found() || !section.Done() {
+		t.Fatalf("Expected a complete cycle diagnostic, got found=%v done=%v", section.Found(), section.Done())
+	}
+	if section.String() != "│ Error: Cycle: aws_instance.a, aws_instance.b\n" {
+		t.Errorf("Expected only the cycle line to be retained, got %q", section.String())
+	}
+}
+
+func TestCycleSection_PlainDiagnostic(t *testing.T) {
+	section := sectionOf("Planning...\nError: Cycle: aws_instance.a,\naws_instance.b\n\nError: Cycle: aws_instance.c, aws_instance.d\n")
+	if section.String() != "Error: Cycle: aws_instance.a,\naws_instance.b\n" {
+		t.Errorf("Expected the first diagnostic up to the blank line, got %q", section.String())
+	}
+}
+
+func TestCycleSection_JSONAndEmpty(t *testing.T) {
+	input := "\n{\n  \"cycle\": {}\n\n}\n"
+	section := sectionOf(input)
+	if !section.JSON() || section.String() != strings.TrimPrefix(input, "\n") {
+		t.Errorf("Expected the JSON report to be retained whole, got %q", section.String())
+	}
+	
+	if section := sectionOf("\n  \n"); !section.Empty() || section.Found() {
+		t.Errorf("Expected blank input to be empty")
+	}
+	if section := sectionOf("Plan: 1 to add\n"); section.Empty() || section.Found() || section.String() != "" {
+		t.Errorf("Expected text without a cycle to retain nothing, got %q", section.String())
+	}
+}
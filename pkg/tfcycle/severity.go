@@ -0,0 +1,101 @@
+package tfcycle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks how disruptive resolving a cycle is likely to be.
+type Severity int
+
+// Severities in increasing order.
+const (
+	SeverityNone Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	default:
+		return "none"
+	}
+}
+
+// ParseSeverity parses none, low, medium or high, ignoring case.
+func ParseSeverity(value string) (Severity, error) {
+	switch strings.ToLower(value) {
+	case "none":
+		return SeverityNone, nil
+	case "low":
+		return SeverityLow, nil
+	case "medium":
+		return SeverityMedium, nil
+	case "high":
+		return SeverityHigh, nil
+	default:
+		return SeverityNone, fmt.Errorf("invalid severity %q (use none, low, medium or high)", value)
+	}
+}
+
+// MarshalText encodes the severity as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name as ParseSeverity does.
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// CycleSeverity rates cycle: high if it destroys or replaces a resource (see
+// CycleNode.Destroying), medium if it
+// spans modules, more than three resources or a stateful resource, low
+// otherwise.
+func (ca *CycleAnalyzer) CycleSeverity(cycle []string) Severity {
+	if len(cycle) == 0 {
+		return SeverityNone
+	}
+	
+	modules := make(map[string]bool)
+	stateful := false
+	for _, name := range cycle {
+		node := ca.cycle.GetNodeByName(name)
+		if node == nil {
+			continue
+		}
+		if node.Destroying() {
+			return SeverityHigh
+		}
+		modules[strings.Join(node.ModulePath, ".")] = true
+		stateful = stateful || node.IsStateful()
+	}
+	
+	if len(modules) > 1 || len(cycle) > 3 || stateful {
+		return SeverityMedium
+	}
+	return SeverityLow
+}
+
+// Severity returns the highest severity of the minimal cycles.
+func (ca *CycleAnalyzer) Severity() Severity {
+	severity := SeverityNone
+	for _, cycle := range ca.FindMinimalCycles() {
+		if s := ca.CycleSeverity(cycle); s > severity {
+			severity = s
+		}
+	}
+	return severity
+}
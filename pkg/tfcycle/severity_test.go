@@ -0,0 +1,33 @@
+package tfcycle
+
+import "testing"
+
+func TestCycleSeverity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Severity
+	}{
+		{`Error: Cycle: aws_security_group.a, aws_security_group.b`, SeverityLow},
+		{`Error: Cycle: aws_security_group.a (destroy), aws_security_group.b`, SeverityHigh},
+		{`Error: Cycle: module.a.aws_iam_role.r, module.b.aws_iam_policy.p`, SeverityMedium},
+	}
+	
+	for _, test := range tests {
+		cycle, err := NewParser().ParseError(test.input)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if severity := NewCycleAnalyzer(cycle).Severity(); severity != test.expected {
+			t.Errorf("Expected severity %s for %q, got %s", test.expected, test.input, severity)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	if severity, err := ParseSeverity("HIGH"); err != nil || severity != SeverityHigh {
+		t.Errorf("Expected high, got %s (%v)", severity, err)
+	}
+	if _, err := ParseSeverity("critical"); err == nil {
+		t.Errorf("Expected error for unknown severity")
+	}
+}
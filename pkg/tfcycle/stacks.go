@@ -0,0 +1,51 @@
+package tfcycle
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Terraform Stacks reports cycles between components, stack inputs, locals
+// and embedded stacks as a diagnostic that lists one item per line:
+//
+//	Error: Self-dependent items in configuration
+//
+//	The following items in your configuration form a circular dependency chain through their references:
+//	  - component.vpc
+//	  - stack.network.component.dns["us-east-1"]
+var (
+	stackCycleRegex = regexp.MustCompile(`(?s)form a circular dependency chain through their references:(.*)`)
+	stackItemRegex  = regexp.MustCompile(`(?m)^[│|\s]*-\s+(\S.*?)\s*$`)
+)
+
+// parseStackCycle parses the items listed by a Terraform Stacks cycle
+// error. Embedded stacks become the module path of a node, and components
+// get the resource type "component".
+func (p *Parser) parseStackCycle(errorText, items string) (*TfCycle, error) {
+	cycle := &TfCycle{RawError: errorText, Nodes: make([]*CycleNode, 0)}
+	for _, match := range stackItemRegex.FindAllStringSubmatch(items, -1) {
+		node, err := p.parseResource(match[1])
+		if err != nil {
+			logger.Warn("failed to parse stack item", "item", match[1], "error", err)
+			continue
+		}
+		cycle.Nodes = append(cycle.Nodes, node)
+	}
+	cycle.Nodes = mergeDuplicateNodes(cycle.Nodes)
+	
+	if len(cycle.Nodes) == 0 {
+		return nil, ErrNoCycleFound
+	}
+	return cycle, nil
+}
+
+// stackComponent reports whether node is a component of a Terraform Stack.
+func (n *CycleNode) stackComponent() bool {
+	return n.ResourceType == "component"
+}
+
+// stackEdgeReason explains an edge between two components, which can only
+// come from the inputs of one referencing the outputs of the other.
+func stackEdgeReason(from, to *CycleNode) string {
+	return fmt.Sprintf("the inputs of %s reference outputs of %s", from.Address(), to.Address())
+}
@@ -0,0 +1,92 @@
+package tfcycle
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const stackLog = `Planning deployment "production"...
+
+╷
+│ Error: Self-dependent items in configuration
+│
+│ The following items in your configuration form a circular dependency chain through their references:
+│   - component.vpc
+│   - stack.network.component.dns["us-east-1"]
+│   - local.zone_name
+│
+│ Terraform uses references to decide a suitable order for performing operations, so configuration items may not refer to their own results either directly or indirectly.
+╵
+`
+
+func TestParseStackCycle(t *testing.T) {
+	cycle, err := NewParser().ParseError(stackLog)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !cycle.Stack() {
+		t.Fatal("Expected a Stacks cycle")
+	}
+	if len(cycle.Nodes) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(cycle.Nodes))
+	}
+	
+	dns := cycle.Nodes[1]
+	if dns.ResourceType != "component" || dns.ResourceName != "dns" || dns.InstanceKey != "us-east-1" {
+		t.Errorf("Expected component dns with its instance key, got %+v", dns)
+	}
+	if strings.Join(dns.ModulePath, ".") != "stack.network" {
+		t.Errorf("Expected the embedded stack as the path, got %v", dns.ModulePath)
+	}
+	if dns.Address() != `stack.network.component.dns["us-east-1"]` {
+		t.Errorf("Expected the stack address, got %s", dns.Address())
+	}
+}
+
+func TestParse_Stack(t *testing.T) {
+	cycle, err := Parse(context.Background(), strings.NewReader(stackLog))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !cycle.Stack() || len(cycle.Nodes) != 3 {
+		t.Errorf("Expected the three stack items, got %+v", cycle.Nodes)
+	}
+}
+
+func TestStack_Analysis(t *testing.T) {
+	cycle, err := NewParser().ParseError(`The following items in your configuration form a circular dependency chain through their references:
+  - component.vpc
+  - component.cluster
+`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	suggestions := analyzer.GenerateSuggestions(analyzer.FindMinimalCycles()[0])
+	if len(suggestions) == 0 || suggestions[0].ID != "stack-one-way-inputs" {
+		t.Fatalf("Expected the Stacks suggestions first, got %+v", suggestions)
+	}
+	if !strings.Contains(suggestions[0].Snippet, `component "vpc"`) || !strings.Contains(suggestions[0].Snippet, "component.cluster.id") {
+		t.Errorf("Expected a snippet with both components, got:\n%s", suggestions[0].Snippet)
+	}
+	for _, suggestion := range suggestions {
+		if !strings.HasPrefix(suggestion.ID, "stack-") {
+			t.Errorf("Expected only Stacks suggestions, got %s", suggestion.ID)
+		}
+	}
+	
+	explanation := analyzer.ExplainNode(cycle.Nodes[0])
+	if len(explanation.Outgoing) == 0 || explanation.Outgoing[0].Reason != "the inputs of component.vpc reference outputs of component.cluster" {
+		t.Errorf("Expected an edge reason about component inputs, got %+v", explanation.Outgoing)
+	}
+	if fixes := NewFixer(analyzer, nil).GenerateFixes(); len(fixes) != 0 {
+		t.Errorf("Expected no Terraform fixes, got %+v", fixes)
+	}
+	for _, stage := range analyzer.StagedApplyPlan().Stages {
+		if strings.Contains(stage.Command, "-target") {
+			t.Errorf("Expected no -target for components, got %s", stage.Command)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package tfcycle
+
+import "fmt"
+
+// Effort estimates how much work applying a Suggestion takes.
+type Effort string
+
+// Efforts in increasing order.
+const (
+	EffortLow    Effort = "low"
+	EffortMedium Effort = "medium"
+	EffortHigh   Effort = "high"
+)
+
+const lifecycleDocURL = "https://developer.hashicorp.com/terraform/language/meta-arguments/lifecycle"
+
+// Suggestion is a remediation for a cycle. ID is stable across releases so
+// tooling can filter on it; Severity is that of the cycle it addresses.
+type Suggestion struct {
+	ID       string   `json:"id"`
+	RuleID   string   `json:"rule_id,omitempty"`
+	Title    string   `json:"title"`
+	Detail   string   `json:"detail,omitempty"`
+	Severity Severity `json:"severity"`
+	Effort   Effort   `json:"effort"`
+	Docs     []string `json:"docs,omitempty"`
+	Snippet  string   `json:"snippet,omitempty"`
+}
+
+func (s Suggestion) String() string {
+	return s.Title
+}
+
+// GenerateSuggestions returns remediation advice for cycle from the rules
+// given with WithSuggestionRules, or DefaultSuggestionRules, leaving out
+// suggestions turned off with WithRuleSelection.
+func (ca *CycleAnalyzer) GenerateSuggestions(cycle []string) []Suggestion {
+	context := ca.ruleContext(cycle)
+	severity := ca.CycleSeverity(cycle)
+	var suggestions []Suggestion
+	for _, rule := range ca.matchRules(context) {
+		for _, suggestion := range rule.Render(context, severity) {
+			if ca.showsSuggestion(suggestion.RuleID) {
+				suggestions = append(suggestions, suggestion)
+			}
+		}
+	}
+	return suggestions
+}
+
+// SuggestionsShown reports whether suggestions are shown at all (see
+// WithoutSuggestions).
+func (ca *CycleAnalyzer) SuggestionsShown() bool {
+	return !ca.noSuggestions
+}
+
+// showsSuggestion reports whether a suggestion of the rule ruleID is neither
+// turned off (WithRuleSelection) nor hidden (WithSuppressedSuggestions,
+// WithoutSuggestions).
+func (ca *CycleAnalyzer) showsSuggestion(ruleID string) bool {
+	if ca.noSuggestions || !ca.rules.allows(ruleID) {
+		return false
+	}
+	return ruleID == "" || !matchesAny(ca.suppressed, ruleID)
+}
+
+// MatchedRules returns the IDs of the suggestion rules that apply to cycle:
+// those whose conditions it meets or, failing that, the fallback rules.
+func (ca *CycleAnalyzer) MatchedRules(cycle []string) []string {
+	var ids []string
+	for _, rule := range ca.matchRules(ca.ruleContext(cycle)) {
+		ids = append(ids, rule.ID)
+	}
+	return ids
+}
+
+func (ca *CycleAnalyzer) ruleContext(cycle []string) RuleContext {
+	context := RuleContext{Versions: ca.cycle.Versions}
+	for _, nodeName := range cycle {
+		node := ca.cycle.GetNodeByName(nodeName)
+		if node == nil {
+			continue
+		}
+		context.Nodes = append(context.Nodes, node)
+		if node.Destroying() {
+			context.Destroyed = append(context.Destroyed, node)
+		}
+		if len(context.Split(node.ResourceType)) > 0 {
+			context.Splittable = append(context.Splittable, node)
+		}
+	}
+	for _, rule := range ca.activeRules() {
+		if rule.Match.Refactorable && !rule.Disabled {
+			context.Refactor = ca.ModuleRefactor(cycle)
+			break
+		}
+	}
+	return context
+}
+
+func (ca *CycleAnalyzer) activeRules() []SuggestionRule {
+	if ca.suggestionRules == nil {
+		return builtinRules
+	}
+	return ca.suggestionRules
+}
+
+func (ca *CycleAnalyzer) matchRules(context RuleContext) []SuggestionRule {
+	rules := ca.activeRules()
+	nodes := context.Nodes
+	
+	var matched []SuggestionRule
+	for _, rule := range rules {
+		if rule.Disabled || rule.Fallback || !rule.Matches(nodes) {
+			continue
+		}
+		if rule.Match.Refactorable && context.Refactor == nil {
+			continue
+		}
+		logger.Debug("suggestion rule matched", "rule", rule.ID)
+		matched = append(matched, rule)
+	}
+	if len(matched) > 0 {
+		return matched
+	}
+	
+	for _, rule := range rules {
+		if rule.Fallback && !rule.Disabled && rule.Matches(nodes) {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+func createBeforeDestroySnippet(node *CycleNode) string {
+	return fmt.Sprintf("resource %q %q {\n  # ...\n\n  lifecycle {\n    create_before_destroy = true\n  }\n}\n",
+		node.ResourceType, node.ResourceName)
+}
@@ -0,0 +1,56 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSuggestions_Structured(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_security_group.web, aws_security_group.db (destroy)")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	suggestions := analyzer.GenerateSuggestions(analyzer.NodeNames())
+	byID := make(map[string]Suggestion)
+	for _, suggestion := range suggestions {
+		if suggestion.ID == "" || suggestion.Title == "" || suggestion.Effort == "" {
+			t.Errorf("Expected ID, title and effort, got %+v", suggestion)
+		}
+		if suggestion.Severity != SeverityHigh {
+			t.Errorf("Expected the destroy cycle's high severity, got %s for %s", suggestion.Severity, suggestion.ID)
+		}
+		byID[suggestion.ID] = suggestion
+	}
+	
+	rule, ok := byID["security-group-rule-resources"]
+	if !ok || !strings.Contains(rule.Snippet, "source_security_group_id = aws_security_group.db.id") {
+		t.Errorf("Expected a security group rule snippet for the cycle's groups, got %+v", rule)
+	}
+	if cbd := byID["create-before-destroy"]; !strings.Contains(cbd.Snippet, `resource "aws_security_group" "db"`) || len(cbd.Docs) == 0 {
+		t.Errorf("Expected a create_before_destroy snippet for the destroyed group, got %+v", cbd)
+	}
+	
+	data, err := json.Marshal(rule)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(string(data), `"severity":"high"`) || !strings.Contains(string(data), `"effort":"medium"`) {
+		t.Errorf("Expected severity and effort as names, got %s", data)
+	}
+}
+
+func TestGenerateSuggestions_Fallback(t *testing.T) {
+	cycle, err := NewParser().ParseError("Error: Cycle: aws_instance.a, aws_instance.b")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	suggestions := analyzer.GenerateSuggestions(analyzer.NodeNames())
+	if len(suggestions) != 4 || suggestions[0].ID != "remove-direct-references" || suggestions[0].Severity != SeverityLow {
+		t.Errorf("Expected the general suggestions, got %+v", suggestions)
+	}
+}
@@ -0,0 +1,93 @@
+package tfcycle
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Report sections and formats that ReportTemplates can override.
+var (
+	ReportSections = []string{"header", "suggestions", "footer"}
+	ReportFormats  = []string{"text", "markdown"}
+)
+
+// ReportTemplates replace sections of the text and Markdown reports. Each
+// template is a file named SECTION.FORMAT.tmpl, such as header.markdown.tmpl.
+type ReportTemplates struct {
+	templates map[string]*template.Template
+}
+
+// ReportSection is the data a report template is executed with.
+type ReportSection struct {
+	// Default is what the section contains without the template, so that
+	// templates can add to it rather than replace it.
+	Default     string
+	Severity    string
+	Partial     bool
+	Terragrunt  bool
+	Resources   int
+	Cycles      [][]string
+	Suggestions []Suggestion
+}
+
+var reportTemplateFuncs = template.FuncMap{
+	"env":  os.Getenv,
+	"join": strings.Join,
+}
+
+// LoadReportTemplates parses the *.tmpl files of dir. Files that do not name
+// a known section and format are an error, so typos do not go unnoticed.
+func LoadReportTemplates(dir string) (*ReportTemplates, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to read template directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	
+	templates := &ReportTemplates{templates: make(map[string]*template.Template)}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+		section, format, _ := strings.Cut(name, ".")
+		if !slices.Contains(ReportSections, section) || !slices.Contains(ReportFormats, format) {
+			return nil, fmt.Errorf("%s: unknown report template (use SECTION.FORMAT.tmpl with sections %s and formats %s)",
+				path, strings.Join(ReportSections, ", "), strings.Join(ReportFormats, ", "))
+		}
+		
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read report template: %w", err)
+		}
+		tmpl, err := template.New(name).Funcs(reportTemplateFuncs).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		templates.templates[name] = tmpl
+	}
+	return templates, nil
+}
+
+// render executes the template for section and format with data, returning
+// data.Default if there is none or it fails.
+func (rt *ReportTemplates) render(section, format string, data ReportSection) string {
+	if rt == nil {
+		return data.Default
+	}
+	tmpl, ok := rt.templates[section+"."+format]
+	if !ok {
+		return data.Default
+	}
+	
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, data); err != nil {
+		logger.Warn("report template failed, using the default", "template", tmpl.Name(), "error", err)
+		return data.Default
+	}
+	return output.String()
+}
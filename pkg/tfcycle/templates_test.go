@@ -0,0 +1,74 @@
+package tfcycle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReportTemplates(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"header.text.tmpl":          "ACME platform — {{ .Severity }} cycle\n\n",
+		"footer.markdown.tmpl":      "---\nRun: {{ env \"TFCYCLE_TEST_RUN_URL\" }} · Ask #platform-help\n",
+		"suggestions.markdown.tmpl": "{{ .Default }}Internal guide: https://wiki.example.com/cycles ({{ len .Suggestions }} suggestions)\n\n",
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("TFCYCLE_TEST_RUN_URL", "https://ci.example.com/run/42")
+	
+	templates, err := LoadReportTemplates(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	cycle, err := NewParser().ParseError(explainTestError)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	formatter := NewOutputFormatter(NewCycleAnalyzer(cycle), false)
+	formatter.SetTemplates(templates)
+	
+	text := formatter.FormatAnalysis()
+	if !strings.HasPrefix(text, "ACME platform — low cycle\n\n") || !strings.Contains(text, "💡 SUGGESTIONS:") {
+		t.Errorf("Expected the custom header and default suggestions, got:\n%s", text)
+	}
+	
+	markdown := formatter.FormatAsMarkdown()
+	if !strings.HasPrefix(markdown, "# ") || !strings.HasSuffix(markdown, "Run: https://ci.example.com/run/42 · Ask #platform-help\n") {
+		t.Errorf("Expected the default title and custom footer, got:\n%s", markdown)
+	}
+	if !strings.Contains(markdown, "\n\nInternal guide: https://wiki.example.com/cycles") {
+		t.Errorf("Expected the guide after the default suggestions, got:\n%s", markdown)
+	}
+}
+
+func TestLoadReportTemplates_Errors(t *testing.T) {
+	tests := map[string]string{
+		"unknown section": "sidebar.text.tmpl",
+		"unknown format":  "header.html.tmpl",
+	}
+	for name, file := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadReportTemplates(dir); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "header.text.tmpl"), []byte("{{ .Severity"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadReportTemplates(dir); err == nil {
+		t.Errorf("Expected a parse error")
+	}
+	if _, err := LoadReportTemplates(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("Expected an error for a missing directory")
+	}
+}
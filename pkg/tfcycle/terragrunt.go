@@ -0,0 +1,50 @@
+package tfcycle
+
+import (
+	"regexp"
+	"strings"
+)
+
+var terragruntCycleRegex = regexp.MustCompile(`Found a dependency cycle between (?:modules|units):?\s*(.*)`)
+
+const terragruntEdgeReason = "dependency block in terragrunt.hcl"
+
+// parseTerragruntCycle parses the chain of unit directories of a Terragrunt
+// dependency cycle error, such as
+//
+//	Found a dependency cycle between modules: /live/vpc -> /live/app -> /live/vpc
+//
+// Each unit depends on the next. Units before the first occurrence of the
+// repeated last unit lead into the cycle and are dropped.
+func parseTerragruntCycle(errorText, chain string) (*TfCycle, error) {
+	var units []string
+	for _, part := range strings.Split(chain, "->") {
+		unit := strings.Trim(strings.TrimSpace(part), `"'`)
+		unit = strings.TrimSuffix(unit, "/")
+		if unit != "" {
+			units = append(units, unit)
+		}
+	}
+	if len(units) < 2 {
+		return nil, ErrNoCycleFound
+	}
+	
+	last := units[len(units)-1]
+	for i, unit := range units[:len(units)-1] {
+		if unit == last {
+			units = units[i : len(units)-1]
+			break
+		}
+	}
+	
+	cycle := &TfCycle{RawError: errorText, Nodes: make([]*CycleNode, 0, len(units))}
+	seen := make(map[string]bool)
+	for _, unit := range units {
+		if seen[unit] {
+			continue
+		}
+		seen[unit] = true
+		cycle.Nodes = append(cycle.Nodes, &CycleNode{Unit: unit, RawString: unit})
+	}
+	return cycle, nil
+}
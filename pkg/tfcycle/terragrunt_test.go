@@ -0,0 +1,114 @@
+package tfcycle
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const terragruntLog = `time=2026-10-01T12:00:00Z level=error msg=Error processing module at '/live/prod/app/terragrunt.hcl'.
+ERRO[0000] Found a dependency cycle between modules: /live/prod/root -> /live/prod/vpc -> /live/prod/app/ -> /live/prod/vpc
+`
+
+func TestParseTerragruntCycle(t *testing.T) {
+	cycle, err := NewParser().ParseError(terragruntLog)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !cycle.Terragrunt() {
+		t.Fatal("Expected a Terragrunt cycle")
+	}
+	
+	var units []string
+	for _, node := range cycle.Nodes {
+		units = append(units, node.Unit)
+	}
+	if !slices.Equal(units, []string{"/live/prod/vpc", "/live/prod/app"}) {
+		t.Errorf("Expected the units of the cycle without the lead-in, got %v", units)
+	}
+	if cycle.Nodes[0].Address() != "/live/prod/vpc" {
+		t.Errorf("Expected the unit as the address, got %s", cycle.Nodes[0].Address())
+	}
+}
+
+func TestParseTerragruntCycle_Units(t *testing.T) {
+	cycle, err := NewParser().ParseError(`Found a dependency cycle between units: "network" -> "dns" -> "network"`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cycle.Nodes) != 2 || cycle.Nodes[0].Unit != "network" || cycle.Nodes[1].Unit != "dns" {
+		t.Errorf("Expected network and dns, got %+v", cycle.Nodes)
+	}
+	
+	if _, err := NewParser().ParseError("Found a dependency cycle between modules: /live/vpc"); err == nil {
+		t.Error("Expected an error for a single unit")
+	}
+}
+
+func TestParse_Terragrunt(t *testing.T) {
+	cycle, err := Parse(context.Background(), strings.NewReader("INFO starting\n"+terragruntLog))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !cycle.Terragrunt() || len(cycle.Nodes) != 2 {
+		t.Errorf("Expected a Terragrunt cycle with two units, got %+v", cycle.Nodes)
+	}
+}
+
+func TestTerragrunt_Analysis(t *testing.T) {
+	cycle, err := NewParser().ParseError(terragruntLog)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	analyzer := NewCycleAnalyzer(cycle)
+	
+	graph := analyzer.HypothesizedGraph()
+	if !slices.Equal(graph["/live/prod/vpc"], []string{"/live/prod/app"}) || !slices.Equal(graph["/live/prod/app"], []string{"/live/prod/vpc"}) {
+		t.Errorf("Expected each unit to depend on the next, got %v", graph)
+	}
+	
+	suggestions := analyzer.GenerateSuggestions(analyzer.FindMinimalCycles()[0])
+	if len(suggestions) == 0 || suggestions[0].ID != "terragrunt-remove-dependency" {
+		t.Fatalf("Expected the Terragrunt suggestions first, got %+v", suggestions)
+	}
+	if !strings.Contains(suggestions[0].Title, "/live/prod/vpc") {
+		t.Errorf("Expected the unit in the title, got %s", suggestions[0].Title)
+	}
+	for _, suggestion := range suggestions {
+		if !strings.HasPrefix(suggestion.ID, "terragrunt-") {
+			t.Errorf("Expected only Terragrunt suggestions, got %s", suggestion.ID)
+		}
+	}
+	if fixes := NewFixer(analyzer, nil).GenerateFixes(); len(fixes) != 0 {
+		t.Errorf("Expected no Terraform fixes, got %+v", fixes)
+	}
+	
+	plan := analyzer.StagedApplyPlan()
+	last := plan.Stages[len(plan.Stages)-1]
+	if last.Command != "terragrunt run-all apply" {
+		t.Errorf("Expected terragrunt run-all apply last, got %s", last.Command)
+	}
+	if !strings.HasPrefix(plan.Stages[0].Command, "(cd '/live/prod/") || !strings.Contains(plan.Stages[0].Command, "terragrunt apply") {
+		t.Errorf("Expected a terragrunt apply in the unit directory, got %s", plan.Stages[0].Command)
+	}
+	
+	report := NewOutputFormatter(analyzer, false).FormatAnalysis()
+	if !strings.Contains(report, "TERRAGRUNT") || strings.Contains(report, "COMMON SOLUTIONS") {
+		t.Errorf("Expected a Terragrunt report without Terraform solutions, got:\n%s", report)
+	}
+}
+
+func TestRedactCycle_Terragrunt(t *testing.T) {
+	cycle, err := NewParser().ParseError(terragruntLog)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	redacted := NewRedactor().RedactCycle(cycle)
+	if redacted.Nodes[0].Unit != "unit_1" || redacted.Nodes[1].Unit != "unit_2" {
+		t.Errorf("Expected unit pseudonyms, got %+v", redacted.Nodes)
+	}
+	if strings.Contains(redacted.RawError, "live") {
+		t.Errorf("Expected the unit paths removed from the raw error, got %s", redacted.RawError)
+	}
+}
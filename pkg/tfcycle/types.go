@@ -0,0 +1,228 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NodeAction is the graph operation Terraform annotates a node with.
+type NodeAction int
+
+// Node actions, parsed from suffixes such as "(destroy)".
+const (
+	ActionNormal NodeAction = iota
+	ActionExpand
+	ActionDestroy
+	ActionClose
+	ActionDestroyDeposed
+)
+
+func (a NodeAction) String() string {
+	switch a {
+	case ActionExpand:
+		return "expand"
+	case ActionDestroy:
+		return "destroy"
+	case ActionClose:
+		return "close"
+	case ActionDestroyDeposed:
+		return "destroy_deposed"
+	default:
+		return "normal"
+	}
+}
+
+// ParseNodeAction returns the action whose String is name.
+func ParseNodeAction(name string) (NodeAction, error) {
+	for action := ActionNormal; action <= ActionDestroyDeposed; action++ {
+		if action.String() == name {
+			return action, nil
+		}
+	}
+	return ActionNormal, fmt.Errorf("unknown node action %q", name)
+}
+
+// MarshalText encodes the action as its name, so JSON reports carry
+// "destroy" rather than an integer.
+func (a NodeAction) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an action name.
+func (a *NodeAction) UnmarshalText(text []byte) error {
+	action, err := ParseNodeAction(string(text))
+	if err != nil {
+		return err
+	}
+	*a = action
+	return nil
+}
+
+// UnmarshalJSON decodes an action name, or the integer that reports before
+// analysis schema version 2 used.
+func (a *NodeAction) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		if n < int(ActionNormal) || n > int(ActionDestroyDeposed) {
+			return fmt.Errorf("unknown node action %d", n)
+		}
+		*a = NodeAction(n)
+		return nil
+	}
+	
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("node action must be a string, got %s", data)
+	}
+	return a.UnmarshalText([]byte(name))
+}
+
+// CycleNode is one resource of a cycle, or one unit of a Terragrunt
+// dependency cycle, in which case only Unit and RawString are set.
+type CycleNode struct {
+	ResourceType   string            `json:"resource_type"`
+	ResourceName   string            `json:"resource_name"`
+	ModulePath     []string          `json:"module_path"`
+	DataSource     bool              `json:"data_source,omitempty"`
+	InstanceKey    string            `json:"instance_key,omitempty"`
+	Unit           string            `json:"unit,omitempty"`
+	Action         NodeAction        `json:"action"`
+	Annotations    map[string]string `json:"annotations,omitempty"`
+	RawString      string            `json:"raw_string"`
+	Location       *SourceLocation   `json:"location,omitempty"`
+	ConstructPath  string            `json:"construct_path,omitempty"`
+}
+
+// SourceLocation is where a resource is declared.
+type SourceLocation struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func (l *SourceLocation) String() string {
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
+
+// FullName returns the node's address including module path and instance
+// key, with the key unquoted. It names the node in graphs and reports.
+func (n *CycleNode) FullName() string {
+	result := n.baseAddress()
+	if n.InstanceKey != "" {
+		result += "[" + n.InstanceKey + "]"
+	}
+	
+	return result
+}
+
+// Address returns the node's address in Terraform's own syntax, as used by
+// plan and state JSON and -target: string instance keys are quoted, e.g.
+// module.app.aws_instance.web["prod"] or data.aws_ami.ubuntu.
+func (n *CycleNode) Address() string {
+	result := n.baseAddress()
+	switch {
+	case n.InstanceKey == "":
+	case n.stringInstanceKey():
+		result += "[" + strconv.Quote(n.InstanceKey) + "]"
+	default:
+		result += "[" + n.InstanceKey + "]"
+	}
+	return result
+}
+
+func (n *CycleNode) baseAddress() string {
+	if n.Unit != "" {
+		return n.Unit
+	}
+	
+	parts := make([]string, 0, len(n.ModulePath)+2)
+	parts = append(parts, n.ModulePath...)
+	if n.DataSource {
+		parts = append(parts, "data")
+	}
+	parts = append(parts, n.ResourceType+"."+n.ResourceName)
+	return strings.Join(parts, ".")
+}
+
+// stringInstanceKey reports whether the instance key is a for_each string key
+// rather than a count index. The parser drops the quotes, so this goes by how
+// Terraform listed the node, falling back to whether the key is a number.
+func (n *CycleNode) stringInstanceKey() bool {
+	switch {
+	case strings.Contains(n.RawString, `["`+n.InstanceKey+`"]`):
+		return true
+	case strings.Contains(n.RawString, "["+n.InstanceKey+"]"):
+		return false
+	}
+	_, err := strconv.Atoi(n.InstanceKey)
+	return err != nil
+}
+
+// MarshalJSON adds the node's Address to its fields.
+func (n *CycleNode) MarshalJSON() ([]byte, error) {
+	type fields CycleNode
+	return json.Marshal(struct {
+		Address string `json:"address"`
+		*fields
+	}{n.Address(), (*fields)(n)})
+}
+
+// String returns the address followed by the action, as Terraform prints it.
+func (n *CycleNode) String() string {
+	name := n.FullName()
+	if n.Action != ActionNormal {
+		name += fmt.Sprintf(" (%s", n.Action.String())
+		if n.Action == ActionDestroyDeposed && n.Annotations["deposed_id"] != "" {
+			name += " " + n.Annotations["deposed_id"]
+		}
+		name += ")"
+	}
+	return name
+}
+
+// TfCycle is a parsed cycle error. Cycles is filled in by CycleAnalyzer.
+// Context is the output before the error, if there was any.
+type TfCycle struct {
+	Nodes     []*CycleNode  `json:"nodes"`
+	RawError  string        `json:"raw_error"`
+	Cycles    [][]string    `json:"cycles,omitempty"`
+	Versions  *Versions     `json:"versions,omitempty"`
+	Context   *CycleContext `json:"context,omitempty"`
+}
+
+// Terragrunt reports whether the cycle is between Terragrunt units rather
+// than resources.
+func (tc *TfCycle) Terragrunt() bool {
+	return len(tc.Nodes) > 0 && tc.Nodes[0].Unit != ""
+}
+
+// Stack reports whether the cycle runs through the components of a
+// Terraform Stack.
+func (tc *TfCycle) Stack() bool {
+	for _, node := range tc.Nodes {
+		if node.stackComponent() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNodeByName returns the node with the given full address, or nil.
+func (tc *TfCycle) GetNodeByName(name string) *CycleNode {
+	for _, node := range tc.Nodes {
+		if node.FullName() == name {
+			return node
+		}
+	}
+	return nil
+}
+
+// GetResourceTypes counts the nodes per resource type.
+func (tc *TfCycle) GetResourceTypes() map[string]int {
+	types := make(map[string]int)
+	for _, node := range tc.Nodes {
+		types[node.ResourceType]++
+	}
+	return types
+}
\ No newline at end of file
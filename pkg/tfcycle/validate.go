@@ -0,0 +1,115 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// validateOutput is the output of "terraform validate -json".
+type validateOutput struct {
+	Valid       *bool                `json:"valid"`
+	Diagnostics []validateDiagnostic `json:"diagnostics"`
+}
+
+type validateDiagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail"`
+	Range    *struct {
+		Filename string `json:"filename"`
+		Start    struct {
+			Line int `json:"line"`
+		} `json:"start"`
+	} `json:"range"`
+	Snippet *struct {
+		Context string `json:"context"`
+	} `json:"snippet"`
+}
+
+// cycle returns the text of the diagnostic as Terraform prints it, if it is a
+// cycle error.
+func (d validateDiagnostic) cycle() (string, bool) {
+	if d.Severity != "error" || !strings.HasPrefix(strings.TrimSpace(d.Summary), "Cycle:") {
+		return "", false
+	}
+	return "Error: " + strings.TrimSpace(d.Summary), true
+}
+
+// decodeValidateJSON decodes text as "terraform validate -json" output. It
+// returns false for other JSON, such as analysis reports.
+func decodeValidateJSON(text string) (validateOutput, bool) {
+	var output validateOutput
+	if !looksLikeJSON(text) || json.Unmarshal([]byte(text), &output) != nil {
+		return output, false
+	}
+	return output, output.Valid != nil && output.Diagnostics != nil
+}
+
+// isValidateCycle reports whether text is "terraform validate -json" output
+// with a cycle error.
+func isValidateCycle(text string) bool {
+	output, ok := decodeValidateJSON(text)
+	if !ok {
+		return false
+	}
+	for _, diagnostic := range output.Diagnostics {
+		if _, ok := diagnostic.cycle(); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseValidateJSON parses the first cycle error of "terraform validate
+// -json" output. Diagnostics whose snippet context is the block of a cycle
+// node give that node its Location, so validate output carries file:line
+// information without --config-dir.
+func (p *Parser) parseValidateJSON(output validateOutput) (*TfCycle, error) {
+	var cycle *TfCycle
+	for _, diagnostic := range output.Diagnostics {
+		errorText, ok := diagnostic.cycle()
+		if !ok {
+			continue
+		}
+		var err error
+		if cycle, err = p.ParseError(errorText); err != nil {
+			return nil, err
+		}
+		break
+	}
+	if cycle == nil {
+		return nil, ErrNoCycleFound
+	}
+	
+	for _, diagnostic := range output.Diagnostics {
+		if diagnostic.Range == nil || diagnostic.Snippet == nil {
+			continue
+		}
+		var matched []*CycleNode
+		for _, node := range cycle.Nodes {
+			if node.Location == nil && diagnostic.Snippet.Context == blockHeader(node) {
+				matched = append(matched, node)
+			}
+		}
+		if len(matched) != 1 {
+			continue
+		}
+		matched[0].Location = &SourceLocation{
+			File: filepath.ToSlash(filepath.Clean(diagnostic.Range.Filename)),
+			Line: diagnostic.Range.Start.Line,
+		}
+	}
+	return cycle, nil
+}
+
+// blockHeader returns the header of the block declaring node, as Terraform
+// shows it in the context of a diagnostic snippet.
+func blockHeader(node *CycleNode) string {
+	kind := "resource"
+	if node.DataSource {
+		kind = "data"
+	}
+	return fmt.Sprintf("%s %q %q", kind, node.ResourceType, node.ResourceName)
+}
@@ -0,0 +1,95 @@
+package tfcycle
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const validateJSON = `{
+  "format_version": "1.0",
+  "valid": false,
+  "error_count": 2,
+  "warning_count": 0,
+  "diagnostics": [
+    {
+      "severity": "error",
+      "summary": "Cycle: aws_security_group.web, module.db.aws_security_group.db",
+      "detail": ""
+    },
+    {
+      "severity": "error",
+      "summary": "Reference to undeclared resource",
+      "detail": "A managed resource \"aws_instance\" \"app\" has not been declared in the root module.",
+      "range": {
+        "filename": "main.tf",
+        "start": {"line": 14, "column": 14, "byte": 300},
+        "end": {"line": 14, "column": 30, "byte": 316}
+      },
+      "snippet": {"context": "resource \"aws_security_group\" \"web\"", "code": "    cidr_blocks = [aws_instance.app.private_ip]", "start_line": 14}
+    },
+    {
+      "severity": "warning",
+      "summary": "Deprecated attribute",
+      "detail": "",
+      "range": {
+        "filename": "modules/db/main.tf",
+        "start": {"line": 3, "column": 3, "byte": 40},
+        "end": {"line": 3, "column": 9, "byte": 46}
+      },
+      "snippet": {"context": "resource \"aws_security_group\" \"db\"", "code": "  name = \"db\"", "start_line": 3}
+    }
+  ]
+}
+`
+
+func TestParseValidateJSON(t *testing.T) {
+	cycle, err := NewParser().ParseError(validateJSON)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cycle.Nodes) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(cycle.Nodes))
+	}
+	if cycle.RawError != "Error: Cycle: aws_security_group.web, module.db.aws_security_group.db" {
+		t.Errorf("Expected the cycle diagnostic as the raw error, got %q", cycle.RawError)
+	}
+	if location := cycle.Nodes[0].Location; location == nil || location.String() != "main.tf:14" {
+		t.Errorf("Expected main.tf:14 for aws_security_group.web, got %v", location)
+	}
+	if location := cycle.Nodes[1].Location; location == nil || location.String() != "modules/db/main.tf:3" {
+		t.Errorf("Expected modules/db/main.tf:3 for the module's group, got %v", location)
+	}
+	
+	report := NewOutputFormatter(NewCycleAnalyzer(cycle), false).FormatAnalysis()
+	if !strings.Contains(report, "📍 main.tf:14") {
+		t.Errorf("Expected the location in the report, got:\n%s", report)
+	}
+}
+
+func TestParseValidateJSON_Inputs(t *testing.T) {
+	if !NewParser().ContainsCycle(validateJSON) || !IsCycleInput(validateJSON) {
+		t.Error("Expected validate output with a cycle to be recognized")
+	}
+	
+	valid := `{"format_version": "1.0", "valid": true, "error_count": 0, "warning_count": 0, "diagnostics": []}`
+	if NewParser().ContainsCycle(valid) {
+		t.Error("Expected no cycle in valid configuration")
+	}
+	if _, err := ParseInput(valid); err == nil {
+		t.Error("Expected an error for validate output without a cycle")
+	}
+	
+	for name, parse := range map[string]func() (*TfCycle, error){
+		"ParseInput": func() (*TfCycle, error) { return ParseInput(validateJSON) },
+		"Parse":      func() (*TfCycle, error) { return Parse(context.Background(), strings.NewReader(validateJSON)) },
+	} {
+		cycle, err := parse()
+		if err != nil {
+			t.Fatalf("%s: expected no error, got: %v", name, err)
+		}
+		if len(cycle.Nodes) != 2 || cycle.Nodes[0].Location == nil {
+			t.Errorf("%s: expected the nodes with locations, got %+v", name, cycle.Nodes)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package tfcycle
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ToolTerraform and ToolOpenTofu are the values of Versions.Tool.
+const (
+	ToolTerraform = "terraform"
+	ToolOpenTofu  = "opentofu"
+)
+
+// Versions are the Terraform or OpenTofu version and the provider versions
+// found in the output around a cycle error. Empty fields are unknown.
+type Versions struct {
+	Tool string `json:"tool,omitempty"`
+	// Core is the Terraform or OpenTofu version, such as "1.5.7".
+	Core string `json:"core,omitempty"`
+	// Providers maps provider names such as "aws" to their versions.
+	Providers map[string]string `json:"providers,omitempty"`
+}
+
+var (
+	// "Terraform v1.5.7" from terraform version, "Terraform version: 1.5.7"
+	// from TF_LOG and "terraform_version": "1.5.7" from plan JSON.
+	coreVersionRegex = regexp.MustCompile(`(?i)\b(terraform|opentofu)(?: v|_version"?:\s*"| version:\s*v?)(\d+\.\d+(?:\.\d+)?)`)
+	// "- Installed hashicorp/aws v5.31.0 (signed by HashiCorp)" from
+	// terraform init and "+ provider registry.terraform.io/hashicorp/aws
+	// v5.31.0" from terraform version.
+	providerVersionRegex = regexp.MustCompile(`(?:Installing|Installed|Using previously-installed|provider) (?:[\w.-]+/)*[\w-]+/([\w-]+) v(\d+\.\d+(?:\.\d+)?)`)
+	versionRegex         = regexp.MustCompile(`^v?\d+(?:\.\d+){0,2}$`)
+)
+
+// DetectVersions returns the versions mentioned in text, or nil if there are
+// none.
+func DetectVersions(text string) *Versions {
+	var versions Versions
+	for _, line := range strings.Split(text, "\n") {
+		versions.addLine(line)
+	}
+	if versions.Empty() {
+		return nil
+	}
+	return &versions
+}
+
+func (v *Versions) addLine(line string) {
+	if matches := coreVersionRegex.FindStringSubmatch(line); matches != nil && v.Core == "" {
+		v.Tool = strings.ToLower(matches[1])
+		v.Core = matches[2]
+	}
+	for _, matches := range providerVersionRegex.FindAllStringSubmatch(line, -1) {
+		if v.Providers == nil {
+			v.Providers = make(map[string]string)
+		}
+		if _, seen := v.Providers[matches[1]]; !seen {
+			v.Providers[matches[1]] = matches[2]
+		}
+	}
+}
+
+// Empty reports whether no version is known.
+func (v *Versions) Empty() bool {
+	return v == nil || (v.Core == "" && len(v.Providers) == 0)
+}
+
+// AtLeast reports whether the version of name, "terraform" for Terraform or
+// OpenTofu or a provider name such as "aws", is minimum or later. Unknown
+// versions satisfy every minimum, so advice is only withheld when a version
+// is known to be too old.
+func (v *Versions) AtLeast(name, minimum string) bool {
+	version := v.Version(name)
+	return version == "" || compareVersions(version, minimum) >= 0
+}
+
+// Version returns the version of name, keyed as for AtLeast, or "" if it is
+// unknown.
+func (v *Versions) Version(name string) string {
+	switch {
+	case v == nil:
+		return ""
+	case name == ToolTerraform:
+		return v.Core
+	default:
+		return v.Providers[name]
+	}
+}
+
+// meets reports whether v satisfies every minimum, keyed as for AtLeast.
+func (v *Versions) meets(minimums map[string]string) bool {
+	for name, minimum := range minimums {
+		if !v.AtLeast(name, minimum) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateVersion returns an error unless version looks like 1.5 or v1.5.7.
+func ValidateVersion(version string) error {
+	if !versionRegex.MatchString(version) {
+		return fmt.Errorf("invalid version %q (use MAJOR.MINOR[.PATCH], e.g. 1.5.7)", version)
+	}
+	return nil
+}
+
+// compareVersions compares dotted numeric versions; missing parts count as 0.
+func compareVersions(a, b string) int {
+	partsA := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	partsB := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			y, _ = strconv.Atoi(partsB[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
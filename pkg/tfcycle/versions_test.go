@@ -0,0 +1,130 @@
+package tfcycle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectVersions(t *testing.T) {
+	text := `Terraform v1.5.7
+on linux_amd64
++ provider registry.terraform.io/hashicorp/aws v5.31.0
+- Installed hashicorp/random v3.6.0 (signed by HashiCorp)
+
+Error: Cycle: aws_security_group.web, aws_security_group.db`
+	
+	versions := DetectVersions(text)
+	if versions == nil || versions.Tool != ToolTerraform || versions.Core != "1.5.7" {
+		t.Fatalf("Expected Terraform 1.5.7, got %+v", versions)
+	}
+	if versions.Providers["aws"] != "5.31.0" || versions.Providers["random"] != "3.6.0" {
+		t.Errorf("Expected the aws and random provider versions, got %v", versions.Providers)
+	}
+	
+	if tofu := DetectVersions("OpenTofu v1.6.2\non linux_amd64"); tofu == nil || tofu.Tool != ToolOpenTofu || tofu.Core != "1.6.2" {
+		t.Errorf("Expected OpenTofu 1.6.2, got %+v", tofu)
+	}
+	if none := DetectVersions("Error: Cycle: a.b, c.d"); none != nil {
+		t.Errorf("Expected no versions, got %+v", none)
+	}
+}
+
+func TestParseError_Versions(t *testing.T) {
+	cycle, err := NewParser().ParseError("Terraform v1.0.11\n\nError: Cycle: aws_instance.a, aws_instance.b")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cycle.Versions == nil || cycle.Versions.Core != "1.0.11" {
+		t.Errorf("Expected the version from the log, got %+v", cycle.Versions)
+	}
+}
+
+func TestVersions_AtLeast(t *testing.T) {
+	versions := &Versions{Core: "1.0.11", Providers: map[string]string{"aws": "4.67.0"}}
+	tests := []struct {
+		name, minimum string
+		want          bool
+	}{
+		{"terraform", "1.1", false},
+		{"terraform", "1.0", true},
+		{"aws", "5.0", false},
+		{"aws", "4.62", true},
+		{"google", "5.0", true},
+	}
+	for _, tt := range tests {
+		if got := versions.AtLeast(tt.name, tt.minimum); got != tt.want {
+			t.Errorf("AtLeast(%s, %s) = %v, want %v", tt.name, tt.minimum, got, tt.want)
+		}
+	}
+	if !(*Versions)(nil).AtLeast("terraform", "99") {
+		t.Errorf("Expected unknown versions to satisfy every minimum")
+	}
+	
+	if err := ValidateVersion("v1.5.7"); err != nil {
+		t.Errorf("Expected v1.5.7 to be valid, got: %v", err)
+	}
+	if err := ValidateVersion("latest"); err == nil {
+		t.Errorf("Expected an error for latest")
+	}
+}
+
+func TestGenerateSuggestions_Versions(t *testing.T) {
+	suggestionIDs := func(versions *Versions) string {
+		cycle, err := NewParser().ParseError("Error: Cycle: aws_security_group.web, aws_security_group.db")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		cycle.Versions = versions
+		analyzer := NewCycleAnalyzer(cycle)
+		var ids []string
+		for _, suggestion := range analyzer.GenerateSuggestions(analyzer.NodeNames()) {
+			ids = append(ids, suggestion.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+	
+	if ids := suggestionIDs(&Versions{Providers: map[string]string{"aws": "4.67.0"}}); strings.Contains(ids, "security-group-vpc-rule-resources") {
+		t.Errorf("Expected no aws_vpc_security_group_ingress_rule advice on AWS provider 4, got %s", ids)
+	}
+	if ids := suggestionIDs(&Versions{Providers: map[string]string{"aws": "5.31.0"}}); !strings.Contains(ids, "security-group-vpc-rule-resources") {
+		t.Errorf("Expected aws_vpc_security_group_ingress_rule advice on AWS provider 5, got %s", ids)
+	}
+	if ids := suggestionIDs(nil); !strings.Contains(ids, "security-group-vpc-rule-resources") {
+		t.Errorf("Expected unknown versions to keep all advice, got %s", ids)
+	}
+}
+
+func TestFixer_SplitVersions(t *testing.T) {
+	dir := writeTestConfig(t, map[string]string{"main.tf": `resource "aws_instance" "a" {
+  ami = "ami-123"
+}
+`})
+	index, err := ScanConfig(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	splitSnippet := func(opts ...Option) string {
+		cycle, err := NewParser().ParseError("Error: Cycle: aws_instance.a, aws_instance.b")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		for _, fix := range NewFixer(NewCycleAnalyzer(cycle, opts...), index).GenerateFixes() {
+			if fix.Kind == "split" {
+				return fix.Snippet
+			}
+		}
+		t.Fatalf("Expected a split fix")
+		return ""
+	}
+	
+	if snippet := splitSnippet(WithTerraformVersion("1.5.7")); !strings.Contains(snippet, "moved {") || strings.Contains(snippet, "state mv") {
+		t.Errorf("Expected only moved blocks on Terraform 1.5, got:\n%s", snippet)
+	}
+	if snippet := splitSnippet(WithTerraformVersion("1.0.11")); strings.Contains(snippet, "moved {") || !strings.Contains(snippet, "\nterraform state mv") {
+		t.Errorf("Expected only state mv commands on Terraform 1.0, got:\n%s", snippet)
+	}
+	if snippet := splitSnippet(); !strings.Contains(snippet, "moved {") || !strings.Contains(snippet, "# terraform state mv") {
+		t.Errorf("Expected both variants for an unknown version, got:\n%s", snippet)
+	}
+}
@@ -0,0 +1,113 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Severities of a WarningsNGIssue.
+const (
+	WarningsNGError  = "ERROR"
+	WarningsNGHigh   = "HIGH"
+	WarningsNGNormal = "NORMAL"
+	WarningsNGLow    = "LOW"
+)
+
+// WarningsNGReport is an issue report in the native JSON format of the
+// Jenkins Warnings Next Generation plugin, read by its issues() tool.
+type WarningsNGReport struct {
+	Issues []WarningsNGIssue `json:"issues"`
+}
+
+// WarningsNGIssue is one issue of a WarningsNGReport. Description is HTML.
+type WarningsNGIssue struct {
+	FileName    string `json:"fileName,omitempty"`
+	LineStart   int    `json:"lineStart,omitempty"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category"`
+	Type        string `json:"type"`
+	ModuleName  string `json:"moduleName,omitempty"`
+}
+
+// WarningsNGSeverity maps a cycle severity to a Warnings NG severity.
+func WarningsNGSeverity(severity Severity) string {
+	switch severity {
+	case SeverityHigh:
+		return WarningsNGError
+	case SeverityMedium:
+		return WarningsNGHigh
+	case SeverityLow:
+		return WarningsNGNormal
+	default:
+		return WarningsNGLow
+	}
+}
+
+// WarningsNGDescription lists the titles of suggestions as HTML, after note
+// if it is not empty.
+func WarningsNGDescription(note string, suggestions []Suggestion) string {
+	var description strings.Builder
+	if note != "" {
+		description.WriteString("<p>" + html.EscapeString(note) + "</p>")
+	}
+	if len(suggestions) > 0 {
+		description.WriteString("<ul>")
+		for _, suggestion := range suggestions {
+			description.WriteString("<li>" + html.EscapeString(suggestion.Title) + "</li>")
+		}
+		description.WriteString("</ul>")
+	}
+	return description.String()
+}
+
+// NewWarningsNGReport returns an issue for each minimal cycle of analyzer,
+// located at its first member whose configuration block is known. The type
+// is the ID of the first suggestion, so trends can be broken down by the
+// pattern behind the cycles.
+func NewWarningsNGReport(analyzer *CycleAnalyzer) WarningsNGReport {
+	report := WarningsNGReport{Issues: []WarningsNGIssue{}}
+	for _, cycle := range analyzer.FindMinimalCycles() {
+		suggestions := analyzer.GenerateSuggestions(cycle)
+		issue := WarningsNGIssue{
+			Severity:    WarningsNGSeverity(analyzer.CycleSeverity(cycle)),
+			Message:     "Cycle: " + CycleStrings([][]string{cycle})[0],
+			Description: WarningsNGDescription("", suggestions),
+			Category:    "cycle",
+			Type:        "cycle",
+		}
+		if len(suggestions) > 0 {
+			issue.Type = suggestions[0].ID
+		}
+		
+		for _, name := range cycle {
+			node := analyzer.cycle.GetNodeByName(name)
+			if node == nil {
+				continue
+			}
+			if issue.ModuleName == "" {
+				issue.ModuleName = strings.Join(node.ModulePath, ".")
+			}
+			if node.Location != nil {
+				issue.FileName = node.Location.File
+				issue.LineStart = node.Location.Line
+				issue.ModuleName = strings.Join(node.ModulePath, ".")
+				break
+			}
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+	return report
+}
+
+// FormatWarningsNG returns report as indented JSON.
+func FormatWarningsNG(report WarningsNGReport) (string, error) {
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Warnings NG report: %w", err)
+	}
+	return string(jsonData) + "\n", nil
+}
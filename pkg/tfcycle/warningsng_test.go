@@ -0,0 +1,40 @@
+package tfcycle
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewWarningsNGReport(t *testing.T) {
+	cycle, err := NewParser().ParseError(explainTestError)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	cycle.GetNodeByName("aws_security_group.sg_ping").Location = &SourceLocation{File: "network/main.tf", Line: 12}
+	
+	output, err := FormatWarningsNG(NewWarningsNGReport(NewCycleAnalyzer(cycle)))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var report WarningsNGReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if len(report.Issues) != 1 {
+		t.Fatalf("Expected one issue per minimal cycle, got %+v", report.Issues)
+	}
+	issue := report.Issues[0]
+	if issue.FileName != "network/main.tf" || issue.LineStart != 12 || issue.Severity != WarningsNGNormal {
+		t.Errorf("Expected a normal issue at network/main.tf:12, got %+v", issue)
+	}
+	if issue.Category != "cycle" || issue.Type == "cycle" || issue.Description == "" {
+		t.Errorf("Expected the first suggestion as type and suggestions as description, got %+v", issue)
+	}
+}
+
+func TestWarningsNGDescription(t *testing.T) {
+	description := WarningsNGDescription("fails on <apply>", []Suggestion{{Title: "Use a & b"}})
+	if description != "<p>fails on &lt;apply&gt;</p><ul><li>Use a &amp; b</li></ul>" {
+		t.Errorf("Expected escaped HTML, got %q", description)
+	}
+}
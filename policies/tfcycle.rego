@@ -0,0 +1,47 @@
+# Example policies for gating on tfcycle findings.
+#
+#   tfcycle analyze --format opa --error-file plan.log > input.json
+#   opa eval --fail-defined -i input.json -d policies/tfcycle.rego 'data.tfcycle.deny[msg]'
+#
+# Copy this file and adjust the rules and data to your organization.
+package tfcycle
+
+import rego.v1
+
+# Resource types whose participation in any cycle blocks the pipeline.
+protected_types := {
+	"aws_db_instance",
+	"aws_rds_cluster",
+	"aws_s3_bucket",
+	"google_sql_database_instance",
+}
+
+# Deny if any minimal cycle has high severity (it destroys or replaces a resource).
+deny contains msg if {
+	some cycle in input.cycles
+	cycle.severity == "high"
+	msg := sprintf("high-severity cycle: %s", [concat(" -> ", cycle.resources)])
+}
+
+# Deny if a cycle involves a protected resource type.
+deny contains msg if {
+	some cycle in input.cycles
+	some resource_type in cycle.resource_types
+	resource_type in protected_types
+	msg := sprintf("cycle involves protected resource type %s: %s", [resource_type, concat(" -> ", cycle.resources)])
+}
+
+# Deny if tfcycle gave up before finding minimal cycles, since the findings are incomplete.
+deny contains msg if {
+	input.partial
+	msg := "tfcycle analysis is partial; minimal cycles were not computed"
+}
+
+# Warn about cycles that span several modules; they usually need a refactor.
+warn contains msg if {
+	some cycle in input.cycles
+	count(cycle.modules) > 1
+	msg := sprintf("cycle spans modules %v: %s", [cycle.modules, concat(" -> ", cycle.resources)])
+}
+
+allow if count(deny) == 0
@@ -0,0 +1,61 @@
+package tfcycle_test
+
+import rego.v1
+
+import data.tfcycle
+
+no_cycle := {"cycle_found": false, "partial": false, "severity": "none", "cycles": []}
+
+low_cycle := {
+	"cycle_found": true,
+	"partial": false,
+	"severity": "low",
+	"cycles": [{
+		"resources": ["aws_security_group.a", "aws_security_group.b"],
+		"resource_types": ["aws_security_group"],
+		"modules": [""],
+		"severity": "low",
+		"has_destroy": false,
+	}],
+}
+
+high_cycle := {
+	"cycle_found": true,
+	"partial": false,
+	"severity": "high",
+	"cycles": [{
+		"resources": ["aws_instance.web", "aws_db_instance.main"],
+		"resource_types": ["aws_db_instance", "aws_instance"],
+		"modules": ["", "module.db"],
+		"severity": "high",
+		"has_destroy": true,
+	}],
+}
+
+partial_cycle := object.union(low_cycle, {"partial": true})
+
+test_allow_without_cycle if {
+	tfcycle.allow with input as no_cycle
+}
+
+test_allow_low_severity if {
+	tfcycle.allow with input as low_cycle
+}
+
+test_deny_high_severity if {
+	"high-severity cycle: aws_instance.web -> aws_db_instance.main" in tfcycle.deny with input as high_cycle
+}
+
+test_deny_protected_type if {
+	denied := tfcycle.deny with input as high_cycle
+	some msg in denied
+	startswith(msg, "cycle involves protected resource type aws_db_instance")
+}
+
+test_warn_cross_module if {
+	count(tfcycle.warn) == 1 with input as high_cycle
+}
+
+test_deny_partial if {
+	not tfcycle.allow with input as partial_cycle
+}
@@ -0,0 +1,203 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: tfcycle/v1/tfcycle.proto
+
+package tfcyclev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TfcycleService_Analyze_FullMethodName   = "/tfcycle.v1.TfcycleService/Analyze"
+	TfcycleService_Visualize_FullMethodName = "/tfcycle.v1.TfcycleService/Visualize"
+	TfcycleService_Explain_FullMethodName   = "/tfcycle.v1.TfcycleService/Explain"
+)
+
+// TfcycleServiceClient is the client API for TfcycleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TfcycleService analyzes Terraform "Error: Cycle:" output. Every request
+// carries the raw error text (or a JSON analysis produced by tfcycle).
+type TfcycleServiceClient interface {
+	Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error)
+	Visualize(ctx context.Context, in *VisualizeRequest, opts ...grpc.CallOption) (*VisualizeResponse, error)
+	Explain(ctx context.Context, in *ExplainRequest, opts ...grpc.CallOption) (*ExplainResponse, error)
+}
+
+type tfcycleServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTfcycleServiceClient(cc grpc.ClientConnInterface) TfcycleServiceClient {
+	return &tfcycleServiceClient{cc}
+}
+
+func (c *tfcycleServiceClient) Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnalyzeResponse)
+	err := c.cc.Invoke(ctx, TfcycleService_Analyze_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tfcycleServiceClient) Visualize(ctx context.Context, in *VisualizeRequest, opts ...grpc.CallOption) (*VisualizeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VisualizeResponse)
+	err := c.cc.Invoke(ctx, TfcycleService_Visualize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tfcycleServiceClient) Explain(ctx context.Context, in *ExplainRequest, opts ...grpc.CallOption) (*ExplainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExplainResponse)
+	err := c.cc.Invoke(ctx, TfcycleService_Explain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TfcycleServiceServer is the server API for TfcycleService service.
+// All implementations must embed UnimplementedTfcycleServiceServer
+// for forward compatibility.
+//
+// TfcycleService analyzes Terraform "Error: Cycle:" output. Every request
+// carries the raw error text (or a JSON analysis produced by tfcycle).
+type TfcycleServiceServer interface {
+	Analyze(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error)
+	Visualize(context.Context, *VisualizeRequest) (*VisualizeResponse, error)
+	Explain(context.Context, *ExplainRequest) (*ExplainResponse, error)
+	mustEmbedUnimplementedTfcycleServiceServer()
+}
+
+// UnimplementedTfcycleServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTfcycleServiceServer struct{}
+
+func (UnimplementedTfcycleServiceServer) Analyze(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Analyze not implemented")
+}
+func (UnimplementedTfcycleServiceServer) Visualize(context.Context, *VisualizeRequest) (*VisualizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Visualize not implemented")
+}
+func (UnimplementedTfcycleServiceServer) Explain(context.Context, *ExplainRequest) (*ExplainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Explain not implemented")
+}
+func (UnimplementedTfcycleServiceServer) mustEmbedUnimplementedTfcycleServiceServer() {}
+func (UnimplementedTfcycleServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeTfcycleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TfcycleServiceServer will
+// result in compilation errors.
+type UnsafeTfcycleServiceServer interface {
+	mustEmbedUnimplementedTfcycleServiceServer()
+}
+
+func RegisterTfcycleServiceServer(s grpc.ServiceRegistrar, srv TfcycleServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTfcycleServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TfcycleService_ServiceDesc, srv)
+}
+
+func _TfcycleService_Analyze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TfcycleServiceServer).Analyze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TfcycleService_Analyze_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TfcycleServiceServer).Analyze(ctx, req.(*AnalyzeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TfcycleService_Visualize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VisualizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TfcycleServiceServer).Visualize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TfcycleService_Visualize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TfcycleServiceServer).Visualize(ctx, req.(*VisualizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TfcycleService_Explain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TfcycleServiceServer).Explain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TfcycleService_Explain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TfcycleServiceServer).Explain(ctx, req.(*ExplainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TfcycleService_ServiceDesc is the grpc.ServiceDesc for TfcycleService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TfcycleService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tfcycle.v1.TfcycleService",
+	HandlerType: (*TfcycleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Analyze",
+			Handler:    _TfcycleService_Analyze_Handler,
+		},
+		{
+			MethodName: "Visualize",
+			Handler:    _TfcycleService_Visualize_Handler,
+		},
+		{
+			MethodName: "Explain",
+			Handler:    _TfcycleService_Explain_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "tfcycle/v1/tfcycle.proto",
+}